@@ -1,17 +1,30 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	nethttp "net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"app/config"
 	_ "app/docs" // Import generated docs
 	"app/internal/adapters/broker/kafka"
+	"app/internal/adapters/embedding"
+	"app/internal/adapters/faceindex"
+	"app/internal/adapters/fieldcrypt"
 	"app/internal/adapters/handler/http"
+	"app/internal/adapters/kms"
+	"app/internal/adapters/onvif"
+	"app/internal/adapters/rtsp"
 	localstorage "app/internal/adapters/storage/local"
 	"app/internal/adapters/storage/postgres"
 	"app/internal/adapters/storage/redis"
+	"app/internal/core/ports"
 	"app/internal/core/services"
 	"app/pkg/logger"
 
@@ -22,6 +35,9 @@ import (
 	"go.uber.org/zap"
 )
 
+// shutdownTimeout bounds how long in-flight requests get to finish.
+const shutdownTimeout = 15 * time.Second
+
 // @title           AI Camera API
 // @version         1.0
 // @description     This is the API server for AI Camera System.
@@ -99,47 +115,153 @@ func main() {
 
 	// --- WIRING DEPENDENCIES ---
 
+	// Field encryption: PII columns are encrypted with per-tenant data keys
+	var fieldCipher ports.FieldCipher = fieldcrypt.NewPlainCipher()
+	var keyRing ports.KeyRing
+	if cfg.Encryption.Enabled {
+		if cfg.Encryption.KMS != config.KMSProviderLocal {
+			logger.Error("Unsupported KMS provider", zap.String("kms", cfg.Encryption.KMS))
+			return
+		}
+		keyManager, err := kms.NewLocalKeyManager(cfg.Encryption)
+		if err != nil {
+			logger.Error("Failed to load master keys", zap.Error(err))
+			return
+		}
+		envelope, err := fieldcrypt.NewEnvelopeCipher(context.Background(), cfg.Encryption.Tenant, keyManager, postgres.NewDataKeyRepository(db))
+		if err != nil {
+			logger.Error("Failed to load data keys", zap.Error(err))
+			return
+		}
+		fieldCipher, keyRing = envelope, envelope
+		logger.Info("Field encryption enabled", zap.String("tenant", cfg.Encryption.Tenant))
+	}
+
 	// Repositories & Adapters
-	cameraRepo := postgres.NewCameraRepository(db)
-	userRepo := postgres.NewUserRepository(db)
+	cameraRepo := postgres.NewCameraRepository(db, fieldCipher)
+	cameraHealthRepo := postgres.NewCameraHealthRepository(db, fieldCipher)
+	uptimeRepo := postgres.NewUptimeRepository(db)
+	maintenanceRepo := postgres.NewMaintenanceRepository(db)
+	discoveryRepo := postgres.NewDiscoveryRepository(db)
+	userRepo := postgres.NewUserRepository(db, fieldCipher)
 	zoneRepo := postgres.NewZoneRepository(db)
-	identityRepo := postgres.NewIdentityRepository(db)
+	floorPlanRepo := postgres.NewFloorPlanRepository(db)
+	identityRepo := postgres.NewIdentityRepository(db, fieldCipher)
 	faceRepo := postgres.NewIdentityFaceRepository(db)
+	versionRepo := postgres.NewIdentityVersionRepository(db, fieldCipher)
+	duplicateRepo := postgres.NewIdentityDuplicateRepository(db, fieldCipher)
+	strangerRepo := postgres.NewStrangerRepository(db)
+	feedbackRepo := postgres.NewRecognitionFeedbackRepository(db)
+	datasetRepo := postgres.NewDatasetRepository(db)
 	aiRepo := postgres.NewAIRepository(db)
+	aiTemplateRepo := postgres.NewAITemplateRepository(db)
 	roleRepo := postgres.NewRoleRepository(db)
 	analyticsRepo := postgres.NewAnalyticsRepository(db)
 	auditRepo := postgres.NewAuditRepository(db)
+	accessLogRepo := postgres.NewAccessLogRepository(db)
 	permRepo := postgres.NewPermissionRepository(db)
+	notificationRepo := postgres.NewNotificationRepository(db)
+	jobRepo := postgres.NewJobRepository(db)
+	privacyRepo := postgres.NewIdentityPrivacyRepository(db, fieldCipher)
+
+	// Jobs die with the process, so anything still running was interrupted
+	if n, err := jobRepo.FailRunning(context.Background(), "interrupted by server restart"); err != nil {
+		logger.Error("Failed to reset interrupted jobs", zap.Error(err))
+	} else if n > 0 {
+		logger.Info("Marked interrupted jobs as failed", zap.Int64("jobs", n))
+	}
 
 	// Host for static files
 	baseURL := fmt.Sprintf("http://localhost:%d/uploads", cfg.Server.Port)
 	fileStorage := localstorage.NewLocalStorage(uploadDir, baseURL)
 
+	// Face search: pgvector when available, otherwise an in-process index
+	var faceIndex ports.FaceIndex
+	backend := cfg.FaceSearch.Backend
+	if backend == config.FaceSearchBackendPgVector ||
+		backend == config.FaceSearchBackendAuto && postgres.HasPgVector(context.Background(), db) {
+		faceIndex = postgres.NewPgVectorFaceIndex(db)
+		logger.Info("Face search using pgvector")
+	} else {
+		memIndex := faceindex.NewMemoryIndex()
+		n, err := memIndex.Load(context.Background(), faceRepo)
+		if err != nil {
+			logger.Error("Failed to load face embeddings", zap.Error(err))
+		}
+		faceIndex = memIndex
+		logger.Info("Face search using in-memory index", zap.Int("faces", n))
+	}
+
+	var embedder ports.EmbeddingProvider
+	if cfg.FaceSearch.EmbeddingURL != "" {
+		embedder = embedding.NewHTTPProvider(cfg.FaceSearch.EmbeddingURL)
+	}
+
 	// Services
-	cameraService := services.NewCameraService(cameraRepo)
+	auditService := services.NewAuditService(auditRepo)
+	accessAuditService := services.NewAccessAuditService(accessLogRepo)
+	defer accessAuditService.Close()
+	notificationService := services.NewNotificationService(notificationRepo)
+	jobService := services.NewJobService(jobRepo)
+	cameraService := services.NewCameraService(cameraRepo, zoneRepo, cameraHealthRepo, auditService)
+	uptimeService := services.NewUptimeService(uptimeRepo)
+	maintenanceService := services.NewMaintenanceService(maintenanceRepo, cameraHealthRepo, cameraRepo, zoneRepo, auditService, cfg.Maintenance)
+	cameraHealthService := services.NewCameraHealthService(cameraHealthRepo, cameraRepo, maintenanceRepo, rtsp.NewProber(time.Duration(cfg.CameraHealth.TimeoutSeconds)*time.Second), aiRepo, cfg.CameraHealth)
 	authService := services.NewAuthService(userRepo)
 	userService := services.NewUserService(userRepo) // Added UserService
 	zoneService := services.NewZoneService(zoneRepo)
-	identityService := services.NewIdentityService(identityRepo, faceRepo)
-	aiService := services.NewAIService(aiRepo)
+	discoveryService := services.NewDiscoveryService(discoveryRepo, onvif.NewClient(cfg.Discovery), cameraService, zoneRepo, jobService, auditService)
+	manifestService := services.NewManifestService(zoneRepo, cameraRepo, aiRepo, auditService)
+	floorPlanService := services.NewFloorPlanService(floorPlanRepo, zoneRepo, cameraRepo, fileStorage, auditService)
+	identityService := services.NewIdentityService(identityRepo, faceRepo, versionRepo, fileStorage, faceIndex, embedder, auditService, notificationService, producer, cfg.FaceQuality, cfg.FaceSearch)
+	identityImportService := services.NewIdentityImportService(identityRepo, versionRepo, fileStorage, faceIndex, embedder, jobService, auditService, cfg.FaceQuality)
+	privacyService := services.NewIdentityPrivacyService(privacyRepo, faceRepo, versionRepo, fileStorage, faceIndex, auditService, producer)
+	duplicateService := services.NewIdentityDuplicateService(duplicateRepo, identityRepo, faceRepo, versionRepo, faceIndex, jobService, auditService, producer, cfg.Duplicates)
+	strangerService := services.NewStrangerService(strangerRepo, identityService, fileStorage, embedder, jobService, auditService, cfg.Strangers)
+	feedbackService := services.NewRecognitionFeedbackService(feedbackRepo, identityRepo, auditService, cfg.Attendance)
+	datasetService := services.NewDatasetService(datasetRepo, fileStorage, jobService, auditService)
+	keyService := services.NewKeyService(keyRing, []ports.PIIStore{identityRepo, userRepo, versionRepo, cameraRepo}, jobService, auditService)
+	piiService := services.NewPIIService(roleRepo, cfg.PIIMasking)
+	aiService := services.NewAIService(aiRepo, maintenanceRepo)
+	aiTemplateService := services.NewAITemplateService(aiTemplateRepo, aiRepo, cameraRepo, auditService)
 	roleService := services.NewRoleService(roleRepo)
-	analyticsService := services.NewAnalyticsService(analyticsRepo)
-	auditService := services.NewAuditService(auditRepo)
+	analyticsService := services.NewAnalyticsService(analyticsRepo, identityRepo)
+	contactTraceService := services.NewContactTraceService(analyticsRepo, identityRepo, auditService, cfg.Contacts)
 	permService := services.NewPermissionService(permRepo)
 	mediaService := services.NewMediaService(fileStorage)
 
 	// Handlers
-	cameraHandler := http.NewCameraHandler(cameraService)
+	cameraHealthHandler := http.NewCameraHealthHandler(cameraHealthService)
+	uptimeHandler := http.NewUptimeHandler(uptimeService)
+	maintenanceHandler := http.NewMaintenanceHandler(maintenanceService)
+	discoveryHandler := http.NewDiscoveryHandler(discoveryService)
 	authHandler := http.NewAuthHandler(authService)
 	userHandler := http.NewUserHandler(userService) // Added UserHandler
-	zoneHandler := http.NewZoneHandler(zoneService)
-	identityHandler := http.NewIdentityHandler(identityService)
-	aiHandler := http.NewAIHandler(aiService)
+	accessRecorder := http.NewAccessRecorder(accessAuditService)
+	piiPresenter := http.NewPIIPresenter(piiService, accessRecorder)
+	cameraScoper := http.NewCameraScoper(piiPresenter, permService)
+	zoneHandler := http.NewZoneHandler(zoneService, cameraScoper)
+	cameraHandler := http.NewCameraHandler(cameraService, piiPresenter, cameraScoper)
+	manifestHandler := http.NewManifestHandler(manifestService, piiPresenter)
+	floorPlanHandler := http.NewFloorPlanHandler(floorPlanService, cameraScoper)
+	identityHandler := http.NewIdentityHandler(identityService, piiPresenter)
+	aiHandler := http.NewAIHandler(aiService, cameraScoper)
+	aiTemplateHandler := http.NewAITemplateHandler(aiTemplateService)
 	roleHandler := http.NewRoleHandler(roleService)
-	analyticsHandler := http.NewAnalyticsHandler(analyticsService)
-	auditHandler := http.NewAuditHandler(auditService)
+	analyticsHandler := http.NewAnalyticsHandler(analyticsService, accessRecorder)
+	auditHandler := http.NewAuditHandler(auditService, accessAuditService, piiPresenter)
 	permHandler := http.NewPermissionHandler(permService)
 	mediaHandler := http.NewMediaHandler(mediaService)
+	notificationHandler := http.NewNotificationHandler(notificationService)
+	jobHandler := http.NewJobHandler(jobService, piiPresenter)
+	identityImportHandler := http.NewIdentityImportHandler(identityImportService, jobService, piiPresenter, accessRecorder)
+	privacyHandler := http.NewIdentityPrivacyHandler(privacyService, piiPresenter, accessRecorder)
+	keyHandler := http.NewKeyHandler(keyService, piiPresenter)
+	duplicateHandler := http.NewIdentityDuplicateHandler(duplicateService, accessRecorder)
+	strangerHandler := http.NewStrangerHandler(strangerService, accessRecorder)
+	feedbackHandler := http.NewRecognitionFeedbackHandler(feedbackService, piiPresenter, accessRecorder)
+	datasetHandler := http.NewDatasetHandler(datasetService)
+	contactTraceHandler := http.NewContactTraceHandler(contactTraceService, piiPresenter, accessRecorder)
 
 	// --- ROUTES ---
 	apiV1 := r.Group("/api/v1")
@@ -158,23 +280,76 @@ func main() {
 
 			// Dashboard & AI
 			protected.GET("/stats/dashboard", aiHandler.GetDashboardStats)
+			protected.GET("/stats/worst-cameras", uptimeHandler.WorstCameras)
 			protected.GET("/ai-configs/camera/:cameraId", aiHandler.GetConfig)
+			protected.GET("/ai-configs/camera/:cameraId/versions", aiHandler.ListConfigVersions)
+			protected.GET("/ai-configs/camera/:cameraId/versions/:version", aiHandler.GetConfigVersion)
+			protected.POST("/ai-configs/camera/:cameraId/rollback", aiHandler.RollbackConfig)
+			protected.DELETE("/ai-configs/camera/:cameraId/template", aiHandler.DetachTemplate)
 			protected.POST("/ai-configs", aiHandler.UpdateConfig)
 			protected.PUT("/ai-configs/:id", aiHandler.UpdateConfig)
 			protected.GET("/events", aiHandler.ListEvents)
 			protected.PATCH("/events/:id", aiHandler.UpdateEventStatus)
 
+			// AI config templates
+			aiTemplates := protected.Group("/ai-config-templates")
+			{
+				aiTemplates.POST("", aiTemplateHandler.CreateTemplate)
+				aiTemplates.GET("", aiTemplateHandler.ListTemplates)
+				aiTemplates.GET("/:id", aiTemplateHandler.GetTemplate)
+				aiTemplates.PUT("/:id", aiTemplateHandler.UpdateTemplate)
+				aiTemplates.DELETE("/:id", aiTemplateHandler.DeleteTemplate)
+				aiTemplates.POST("/:id/apply", aiTemplateHandler.ApplyTemplate)
+				aiTemplates.GET("/:id/drift", aiTemplateHandler.TemplateDrift)
+			}
+
 			// Analytics & Attendance
 			analytics := protected.Group("")
 			{
 				analytics.GET("/recognition/logs", analyticsHandler.ListRecognitionLogs)
+				analytics.POST("/recognition/logs/:id/feedback", feedbackHandler.SubmitFeedback)
+				analytics.GET("/recognition/feedback", feedbackHandler.ListFeedback)
+				analytics.GET("/recognition/feedback/export", feedbackHandler.ExportFeedbackDataset)
+				analytics.GET("/recognition/strangers", strangerHandler.ListStrangerClusters)
+				analytics.POST("/recognition/strangers/cluster", strangerHandler.ClusterStrangers)
+				analytics.GET("/recognition/strangers/:id", strangerHandler.GetStrangerCluster)
+				analytics.GET("/recognition/strangers/:id/logs", strangerHandler.ListStrangerLogs)
+				analytics.POST("/recognition/strangers/:id/promote", strangerHandler.PromoteStranger)
 				analytics.GET("/attendance/records", analyticsHandler.ListAttendance)
 				analytics.GET("/attendance/summary", analyticsHandler.GetSummary)
 			}
 
+			// Notifications
+			protected.GET("/notifications", notificationHandler.ListNotifications)
+			protected.PATCH("/notifications/:id/read", notificationHandler.MarkRead)
+			protected.GET("/jobs", jobHandler.ListJobs)
+			protected.GET("/jobs/:id", jobHandler.GetJob)
+
+			// Camera availability
+			protected.GET("/reports/uptime", uptimeHandler.GetUptimeReport)
+			protected.GET("/reports/uptime/export", uptimeHandler.ExportUptimeReport)
+
+			// Maintenance windows
+			maintenance := protected.Group("/maintenance-windows")
+			{
+				maintenance.POST("", maintenanceHandler.CreateWindow)
+				maintenance.GET("", maintenanceHandler.ListWindows)
+				maintenance.GET("/:id", maintenanceHandler.GetWindow)
+				maintenance.PUT("/:id", maintenanceHandler.UpdateWindow)
+				maintenance.DELETE("/:id", maintenanceHandler.DeleteWindow)
+			}
+
+			// Training datasets
+			protected.POST("/datasets/export", datasetHandler.ExportDataset)
+
 			// System Logs
 			protected.GET("/audit-logs", auditHandler.ListLogs)
 
+			// Field encryption keys
+			protected.GET("/security/keys", keyHandler.ListKeys)
+			protected.POST("/security/keys/rotate", keyHandler.RotateKey)
+			protected.POST("/security/keys/reencrypt", keyHandler.Reencrypt)
+
 			// Permissions (Data Scoping)
 			protected.GET("/permissions/:userId", permHandler.GetPermissions)
 			protected.POST("/permissions/:userId/cameras", permHandler.UpdateCameraPermissions)
@@ -185,19 +360,41 @@ func main() {
 			{
 				zones.POST("", zoneHandler.CreateZone)
 				zones.GET("", zoneHandler.ListZones)
+				zones.GET("/tree", zoneHandler.ZoneTree)
 				zones.GET("/:id", zoneHandler.GetZone)
 				zones.PUT("/:id", zoneHandler.UpdateZone)
 				zones.DELETE("/:id", zoneHandler.DeleteZone)
+				zones.PUT("/:id/floor-plan", floorPlanHandler.UploadFloorPlan)
+				zones.GET("/:id/floor-plan", floorPlanHandler.GetFloorPlan)
+				zones.DELETE("/:id/floor-plan", floorPlanHandler.DeleteFloorPlan)
+				zones.GET("/:id/map", floorPlanHandler.GetZoneMap)
+				zones.PUT("/:id/area", floorPlanHandler.SetZoneArea)
+				zones.DELETE("/:id/area", floorPlanHandler.RemoveZoneArea)
 			}
 
 			// Cameras
 			cameras := protected.Group("/cameras")
 			{
 				cameras.POST("", cameraHandler.CreateCamera)
+				cameras.POST("/discovery", discoveryHandler.StartDiscovery)
+				cameras.POST("/discovery/adopt", discoveryHandler.AdoptDevices)
+				cameras.GET("/discovery/:job_id/devices", discoveryHandler.ListDiscoveredDevices)
 				cameras.GET("", cameraHandler.ListCameras)
 				cameras.GET("/:id", cameraHandler.GetCamera)
 				cameras.PUT("/:id", cameraHandler.UpdateCamera)
 				cameras.DELETE("/:id", cameraHandler.DeleteCamera)
+				cameras.GET("/:id/status-history", cameraHealthHandler.ListStatusHistory)
+				cameras.POST("/:id/credentials/reveal", cameraHandler.RevealCredentials)
+				cameras.PUT("/:id/placement", floorPlanHandler.PlaceCamera)
+				cameras.DELETE("/:id/placement", floorPlanHandler.RemovePlacement)
+			}
+
+			// Site manifest
+			manifest := protected.Group("/site-manifest")
+			{
+				manifest.GET("", manifestHandler.ExportManifest)
+				manifest.POST("/plan", manifestHandler.PlanManifest)
+				manifest.POST("/apply", manifestHandler.ApplyManifest)
 			}
 
 			// Identities & Faces
@@ -205,15 +402,35 @@ func main() {
 			{
 				identities.POST("", identityHandler.CreateIdentity)
 				identities.GET("", identityHandler.ListIdentities)
+				identities.GET("/pending", identityHandler.ListPending)
+				identities.POST("/import", identityImportHandler.ImportIdentities)
+				identities.GET("/import/:job_id/report", identityImportHandler.ImportReport)
+				identities.GET("/duplicates", duplicateHandler.ListDuplicates)
+				identities.POST("/duplicates/scan", duplicateHandler.ScanDuplicates)
+				identities.POST("/duplicates/:candidate_id/dismiss", duplicateHandler.DismissDuplicate)
 				identities.GET("/:id", identityHandler.GetIdentity)
 				identities.PUT("/:id", identityHandler.UpdateIdentity)
 				identities.PATCH("/:id/status", identityHandler.UpdateStatus)
+				identities.POST("/:id/approve", identityHandler.ApproveIdentity)
+				identities.POST("/:id/reject", identityHandler.RejectIdentity)
 				identities.DELETE("/:id", identityHandler.DeleteIdentity)
+				identities.GET("/:id/export", privacyHandler.ExportIdentity)
+				identities.POST("/:id/erase", privacyHandler.EraseIdentity)
+				identities.GET("/:id/access-report", auditHandler.IdentityAccessReport)
+				identities.GET("/:id/history", identityHandler.ListHistory)
+				identities.GET("/:id/timeline", analyticsHandler.GetTimeline)
+				identities.GET("/:id/contacts", contactTraceHandler.TraceContacts)
+				identities.GET("/:id/contacts/export", contactTraceHandler.ExportContacts)
+				identities.POST("/:id/revert", identityHandler.RevertIdentity)
+				identities.POST("/:id/merge", duplicateHandler.MergeIdentity)
 
 				identities.POST("/enroll-face", identityHandler.EnrollFace)
 				identities.DELETE("/faces/:face_id", identityHandler.DeleteFace)
 			}
 
+			// Face Search
+			protected.POST("/faces/search", identityHandler.SearchFaces)
+
 			// Roles
 			roles := protected.Group("/roles")
 			{
@@ -236,9 +453,26 @@ func main() {
 		}
 	}
 
+	// Shut down on SIGINT/SIGTERM rather than exiting, so in-flight requests
+	// finish and the deferred closes flush what is still queued
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	addr := fmt.Sprintf(":%d", cfg.Server.Port)
-	logger.Info("Server listening", zap.String("addr", addr))
-	if err := r.Run(addr); err != nil {
-		logger.Error("Failed to run server", zap.Error(err))
+	server := &nethttp.Server{Addr: addr, Handler: r}
+	go func() {
+		logger.Info("Server listening", zap.String("addr", addr))
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, nethttp.ErrServerClosed) {
+			logger.Error("Failed to run server", zap.Error(err))
+			stop()
+		}
+	}()
+
+	<-ctx.Done()
+	logger.Info("Shutting down server")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		logger.Error("Failed to shut down server", zap.Error(err))
 	}
 }
@@ -1,14 +1,102 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"os/signal"
+	"sync"
+	"syscall"
 	"time"
+
+	"app/config"
+	"app/internal/adapters/fieldcrypt"
+	"app/internal/adapters/kms"
+	"app/internal/adapters/rtsp"
+	"app/internal/adapters/storage/postgres"
+	"app/internal/core/ports"
+	"app/internal/core/services"
+	"app/pkg/logger"
+
+	"go.uber.org/zap"
 )
 
 func main() {
-	log.Println("Starting worker...")
-	for {
-		log.Println("Worker running...")
-		time.Sleep(5 * time.Second)
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	logger.InitLogger("development")
+	defer logger.Log.Sync()
+	logger.Info("Starting worker...")
+
+	db, err := postgres.NewPostgresDB(cfg.Database)
+	if err != nil {
+		logger.Error("Failed to connect to database", zap.Error(err))
+		return
+	}
+	defer db.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Camera credentials are encrypted with the same data keys as the API
+	fieldCipher, err := newFieldCipher(cfg, db)
+	if err != nil {
+		logger.Error("Failed to set up field encryption", zap.Error(err))
+		return
+	}
+
+	cameraHealthRepo := postgres.NewCameraHealthRepository(db, fieldCipher)
+	cameraRepo := postgres.NewCameraRepository(db, fieldCipher)
+	maintenanceRepo := postgres.NewMaintenanceRepository(db)
+
+	// Maintenance scheduler
+	maintenanceService := services.NewMaintenanceService(
+		maintenanceRepo,
+		cameraHealthRepo,
+		cameraRepo,
+		postgres.NewZoneRepository(db),
+		services.NewAuditService(postgres.NewAuditRepository(db)),
+		cfg.Maintenance,
+	)
+
+	// Camera health monitor
+	cameraHealthService := services.NewCameraHealthService(
+		cameraHealthRepo,
+		cameraRepo,
+		maintenanceRepo,
+		rtsp.NewProber(time.Duration(cfg.CameraHealth.TimeoutSeconds)*time.Second),
+		postgres.NewAIRepository(db),
+		cfg.CameraHealth,
+	)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		maintenanceService.Run(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		cameraHealthService.Run(ctx)
+	}()
+	wg.Wait()
+
+	logger.Info("Worker stopped")
+}
+
+func newFieldCipher(cfg *config.Config, db *postgres.PostgresDB) (ports.FieldCipher, error) {
+	if !cfg.Encryption.Enabled {
+		return fieldcrypt.NewPlainCipher(), nil
+	}
+	if cfg.Encryption.KMS != config.KMSProviderLocal {
+		return nil, fmt.Errorf("unsupported KMS provider %q", cfg.Encryption.KMS)
+	}
+	keyManager, err := kms.NewLocalKeyManager(cfg.Encryption)
+	if err != nil {
+		return nil, err
 	}
+	return fieldcrypt.NewEnvelopeCipher(context.Background(), cfg.Encryption.Tenant, keyManager, postgres.NewDataKeyRepository(db))
 }
@@ -12,6 +12,18 @@ type Config struct {
 	Database DatabaseConfig `mapstructure:"database"`
 	Redis    RedisConfig    `mapstructure:"redis"`
 	Kafka    KafkaConfig    `mapstructure:"kafka"`
+
+	FaceQuality  FaceQualityConfig  `mapstructure:"face_quality"`
+	FaceSearch   FaceSearchConfig   `mapstructure:"face_search"`
+	Encryption   EncryptionConfig   `mapstructure:"encryption"`
+	PIIMasking   PIIMaskingConfig   `mapstructure:"pii_masking"`
+	Duplicates   DuplicatesConfig   `mapstructure:"duplicates"`
+	Strangers    StrangersConfig    `mapstructure:"strangers"`
+	Attendance   AttendanceConfig   `mapstructure:"attendance"`
+	Contacts     ContactsConfig     `mapstructure:"contacts"`
+	CameraHealth CameraHealthConfig `mapstructure:"camera_health"`
+	Maintenance  MaintenanceConfig  `mapstructure:"maintenance"`
+	Discovery    DiscoveryConfig    `mapstructure:"discovery"`
 }
 
 type ServerConfig struct {
@@ -37,6 +49,127 @@ type KafkaConfig struct {
 	Brokers []string `mapstructure:"brokers"`
 }
 
+const (
+	FaceQualityModeReject = "reject"
+	FaceQualityModeFlag   = "flag"
+)
+
+// FaceQualityConfig holds the thresholds applied to face images on enrollment.
+// Mode is either "reject" (refuse the face) or "flag" (store it but mark it).
+type FaceQualityConfig struct {
+	Mode           string  `mapstructure:"mode"`
+	MinSharpness   float64 `mapstructure:"min_sharpness"` // Laplacian variance
+	MinBrightness  float64 `mapstructure:"min_brightness"`
+	MaxBrightness  float64 `mapstructure:"max_brightness"`
+	MinContrast    float64 `mapstructure:"min_contrast"`
+	MinWidth       int     `mapstructure:"min_width"`
+	MinHeight      int     `mapstructure:"min_height"`
+	MinAspectRatio float64 `mapstructure:"min_aspect_ratio"` // width / height
+	MaxAspectRatio float64 `mapstructure:"max_aspect_ratio"`
+}
+
+const (
+	FaceSearchBackendAuto     = "auto"
+	FaceSearchBackendMemory   = "memory"
+	FaceSearchBackendPgVector = "pgvector"
+)
+
+// FaceSearchConfig configures embedding storage and similarity search.
+// Backend "auto" uses pgvector when the extension is installed and falls
+// back to the in-process index otherwise.
+type FaceSearchConfig struct {
+	Backend      string  `mapstructure:"backend"`
+	Dimension    int     `mapstructure:"dimension"`
+	TopK         int     `mapstructure:"top_k"`
+	MinScore     float64 `mapstructure:"min_score"`
+	EmbeddingURL string  `mapstructure:"embedding_url"` // Optional image -> embedding inference endpoint
+}
+
+const KMSProviderLocal = "local"
+
+// EncryptionConfig enables envelope encryption of PII columns. A data key is
+// generated per tenant, wrapped by the KMS master key and kept in the
+// database. The local KMS reads its master keys from MasterKeyFile, or uses
+// MasterKey (base64, 32 bytes) when no file is configured.
+type EncryptionConfig struct {
+	Enabled       bool   `mapstructure:"enabled"`
+	Tenant        string `mapstructure:"tenant"`
+	KMS           string `mapstructure:"kms"`
+	MasterKeyFile string `mapstructure:"master_key_file"`
+	MasterKey     string `mapstructure:"master_key"`
+}
+
+// PIIMaskingConfig controls how identity PII is redacted for callers without
+// the identities:pii:read permission. RevealLast keeps the trailing characters
+// of phone and ID numbers so records can still be told apart.
+type PIIMaskingConfig struct {
+	RevealLast   int      `mapstructure:"reveal_last"`
+	MetadataKeys []string `mapstructure:"metadata_keys"`
+}
+
+// DuplicatesConfig tunes the duplicate identity scan. A pair is reported when
+// its combined score reaches MinScore; face matches below FaceMinScore are
+// ignored.
+type DuplicatesConfig struct {
+	MinScore     float64 `mapstructure:"min_score"`
+	FaceMinScore float64 `mapstructure:"face_min_score"`
+}
+
+// StrangersConfig tunes stranger clustering. A stranger joins the closest
+// open cluster whose centroid similarity reaches MinScore. Promotion enrolls
+// up to PromoteFaces crops as faces of the new identity.
+type StrangersConfig struct {
+	MinScore     float64 `mapstructure:"min_score"`
+	LookbackDays int     `mapstructure:"lookback_days"`
+	BatchSize    int     `mapstructure:"batch_size"`
+	PromoteFaces int     `mapstructure:"promote_faces"`
+}
+
+// AttendanceConfig holds the rules used when a day's attendance is rebuilt
+// from recognition logs. WorkStart and WorkEnd are "15:04" in Timezone.
+type AttendanceConfig struct {
+	Timezone         string `mapstructure:"timezone"`
+	WorkStart        string `mapstructure:"work_start"`
+	WorkEnd          string `mapstructure:"work_end"`
+	LateGraceMinutes int    `mapstructure:"late_grace_minutes"`
+}
+
+// ContactsConfig holds contact tracing defaults. Two people count as in
+// contact when seen in the same zone at most WindowMinutes apart.
+// MaxSightings caps the rows loaded for one trace.
+type ContactsConfig struct {
+	WindowMinutes int `mapstructure:"window_minutes"`
+	LookbackDays  int `mapstructure:"lookback_days"`
+	MaxSightings  int `mapstructure:"max_sightings"`
+}
+
+// CameraHealthConfig drives the worker's camera monitor. A camera goes
+// offline after FailureThreshold failed probes in a row and back online after
+// RecoveryThreshold successful ones, so a single dropped probe does not flap
+// its status.
+type CameraHealthConfig struct {
+	IntervalSeconds   int `mapstructure:"interval_seconds"`
+	TimeoutSeconds    int `mapstructure:"timeout_seconds"`
+	FailureThreshold  int `mapstructure:"failure_threshold"`
+	RecoveryThreshold int `mapstructure:"recovery_threshold"`
+	Concurrency       int `mapstructure:"concurrency"`
+}
+
+// MaintenanceConfig sets how often the worker starts and ends scheduled
+// maintenance windows.
+type MaintenanceConfig struct {
+	IntervalSeconds int `mapstructure:"interval_seconds"`
+}
+
+// DiscoveryConfig tunes ONVIF discovery. ProbeSeconds is how long replies to
+// a WS-Discovery probe are collected; MulticastAddress can point at a
+// simulated responder.
+type DiscoveryConfig struct {
+	ProbeSeconds          int    `mapstructure:"probe_seconds"`
+	RequestTimeoutSeconds int    `mapstructure:"request_timeout_seconds"`
+	MulticastAddress      string `mapstructure:"multicast_address"`
+}
+
 func LoadConfig() (*Config, error) {
 	viper.SetConfigName("config") // name of config file (without extension)
 	viper.SetConfigType("yaml")   // REQUIRED if the config file does not have the extension in the name
@@ -24,8 +24,38 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/ai-configs": {
+        "/ai-config-templates": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ai-templates"
+                ],
+                "summary": "List AI config templates",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.AIConfigTemplate"
+                            }
+                        }
+                    }
+                }
+            },
             "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "consumes": [
                     "application/json"
                 ],
@@ -33,47 +63,61 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "ai"
+                    "ai-templates"
                 ],
-                "summary": "Update AI configuration",
+                "summary": "Create an AI config template",
                 "parameters": [
                     {
-                        "description": "AI Config Info",
-                        "name": "request",
+                        "description": "Template",
+                        "name": "template",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/domain.AIConfig"
+                            "$ref": "#/definitions/ports.AITemplateRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/domain.AIConfig"
+                            "$ref": "#/definitions/domain.AIConfigTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.AIConfigErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/ai-configs/camera/{cameraId}": {
+        "/ai-config-templates/{id}": {
             "get": {
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "ai"
+                    "ai-templates"
                 ],
-                "summary": "Get AI configuration for a camera",
+                "summary": "Get an AI config template",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Camera ID",
-                        "name": "cameraId",
+                        "description": "Template ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -82,14 +126,24 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.AIConfig"
+                            "$ref": "#/definitions/domain.AIConfigTemplate"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/attendance/records": {
-            "get": {
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cameras the template was applied to are not changed; they show as drifting until it is applied again.",
                 "consumes": [
                     "application/json"
                 ],
@@ -97,69 +151,95 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "analytics"
+                    "ai-templates"
                 ],
-                "summary": "List attendance records",
+                "summary": "Update an AI config template",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Identity ID",
-                        "name": "identity_id",
-                        "in": "query"
+                        "description": "Template ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Status (late, on_time, absent, early_leave)",
-                        "name": "status",
-                        "in": "query"
+                        "description": "Template",
+                        "name": "template",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ports.AITemplateRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/http.AttendanceRecordResponse"
+                            "$ref": "#/definitions/domain.AIConfigTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.AIConfigErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/attendance/summary": {
-            "get": {
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Linked cameras keep their settings and are unlinked.",
                 "tags": [
-                    "analytics"
+                    "ai-templates"
                 ],
-                "summary": "Get daily attendance summary",
+                "summary": "Delete an AI config template",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Date (YYYY-MM-DD)",
-                        "name": "date",
-                        "in": "query"
+                        "description": "Template ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "integer",
-                                "format": "int64"
-                            }
+                            "$ref": "#/definitions/http.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/audit-logs": {
-            "get": {
+        "/ai-config-templates/{id}/apply": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Applies the template to the listed cameras and every camera in the zone's subtree, with per-camera overrides keyed by camera ID. Each changed camera gets a new config version; invalid results are reported per camera.",
                 "consumes": [
                     "application/json"
                 ],
@@ -167,91 +247,95 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "audit"
+                    "ai-templates"
                 ],
-                "summary": "List audit logs",
+                "summary": "Apply an AI config template to cameras",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "user_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Action",
-                        "name": "action",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Table Name",
-                        "name": "table",
-                        "in": "query"
+                        "description": "Template ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Limit",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "Targets and overrides",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ports.ApplyAITemplateRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/http.AuditLogResponse"
+                            "$ref": "#/definitions/domain.AITemplateApplyResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/auth/login": {
-            "post": {
-                "description": "Login with username and password to get access token",
-                "consumes": [
-                    "application/json"
+        "/ai-config-templates/{id}/drift": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Lists every camera linked to the template, drifted ones first, with the fields where its config differs from the template plus its overrides.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "ai-templates"
                 ],
-                "summary": "Login to system",
+                "summary": "Show cameras drifting from an AI config template",
                 "parameters": [
                     {
-                        "description": "Login Credentials",
-                        "name": "credentials",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/domain.LoginRequest"
-                        }
+                        "type": "string",
+                        "description": "Template ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.LoginResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.AIConfigDrift"
+                            }
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/http.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/auth/register": {
+        "/ai-configs": {
             "post": {
-                "description": "Register a new user account",
+                "description": "ROI polygons use normalized 0-1 frame coordinates and must not intersect themselves; active hours windows must not overlap.",
                 "consumes": [
                     "application/json"
                 ],
@@ -259,87 +343,75 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "ai"
                 ],
-                "summary": "Register a new user",
+                "summary": "Update AI configuration",
                 "parameters": [
                     {
-                        "description": "User Info",
-                        "name": "user",
+                        "description": "AI Config Info",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/domain.RegisterRequest"
+                            "$ref": "#/definitions/domain.AIConfig"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.User"
+                            "$ref": "#/definitions/domain.AIConfig"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/http.AIConfigErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/cameras": {
+        "/ai-configs/camera/{cameraId}": {
             "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "List cameras, optionally filtered by zone_id",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "cameras"
+                    "ai"
                 ],
-                "summary": "List cameras",
+                "summary": "Get AI configuration for a camera",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Filter by Zone ID",
-                        "name": "zone_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Search query",
-                        "name": "q",
-                        "in": "query"
+                        "description": "Camera ID",
+                        "name": "cameraId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/domain.Camera"
-                            }
+                            "$ref": "#/definitions/domain.AIConfig"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/ai-configs/camera/{cameraId}/rollback": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new camera in the system",
+                "description": "Saves the version's settings and template link as a new version; history is never rewritten.",
                 "consumes": [
                     "application/json"
                 ],
@@ -347,76 +419,63 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "cameras"
+                    "ai"
                 ],
-                "summary": "Create a new camera",
+                "summary": "Roll a camera's AI config back to a version",
                 "parameters": [
                     {
-                        "description": "Camera Info",
-                        "name": "camera",
+                        "type": "string",
+                        "description": "Camera ID",
+                        "name": "cameraId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Version to restore",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/domain.CreateCameraRequest"
+                            "$ref": "#/definitions/ports.RollbackAIConfigRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/domain.Camera"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/domain.AIConfig"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/http.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/cameras/{id}": {
-            "get": {
+        "/ai-configs/camera/{cameraId}/template": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
+                "description": "Keeps the camera's current settings and drops its overrides.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "cameras"
+                    "ai"
                 ],
-                "summary": "Get camera by ID",
+                "summary": "Unlink a camera's AI config from its template",
                 "parameters": [
                     {
                         "type": "string",
                         "description": "Camera ID",
-                        "name": "id",
+                        "name": "cameraId",
                         "in": "path",
                         "required": true
                     }
@@ -425,87 +484,81 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.Camera"
+                            "$ref": "#/definitions/domain.AIConfig"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/http.ErrorResponse"
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/ai-configs/camera/{cameraId}/versions": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Newest first. Every save, template apply and rollback adds a version.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "cameras"
+                    "ai"
                 ],
-                "summary": "Update camera",
+                "summary": "List a camera's AI config versions",
                 "parameters": [
                     {
                         "type": "string",
                         "description": "Camera ID",
-                        "name": "id",
+                        "name": "cameraId",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Update Info",
-                        "name": "camera",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/domain.UpdateCameraRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.Camera"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.AIConfigVersion"
                             }
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/ai-configs/camera/{cameraId}/versions/{version}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "cameras"
+                    "ai"
                 ],
-                "summary": "Delete camera",
+                "summary": "Get one AI config version of a camera",
                 "parameters": [
                     {
                         "type": "string",
                         "description": "Camera ID",
-                        "name": "id",
+                        "name": "cameraId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Version",
+                        "name": "version",
                         "in": "path",
                         "required": true
                     }
@@ -514,16 +567,19 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/domain.AIConfigVersion"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/events": {
+        "/attendance/records": {
             "get": {
                 "consumes": [
                     "application/json"
@@ -532,53 +588,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "ai"
+                    "analytics"
                 ],
-                "summary": "List AI events",
+                "summary": "List attendance records",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Camera ID",
-                        "name": "camera_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Event Type",
-                        "name": "event_type",
+                        "description": "Identity ID",
+                        "name": "identity_id",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Status",
+                        "description": "Status (late, on_time, absent, early_leave)",
                         "name": "status",
                         "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "From Date",
-                        "name": "from_date",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "To Date",
-                        "name": "to_date",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/http.PaginatedResponse"
+                            "$ref": "#/definitions/http.AttendanceRecordResponse"
                         }
                     }
                 }
             }
         },
-        "/events/{id}": {
-            "patch": {
+        "/attendance/summary": {
+            "get": {
                 "consumes": [
                     "application/json"
                 ],
@@ -586,38 +624,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "ai"
+                    "analytics"
                 ],
-                "summary": "Update AI event status",
+                "summary": "Get daily attendance summary",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Event ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Status object",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "object"
-                        }
+                        "description": "Date (YYYY-MM-DD)",
+                        "name": "date",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.AIEvent"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "integer"
+                            }
                         }
                     }
                 }
             }
         },
-        "/identities": {
+        "/audit-logs": {
             "get": {
                 "consumes": [
                     "application/json"
@@ -626,26 +657,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "identities"
+                    "audit"
                 ],
-                "summary": "List identities",
+                "summary": "List audit logs",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Page number",
-                        "name": "page",
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
                         "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "description": "Limit",
-                        "name": "limit",
+                        "type": "string",
+                        "description": "Action",
+                        "name": "action",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Search query",
-                        "name": "q",
+                        "description": "Table Name",
+                        "name": "table",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
                         "in": "query"
                     }
                 ],
@@ -653,12 +690,15 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/http.PaginatedResponse"
+                            "$ref": "#/definitions/http.AuditLogResponse"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/auth/login": {
             "post": {
+                "description": "Login with username and password to get access token",
                 "consumes": [
                     "application/json"
                 ],
@@ -666,32 +706,42 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "identities"
+                    "auth"
                 ],
-                "summary": "Create a new identity",
+                "summary": "Login to system",
                 "parameters": [
                     {
-                        "description": "Identity Info",
-                        "name": "request",
+                        "description": "Login Credentials",
+                        "name": "credentials",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/ports.CreateIdentityRequest"
+                            "$ref": "#/definitions/domain.LoginRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.Identity"
+                            "$ref": "#/definitions/domain.LoginResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/identities/enroll-face": {
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/register": {
             "post": {
+                "description": "Register a new user account",
                 "consumes": [
                     "application/json"
                 ],
@@ -699,83 +749,87 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "identities"
+                    "auth"
                 ],
-                "summary": "Enroll a new face for an identity",
+                "summary": "Register a new user",
                 "parameters": [
                     {
-                        "description": "Face Info",
-                        "name": "request",
+                        "description": "User Info",
+                        "name": "user",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/domain.RegisterRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/domain.IdentityFace"
+                            "$ref": "#/definitions/domain.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
             }
         },
-        "/identities/faces/{face_id}": {
-            "delete": {
-                "tags": [
-                    "identities"
-                ],
-                "summary": "Delete a face",
-                "parameters": [
+        "/cameras": {
+            "get": {
+                "security": [
                     {
-                        "type": "string",
-                        "description": "Face ID",
-                        "name": "face_id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "204": {
-                        "description": "No Content"
+                        "BearerAuth": []
                     }
-                }
-            }
-        },
-        "/identities/{id}": {
-            "get": {
-                "consumes": [
-                    "application/json"
                 ],
+                "description": "List the cameras the caller may see, optionally filtered by zone_id. A zone filter includes the zones below it.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "identities"
+                    "cameras"
                 ],
-                "summary": "Get an identity by ID",
+                "summary": "List cameras",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Identity ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Filter by Zone ID",
+                        "name": "zone_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search query",
+                        "name": "q",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.Identity"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Camera"
+                            }
                         }
                     }
                 }
             },
-            "put": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new camera in the system. Credentials embedded in rtsp_url are stored encrypted and removed from the URL.",
                 "consumes": [
                     "application/json"
                 ],
@@ -783,59 +837,65 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "identities"
+                    "cameras"
                 ],
-                "summary": "Update an identity",
+                "summary": "Create a new camera",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Identity ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Identity Update Info",
-                        "name": "request",
+                        "description": "Camera Info",
+                        "name": "camera",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/ports.UpdateIdentityRequest"
+                            "$ref": "#/definitions/domain.CreateCameraRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/domain.Identity"
+                            "$ref": "#/definitions/domain.Camera"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
-            },
-            "delete": {
-                "tags": [
-                    "identities"
-                ],
-                "summary": "Delete an identity",
-                "parameters": [
+            }
+        },
+        "/cameras/discovery": {
+            "post": {
+                "security": [
                     {
-                        "type": "string",
-                        "description": "Identity ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "BearerAuth": []
                     }
                 ],
-                "responses": {
-                    "204": {
-                        "description": "No Content"
-                    }
-                }
-            }
-        },
-        "/identities/{id}/status": {
-            "patch": {
+                "description": "Starts a background job that sends a WS-Discovery probe, then reads device information and media profiles, with proposed RTSP URLs, from each device that answers. Poll GET /jobs/{id} and list the devices once it completes. The credentials are used to query devices and are not stored.",
                 "consumes": [
                     "application/json"
                 ],
@@ -843,94 +903,105 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "identities"
+                    "cameras"
                 ],
-                "summary": "Update identity status",
+                "summary": "Discover ONVIF cameras on the local network",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Identity ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Status object",
+                        "description": "Device credentials",
                         "name": "request",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/ports.DiscoveryRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "$ref": "#/definitions/domain.Identity"
+                            "$ref": "#/definitions/domain.Job"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/media/upload": {
+        "/cameras/discovery/adopt": {
             "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates one camera per device from the chosen profile's stream URI, all in the given zone. Devices that cannot be adopted are listed in errors; the rest are still created.",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "media"
+                    "cameras"
                 ],
-                "summary": "Upload an image",
+                "summary": "Adopt discovered devices as cameras",
                 "parameters": [
                     {
-                        "type": "file",
-                        "description": "Image file",
-                        "name": "file",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Subfolder name",
-                        "name": "folder",
-                        "in": "query"
+                        "description": "Devices to adopt",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ports.AdoptDevicesRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/ports.AdoptDevicesResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/permissions/{userId}": {
+        "/cameras/discovery/{job_id}/devices": {
             "get": {
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "permissions"
+                    "cameras"
                 ],
-                "summary": "Get user permissions",
+                "summary": "List devices found by a discovery job",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "userId",
+                        "description": "Discovery job ID",
+                        "name": "job_id",
                         "in": "path",
                         "required": true
                     }
@@ -939,51 +1010,68 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/ports.UserPermissions"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.DiscoveredDevice"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/permissions/{userId}/cameras": {
-            "post": {
-                "consumes": [
-                    "application/json"
+        "/cameras/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "permissions"
+                    "cameras"
                 ],
-                "summary": "Update user camera permissions",
+                "summary": "Get camera by ID",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "userId",
+                        "description": "Camera ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Camera IDs array",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "object"
-                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Camera"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
-            }
-        },
-        "/permissions/{userId}/zones": {
-            "post": {
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "consumes": [
                     "application/json"
                 ],
@@ -991,174 +1079,146 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "permissions"
+                    "cameras"
                 ],
-                "summary": "Update user zone permissions",
+                "summary": "Update camera",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "userId",
+                        "description": "Camera ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Zone IDs array",
-                        "name": "request",
+                        "description": "Update Info",
+                        "name": "camera",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/domain.UpdateCameraRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Camera"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
-            }
-        },
-        "/recognition/logs": {
-            "get": {
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
                 "tags": [
-                    "analytics"
+                    "cameras"
                 ],
-                "summary": "List recognition logs",
+                "summary": "Delete camera",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Identity ID",
-                        "name": "identity_id",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
                         "description": "Camera ID",
-                        "name": "camera_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "From Date (RFC3339)",
-                        "name": "from",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "To Date (RFC3339)",
-                        "name": "to",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Limit",
-                        "name": "limit",
-                        "in": "query"
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/http.RecognitionLogResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
             }
         },
-        "/roles": {
-            "get": {
+        "/cameras/{id}/credentials/reveal": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the stored RTSP username and password and the full stream URL. Requires the cameras:credentials:reveal permission; every reveal is audited.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "roles"
+                    "cameras"
                 ],
-                "summary": "List all roles",
+                "summary": "Reveal camera credentials",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Search query",
-                        "name": "q",
-                        "in": "query"
+                        "description": "Camera ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/domain.Role"
-                            }
+                            "$ref": "#/definitions/domain.CameraCredentials"
                         }
-                    }
-                }
-            },
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "roles"
-                ],
-                "summary": "Create a new role",
-                "parameters": [
-                    {
-                        "description": "Role Info",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/domain.Role"
+                            "$ref": "#/definitions/http.ErrorResponse"
                         }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "Created",
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/domain.Role"
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/roles/{id}": {
-            "get": {
-                "tags": [
-                    "roles"
-                ],
-                "summary": "Get role by ID",
-                "parameters": [
+        "/cameras/{id}/placement": {
+            "put": {
+                "security": [
                     {
-                        "type": "string",
-                        "description": "Role ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "BearerAuth": []
                     }
                 ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/domain.Role"
-                        }
-                    }
-                }
-            },
-            "put": {
+                "description": "Sets the camera's position, heading (degrees clockwise from plan up), field of view and range on the plan of its zone or a zone above it.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1166,24 +1226,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "roles"
+                    "floor-plans"
                 ],
-                "summary": "Update a role",
+                "summary": "Place a camera on a floor plan",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Role ID",
+                        "description": "Camera ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Role Info",
-                        "name": "request",
+                        "description": "Placement in plan coordinates",
+                        "name": "placement",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/domain.Role"
+                            "$ref": "#/definitions/ports.CameraPlacementRequest"
                         }
                     }
                 ],
@@ -1191,20 +1251,37 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.Role"
+                            "$ref": "#/definitions/domain.CameraPlacement"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
                         }
                     }
                 }
             },
             "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "tags": [
-                    "roles"
+                    "floor-plans"
                 ],
-                "summary": "Delete a role",
+                "summary": "Remove a camera from its floor plan",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Role ID",
+                        "description": "Camera ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1213,46 +1290,61 @@ const docTemplate = `{
                 "responses": {
                     "204": {
                         "description": "No Content"
-                    }
-                }
-            }
-        },
-        "/stats/dashboard": {
-            "get": {
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "ai"
-                ],
-                "summary": "Get dashboard statistics",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/domain.DashboardStats"
+                            "$ref": "#/definitions/http.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/users": {
+        "/cameras/{id}/status-history": {
             "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Changes made by the health monitor and by hand, newest first",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "cameras"
                 ],
-                "summary": "List all users",
+                "summary": "List a camera's status transitions",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Search query",
-                        "name": "q",
+                        "description": "Camera ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "From (RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "To (RFC3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
                         "in": "query"
                     }
                 ],
@@ -1260,15 +1352,47 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/domain.User"
-                            }
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/http.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/domain.CameraStatusChange"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/datasets/export": {
             "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Builds a ZIP of snapshots with COCO annotations and/or YOLO labels in the background, split into train and val. AI events need a verdict; recognitions need a review. False positives become images without boxes. The archive URL is set as the job's result_url.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1276,41 +1400,38 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "datasets"
                 ],
-                "summary": "Create a new user",
+                "summary": "Export reviewed detections as a training dataset",
                 "parameters": [
                     {
-                        "description": "User Info",
+                        "description": "Filters, format and split",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/domain.CreateWebUserRequest"
+                            "$ref": "#/definitions/ports.DatasetExportRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "$ref": "#/definitions/domain.User"
+                            "$ref": "#/definitions/domain.Job"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/http.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/users/{id}": {
-            "put": {
+        "/events": {
+            "get": {
                 "consumes": [
                     "application/json"
                 ],
@@ -1318,115 +1439,190 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "ai"
                 ],
-                "summary": "Update a user",
+                "summary": "List AI events",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Camera ID",
+                        "name": "camera_id",
+                        "in": "query"
                     },
                     {
-                        "description": "User Info",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/domain.UpdateWebUserRequest"
-                        }
+                        "type": "string",
+                        "description": "Event Type",
+                        "name": "event_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "From Date",
+                        "name": "from_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "To Date",
+                        "name": "to_date",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.User"
+                            "$ref": "#/definitions/http.PaginatedResponse"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/events/{id}": {
+            "patch": {
+                "description": "Sets the status and/or the true_positive / false_positive verdict used for training exports",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "users"
+                    "ai"
                 ],
-                "summary": "Delete a user",
+                "summary": "Update AI event status",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
+                        "description": "Event ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Status and verdict",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ports.UpdateEventRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.AIEvent"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/users/{user_id}/reset-password": {
+        "/faces/search": {
             "post": {
+                "description": "Accepts a JSON body with an embedding, or a multipart form with an \"image\" file when an embedding provider is configured",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "identities"
                 ],
-                "summary": "Reset user password",
+                "summary": "Search identities by face similarity",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "User ID",
-                        "name": "user_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "New Password",
+                        "description": "Embedding search",
                         "name": "request",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/ports.FaceSearchRequest"
                         }
+                    },
+                    {
+                        "type": "file",
+                        "description": "Face image",
+                        "name": "image",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of identities to return",
+                        "name": "top_k",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.FaceMatch"
                             }
                         }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "501": {
+                        "description": "Not Implemented",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/zones": {
+        "/identities": {
             "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
+                "consumes": [
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "zones"
+                    "identities"
                 ],
-                "summary": "List all zones",
+                "summary": "List identities",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "description": "Search query",
@@ -1438,20 +1634,12 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/domain.Zone"
-                            }
+                            "$ref": "#/definitions/http.PaginatedResponse"
                         }
                     }
                 }
             },
             "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
                 "consumes": [
                     "application/json"
                 ],
@@ -1459,17 +1647,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "zones"
+                    "identities"
                 ],
-                "summary": "Create a new zone",
+                "summary": "Create a new identity",
                 "parameters": [
                     {
-                        "description": "Zone Info",
-                        "name": "zone",
+                        "description": "Identity Info",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/domain.CreateZoneRequest"
+                            "$ref": "#/definitions/ports.CreateIdentityRequest"
                         }
                     }
                 ],
@@ -1477,22 +1665,13 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/domain.Zone"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/domain.Identity"
                         }
                     }
                 }
             }
         },
-        "/zones/{id}": {
+        "/identities/duplicates": {
             "get": {
                 "security": [
                     {
@@ -1503,67 +1682,145 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "zones"
+                    "identities"
                 ],
-                "summary": "Get a zone by ID",
+                "summary": "List duplicate identity candidates",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Zone ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "open (default), dismissed or merged",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only pairs involving this identity",
+                        "name": "identity_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.Zone"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/http.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/domain.DuplicateCandidate"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/identities/duplicates/scan": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Compares identities by CCCD, phone, face similarity and normalized name in the background. Open candidates are replaced by the result; dismissed pairs stay dismissed.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "zones"
+                    "identities"
                 ],
-                "summary": "Update a zone",
+                "summary": "Scan for duplicate identities",
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Job"
+                        }
+                    }
+                }
+            }
+        },
+        "/identities/duplicates/{candidate_id}/dismiss": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Marks the pair as not the same person so later scans do not report it again",
+                "tags": [
+                    "identities"
+                ],
+                "summary": "Dismiss a duplicate candidate",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Zone ID",
-                        "name": "id",
+                        "description": "Candidate ID",
+                        "name": "candidate_id",
                         "in": "path",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/identities/enroll-face": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "identities"
+                ],
+                "summary": "Enroll a new face for an identity",
+                "parameters": [
                     {
-                        "description": "Update Info",
-                        "name": "zone",
+                        "description": "Face Info",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/domain.UpdateZoneRequest"
+                            "$ref": "#/definitions/ports.EnrollFaceRequest"
                         }
                     }
                 ],
@@ -1571,35 +1828,120 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.Zone"
+                            "$ref": "#/definitions/domain.IdentityFace"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/identities/faces/{face_id}": {
             "delete": {
+                "tags": [
+                    "identities"
+                ],
+                "summary": "Delete a face",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Face ID",
+                        "name": "face_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/identities/import": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
+                "description": "Imports identities from a CSV or XLSX sheet with an optional ZIP of face photos named by code (CODE.jpg, CODE_2.jpg). Runs in the background; poll the returned job for progress.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "zones"
+                    "identities"
                 ],
-                "summary": "Delete a zone",
+                "summary": "Bulk import identities",
                 "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV or XLSX sheet",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "ZIP of face photos",
+                        "name": "photos",
+                        "in": "formData"
+                    },
                     {
                         "type": "string",
-                        "description": "Zone ID",
-                        "name": "id",
+                        "description": "all_or_nothing (default) or skip_invalid",
+                        "name": "mode",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Job"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/identities/import/{job_id}/report": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "identities"
+                ],
+                "summary": "Download the rejected rows of an identity import",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Import job ID",
+                        "name": "job_id",
                         "in": "path",
                         "required": true
                     }
@@ -1608,784 +1950,6421 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "type": "file"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "domain.AIConfig": {
-            "type": "object",
-            "properties": {
-                "active_hours": {
-                    "description": "JSONB"
-                },
-                "ai_enabled": {
-                    "type": "boolean"
-                },
-                "ai_types": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/domain.EventType"
-                    }
-                },
-                "camera_id": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "min_confidence": {
-                    "type": "integer"
-                },
-                "roi_zones": {
-                    "description": "JSONB"
-                },
-                "sensitivity": {
-                    "type": "integer"
-                },
-                "updated_at": {
-                    "type": "string"
-                }
-            }
         },
-        "domain.AIEvent": {
-            "type": "object",
-            "properties": {
-                "camera_id": {
-                    "type": "string"
-                },
-                "confidence": {
-                    "type": "number"
-                },
-                "created_at": {
-                    "type": "string"
+        "/identities/pending": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "identities"
+                ],
+                "summary": "List identities waiting for review",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.PaginatedResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/identities/{id}": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "identities"
+                ],
+                "summary": "Get an identity by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Identity ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Return the identity as it was at this time (RFC3339)",
+                        "name": "as_of",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Identity"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "identities"
+                ],
+                "summary": "Update an identity",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Identity ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Identity Update Info",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ports.UpdateIdentityRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Identity"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Soft delete; faces and history are kept. Use /identities/{id}/erase for a data subject erasure request.",
+                "tags": [
+                    "identities"
+                ],
+                "summary": "Delete an identity",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Identity ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/identities/{id}/access-report": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the users who viewed an identity, its faces, recognition or attendance records, or exported it, with a per-user summary. Reads are written asynchronously, so the last few seconds may be missing. Requires the identities:pii:read permission.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "audit"
+                ],
+                "summary": "Who accessed an identity",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Identity ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "From Date (RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "To Date (RFC3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only entries by this user",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.IdentityAccessReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/identities/{id}/approve": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "identities"
+                ],
+                "summary": "Approve a pending identity",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Identity ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Approval reason",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.ReviewReasonRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Identity"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/identities/{id}/contacts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Identities seen in the same zone (or on the same camera when it has no zone) within window_minutes of the identity's sightings, ranked by overlap duration then number of encounters. Requires the contacts:trace permission.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "identities"
+                ],
+                "summary": "List who was co-located with an identity",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Identity ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "From (RFC3339), default lookback_days before to",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "To (RFC3339), default now",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Max minutes between two sightings that count as contact",
+                        "name": "window_minutes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Drop contacts with fewer encounters",
+                        "name": "min_encounters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Top contacts to return",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ContactTrace"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/identities/{id}/contacts/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "One row per contact with the same filters and ranking as the JSON trace. Requires the contacts:trace permission.",
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "identities"
+                ],
+                "summary": "Export a contact trace as CSV",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Identity ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "From (RFC3339), default lookback_days before to",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "To (RFC3339), default now",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Max minutes between two sightings that count as contact",
+                        "name": "window_minutes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Drop contacts with fewer encounters",
+                        "name": "min_encounters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Top contacts to return",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/identities/{id}/erase": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes faces, embeddings, media and PII. Attendance and recognition counts are kept against an anonymous record. This cannot be undone. Requires the identities:erase permission.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "identities"
+                ],
+                "summary": "Permanently erase an identity's personal data",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Identity ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason for the erasure request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ports.EraseIdentityRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.IdentityErasure"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/identities/{id}/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "ZIP with the identity record, face images and embeddings, recognition logs, attendance records and related audit entries",
+                "produces": [
+                    "application/zip"
+                ],
+                "tags": [
+                    "identities"
+                ],
+                "summary": "Export all personal data held about an identity",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Identity ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/identities/{id}/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Newest first. Each version holds the full state after the change and the fields that changed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "identities"
+                ],
+                "summary": "List an identity's change history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Identity ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/http.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/domain.IdentityVersion"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/identities/{id}/merge": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Moves faces, recognition logs and attendance records from source_id to this identity, reconciling days both have attendance for, and soft deletes the source.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "identities"
+                ],
+                "summary": "Merge a duplicate identity into this one",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Surviving identity ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Source identity and reason",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ports.MergeIdentitiesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.IdentityMerge"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/identities/{id}/reject": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "identities"
+                ],
+                "summary": "Reject a pending identity",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Identity ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Rejection reason",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.ReviewReasonRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Identity"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/identities/{id}/revert": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Restores name, type, contact, department, metadata, note and face image from the version. Status is not changed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "identities"
+                ],
+                "summary": "Revert an identity to a previous version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Identity ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Version and reason",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ports.RevertIdentityRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Identity"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/identities/{id}/status": {
+            "patch": {
+                "description": "Approves (status \"active\") or rejects (status \"rejected\") a pending identity",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "identities"
+                ],
+                "summary": "Review identity status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Identity ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Decision and reason",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ports.ReviewIdentityRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Identity"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/identities/{id}/timeline": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Recognitions merged into visits: consecutive sightings on one camera at most gap_minutes apart form a dwell interval. Includes snapshots, zone transitions and time spent per zone.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "identities"
+                ],
+                "summary": "Get a person's movements across cameras",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Identity ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "From (RFC3339), default 7 days before to",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "To (RFC3339), default now",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Max minutes between sightings of one visit (default 10)",
+                        "name": "gap_minutes",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.PersonTimeline"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/jobs": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "List background jobs started by the current user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job type, e.g. identity_import",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Job"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/jobs/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Only the user who started the job, or an admin, can see it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Get a background job with its progress",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Job"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/maintenance-windows": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "maintenance"
+                ],
+                "summary": "List maintenance windows",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Windows covering this camera, directly or through its zone or a zone above it",
+                        "name": "camera_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Zone ID",
+                        "name": "zone_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only windows open at this time (RFC3339), or 'now'",
+                        "name": "active_at",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/http.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/domain.MaintenanceWindow"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "For one camera or every camera in a zone and the zones below it. While a window is open its cameras are in maintenance, offline alerts are suppressed and AI events are dropped or tagged per event_policy. Recurring windows repeat daily or weekly in their timezone.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "maintenance"
+                ],
+                "summary": "Schedule a maintenance window",
+                "parameters": [
+                    {
+                        "description": "Window",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ports.MaintenanceWindowRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/domain.MaintenanceWindow"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/maintenance-windows/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "maintenance"
+                ],
+                "summary": "Get a maintenance window",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Window ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.MaintenanceWindow"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Takes effect on the scheduler's next pass; cameras are released if the window no longer covers them.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "maintenance"
+                ],
+                "summary": "Replace a maintenance window",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Window ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Window",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ports.MaintenanceWindowRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.MaintenanceWindow"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cameras it holds in maintenance return to monitoring on the scheduler's next pass.",
+                "tags": [
+                    "maintenance"
+                ],
+                "summary": "Delete a maintenance window",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Window ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/media/upload": {
+            "post": {
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "media"
+                ],
+                "summary": "Upload an image",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Image file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Subfolder name",
+                        "name": "folder",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "List notifications of the current user",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Only unread notifications",
+                        "name": "unread",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Notification"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications/{id}/read": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Mark a notification as read",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Notification ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/permissions/{userId}": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "permissions"
+                ],
+                "summary": "Get user permissions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ports.UserPermissions"
+                        }
+                    }
+                }
+            }
+        },
+        "/permissions/{userId}/cameras": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "permissions"
+                ],
+                "summary": "Update user camera permissions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Camera IDs array",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/permissions/{userId}/zones": {
+            "post": {
+                "description": "A zone grant covers every camera in the zone and in the zones below it. Users with camera or zone grants only see the cameras they cover, unless they hold cameras:all.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "permissions"
+                ],
+                "summary": "Update user zone permissions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Zone IDs array",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/recognition/feedback": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recognition"
+                ],
+                "summary": "List recognition feedback",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "confirm, reject or reassign",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Original or corrected identity",
+                        "name": "identity_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Camera ID",
+                        "name": "camera_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reviewed from (RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reviewed to (RFC3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/http.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/domain.RecognitionFeedback"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/recognition/feedback/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "JSON lines, one per reviewed log with its latest verdict: predicted and corrected identity, crop, embedding and confidence. Requires the recognition:feedback:export permission.",
+                "produces": [
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "recognition"
+                ],
+                "summary": "Export the labeled feedback dataset",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "confirm, reject or reassign",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Original or corrected identity",
+                        "name": "identity_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Camera ID",
+                        "name": "camera_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reviewed from (RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reviewed to (RFC3339)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/recognition/logs": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "analytics"
+                ],
+                "summary": "List recognition logs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Identity ID",
+                        "name": "identity_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Camera ID",
+                        "name": "camera_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only logs of unrecognized faces",
+                        "name": "strangers",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "From Date (RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "To Date (RFC3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.RecognitionLogResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/recognition/logs/{id}/feedback": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Reject turns the log into a stranger log; reassign moves it to identity_id. Both need a reason and rebuild attendance for the affected identities on that day.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recognition"
+                ],
+                "summary": "Confirm, reject or reassign a recognition",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Recognition log ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Verdict",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ports.RecognitionFeedbackRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.RecognitionReview"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/recognition/strangers": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recognition"
+                ],
+                "summary": "List stranger clusters",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "open or promoted",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only clusters seen by this camera",
+                        "name": "camera_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/http.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/domain.StrangerCluster"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/recognition/strangers/cluster": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Groups stranger recognition logs by face embedding into \"Unknown person #N\" clusters in the background. Existing open clusters keep growing.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recognition"
+                ],
+                "summary": "Cluster unrecognized faces",
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Job"
+                        }
+                    }
+                }
+            }
+        },
+        "/recognition/strangers/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recognition"
+                ],
+                "summary": "Get a stranger cluster",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cluster ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.StrangerCluster"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/recognition/strangers/{id}/logs": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recognition"
+                ],
+                "summary": "List the recognition logs of a stranger cluster",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cluster ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/http.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/domain.RecognitionLog"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/recognition/strangers/{id}/promote": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a pending identity, links the cluster's recognition logs to it and enrolls the best crops as faces. Crops failing the quality gate are skipped and reported.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recognition"
+                ],
+                "summary": "Turn a stranger cluster into a pending identity",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cluster ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Identity details; code and name default to the cluster label",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/ports.PromoteStrangerRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/domain.StrangerPromotion"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/uptime": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Uptime percentage, outage count, mean time to recovery and longest outage per camera, per zone and overall. Time in maintenance is excluded from uptime.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Camera availability report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Month (YYYY-MM, UTC), default current month",
+                        "name": "month",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "From (RFC3339), overrides month",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "To (RFC3339), overrides month",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Zone ID, including the zones below it",
+                        "name": "zone_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.UptimeReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/uptime/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "One row per camera, or per zone with group=zone. Same filters as the JSON report.",
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Export the camera availability report as CSV",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Month (YYYY-MM, UTC), default current month",
+                        "name": "month",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "From (RFC3339), overrides month",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "To (RFC3339), overrides month",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Zone ID, including the zones below it",
+                        "name": "zone_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "camera (default) or zone",
+                        "name": "group",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/roles": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "roles"
+                ],
+                "summary": "List all roles",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search query",
+                        "name": "q",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Role"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "roles"
+                ],
+                "summary": "Create a new role",
+                "parameters": [
+                    {
+                        "description": "Role Info",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.Role"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Role"
+                        }
+                    }
+                }
+            }
+        },
+        "/roles/{id}": {
+            "get": {
+                "tags": [
+                    "roles"
+                ],
+                "summary": "Get role by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Role"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "roles"
+                ],
+                "summary": "Update a role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Role Info",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.Role"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Role"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "roles"
+                ],
+                "summary": "Delete a role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/security/keys": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Key metadata only; key material is never returned",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "security"
+                ],
+                "summary": "List field encryption data keys",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.DataKey"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/security/keys/reencrypt": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Encrypts rows written before encryption was enabled and moves rows off retired keys. Requires the security:keys:manage permission.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "security"
+                ],
+                "summary": "Re-encrypt PII under the active data key",
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Job"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/security/keys/rotate": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Activates a new data key and re-encrypts existing PII in the background. Requires the security:keys:manage permission.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "security"
+                ],
+                "summary": "Rotate the data encryption key",
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Job"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/site-manifest": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Downloads the site as YAML (default) or CSV. Camera passwords are left out unless credentials=true, which needs the cameras:credentials:reveal permission and is audited.",
+                "produces": [
+                    "application/yaml",
+                    "text/csv"
+                ],
+                "tags": [
+                    "cameras"
+                ],
+                "summary": "Export zones, cameras and AI configs as a site manifest",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "yaml (default) or csv",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include camera passwords",
+                        "name": "credentials",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/site-manifest/apply": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Makes the site match the manifest. Applying the same manifest again changes nothing. Changes run in plan order and stop at the first failure, which is reported on the failing change with a 422; earlier changes are kept.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cameras"
+                ],
+                "summary": "Apply a site manifest",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "YAML or CSV manifest",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "yaml or csv; taken from the file extension when omitted",
+                        "name": "format",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Delete cameras and zones missing from the manifest",
+                        "name": "prune",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ManifestPlan"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.ManifestErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ManifestPlan"
+                        }
+                    }
+                }
+            }
+        },
+        "/site-manifest/plan": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Matches zones by path, such as \"Building A/Floor 1\", and cameras by name, and lists what applying the manifest would create, update and, with prune, delete. Nothing is changed.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cameras"
+                ],
+                "summary": "Preview the changes a site manifest would make",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "YAML or CSV manifest",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "yaml or csv; taken from the file extension when omitted",
+                        "name": "format",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Delete cameras and zones missing from the manifest",
+                        "name": "prune",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ManifestPlan"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.ManifestErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/dashboard": {
+            "get": {
+                "description": "Counts the cameras and events the caller may see, rolled up over the subtree of zone_id when given.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ai"
+                ],
+                "summary": "Get dashboard statistics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Zone ID",
+                        "name": "zone_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.DashboardStats"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/worst-cameras": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ai"
+                ],
+                "summary": "Dashboard widget: cameras with the lowest uptime",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Look back this many days (default 30)",
+                        "name": "days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Cameras to list (default 5)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.CameraUptime"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "List all users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search query",
+                        "name": "q",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.User"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Create a new user",
+                "parameters": [
+                    {
+                        "description": "User Info",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.CreateWebUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/domain.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User Info",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.UpdateWebUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.User"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "users"
+                ],
+                "summary": "Delete a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/reset-password": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Reset user password",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New Password",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/zones": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "zones"
+                ],
+                "summary": "List all zones",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search query",
+                        "name": "q",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Zone"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a top-level zone, or one nested under parent_id.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "zones"
+                ],
+                "summary": "Create a new zone",
+                "parameters": [
+                    {
+                        "description": "Zone Info",
+                        "name": "zone",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.CreateZoneRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Zone"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/zones/tree": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Every zone nested under its parent, with the number of cameras the caller may see in each zone and in its whole subtree.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "zones"
+                ],
+                "summary": "Get the zone hierarchy",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.ZoneNode"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/zones/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "zones"
+                ],
+                "summary": "Get a zone by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Zone ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Zone"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Setting parent_id moves the zone with its whole subtree; an empty parent_id makes it top-level. A zone cannot be moved under itself or its descendants.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "zones"
+                ],
+                "summary": "Update a zone",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Zone ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update Info",
+                        "name": "zone",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.UpdateZoneRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Zone"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Zones with child zones cannot be deleted.",
+                "tags": [
+                    "zones"
+                ],
+                "summary": "Delete a zone",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Zone ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/zones/{id}/area": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Outlines the zone as a polygon on the plan of a zone above it, by default its parent.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "floor-plans"
+                ],
+                "summary": "Draw a zone on a floor plan",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Zone ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Polygon in plan coordinates",
+                        "name": "area",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ports.ZoneAreaRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ZoneArea"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "tags": [
+                    "floor-plans"
+                ],
+                "summary": "Remove a zone's floor plan area",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Zone ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/zones/{id}/floor-plan": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "floor-plans"
+                ],
+                "summary": "Get a zone's floor plan",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Zone ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.FloorPlan"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sets or replaces the zone's plan image. Plan coordinates are image pixels from the top-left corner; existing placements and areas keep theirs.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "floor-plans"
+                ],
+                "summary": "Upload a zone's floor plan",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Zone ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "PNG, JPEG or GIF image",
+                        "name": "image",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.FloorPlan"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes the plan image along with every placement and area drawn on it.",
+                "tags": [
+                    "floor-plans"
+                ],
+                "summary": "Delete a zone's floor plan",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Zone ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/zones/{id}/map": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the plan's zone areas, cameras and their field-of-view cones as a GeoJSON FeatureCollection in plan coordinates, with AI event counts over the window. Cameras outside the caller's grants are left out.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "floor-plans"
+                ],
+                "summary": "Get a zone's floor plan map",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Zone ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Event count window in minutes (default 60)",
+                        "name": "window_minutes",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.FloorPlanMap"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "domain.AIConfig": {
+            "type": "object",
+            "properties": {
+                "active_hours": {
+                    "$ref": "#/definitions/domain.ActiveHours"
+                },
+                "ai_enabled": {
+                    "type": "boolean"
+                },
+                "ai_types": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.EventType"
+                    }
+                },
+                "camera_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "min_confidence": {
+                    "type": "integer"
+                },
+                "overrides": {
+                    "$ref": "#/definitions/domain.AIConfigOverrides"
+                },
+                "roi_zones": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.ROIZone"
+                    }
+                },
+                "sensitivity": {
+                    "type": "integer"
+                },
+                "template_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.AIConfigDrift": {
+            "type": "object",
+            "properties": {
+                "camera_id": {
+                    "type": "string"
+                },
+                "camera_name": {
+                    "type": "string"
+                },
+                "drifted": {
+                    "type": "boolean"
+                },
+                "fields": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.AIConfigOverrides": {
+            "type": "object",
+            "properties": {
+                "active_hours": {
+                    "$ref": "#/definitions/domain.ActiveHours"
+                },
+                "ai_enabled": {
+                    "type": "boolean"
+                },
+                "ai_types": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.EventType"
+                    }
+                },
+                "min_confidence": {
+                    "type": "integer"
+                },
+                "roi_zones": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.ROIZone"
+                    }
+                },
+                "sensitivity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.AIConfigSource": {
+            "type": "string",
+            "enum": [
+                "manual",
+                "template",
+                "rollback",
+                "manifest"
+            ],
+            "x-enum-varnames": [
+                "AIConfigSourceManual",
+                "AIConfigSourceTemplate",
+                "AIConfigSourceRollback",
+                "AIConfigSourceManifest"
+            ]
+        },
+        "domain.AIConfigTemplate": {
+            "type": "object",
+            "properties": {
+                "active_hours": {
+                    "$ref": "#/definitions/domain.ActiveHours"
+                },
+                "ai_enabled": {
+                    "type": "boolean"
+                },
+                "ai_types": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.EventType"
+                    }
+                },
+                "cameras": {
+                    "description": "Cameras linked to the template",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "min_confidence": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "roi_zones": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.ROIZone"
+                    }
+                },
+                "sensitivity": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.AIConfigVersion": {
+            "type": "object",
+            "properties": {
+                "active_hours": {
+                    "$ref": "#/definitions/domain.ActiveHours"
+                },
+                "ai_enabled": {
+                    "type": "boolean"
+                },
+                "ai_types": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.EventType"
+                    }
+                },
+                "camera_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "min_confidence": {
+                    "type": "integer"
+                },
+                "overrides": {
+                    "$ref": "#/definitions/domain.AIConfigOverrides"
+                },
+                "roi_zones": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.ROIZone"
+                    }
+                },
+                "sensitivity": {
+                    "type": "integer"
+                },
+                "source": {
+                    "$ref": "#/definitions/domain.AIConfigSource"
+                },
+                "template_id": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.AIEvent": {
+            "type": "object",
+            "properties": {
+                "camera_id": {
+                    "type": "string"
+                },
+                "confidence": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "event_type": {
+                    "$ref": "#/definitions/domain.EventType"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "resolved_by": {
+                    "type": "string"
+                },
+                "snapshot_url": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/domain.EventStatus"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "verdict": {
+                    "$ref": "#/definitions/domain.EventVerdict"
+                }
+            }
+        },
+        "domain.AITemplateApplyFailure": {
+            "type": "object",
+            "properties": {
+                "camera_id": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "fields": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.FieldError"
+                    }
+                }
+            }
+        },
+        "domain.AITemplateApplyResult": {
+            "type": "object",
+            "properties": {
+                "applied": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "failed": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.AITemplateApplyFailure"
+                    }
+                },
+                "template_id": {
+                    "type": "string"
+                },
+                "unchanged": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "domain.AccessLog": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "filters": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "identity_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "ip_address": {
+                    "type": "string"
+                },
+                "pii_unmasked": {
+                    "type": "boolean"
+                },
+                "resource_id": {
+                    "type": "string"
+                },
+                "resource_type": {
+                    "type": "string"
+                },
+                "route": {
+                    "type": "string"
+                },
+                "user_agent": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "username": {
+                    "description": "Join fields",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.ActiveHours": {
+            "type": "object",
+            "properties": {
+                "timezone": {
+                    "type": "string"
+                },
+                "windows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.ActiveHoursWindow"
+                    }
+                }
+            }
+        },
+        "domain.ActiveHoursWindow": {
+            "type": "object",
+            "properties": {
+                "days": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "end": {
+                    "type": "string"
+                },
+                "start": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.AttendanceRecord": {
+            "type": "object",
+            "properties": {
+                "check_in": {
+                    "type": "string"
+                },
+                "check_out": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "identity_id": {
+                    "type": "string"
+                },
+                "identity_name": {
+                    "description": "Join fields",
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/domain.AttendanceStatus"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "work_hours": {
+                    "type": "number"
+                }
+            }
+        },
+        "domain.AttendanceStatus": {
+            "type": "string",
+            "enum": [
+                "late",
+                "on_time",
+                "absent",
+                "early_leave"
+            ],
+            "x-enum-varnames": [
+                "AttendanceLate",
+                "AttendanceOnTime",
+                "AttendanceAbsent",
+                "AttendanceEarlyLeave"
+            ]
+        },
+        "domain.AuditLog": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "ip_address": {
+                    "type": "string"
+                },
+                "new_value": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "old_value": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "record_id": {
+                    "type": "string"
+                },
+                "table_name": {
+                    "type": "string"
+                },
+                "user_agent": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "username": {
+                    "description": "Join fields",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Camera": {
+            "type": "object",
+            "properties": {
+                "ai_enabled": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "credentials_unreadable": {
+                    "description": "Set when the stored credentials could not be decrypted; they read as\nempty and are kept as stored until new ones are set",
+                    "type": "boolean"
+                },
+                "has_credentials": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "ip_address": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rtsp_url": {
+                    "description": "Without credentials",
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/domain.CameraStatus"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "zone_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.CameraCredentials": {
+            "type": "object",
+            "properties": {
+                "camera_id": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "stream_url": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.CameraPlacement": {
+            "type": "object",
+            "properties": {
+                "camera_id": {
+                    "type": "string"
+                },
+                "fov": {
+                    "type": "number"
+                },
+                "heading": {
+                    "type": "number"
+                },
+                "plan_zone_id": {
+                    "type": "string"
+                },
+                "range": {
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "x": {
+                    "type": "number"
+                },
+                "y": {
+                    "type": "number"
+                }
+            }
+        },
+        "domain.CameraStatus": {
+            "type": "string",
+            "enum": [
+                "online",
+                "offline",
+                "maintenance"
+            ],
+            "x-enum-varnames": [
+                "CameraStatusOnline",
+                "CameraStatusOffline",
+                "CameraStatusMaintenance"
+            ]
+        },
+        "domain.CameraStatusChange": {
+            "type": "object",
+            "properties": {
+                "camera_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "from_status": {
+                    "$ref": "#/definitions/domain.CameraStatus"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "latency_ms": {
+                    "type": "integer"
+                },
+                "maintenance_window_id": {
+                    "description": "Window behind a scheduled change",
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "source": {
+                    "$ref": "#/definitions/domain.CameraStatusSource"
+                },
+                "to_status": {
+                    "$ref": "#/definitions/domain.CameraStatus"
+                }
+            }
+        },
+        "domain.CameraStatusSource": {
+            "type": "string",
+            "enum": [
+                "monitor",
+                "manual",
+                "schedule"
+            ],
+            "x-enum-varnames": [
+                "CameraStatusSourceMonitor",
+                "CameraStatusSourceManual",
+                "CameraStatusSourceSchedule"
+            ]
+        },
+        "domain.CameraUptime": {
+            "type": "object",
+            "properties": {
+                "camera_id": {
+                    "type": "string"
+                },
+                "camera_name": {
+                    "type": "string"
+                },
+                "longest_outage_seconds": {
+                    "type": "number"
+                },
+                "maintenance_seconds": {
+                    "type": "number"
+                },
+                "mttr_seconds": {
+                    "type": "number"
+                },
+                "offline_seconds": {
+                    "type": "number"
+                },
+                "online_seconds": {
+                    "type": "number"
+                },
+                "outages": {
+                    "type": "integer"
+                },
+                "uptime_percent": {
+                    "type": "number"
+                },
+                "zone_id": {
+                    "type": "string"
+                },
+                "zone_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Contact": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "details": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.ContactEncounter"
+                    }
+                },
+                "encounters": {
+                    "type": "integer"
+                },
+                "first_contact_at": {
+                    "type": "string"
+                },
+                "full_name": {
+                    "type": "string"
+                },
+                "identity_id": {
+                    "type": "string"
+                },
+                "last_contact_at": {
+                    "type": "string"
+                },
+                "overlap_seconds": {
+                    "type": "number"
+                },
+                "zones": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "domain.ContactEncounter": {
+            "type": "object",
+            "properties": {
+                "camera_name": {
+                    "type": "string"
+                },
+                "end_at": {
+                    "type": "string"
+                },
+                "overlap_seconds": {
+                    "type": "number"
+                },
+                "start_at": {
+                    "type": "string"
+                },
+                "zone_id": {
+                    "type": "string"
+                },
+                "zone_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.ContactTrace": {
+            "type": "object",
+            "properties": {
+                "contacts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Contact"
+                    }
+                },
+                "from": {
+                    "type": "string"
+                },
+                "identity_id": {
+                    "type": "string"
+                },
+                "sightings": {
+                    "type": "integer"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "truncated": {
+                    "description": "Sightings were capped; narrow the range",
+                    "type": "boolean"
+                },
+                "window_seconds": {
+                    "type": "number"
+                }
+            }
+        },
+        "domain.CreateCameraRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "rtsp_url"
+            ],
+            "properties": {
+                "ai_enabled": {
+                    "type": "boolean"
+                },
+                "ip_address": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "rtsp_url": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                },
+                "zone_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.CreateWebUserRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password",
+                "username"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "full_name": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 6
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "role_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/domain.UserStatus"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.CreateZoneRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "kind": {
+                    "$ref": "#/definitions/domain.ZoneKind"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.DashboardStats": {
+            "type": "object",
+            "properties": {
+                "maintenance_cameras": {
+                    "type": "integer"
+                },
+                "offline_cameras": {
+                    "type": "integer"
+                },
+                "online_cameras": {
+                    "type": "integer"
+                },
+                "recent_events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.AIEvent"
+                    }
+                },
+                "today_events": {
+                    "type": "integer"
+                },
+                "total_cameras": {
+                    "type": "integer"
+                },
+                "unresolved_events": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.DataKey": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "master_key_id": {
+                    "type": "string"
+                },
+                "purpose": {
+                    "type": "string"
+                },
+                "retired_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "tenant": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.DatasetFormat": {
+            "type": "string",
+            "enum": [
+                "coco",
+                "yolo",
+                "both"
+            ],
+            "x-enum-varnames": [
+                "DatasetFormatCOCO",
+                "DatasetFormatYOLO",
+                "DatasetFormatBoth"
+            ]
+        },
+        "domain.DeviceProfile": {
+            "type": "object",
+            "properties": {
+                "encoding": {
+                    "type": "string"
+                },
+                "height": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rtsp_url": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "width": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.DiscoveredDevice": {
+            "type": "object",
+            "properties": {
+                "adopted_at": {
+                    "type": "string"
+                },
+                "camera_id": {
+                    "type": "string"
+                },
+                "discovered_at": {
+                    "type": "string"
+                },
+                "endpoint_ref": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "firmware_version": {
+                    "type": "string"
+                },
+                "hardware_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "ip_address": {
+                    "type": "string"
+                },
+                "job_id": {
+                    "type": "string"
+                },
+                "manufacturer": {
+                    "type": "string"
+                },
+                "model": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "profiles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.DeviceProfile"
+                    }
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "serial_number": {
+                    "type": "string"
+                },
+                "xaddr": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.DuplicateCandidate": {
+            "type": "object",
+            "properties": {
+                "code_a": {
+                    "description": "Join fields",
+                    "type": "string"
+                },
+                "code_b": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "face_score": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "identity_a": {
+                    "type": "string"
+                },
+                "identity_b": {
+                    "type": "string"
+                },
+                "name_a": {
+                    "type": "string"
+                },
+                "name_b": {
+                    "type": "string"
+                },
+                "reasons": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "resolved_at": {
+                    "type": "string"
+                },
+                "resolved_by": {
+                    "type": "string"
+                },
+                "score": {
+                    "description": "0..1, combined from Reasons",
+                    "type": "number"
+                },
+                "status": {
+                    "$ref": "#/definitions/domain.DuplicateStatus"
+                }
+            }
+        },
+        "domain.DuplicateStatus": {
+            "type": "string",
+            "enum": [
+                "open",
+                "dismissed",
+                "merged"
+            ],
+            "x-enum-varnames": [
+                "DuplicateStatusOpen",
+                "DuplicateStatusDismissed",
+                "DuplicateStatusMerged"
+            ]
+        },
+        "domain.EventStatus": {
+            "type": "string",
+            "enum": [
+                "new",
+                "processing",
+                "resolved",
+                "ignored"
+            ],
+            "x-enum-varnames": [
+                "EventStatusNew",
+                "EventStatusProcessing",
+                "EventStatusResolved",
+                "EventStatusIgnored"
+            ]
+        },
+        "domain.EventType": {
+            "type": "string",
+            "enum": [
+                "person",
+                "vehicle",
+                "face",
+                "intrusion",
+                "loitering",
+                "crowd",
+                "fire",
+                "other",
+                "camera_offline"
+            ],
+            "x-enum-varnames": [
+                "EventTypePerson",
+                "EventTypeVehicle",
+                "EventTypeFace",
+                "EventTypeIntrusion",
+                "EventTypeLoitering",
+                "EventTypeCrowd",
+                "EventTypeFire",
+                "EventTypeOther",
+                "EventTypeCameraOffline"
+            ]
+        },
+        "domain.EventVerdict": {
+            "type": "string",
+            "enum": [
+                "true_positive",
+                "false_positive"
+            ],
+            "x-enum-varnames": [
+                "EventVerdictTruePositive",
+                "EventVerdictFalsePositive"
+            ]
+        },
+        "domain.FaceMatch": {
+            "type": "object",
+            "properties": {
+                "face_id": {
+                    "type": "string"
+                },
+                "identity": {
+                    "$ref": "#/definitions/domain.Identity"
+                },
+                "identity_id": {
+                    "type": "string"
+                },
+                "score": {
+                    "description": "Cosine similarity, -1..1",
+                    "type": "number"
+                }
+            }
+        },
+        "domain.FaceQuality": {
+            "type": "object",
+            "properties": {
+                "aspect_ratio": {
+                    "type": "number"
+                },
+                "brightness": {
+                    "description": "Mean luminance, 0-255",
+                    "type": "number"
+                },
+                "contrast": {
+                    "description": "Luminance standard deviation",
+                    "type": "number"
+                },
+                "height": {
+                    "type": "integer"
+                },
+                "issues": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "sharpness": {
+                    "description": "Laplacian variance, higher is sharper",
+                    "type": "number"
+                },
+                "width": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.FeedbackAction": {
+            "type": "string",
+            "enum": [
+                "confirm",
+                "reject",
+                "reassign"
+            ],
+            "x-enum-varnames": [
+                "FeedbackConfirm",
+                "FeedbackReject",
+                "FeedbackReassign"
+            ]
+        },
+        "domain.FieldChange": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "new": {},
+                "old": {}
+            }
+        },
+        "domain.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.FloorPlan": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "height": {
+                    "type": "integer"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "width": {
+                    "type": "integer"
+                },
+                "zone_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.FloorPlanMap": {
+            "type": "object",
+            "properties": {
+                "features": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.MapFeature"
+                    }
+                },
+                "floor_plan": {
+                    "$ref": "#/definitions/domain.FloorPlan"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "window_minutes": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.Identity": {
+            "type": "object",
+            "properties": {
+                "approved_at": {
+                    "type": "string"
+                },
+                "approved_by": {
+                    "type": "string"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "department": {
+                    "type": "string"
+                },
+                "face_image_url": {
+                    "type": "string"
+                },
+                "faces": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.IdentityFace"
+                    }
+                },
+                "full_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "identity_card_number": {
+                    "type": "string"
+                },
+                "merged_into": {
+                    "type": "string"
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "note": {
+                    "type": "string"
+                },
+                "phone_number": {
+                    "type": "string"
+                },
+                "review_reason": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/domain.IdentityStatus"
+                },
+                "type": {
+                    "description": "STAFF, STUDENT, VIP...",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_account_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.IdentityAccessReport": {
+            "type": "object",
+            "properties": {
+                "accessors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.IdentityAccessor"
+                    }
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.AccessLog"
+                    }
+                },
+                "from": {
+                    "type": "string"
+                },
+                "identity_id": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.IdentityAccessor": {
+            "type": "object",
+            "properties": {
+                "accesses": {
+                    "type": "integer"
+                },
+                "first_at": {
+                    "type": "string"
+                },
+                "last_at": {
+                    "type": "string"
+                },
+                "pii_unmasked": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.IdentityErasure": {
+            "type": "object",
+            "properties": {
+                "attendance_records_kept": {
+                    "type": "integer"
+                },
+                "audit_logs_redacted": {
+                    "type": "integer"
+                },
+                "erased_at": {
+                    "type": "string"
+                },
+                "faces_deleted": {
+                    "type": "integer"
+                },
+                "feedback_deleted": {
+                    "type": "integer"
+                },
+                "identity_id": {
+                    "type": "string"
+                },
+                "media_deleted": {
+                    "type": "integer"
+                },
+                "recognition_logs_anonymized": {
+                    "type": "integer"
+                },
+                "stranger_clusters_deleted": {
+                    "type": "integer"
+                },
+                "versions_deleted": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.IdentityFace": {
+            "type": "object",
+            "properties": {
+                "blur_score": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "has_embedding": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "identity_id": {
+                    "type": "string"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "is_primary": {
+                    "type": "boolean"
+                },
+                "quality": {
+                    "$ref": "#/definitions/domain.FaceQuality"
+                },
+                "quality_flagged": {
+                    "type": "boolean"
+                },
+                "quality_reason": {
+                    "type": "string"
+                },
+                "quality_score": {
+                    "type": "number"
+                }
+            }
+        },
+        "domain.IdentityMerge": {
+            "type": "object",
+            "properties": {
+                "attendance_days_reconciled": {
+                    "type": "integer"
+                },
+                "attendance_records_moved": {
+                    "type": "integer"
+                },
+                "faces_moved": {
+                    "type": "integer"
+                },
+                "merged_at": {
+                    "type": "string"
+                },
+                "merged_id": {
+                    "type": "string"
+                },
+                "recognition_logs_moved": {
+                    "type": "integer"
+                },
+                "survivor_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.IdentityStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "active",
+                "rejected"
+            ],
+            "x-enum-varnames": [
+                "IdentityStatusPending",
+                "IdentityStatusActive",
+                "IdentityStatusRejected"
+            ]
+        },
+        "domain.IdentityVersion": {
+            "type": "object",
+            "properties": {
+                "changed_at": {
+                    "type": "string"
+                },
+                "changed_by": {
+                    "type": "string"
+                },
+                "changed_by_name": {
+                    "description": "Join fields",
+                    "type": "string"
+                },
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.FieldChange"
+                    }
+                },
+                "entity_type": {
+                    "type": "string"
+                },
+                "face": {
+                    "$ref": "#/definitions/domain.IdentityFace"
+                },
+                "face_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "identity": {
+                    "$ref": "#/definitions/domain.Identity"
+                },
+                "identity_id": {
+                    "type": "string"
+                },
+                "operation": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "revert_of": {
+                    "type": "integer"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.Job": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "params": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "processed": {
+                    "type": "integer"
+                },
+                "result": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "result_url": {
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/domain.JobStatus"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.JobStatus": {
+            "type": "string",
+            "enum": [
+                "queued",
+                "running",
+                "completed",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "JobStatusQueued",
+                "JobStatusRunning",
+                "JobStatusCompleted",
+                "JobStatusFailed"
+            ]
+        },
+        "domain.LoginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "description": "Login by Email",
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/domain.User"
+                }
+            }
+        },
+        "domain.MaintenanceEventPolicy": {
+            "type": "string",
+            "enum": [
+                "drop",
+                "tag"
+            ],
+            "x-enum-varnames": [
+                "MaintenanceEventsDrop",
+                "MaintenanceEventsTag"
+            ]
+        },
+        "domain.MaintenanceRecurrence": {
+            "type": "string",
+            "enum": [
+                "none",
+                "daily",
+                "weekly"
+            ],
+            "x-enum-varnames": [
+                "MaintenanceRecurrenceNone",
+                "MaintenanceRecurrenceDaily",
+                "MaintenanceRecurrenceWeekly"
+            ]
+        },
+        "domain.MaintenanceWindow": {
+            "type": "object",
+            "properties": {
+                "active_from": {
+                    "description": "Set when listing with at: the occurrence covering that moment",
+                    "type": "string"
+                },
+                "active_until": {
+                    "type": "string"
+                },
+                "camera_id": {
+                    "type": "string"
+                },
+                "camera_ids": {
+                    "description": "Cameras the window covers: its camera, or every camera in its zone's\nsubtree",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "event_policy": {
+                    "$ref": "#/definitions/domain.MaintenanceEventPolicy"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "recurrence": {
+                    "$ref": "#/definitions/domain.MaintenanceRecurrence"
+                },
+                "repeat_until": {
+                    "type": "string"
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "timezone": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "zone_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.ManifestAction": {
+            "type": "string",
+            "enum": [
+                "create",
+                "update",
+                "delete"
+            ],
+            "x-enum-varnames": [
+                "ManifestActionCreate",
+                "ManifestActionUpdate",
+                "ManifestActionDelete"
+            ]
+        },
+        "domain.ManifestChange": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "$ref": "#/definitions/domain.ManifestAction"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "fields": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "$ref": "#/definitions/domain.ManifestKind"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.ManifestKind": {
+            "type": "string",
+            "enum": [
+                "zone",
+                "camera",
+                "ai_config"
+            ],
+            "x-enum-varnames": [
+                "ManifestKindZone",
+                "ManifestKindCamera",
+                "ManifestKindAIConfig"
+            ]
+        },
+        "domain.ManifestPlan": {
+            "type": "object",
+            "properties": {
+                "applied": {
+                    "type": "boolean"
+                },
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.ManifestChange"
+                    }
+                },
+                "creates": {
+                    "type": "integer"
+                },
+                "deletes": {
+                    "type": "integer"
+                },
+                "prune": {
+                    "type": "boolean"
+                },
+                "unchanged": {
+                    "type": "integer"
+                },
+                "updates": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.MapFeature": {
+            "type": "object",
+            "properties": {
+                "geometry": {
+                    "$ref": "#/definitions/domain.MapGeometry"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "properties": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.MapGeometry": {
+            "type": "object",
+            "properties": {
+                "coordinates": {},
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Notification": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "read_at": {
+                    "type": "string"
+                },
+                "resource_id": {
+                    "type": "string"
+                },
+                "resource_type": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/domain.NotificationType"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.NotificationType": {
+            "type": "string",
+            "enum": [
+                "identity_approved",
+                "identity_rejected"
+            ],
+            "x-enum-varnames": [
+                "NotificationIdentityApproved",
+                "NotificationIdentityRejected"
+            ]
+        },
+        "domain.PersonTimeline": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string"
+                },
+                "identity_id": {
+                    "type": "string"
+                },
+                "sightings": {
+                    "type": "integer"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "transitions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.ZoneTransition"
+                    }
+                },
+                "truncated": {
+                    "description": "More sightings than the limit; narrow the range",
+                    "type": "boolean"
+                },
+                "visits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.TimelineVisit"
+                    }
+                },
+                "zones": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.ZoneSummary"
+                    }
+                }
+            }
+        },
+        "domain.ROIMode": {
+            "type": "string",
+            "enum": [
+                "include",
+                "exclude"
+            ],
+            "x-enum-varnames": [
+                "ROIModeInclude",
+                "ROIModeExclude"
+            ]
+        },
+        "domain.ROIZone": {
+            "type": "object",
+            "properties": {
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.EventType"
+                    }
+                },
+                "mode": {
+                    "$ref": "#/definitions/domain.ROIMode"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "polygon": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "type": "number"
+                        }
+                    }
+                }
+            }
+        },
+        "domain.RecognitionFeedback": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "$ref": "#/definitions/domain.FeedbackAction"
+                },
+                "camera_id": {
+                    "type": "string"
+                },
+                "confidence": {
+                    "type": "number"
+                },
+                "corrected_identity_id": {
+                    "description": "nil when rejected as a stranger",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "embedding": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "face_crop_url": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "log_id": {
+                    "type": "string"
+                },
+                "log_occurred_at": {
+                    "type": "string"
+                },
+                "original_identity_id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
                 },
-                "event_type": {
-                    "$ref": "#/definitions/domain.EventType"
+                "reviewed_by": {
+                    "type": "string"
+                },
+                "snapshot_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.RecognitionLog": {
+            "type": "object",
+            "properties": {
+                "camera_id": {
+                    "type": "string"
+                },
+                "camera_name": {
+                    "type": "string"
+                },
+                "confidence": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "face_crop_url": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "identity_id": {
+                    "type": "string"
+                },
+                "identity_name": {
+                    "description": "Join fields",
+                    "type": "string"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "metadata": {
+                    "description": "Detector output such as bbox",
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "occurred_at": {
+                    "type": "string"
+                },
+                "review_status": {
+                    "type": "string"
+                },
+                "snapshot_url": {
+                    "type": "string"
+                },
+                "stranger_cluster_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.RecognitionReview": {
+            "type": "object",
+            "properties": {
+                "attendance": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.AttendanceRecord"
+                    }
+                },
+                "feedback": {
+                    "$ref": "#/definitions/domain.RecognitionFeedback"
+                },
+                "log": {
+                    "$ref": "#/definitions/domain.RecognitionLog"
+                }
+            }
+        },
+        "domain.RegisterRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password",
+                "username"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "full_name": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 6
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Role": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_system": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "permissions": {
+                    "description": "JSONB"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.SkippedCrop": {
+            "type": "object",
+            "properties": {
+                "log_id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.StrangerCluster": {
+            "type": "object",
+            "properties": {
+                "best_crop_url": {
+                    "type": "string"
+                },
+                "camera_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "camera_names": {
+                    "description": "Join fields",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "first_seen_at": {
+                    "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "metadata": {
-                    "type": "object",
-                    "additionalProperties": {}
-                },
-                "resolved_by": {
+                "label": {
+                    "description": "\"Unknown person #Seq\"",
+                    "type": "string"
+                },
+                "last_seen_at": {
+                    "type": "string"
+                },
+                "log_count": {
+                    "type": "integer"
+                },
+                "promoted_at": {
+                    "type": "string"
+                },
+                "promoted_by": {
+                    "type": "string"
+                },
+                "promoted_identity_id": {
+                    "type": "string"
+                },
+                "seq": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/domain.StrangerClusterStatus"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.StrangerClusterStatus": {
+            "type": "string",
+            "enum": [
+                "open",
+                "promoted"
+            ],
+            "x-enum-varnames": [
+                "StrangerClusterOpen",
+                "StrangerClusterPromoted"
+            ]
+        },
+        "domain.StrangerPromotion": {
+            "type": "object",
+            "properties": {
+                "cluster_id": {
+                    "type": "string"
+                },
+                "faces": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.IdentityFace"
+                    }
+                },
+                "identity": {
+                    "$ref": "#/definitions/domain.Identity"
+                },
+                "logs_linked": {
+                    "type": "integer"
+                },
+                "skipped_crops": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.SkippedCrop"
+                    }
+                }
+            }
+        },
+        "domain.TimelineVisit": {
+            "type": "object",
+            "properties": {
+                "camera_id": {
+                    "type": "string"
+                },
+                "camera_name": {
+                    "type": "string"
+                },
+                "dwell_seconds": {
+                    "type": "number"
+                },
+                "end_at": {
+                    "type": "string"
+                },
+                "max_confidence": {
+                    "type": "number"
+                },
+                "sightings": {
+                    "type": "integer"
+                },
+                "snapshots": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "start_at": {
+                    "type": "string"
+                },
+                "zone_id": {
+                    "type": "string"
+                },
+                "zone_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.UpdateCameraRequest": {
+            "type": "object",
+            "properties": {
+                "ai_enabled": {
+                    "description": "Pointer to distinguish false vs nil",
+                    "type": "boolean"
+                },
+                "ip_address": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "password": {
+                    "description": "Empty string clears",
+                    "type": "string"
+                },
+                "rtsp_url": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/domain.CameraStatus"
+                },
+                "username": {
+                    "description": "Empty string clears",
+                    "type": "string"
+                },
+                "zone_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.UpdateWebUserRequest": {
+            "type": "object",
+            "properties": {
+                "full_name": {
+                    "type": "string"
+                },
+                "phone": {
                     "type": "string"
                 },
-                "snapshot_url": {
+                "role_id": {
                     "type": "string"
                 },
                 "status": {
-                    "$ref": "#/definitions/domain.EventStatus"
+                    "$ref": "#/definitions/domain.UserStatus"
+                }
+            }
+        },
+        "domain.UpdateZoneRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
                 },
-                "updated_at": {
+                "kind": {
+                    "$ref": "#/definitions/domain.ZoneKind"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parent_id": {
                     "type": "string"
                 }
             }
         },
-        "domain.AttendanceRecord": {
+        "domain.UptimeReport": {
             "type": "object",
             "properties": {
-                "check_in": {
+                "cameras": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.CameraUptime"
+                    }
+                },
+                "from": {
                     "type": "string"
                 },
-                "check_out": {
+                "overall": {
+                    "$ref": "#/definitions/domain.UptimeStats"
+                },
+                "to": {
                     "type": "string"
                 },
+                "zones": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.ZoneUptime"
+                    }
+                }
+            }
+        },
+        "domain.UptimeStats": {
+            "type": "object",
+            "properties": {
+                "longest_outage_seconds": {
+                    "type": "number"
+                },
+                "maintenance_seconds": {
+                    "type": "number"
+                },
+                "mttr_seconds": {
+                    "type": "number"
+                },
+                "offline_seconds": {
+                    "type": "number"
+                },
+                "online_seconds": {
+                    "type": "number"
+                },
+                "outages": {
+                    "type": "integer"
+                },
+                "uptime_percent": {
+                    "type": "number"
+                }
+            }
+        },
+        "domain.User": {
+            "type": "object",
+            "properties": {
                 "created_at": {
                     "type": "string"
                 },
-                "date": {
+                "email": {
+                    "type": "string"
+                },
+                "full_name": {
                     "type": "string"
                 },
                 "id": {
+                    "description": "Converted from UUID",
                     "type": "string"
                 },
-                "identity_id": {
+                "last_login_at": {
                     "type": "string"
                 },
-                "identity_name": {
-                    "description": "Join fields",
+                "phone": {
+                    "type": "string"
+                },
+                "role_id": {
                     "type": "string"
                 },
                 "status": {
-                    "$ref": "#/definitions/domain.AttendanceStatus"
+                    "$ref": "#/definitions/domain.UserStatus"
                 },
                 "updated_at": {
                     "type": "string"
                 },
-                "work_hours": {
-                    "type": "number"
+                "username": {
+                    "type": "string"
                 }
             }
         },
-        "domain.AttendanceStatus": {
+        "domain.UserStatus": {
             "type": "string",
             "enum": [
-                "late",
-                "on_time",
-                "absent",
-                "early_leave"
+                "active",
+                "locked",
+                "banned"
             ],
             "x-enum-varnames": [
-                "AttendanceLate",
-                "AttendanceOnTime",
-                "AttendanceAbsent",
-                "AttendanceEarlyLeave"
+                "UserStatusActive",
+                "UserStatusLocked",
+                "UserStatusBanned"
             ]
         },
-        "domain.AuditLog": {
+        "domain.Zone": {
             "type": "object",
             "properties": {
-                "action": {
+                "created_at": {
                     "type": "string"
                 },
-                "created_at": {
+                "description": {
                     "type": "string"
                 },
                 "id": {
-                    "type": "integer"
-                },
-                "ip_address": {
                     "type": "string"
                 },
-                "new_value": {
-                    "type": "object",
-                    "additionalProperties": {}
-                },
-                "old_value": {
-                    "type": "object",
-                    "additionalProperties": {}
+                "kind": {
+                    "$ref": "#/definitions/domain.ZoneKind"
                 },
-                "record_id": {
+                "name": {
                     "type": "string"
                 },
-                "table_name": {
+                "parent_id": {
                     "type": "string"
                 },
-                "user_agent": {
+                "path": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.ZoneArea": {
+            "type": "object",
+            "properties": {
+                "plan_zone_id": {
                     "type": "string"
                 },
-                "user_id": {
+                "polygon": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "type": "number"
+                        }
+                    }
+                },
+                "updated_at": {
                     "type": "string"
                 },
-                "username": {
-                    "description": "Join fields",
+                "zone_id": {
                     "type": "string"
                 }
             }
         },
-        "domain.Camera": {
+        "domain.ZoneKind": {
+            "type": "string",
+            "enum": [
+                "site",
+                "building",
+                "floor",
+                "area"
+            ],
+            "x-enum-varnames": [
+                "ZoneKindSite",
+                "ZoneKindBuilding",
+                "ZoneKindFloor",
+                "ZoneKindArea"
+            ]
+        },
+        "domain.ZoneNode": {
             "type": "object",
             "properties": {
-                "ai_enabled": {
-                    "type": "boolean"
+                "cameras": {
+                    "type": "integer"
+                },
+                "children": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.ZoneNode"
+                    }
                 },
                 "created_at": {
                     "type": "string"
                 },
-                "id": {
+                "description": {
                     "type": "string"
                 },
-                "ip_address": {
+                "id": {
                     "type": "string"
                 },
+                "kind": {
+                    "$ref": "#/definitions/domain.ZoneKind"
+                },
                 "name": {
                     "type": "string"
                 },
-                "rtsp_url": {
+                "parent_id": {
                     "type": "string"
                 },
-                "status": {
-                    "$ref": "#/definitions/domain.CameraStatus"
-                },
-                "updated_at": {
+                "path": {
                     "type": "string"
                 },
-                "zone_id": {
-                    "type": "string"
+                "total_cameras": {
+                    "type": "integer"
                 }
             }
         },
-        "domain.CameraStatus": {
-            "type": "string",
-            "enum": [
-                "online",
-                "offline",
-                "maintenance"
-            ],
-            "x-enum-varnames": [
-                "CameraStatusOnline",
-                "CameraStatusOffline",
-                "CameraStatusMaintenance"
-            ]
-        },
-        "domain.CreateCameraRequest": {
+        "domain.ZoneSummary": {
             "type": "object",
-            "required": [
-                "name",
-                "rtsp_url"
-            ],
             "properties": {
-                "ai_enabled": {
-                    "type": "boolean"
+                "dwell_seconds": {
+                    "type": "number"
                 },
-                "ip_address": {
+                "first_seen_at": {
                     "type": "string"
                 },
-                "name": {
+                "last_seen_at": {
                     "type": "string"
                 },
-                "rtsp_url": {
-                    "type": "string"
+                "sightings": {
+                    "type": "integer"
+                },
+                "visits": {
+                    "type": "integer"
                 },
                 "zone_id": {
                     "type": "string"
+                },
+                "zone_name": {
+                    "type": "string"
                 }
             }
         },
-        "domain.CreateWebUserRequest": {
+        "domain.ZoneTransition": {
             "type": "object",
-            "required": [
-                "email",
-                "password",
-                "username"
-            ],
             "properties": {
-                "email": {
+                "entered_at": {
                     "type": "string"
                 },
-                "full_name": {
+                "from_zone_id": {
                     "type": "string"
                 },
-                "password": {
-                    "type": "string",
-                    "minLength": 6
+                "from_zone_name": {
+                    "type": "string"
                 },
-                "phone": {
+                "left_at": {
                     "type": "string"
                 },
-                "role_id": {
+                "to_zone_id": {
                     "type": "string"
                 },
-                "status": {
-                    "$ref": "#/definitions/domain.UserStatus"
+                "to_zone_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.ZoneUptime": {
+            "type": "object",
+            "properties": {
+                "cameras": {
+                    "type": "integer"
                 },
-                "username": {
+                "longest_outage_seconds": {
+                    "type": "number"
+                },
+                "maintenance_seconds": {
+                    "type": "number"
+                },
+                "mttr_seconds": {
+                    "type": "number"
+                },
+                "offline_seconds": {
+                    "type": "number"
+                },
+                "online_seconds": {
+                    "type": "number"
+                },
+                "outages": {
+                    "type": "integer"
+                },
+                "uptime_percent": {
+                    "type": "number"
+                },
+                "zone_id": {
+                    "type": "string"
+                },
+                "zone_name": {
                     "type": "string"
                 }
             }
         },
-        "domain.CreateZoneRequest": {
+        "http.AIConfigErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "fields": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.FieldError"
+                    }
+                }
+            }
+        },
+        "http.AttendanceRecordResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.AttendanceRecord"
+                    }
+                }
+            }
+        },
+        "http.AuditLogResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.AuditLog"
+                    }
+                }
+            }
+        },
+        "http.ErrorResponse": {
             "type": "object",
-            "required": [
-                "name"
-            ],
             "properties": {
-                "description": {
-                    "type": "string"
-                },
-                "name": {
+                "error": {
                     "type": "string"
                 }
             }
         },
-        "domain.DashboardStats": {
+        "http.ManifestErrorResponse": {
             "type": "object",
             "properties": {
-                "maintenance_cameras": {
-                    "type": "integer"
-                },
-                "offline_cameras": {
-                    "type": "integer"
-                },
-                "online_cameras": {
-                    "type": "integer"
+                "error": {
+                    "type": "string"
                 },
-                "recent_events": {
+                "problems": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/domain.AIEvent"
+                        "type": "string"
                     }
-                },
-                "today_events": {
+                }
+            }
+        },
+        "http.PaginatedResponse": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "limit": {
                     "type": "integer"
                 },
-                "total_cameras": {
+                "page": {
                     "type": "integer"
                 },
-                "unresolved_events": {
+                "total": {
                     "type": "integer"
                 }
             }
         },
-        "domain.EventStatus": {
-            "type": "string",
-            "enum": [
-                "new",
-                "processing",
-                "resolved",
-                "ignored"
-            ],
-            "x-enum-varnames": [
-                "EventStatusNew",
-                "EventStatusProcessing",
-                "EventStatusResolved",
-                "EventStatusIgnored"
-            ]
+        "http.RecognitionLogResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.RecognitionLog"
+                    }
+                }
+            }
         },
-        "domain.EventType": {
-            "type": "string",
-            "enum": [
-                "person",
-                "vehicle",
-                "face",
-                "intrusion",
-                "loitering",
-                "crowd",
-                "fire",
-                "other"
+        "http.ReviewReasonRequest": {
+            "type": "object",
+            "required": [
+                "reason"
             ],
-            "x-enum-varnames": [
-                "EventTypePerson",
-                "EventTypeVehicle",
-                "EventTypeFace",
-                "EventTypeIntrusion",
-                "EventTypeLoitering",
-                "EventTypeCrowd",
-                "EventTypeFire",
-                "EventTypeOther"
-            ]
+            "properties": {
+                "reason": {
+                    "type": "string"
+                }
+            }
         },
-        "domain.Identity": {
+        "ports.AITemplateRequest": {
             "type": "object",
+            "required": [
+                "name"
+            ],
             "properties": {
-                "approved_by": {
-                    "type": "string"
-                },
-                "code": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "created_by": {
-                    "type": "string"
-                },
-                "deleted_at": {
-                    "type": "string"
-                },
-                "department": {
-                    "type": "string"
+                "active_hours": {
+                    "$ref": "#/definitions/domain.ActiveHours"
                 },
-                "face_image_url": {
-                    "type": "string"
+                "ai_enabled": {
+                    "type": "boolean"
                 },
-                "faces": {
+                "ai_types": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/domain.IdentityFace"
+                        "$ref": "#/definitions/domain.EventType"
                     }
                 },
-                "full_name": {
+                "description": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "string"
+                "min_confidence": {
+                    "type": "integer"
                 },
-                "identity_card_number": {
+                "name": {
                     "type": "string"
                 },
-                "metadata": {
-                    "type": "object",
-                    "additionalProperties": {}
+                "roi_zones": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.ROIZone"
+                    }
                 },
-                "note": {
+                "sensitivity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "ports.AdoptDevice": {
+            "type": "object",
+            "required": [
+                "device_id"
+            ],
+            "properties": {
+                "device_id": {
                     "type": "string"
                 },
-                "phone_number": {
+                "name": {
+                    "description": "Name defaults to the device's ONVIF name, then its model",
                     "type": "string"
                 },
-                "status": {
-                    "$ref": "#/definitions/domain.IdentityStatus"
-                },
-                "type": {
-                    "description": "STAFF, STUDENT, VIP...",
+                "profile_token": {
+                    "description": "ProfileToken picks the stream; the device's first profile by default",
                     "type": "string"
-                },
-                "updated_at": {
+                }
+            }
+        },
+        "ports.AdoptDeviceError": {
+            "type": "object",
+            "properties": {
+                "device_id": {
                     "type": "string"
                 },
-                "user_account_id": {
+                "error": {
                     "type": "string"
                 }
             }
         },
-        "domain.IdentityFace": {
+        "ports.AdoptDevicesRequest": {
             "type": "object",
+            "required": [
+                "devices"
+            ],
             "properties": {
-                "blur_score": {
-                    "type": "number"
+                "ai_enabled": {
+                    "type": "boolean"
                 },
-                "created_at": {
-                    "type": "string"
+                "devices": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ports.AdoptDevice"
+                    }
                 },
-                "id": {
+                "password": {
                     "type": "string"
                 },
-                "identity_id": {
+                "username": {
                     "type": "string"
                 },
-                "image_url": {
+                "zone_id": {
                     "type": "string"
-                },
-                "is_primary": {
-                    "type": "boolean"
-                },
-                "quality_score": {
-                    "type": "number"
                 }
             }
         },
-        "domain.IdentityStatus": {
-            "type": "string",
-            "enum": [
-                "pending",
-                "active",
-                "rejected"
-            ],
-            "x-enum-varnames": [
-                "IdentityStatusPending",
-                "IdentityStatusActive",
-                "IdentityStatusRejected"
-            ]
-        },
-        "domain.LoginRequest": {
+        "ports.AdoptDevicesResult": {
             "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
             "properties": {
-                "email": {
-                    "description": "Login by Email",
-                    "type": "string"
+                "cameras": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Camera"
+                    }
                 },
-                "password": {
-                    "type": "string"
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ports.AdoptDeviceError"
+                    }
                 }
             }
         },
-        "domain.LoginResponse": {
+        "ports.ApplyAITemplateRequest": {
             "type": "object",
             "properties": {
-                "access_token": {
-                    "type": "string"
+                "camera_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "user": {
-                    "$ref": "#/definitions/domain.User"
+                "overrides": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/domain.AIConfigOverrides"
+                    }
+                },
+                "zone_id": {
+                    "type": "string"
                 }
             }
         },
-        "domain.RecognitionLog": {
+        "ports.CameraPlacementRequest": {
             "type": "object",
             "properties": {
-                "camera_id": {
-                    "type": "string"
+                "fov": {
+                    "description": "Default 90",
+                    "type": "number"
                 },
-                "camera_name": {
+                "heading": {
+                    "type": "number"
+                },
+                "plan_zone_id": {
                     "type": "string"
                 },
-                "confidence": {
+                "range": {
+                    "description": "Default a tenth of the plan's larger side",
                     "type": "number"
                 },
-                "created_at": {
+                "x": {
+                    "type": "number"
+                },
+                "y": {
+                    "type": "number"
+                }
+            }
+        },
+        "ports.CreateIdentityRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "full_name"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "createdBy": {
                     "type": "string"
                 },
-                "face_crop_url": {
+                "department": {
                     "type": "string"
                 },
-                "id": {
+                "full_name": {
                     "type": "string"
                 },
-                "identity_id": {
+                "identity_card_number": {
                     "type": "string"
                 },
-                "identity_name": {
-                    "description": "Join fields",
-                    "type": "string"
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {}
                 },
-                "label": {
+                "note": {
                     "type": "string"
                 },
-                "occurred_at": {
+                "phone_number": {
                     "type": "string"
                 },
-                "snapshot_url": {
+                "type": {
+                    "description": "STAFF, STUDENT, VIP...",
                     "type": "string"
                 }
             }
         },
-        "domain.RegisterRequest": {
+        "ports.DatasetExportRequest": {
             "type": "object",
-            "required": [
-                "email",
-                "password",
-                "username"
-            ],
             "properties": {
-                "email": {
+                "camera_id": {
                     "type": "string"
                 },
-                "full_name": {
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.EventType"
+                    }
+                },
+                "format": {
+                    "description": "coco, yolo or both (default)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.DatasetFormat"
+                        }
+                    ]
+                },
+                "from": {
                     "type": "string"
                 },
-                "password": {
-                    "type": "string",
-                    "minLength": 6
+                "skip_negatives": {
+                    "description": "Leave out false positives",
+                    "type": "boolean"
                 },
-                "username": {
+                "to": {
                     "type": "string"
+                },
+                "val_ratio": {
+                    "type": "number"
                 }
             }
         },
-        "domain.Role": {
+        "ports.DiscoveryRequest": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "is_system": {
-                    "type": "boolean"
-                },
-                "name": {
+                "password": {
                     "type": "string"
                 },
-                "permissions": {
-                    "description": "JSONB"
-                },
-                "updated_at": {
+                "username": {
                     "type": "string"
                 }
             }
         },
-        "domain.UpdateCameraRequest": {
+        "ports.EnrollFaceRequest": {
             "type": "object",
+            "required": [
+                "identity_id",
+                "image_url"
+            ],
             "properties": {
-                "ai_enabled": {
-                    "description": "Pointer to distinguish false vs nil",
-                    "type": "boolean"
-                },
-                "ip_address": {
-                    "type": "string"
+                "embedding": {
+                    "description": "Optional, computed by the embedding provider when omitted",
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
                 },
-                "name": {
+                "identity_id": {
                     "type": "string"
                 },
-                "rtsp_url": {
+                "image_url": {
                     "type": "string"
                 },
-                "status": {
-                    "$ref": "#/definitions/domain.CameraStatus"
-                },
-                "zone_id": {
-                    "type": "string"
+                "is_primary": {
+                    "type": "boolean"
                 }
             }
         },
-        "domain.UpdateWebUserRequest": {
+        "ports.EraseIdentityRequest": {
             "type": "object",
             "properties": {
-                "full_name": {
-                    "type": "string"
-                },
-                "phone": {
+                "reason": {
                     "type": "string"
                 },
-                "role_id": {
+                "requestedBy": {
                     "type": "string"
-                },
-                "status": {
-                    "$ref": "#/definitions/domain.UserStatus"
                 }
             }
         },
-        "domain.UpdateZoneRequest": {
+        "ports.FaceSearchRequest": {
             "type": "object",
             "properties": {
-                "description": {
-                    "type": "string"
+                "embedding": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
                 },
-                "name": {
-                    "type": "string"
+                "min_score": {
+                    "type": "number"
+                },
+                "top_k": {
+                    "type": "integer"
                 }
             }
         },
-        "domain.User": {
+        "ports.MaintenanceWindowRequest": {
             "type": "object",
+            "required": [
+                "ends_at",
+                "name",
+                "starts_at"
+            ],
             "properties": {
-                "created_at": {
+                "camera_id": {
                     "type": "string"
                 },
-                "email": {
+                "ends_at": {
                     "type": "string"
                 },
-                "full_name": {
-                    "type": "string"
+                "event_policy": {
+                    "description": "Default tag",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.MaintenanceEventPolicy"
+                        }
+                    ]
                 },
-                "id": {
-                    "description": "Converted from UUID",
+                "name": {
                     "type": "string"
                 },
-                "last_login_at": {
+                "note": {
                     "type": "string"
                 },
-                "phone": {
-                    "type": "string"
+                "recurrence": {
+                    "description": "Default none",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.MaintenanceRecurrence"
+                        }
+                    ]
                 },
-                "role_id": {
+                "repeat_until": {
+                    "description": "Recurring windows only",
                     "type": "string"
                 },
-                "status": {
-                    "$ref": "#/definitions/domain.UserStatus"
+                "starts_at": {
+                    "type": "string"
                 },
-                "updated_at": {
+                "timezone": {
+                    "description": "Default UTC",
                     "type": "string"
                 },
-                "username": {
+                "zone_id": {
                     "type": "string"
                 }
             }
         },
-        "domain.UserStatus": {
-            "type": "string",
-            "enum": [
-                "active",
-                "locked",
-                "banned"
+        "ports.MergeIdentitiesRequest": {
+            "type": "object",
+            "required": [
+                "reason",
+                "source_id"
             ],
-            "x-enum-varnames": [
-                "UserStatusActive",
-                "UserStatusLocked",
-                "UserStatusBanned"
-            ]
+            "properties": {
+                "reason": {
+                    "type": "string"
+                },
+                "source_id": {
+                    "type": "string"
+                }
+            }
         },
-        "domain.Zone": {
+        "ports.PromoteStrangerRequest": {
             "type": "object",
             "properties": {
-                "created_at": {
+                "code": {
                     "type": "string"
                 },
-                "description": {
+                "department": {
                     "type": "string"
                 },
-                "id": {
+                "full_name": {
                     "type": "string"
                 },
-                "name": {
+                "note": {
+                    "type": "string"
+                },
+                "type": {
                     "type": "string"
                 }
             }
         },
-        "http.AttendanceRecordResponse": {
+        "ports.RecognitionFeedbackRequest": {
             "type": "object",
+            "required": [
+                "action"
+            ],
             "properties": {
-                "data": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/domain.AttendanceRecord"
-                    }
+                "action": {
+                    "$ref": "#/definitions/domain.FeedbackAction"
+                },
+                "identity_id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
                 }
             }
         },
-        "http.AuditLogResponse": {
+        "ports.RevertIdentityRequest": {
             "type": "object",
+            "required": [
+                "reason",
+                "version"
+            ],
             "properties": {
-                "data": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/domain.AuditLog"
-                    }
+                "reason": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
-        "http.PaginatedResponse": {
+        "ports.ReviewIdentityRequest": {
             "type": "object",
+            "required": [
+                "reason"
+            ],
             "properties": {
-                "data": {},
-                "limit": {
-                    "type": "integer"
-                },
-                "page": {
-                    "type": "integer"
+                "reason": {
+                    "type": "string"
                 },
-                "total": {
-                    "type": "integer"
+                "status": {
+                    "$ref": "#/definitions/domain.IdentityStatus"
                 }
             }
         },
-        "http.RecognitionLogResponse": {
+        "ports.RollbackAIConfigRequest": {
             "type": "object",
+            "required": [
+                "version"
+            ],
             "properties": {
-                "data": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/domain.RecognitionLog"
-                    }
+                "version": {
+                    "type": "integer"
                 }
             }
         },
-        "ports.CreateIdentityRequest": {
+        "ports.UpdateEventRequest": {
             "type": "object",
-            "required": [
-                "code",
-                "full_name"
-            ],
             "properties": {
-                "code": {
-                    "type": "string"
-                },
-                "createdBy": {
-                    "type": "string"
-                },
-                "department": {
-                    "type": "string"
-                },
-                "full_name": {
-                    "type": "string"
-                },
-                "identity_card_number": {
-                    "type": "string"
-                },
-                "metadata": {
-                    "type": "object",
-                    "additionalProperties": {}
-                },
-                "note": {
-                    "type": "string"
-                },
-                "phone_number": {
-                    "type": "string"
+                "status": {
+                    "$ref": "#/definitions/domain.EventStatus"
                 },
-                "type": {
-                    "description": "STAFF, STUDENT, VIP...",
-                    "type": "string"
+                "verdict": {
+                    "$ref": "#/definitions/domain.EventVerdict"
                 }
             }
         },
@@ -2432,6 +8411,26 @@ const docTemplate = `{
                     }
                 }
             }
+        },
+        "ports.ZoneAreaRequest": {
+            "type": "object",
+            "required": [
+                "polygon"
+            ],
+            "properties": {
+                "plan_zone_id": {
+                    "type": "string"
+                },
+                "polygon": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "type": "number"
+                        }
+                    }
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -0,0 +1,53 @@
+package embedding
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"app/internal/core/ports"
+)
+
+// HTTPProvider posts the raw image to an inference service that replies
+// with {"embedding": [...]}.
+type HTTPProvider struct {
+	url    string
+	client *http.Client
+}
+
+func NewHTTPProvider(url string) ports.EmbeddingProvider {
+	return &HTTPProvider{
+		url:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (p *HTTPProvider) Embed(ctx context.Context, image io.Reader) ([]float32, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, image)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("embedding request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("embedding service returned %d: %s", resp.StatusCode, body)
+	}
+
+	var out struct {
+		Embedding []float32 `json:"embedding"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("invalid embedding response: %w", err)
+	}
+	return out.Embedding, nil
+}
@@ -0,0 +1,136 @@
+package faceindex
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+
+	"github.com/google/uuid"
+)
+
+type entry struct {
+	identityID uuid.UUID
+	vector     []float32 // L2-normalised
+}
+
+// MemoryIndex is a brute-force cosine-similarity index kept in process.
+// It is fine for tens of thousands of faces; beyond that use pgvector.
+type MemoryIndex struct {
+	mu      sync.RWMutex
+	entries map[uuid.UUID]entry
+}
+
+func NewMemoryIndex() *MemoryIndex {
+	return &MemoryIndex{entries: make(map[uuid.UUID]entry)}
+}
+
+// Load replaces the index content with the embeddings stored in the repository.
+func (m *MemoryIndex) Load(ctx context.Context, repo ports.IdentityFaceRepository) (int, error) {
+	faces, err := repo.ListEmbeddings(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	entries := make(map[uuid.UUID]entry, len(faces))
+	for _, f := range faces {
+		if v := normalize(f.Embedding); v != nil {
+			entries[f.ID] = entry{identityID: f.IdentityID, vector: v}
+		}
+	}
+
+	m.mu.Lock()
+	m.entries = entries
+	m.mu.Unlock()
+	return len(entries), nil
+}
+
+func (m *MemoryIndex) Upsert(ctx context.Context, face *domain.IdentityFace) error {
+	v := normalize(face.Embedding)
+	if v == nil {
+		return nil
+	}
+	m.mu.Lock()
+	m.entries[face.ID] = entry{identityID: face.IdentityID, vector: v}
+	m.mu.Unlock()
+	return nil
+}
+
+func (m *MemoryIndex) Remove(ctx context.Context, faceID uuid.UUID) error {
+	m.mu.Lock()
+	delete(m.entries, faceID)
+	m.mu.Unlock()
+	return nil
+}
+
+func (m *MemoryIndex) RemoveIdentity(ctx context.Context, identityID uuid.UUID) error {
+	m.mu.Lock()
+	for id, e := range m.entries {
+		if e.identityID == identityID {
+			delete(m.entries, id)
+		}
+	}
+	m.mu.Unlock()
+	return nil
+}
+
+// Search returns the best matching face per identity, highest score first.
+func (m *MemoryIndex) Search(ctx context.Context, embedding []float32, topK int, minScore float64) ([]*domain.FaceMatch, error) {
+	query := normalize(embedding)
+	if query == nil {
+		return nil, domain.ErrInvalidEmbedding
+	}
+
+	best := make(map[uuid.UUID]*domain.FaceMatch)
+	m.mu.RLock()
+	for faceID, e := range m.entries {
+		if len(e.vector) != len(query) {
+			continue
+		}
+		score := dot(query, e.vector)
+		if score < minScore {
+			continue
+		}
+		if cur, ok := best[e.identityID]; !ok || score > cur.Score {
+			best[e.identityID] = &domain.FaceMatch{IdentityID: e.identityID, FaceID: faceID, Score: score}
+		}
+	}
+	m.mu.RUnlock()
+
+	matches := make([]*domain.FaceMatch, 0, len(best))
+	for _, match := range best {
+		matches = append(matches, match)
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	if topK > 0 && len(matches) > topK {
+		matches = matches[:topK]
+	}
+	return matches, nil
+}
+
+func normalize(v []float32) []float32 {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	if sum == 0 {
+		return nil
+	}
+	norm := float32(math.Sqrt(sum))
+	out := make([]float32, len(v))
+	for i, x := range v {
+		out[i] = x / norm
+	}
+	return out
+}
+
+func dot(a, b []float32) float64 {
+	var sum float64
+	for i := range a {
+		sum += float64(a[i]) * float64(b[i])
+	}
+	return sum
+}
@@ -0,0 +1,332 @@
+package fieldcrypt
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+	"app/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// ciphertextPrefix marks encrypted values: enc:<key version>:<base64 nonce+sealed>.
+const ciphertextPrefix = "enc:"
+
+// lookupReplacer strips the separators people type inconsistently in phone
+// and ID numbers so blind-index lookups match regardless of formatting.
+var lookupReplacer = strings.NewReplacer(" ", "", ".", "", "-", "", "(", "", ")", "")
+
+// reloadInterval limits how often keys are reloaded from the database:
+// Encrypt reloads at most this often to pick up a rotation made by another
+// replica, and Decrypt when it meets an unknown key version.
+const reloadInterval = 5 * time.Second
+
+// EnvelopeCipher encrypts fields with AES-256-GCM under the tenant's data
+// keys, which are stored wrapped by the KMS master key.
+type EnvelopeCipher struct {
+	tenant string
+	kms    ports.KeyManager
+	repo   ports.DataKeyRepository
+
+	mu     sync.RWMutex
+	active int
+	keys   map[int]cipher.AEAD
+	index  []byte
+
+	// reloadMu lets one caller at a time reload keys another replica added
+	reloadMu   sync.Mutex
+	reloadedAt time.Time
+}
+
+// NewEnvelopeCipher loads the tenant's data keys, creating the first data and
+// blind-index keys on an empty database.
+func NewEnvelopeCipher(ctx context.Context, tenant string, kms ports.KeyManager, repo ports.DataKeyRepository) (*EnvelopeCipher, error) {
+	c := &EnvelopeCipher{tenant: tenant, kms: kms, repo: repo}
+	if err := c.load(ctx); err != nil {
+		return nil, err
+	}
+	c.reloadedAt = time.Now()
+	return c, nil
+}
+
+func (c *EnvelopeCipher) load(ctx context.Context) error {
+	stored, err := c.repo.ListKeys(ctx, c.tenant)
+	if err != nil {
+		return err
+	}
+
+	keys := make(map[int]cipher.AEAD)
+	active := 0
+	var index []byte
+	for _, k := range stored {
+		raw, err := c.kms.Unwrap(ctx, k.MasterKeyID, k.WrappedKey)
+		if err != nil {
+			return fmt.Errorf("failed to unwrap %s key v%d: %w", k.Purpose, k.Version, err)
+		}
+		switch k.Purpose {
+		case domain.DataKeyPurposeData:
+			aead, err := newGCM(raw)
+			if err != nil {
+				return err
+			}
+			keys[k.Version] = aead
+			if k.Status == domain.DataKeyStatusActive {
+				active = k.Version
+			}
+		case domain.DataKeyPurposeBlindIndex:
+			if k.Status == domain.DataKeyStatusActive {
+				index = raw
+			}
+		}
+	}
+
+	if active == 0 {
+		k, raw, err := c.createKey(ctx, domain.DataKeyPurposeData, len(keys)+1)
+		if err != nil {
+			return err
+		}
+		aead, err := newGCM(raw)
+		if err != nil {
+			return err
+		}
+		keys[k.Version] = aead
+		active = k.Version
+	}
+	// The blind-index key is never rotated: that would break every lookup
+	// until all rows were rehashed
+	if index == nil {
+		if _, index, err = c.createKey(ctx, domain.DataKeyPurposeBlindIndex, 1); err != nil {
+			return err
+		}
+	}
+
+	c.mu.Lock()
+	c.keys, c.active, c.index = keys, active, index
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *EnvelopeCipher) createKey(ctx context.Context, purpose string, version int) (*domain.DataKey, []byte, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, nil, err
+	}
+	wrapped, masterKeyID, err := c.kms.Wrap(ctx, raw)
+	if err != nil {
+		return nil, nil, err
+	}
+	key := &domain.DataKey{
+		Tenant:      c.tenant,
+		Purpose:     purpose,
+		Version:     version,
+		MasterKeyID: masterKeyID,
+		WrappedKey:  wrapped,
+		Status:      domain.DataKeyStatusActive,
+	}
+	if err := c.repo.CreateKey(ctx, key); err != nil {
+		return nil, nil, err
+	}
+	return key, raw, nil
+}
+
+func (c *EnvelopeCipher) Enabled() bool {
+	return true
+}
+
+func (c *EnvelopeCipher) Encrypt(plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+	c.refresh()
+	c.mu.RLock()
+	version, aead := c.active, c.keys[c.active]
+	c.mu.RUnlock()
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(c.tenant))
+	return ciphertextPrefix + strconv.Itoa(version) + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+func (c *EnvelopeCipher) Decrypt(value string) (string, error) {
+	version, payload, ok := parseCiphertext(value)
+	if !ok {
+		return value, nil
+	}
+	aead, err := c.key(version)
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := base64.RawStdEncoding.DecodeString(payload)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", errors.New("malformed ciphertext")
+	}
+	plain, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(c.tenant))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt field: %w", err)
+	}
+	return string(plain), nil
+}
+
+// refresh reloads the keys once reloadInterval has passed, so new values
+// are sealed under the key another replica rotated to. It never waits on a
+// reload already running, and a failed one leaves the current keys in use.
+func (c *EnvelopeCipher) refresh() {
+	if !c.reloadMu.TryLock() {
+		return
+	}
+	defer c.reloadMu.Unlock()
+	if time.Since(c.reloadedAt) < reloadInterval {
+		return
+	}
+	c.reloadedAt = time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := c.load(ctx); err != nil {
+		logger.Error("Failed to reload data keys", zap.String("tenant", c.tenant), zap.Error(err))
+	}
+}
+
+// key returns the data key for version. A version this process has not seen
+// was usually created by a rotation on another replica, so the keys are
+// reloaded once before giving up.
+func (c *EnvelopeCipher) key(version int) (cipher.AEAD, error) {
+	c.mu.RLock()
+	aead, found := c.keys[version]
+	c.mu.RUnlock()
+	if found {
+		return aead, nil
+	}
+
+	c.reloadMu.Lock()
+	defer c.reloadMu.Unlock()
+	// Another caller may have reloaded while this one waited
+	c.mu.RLock()
+	aead, found = c.keys[version]
+	c.mu.RUnlock()
+	if found {
+		return aead, nil
+	}
+	if time.Since(c.reloadedAt) >= reloadInterval {
+		c.reloadedAt = time.Now()
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := c.load(ctx); err != nil {
+			return nil, fmt.Errorf("%w: v%d: reloading keys: %v", domain.ErrUnknownDataKey, version, err)
+		}
+		c.mu.RLock()
+		aead, found = c.keys[version]
+		c.mu.RUnlock()
+		if found {
+			return aead, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: v%d", domain.ErrUnknownDataKey, version)
+}
+
+func (c *EnvelopeCipher) BlindIndex(value string) string {
+	value = strings.ToLower(lookupReplacer.Replace(strings.TrimSpace(value)))
+	if value == "" {
+		return ""
+	}
+	c.mu.RLock()
+	mac := hmac.New(sha256.New, c.index)
+	c.mu.RUnlock()
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (c *EnvelopeCipher) IsCurrent(value string) bool {
+	if value == "" {
+		return true
+	}
+	version, _, ok := parseCiphertext(value)
+	c.refresh()
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return ok && version == c.active
+}
+
+func (c *EnvelopeCipher) ListKeys(ctx context.Context) ([]*domain.DataKey, error) {
+	return c.repo.ListKeys(ctx, c.tenant)
+}
+
+// Rotate re-wraps keys still under an old master key and activates a new data
+// key. Existing ciphertext stays readable; a re-encryption job moves it over.
+func (c *EnvelopeCipher) Rotate(ctx context.Context) (*domain.DataKey, error) {
+	stored, err := c.repo.ListKeys(ctx, c.tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	latest := 0
+	for _, k := range stored {
+		if k.Purpose == domain.DataKeyPurposeData && k.Version > latest {
+			latest = k.Version
+		}
+		if k.MasterKeyID == c.kms.ActiveKeyID() {
+			continue
+		}
+		raw, err := c.kms.Unwrap(ctx, k.MasterKeyID, k.WrappedKey)
+		if err != nil {
+			return nil, err
+		}
+		wrapped, masterKeyID, err := c.kms.Wrap(ctx, raw)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.repo.UpdateWrappedKey(ctx, k.ID, masterKeyID, wrapped); err != nil {
+			return nil, err
+		}
+	}
+
+	key, _, err := c.createKey(ctx, domain.DataKeyPurposeData, latest+1)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.load(ctx); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func parseCiphertext(value string) (int, string, bool) {
+	rest, ok := strings.CutPrefix(value, ciphertextPrefix)
+	if !ok {
+		return 0, "", false
+	}
+	versionStr, payload, ok := strings.Cut(rest, ":")
+	if !ok {
+		return 0, "", false
+	}
+	version, err := strconv.Atoi(versionStr)
+	if err != nil {
+		return 0, "", false
+	}
+	return version, payload, true
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
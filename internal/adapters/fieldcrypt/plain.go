@@ -0,0 +1,40 @@
+package fieldcrypt
+
+import (
+	"fmt"
+
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+)
+
+// PlainCipher is used when encryption is disabled; values pass through as-is.
+type PlainCipher struct{}
+
+func NewPlainCipher() ports.FieldCipher {
+	return PlainCipher{}
+}
+
+func (PlainCipher) Enabled() bool {
+	return false
+}
+
+func (PlainCipher) Encrypt(plaintext string) (string, error) {
+	return plaintext, nil
+}
+
+// Decrypt refuses ciphertext rather than leaking it to clients as if it were
+// the real value.
+func (PlainCipher) Decrypt(value string) (string, error) {
+	if _, _, ok := parseCiphertext(value); ok {
+		return "", fmt.Errorf("%w: found encrypted value", domain.ErrEncryptionDisabled)
+	}
+	return value, nil
+}
+
+func (PlainCipher) BlindIndex(value string) string {
+	return ""
+}
+
+func (PlainCipher) IsCurrent(value string) bool {
+	return true
+}
@@ -0,0 +1,63 @@
+package http
+
+import (
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AccessRecorder adds the request context (actor, route, client) to read-audit
+// entries before handing them to the asynchronous access audit service.
+type AccessRecorder struct {
+	service ports.AccessAuditService
+}
+
+func NewAccessRecorder(service ports.AccessAuditService) *AccessRecorder {
+	return &AccessRecorder{service: service}
+}
+
+func (r *AccessRecorder) Record(c *gin.Context, log *domain.AccessLog) {
+	if userID, ok := currentUserID(c); ok {
+		log.UserID = &userID
+	}
+	log.Route = c.Request.Method + " " + c.FullPath()
+	log.IPAddress = c.ClientIP()
+	log.UserAgent = c.Request.UserAgent()
+	if log.Filters == nil {
+		log.Filters = queryFilters(c)
+	}
+	r.service.Record(log)
+}
+
+// queryFilters captures the query string a read was made with.
+func queryFilters(c *gin.Context) map[string]any {
+	query := c.Request.URL.Query()
+	if len(query) == 0 {
+		return nil
+	}
+	filters := make(map[string]any, len(query))
+	for k, v := range query {
+		if len(v) == 1 {
+			filters[k] = v[0]
+		} else {
+			filters[k] = v
+		}
+	}
+	return filters
+}
+
+// uniqueIdentityIDs drops nil and repeated IDs, keeping first-seen order.
+func uniqueIdentityIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	out := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if id == uuid.Nil || seen[id] {
+			continue
+		}
+		seen[id] = true
+		out = append(out, id)
+	}
+	return out
+}
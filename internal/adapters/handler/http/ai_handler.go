@@ -1,6 +1,7 @@
 package http
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
@@ -14,10 +15,11 @@ import (
 
 type AIHandler struct {
 	service ports.AIService
+	scoper  *CameraScoper
 }
 
-func NewAIHandler(service ports.AIService) *AIHandler {
-	return &AIHandler{service: service}
+func NewAIHandler(service ports.AIService, scoper *CameraScoper) *AIHandler {
+	return &AIHandler{service: service, scoper: scoper}
 }
 
 // GetConfig godoc
@@ -42,20 +44,30 @@ func (h *AIHandler) GetConfig(c *gin.Context) {
 	}
 	if config == nil {
 		// Return defaults or 404
-		c.JSON(http.StatusOK, domain.AIConfig{CameraID: cameraID, AIEnabled: false})
+		config = &domain.AIConfig{CameraID: cameraID}
+		config.Normalize()
+		c.JSON(http.StatusOK, config)
 		return
 	}
 
 	c.JSON(http.StatusOK, config)
 }
 
+// AIConfigErrorResponse names each invalid field of a rejected AI config.
+type AIConfigErrorResponse struct {
+	Error  string              `json:"error"`
+	Fields []domain.FieldError `json:"fields"`
+}
+
 // UpdateConfig godoc
 // @Summary Update AI configuration
+// @Description ROI polygons use normalized 0-1 frame coordinates and must not intersect themselves; active hours windows must not overlap.
 // @Tags ai
 // @Accept json
 // @Produce json
 // @Param request body domain.AIConfig true "AI Config Info"
 // @Success 200 {object} domain.AIConfig
+// @Failure 400 {object} AIConfigErrorResponse
 // @Router /ai-configs [post]
 func (h *AIHandler) UpdateConfig(c *gin.Context) {
 	var req domain.AIConfig
@@ -64,14 +76,123 @@ func (h *AIHandler) UpdateConfig(c *gin.Context) {
 		return
 	}
 
+	req.UpdatedBy = requestUserID(c)
+
 	if err := h.service.UpdateConfig(c.Request.Context(), &req); err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		respondAIConfigError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, req)
 }
 
+// ListConfigVersions godoc
+// @Summary List a camera's AI config versions
+// @Description Newest first. Every save, template apply and rollback adds a version.
+// @Tags ai
+// @Produce json
+// @Security BearerAuth
+// @Param cameraId path string true "Camera ID"
+// @Success 200 {array} domain.AIConfigVersion
+// @Router /ai-configs/camera/{cameraId}/versions [get]
+func (h *AIHandler) ListConfigVersions(c *gin.Context) {
+	cameraID, err := uuid.Parse(c.Param("cameraId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid Camera ID"})
+		return
+	}
+	versions, err := h.service.ListConfigVersions(c.Request.Context(), cameraID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, versions)
+}
+
+// GetConfigVersion godoc
+// @Summary Get one AI config version of a camera
+// @Tags ai
+// @Produce json
+// @Security BearerAuth
+// @Param cameraId path string true "Camera ID"
+// @Param version path int true "Version"
+// @Success 200 {object} domain.AIConfigVersion
+// @Failure 404 {object} ErrorResponse
+// @Router /ai-configs/camera/{cameraId}/versions/{version} [get]
+func (h *AIHandler) GetConfigVersion(c *gin.Context) {
+	cameraID, err := uuid.Parse(c.Param("cameraId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid Camera ID"})
+		return
+	}
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid version"})
+		return
+	}
+	v, err := h.service.GetConfigVersion(c.Request.Context(), cameraID, version)
+	if err != nil {
+		respondAIConfigError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, v)
+}
+
+// RollbackConfig godoc
+// @Summary Roll a camera's AI config back to a version
+// @Description Saves the version's settings and template link as a new version; history is never rewritten.
+// @Tags ai
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param cameraId path string true "Camera ID"
+// @Param request body ports.RollbackAIConfigRequest true "Version to restore"
+// @Success 200 {object} domain.AIConfig
+// @Failure 404 {object} ErrorResponse
+// @Router /ai-configs/camera/{cameraId}/rollback [post]
+func (h *AIHandler) RollbackConfig(c *gin.Context) {
+	cameraID, err := uuid.Parse(c.Param("cameraId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid Camera ID"})
+		return
+	}
+	var req ports.RollbackAIConfigRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	config, err := h.service.RollbackConfig(c.Request.Context(), cameraID, req.Version, requestUserID(c))
+	if err != nil {
+		respondAIConfigError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, config)
+}
+
+// DetachTemplate godoc
+// @Summary Unlink a camera's AI config from its template
+// @Description Keeps the camera's current settings and drops its overrides.
+// @Tags ai
+// @Produce json
+// @Security BearerAuth
+// @Param cameraId path string true "Camera ID"
+// @Success 200 {object} domain.AIConfig
+// @Failure 404 {object} ErrorResponse
+// @Router /ai-configs/camera/{cameraId}/template [delete]
+func (h *AIHandler) DetachTemplate(c *gin.Context) {
+	cameraID, err := uuid.Parse(c.Param("cameraId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid Camera ID"})
+		return
+	}
+	config, err := h.service.DetachTemplate(c.Request.Context(), cameraID, requestUserID(c))
+	if err != nil {
+		respondAIConfigError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, config)
+}
+
 // ListEvents godoc
 // @Summary List AI events
 // @Tags ai
@@ -136,12 +257,15 @@ func (h *AIHandler) ListEvents(c *gin.Context) {
 
 // UpdateEventStatus godoc
 // @Summary Update AI event status
+// @Description Sets the status and/or the true_positive / false_positive verdict used for training exports
 // @Tags ai
 // @Accept json
 // @Produce json
 // @Param id path string true "Event ID"
-// @Param request body object true "Status object"
+// @Param request body ports.UpdateEventRequest true "Status and verdict"
 // @Success 200 {object} domain.AIEvent
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
 // @Router /events/{id} [patch]
 func (h *AIHandler) UpdateEventStatus(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
@@ -150,22 +274,16 @@ func (h *AIHandler) UpdateEventStatus(c *gin.Context) {
 		return
 	}
 
-	var req struct {
-		Status string `json:"status" binding:"required"`
-	}
+	var req ports.UpdateEventRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
 		return
 	}
+	req.ResolvedBy = requestUserID(c)
 
-	var resolvedBy uuid.UUID
-	if val, exists := c.Get("userID"); exists {
-		resolvedBy, _ = uuid.Parse(val.(string))
-	}
-
-	event, err := h.service.UpdateEventStatus(c.Request.Context(), id, domain.EventStatus(req.Status), resolvedBy)
+	event, err := h.service.UpdateEventStatus(c.Request.Context(), id, &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		c.JSON(eventErrorStatus(err), ErrorResponse{Error: err.Error()})
 		return
 	}
 
@@ -174,16 +292,63 @@ func (h *AIHandler) UpdateEventStatus(c *gin.Context) {
 
 // GetDashboardStats godoc
 // @Summary Get dashboard statistics
+// @Description Counts the cameras and events the caller may see, rolled up over the subtree of zone_id when given.
 // @Tags ai
 // @Accept json
 // @Produce json
+// @Param zone_id query string false "Zone ID"
 // @Success 200 {object} domain.DashboardStats
+// @Failure 400 {object} ErrorResponse
 // @Router /stats/dashboard [get]
 func (h *AIHandler) GetDashboardStats(c *gin.Context) {
-	stats, err := h.service.GetDashboardStats(c.Request.Context())
+	scope, ok := h.scoper.Scope(c)
+	if !ok {
+		return
+	}
+	filter := &ports.DashboardFilter{Scope: scope}
+	if zid := c.Query("zone_id"); zid != "" {
+		zoneID, err := uuid.Parse(zid)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid zone_id"})
+			return
+		}
+		filter.ZoneID = &zoneID
+	}
+
+	stats, err := h.service.GetDashboardStats(c.Request.Context(), filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
 		return
 	}
 	c.JSON(http.StatusOK, stats)
 }
+
+// respondAIConfigError answers for both configs and templates, listing the
+// invalid fields of a rejected one.
+func respondAIConfigError(c *gin.Context, err error) {
+	var invalid *domain.AIConfigError
+	switch {
+	case errors.As(err, &invalid):
+		c.JSON(http.StatusBadRequest, AIConfigErrorResponse{Error: "Invalid AI config", Fields: invalid.Fields})
+	case errors.Is(err, domain.ErrAIConfigNotFound), errors.Is(err, domain.ErrAIConfigVersionNotFound),
+		errors.Is(err, domain.ErrAITemplateNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, domain.ErrAITemplateTargets), errors.Is(err, domain.ErrAITemplateOverride):
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, domain.ErrAITemplateNameTaken):
+		c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	}
+}
+
+func eventErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrEventNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrInvalidEventVerdict), errors.Is(err, domain.ErrEventUpdateEmpty):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,196 @@
+package http
+
+import (
+	"net/http"
+
+	"app/internal/core/ports"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type AITemplateHandler struct {
+	service ports.AITemplateService
+}
+
+func NewAITemplateHandler(service ports.AITemplateService) *AITemplateHandler {
+	return &AITemplateHandler{service: service}
+}
+
+// CreateTemplate godoc
+// @Summary Create an AI config template
+// @Tags ai-templates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param template body ports.AITemplateRequest true "Template"
+// @Success 201 {object} domain.AIConfigTemplate
+// @Failure 400 {object} AIConfigErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /ai-config-templates [post]
+func (h *AITemplateHandler) CreateTemplate(c *gin.Context) {
+	var req ports.AITemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	req.RequestedBy = requestUserID(c)
+	template, err := h.service.CreateTemplate(c.Request.Context(), &req)
+	if err != nil {
+		respondAIConfigError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, template)
+}
+
+// ListTemplates godoc
+// @Summary List AI config templates
+// @Tags ai-templates
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} domain.AIConfigTemplate
+// @Router /ai-config-templates [get]
+func (h *AITemplateHandler) ListTemplates(c *gin.Context) {
+	templates, err := h.service.ListTemplates(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, templates)
+}
+
+// GetTemplate godoc
+// @Summary Get an AI config template
+// @Tags ai-templates
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Template ID"
+// @Success 200 {object} domain.AIConfigTemplate
+// @Failure 404 {object} ErrorResponse
+// @Router /ai-config-templates/{id} [get]
+func (h *AITemplateHandler) GetTemplate(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid ID"})
+		return
+	}
+	template, err := h.service.GetTemplate(c.Request.Context(), id)
+	if err != nil {
+		respondAIConfigError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, template)
+}
+
+// UpdateTemplate godoc
+// @Summary Update an AI config template
+// @Description Cameras the template was applied to are not changed; they show as drifting until it is applied again.
+// @Tags ai-templates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Template ID"
+// @Param template body ports.AITemplateRequest true "Template"
+// @Success 200 {object} domain.AIConfigTemplate
+// @Failure 400 {object} AIConfigErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /ai-config-templates/{id} [put]
+func (h *AITemplateHandler) UpdateTemplate(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid ID"})
+		return
+	}
+	var req ports.AITemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	req.RequestedBy = requestUserID(c)
+	template, err := h.service.UpdateTemplate(c.Request.Context(), id, &req)
+	if err != nil {
+		respondAIConfigError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, template)
+}
+
+// DeleteTemplate godoc
+// @Summary Delete an AI config template
+// @Description Linked cameras keep their settings and are unlinked.
+// @Tags ai-templates
+// @Security BearerAuth
+// @Param id path string true "Template ID"
+// @Success 204
+// @Failure 404 {object} ErrorResponse
+// @Router /ai-config-templates/{id} [delete]
+func (h *AITemplateHandler) DeleteTemplate(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid ID"})
+		return
+	}
+	if err := h.service.DeleteTemplate(c.Request.Context(), id, requestUserID(c)); err != nil {
+		respondAIConfigError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ApplyTemplate godoc
+// @Summary Apply an AI config template to cameras
+// @Description Applies the template to the listed cameras and every camera in the zone's subtree, with per-camera overrides keyed by camera ID. Each changed camera gets a new config version; invalid results are reported per camera.
+// @Tags ai-templates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Template ID"
+// @Param request body ports.ApplyAITemplateRequest true "Targets and overrides"
+// @Success 200 {object} domain.AITemplateApplyResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /ai-config-templates/{id}/apply [post]
+func (h *AITemplateHandler) ApplyTemplate(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid ID"})
+		return
+	}
+	var req ports.ApplyAITemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	req.RequestedBy = requestUserID(c)
+	result, err := h.service.ApplyTemplate(c.Request.Context(), id, &req)
+	if err != nil {
+		respondAIConfigError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// TemplateDrift godoc
+// @Summary Show cameras drifting from an AI config template
+// @Description Lists every camera linked to the template, drifted ones first, with the fields where its config differs from the template plus its overrides.
+// @Tags ai-templates
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Template ID"
+// @Success 200 {array} domain.AIConfigDrift
+// @Failure 404 {object} ErrorResponse
+// @Router /ai-config-templates/{id}/drift [get]
+func (h *AITemplateHandler) TemplateDrift(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid ID"})
+		return
+	}
+	drift, err := h.service.TemplateDrift(c.Request.Context(), id)
+	if err != nil {
+		respondAIConfigError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, drift)
+}
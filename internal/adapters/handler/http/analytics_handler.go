@@ -14,10 +14,11 @@ import (
 
 type AnalyticsHandler struct {
 	service ports.AnalyticsService
+	access  *AccessRecorder
 }
 
-func NewAnalyticsHandler(service ports.AnalyticsService) *AnalyticsHandler {
-	return &AnalyticsHandler{service: service}
+func NewAnalyticsHandler(service ports.AnalyticsService, access *AccessRecorder) *AnalyticsHandler {
+	return &AnalyticsHandler{service: service, access: access}
 }
 
 // ListRecognitionLogs godoc
@@ -27,6 +28,7 @@ func NewAnalyticsHandler(service ports.AnalyticsService) *AnalyticsHandler {
 // @Produce json
 // @Param identity_id query string false "Identity ID"
 // @Param camera_id query string false "Camera ID"
+// @Param strangers query bool false "Only logs of unrecognized faces"
 // @Param from query string false "From Date (RFC3339)"
 // @Param to query string false "To Date (RFC3339)"
 // @Param limit query int false "Limit"
@@ -48,6 +50,7 @@ func (h *AnalyticsHandler) ListRecognitionLogs(c *gin.Context) {
 			filter.CameraID = &uid
 		}
 	}
+	filter.StrangersOnly = c.Query("strangers") == "true"
 	if from := c.Query("from"); from != "" {
 		if t, err := time.Parse(time.RFC3339, from); err == nil {
 			filter.FromDate = &t
@@ -69,6 +72,17 @@ func (h *AnalyticsHandler) ListRecognitionLogs(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
 		return
 	}
+
+	ids := make([]uuid.UUID, 0, len(logs))
+	for _, l := range logs {
+		if l.IdentityID != nil {
+			ids = append(ids, *l.IdentityID)
+		}
+	}
+	h.access.Record(c, &domain.AccessLog{
+		ResourceType: domain.AccessResourceRecognitionLog,
+		IdentityIDs:  uniqueIdentityIDs(ids),
+	})
 	c.JSON(http.StatusOK, logs)
 }
 
@@ -102,9 +116,76 @@ func (h *AnalyticsHandler) ListAttendance(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
 		return
 	}
+
+	ids := make([]uuid.UUID, len(records))
+	for i, r := range records {
+		ids[i] = r.IdentityID
+	}
+	h.access.Record(c, &domain.AccessLog{
+		ResourceType: domain.AccessResourceAttendanceRecord,
+		IdentityIDs:  uniqueIdentityIDs(ids),
+	})
 	c.JSON(http.StatusOK, records)
 }
 
+// GetTimeline godoc
+// @Summary Get a person's movements across cameras
+// @Description Recognitions merged into visits: consecutive sightings on one camera at most gap_minutes apart form a dwell interval. Includes snapshots, zone transitions and time spent per zone.
+// @Tags identities
+// @Produce json
+// @Param id path string true "Identity ID"
+// @Param from query string false "From (RFC3339), default 7 days before to"
+// @Param to query string false "To (RFC3339), default now"
+// @Param gap_minutes query int false "Max minutes between sightings of one visit (default 10)"
+// @Success 200 {object} domain.PersonTimeline
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /identities/{id}/timeline [get]
+func (h *AnalyticsHandler) GetTimeline(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid ID format"})
+		return
+	}
+
+	filter := &ports.TimelineFilter{}
+	if from := c.Query("from"); from != "" {
+		t, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid from date"})
+			return
+		}
+		filter.FromDate = &t
+	}
+	if to := c.Query("to"); to != "" {
+		t, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid to date"})
+			return
+		}
+		filter.ToDate = &t
+	}
+	if g := c.Query("gap_minutes"); g != "" {
+		if val, err := strconv.Atoi(g); err == nil {
+			filter.MaxGap = time.Duration(val) * time.Minute
+		}
+	}
+
+	timeline, err := h.service.GetTimeline(c.Request.Context(), id, filter)
+	if err != nil {
+		c.JSON(identityErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	h.access.Record(c, &domain.AccessLog{
+		ResourceType: domain.AccessResourceIdentityTimeline,
+		ResourceID:   id.String(),
+		IdentityIDs:  []uuid.UUID{id},
+	})
+	c.JSON(http.StatusOK, timeline)
+}
+
 // GetSummary godoc
 // @Summary Get daily attendance summary
 // @Tags analytics
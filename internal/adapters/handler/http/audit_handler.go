@@ -3,7 +3,9 @@ package http
 import (
 	"net/http"
 	"strconv"
+	"time"
 
+	"app/internal/core/domain"
 	"app/internal/core/ports"
 
 	"github.com/gin-gonic/gin"
@@ -12,10 +14,12 @@ import (
 
 type AuditHandler struct {
 	service ports.AuditService
+	access  ports.AccessAuditService
+	pii     *PIIPresenter
 }
 
-func NewAuditHandler(service ports.AuditService) *AuditHandler {
-	return &AuditHandler{service: service}
+func NewAuditHandler(service ports.AuditService, access ports.AccessAuditService, pii *PIIPresenter) *AuditHandler {
+	return &AuditHandler{service: service, access: access, pii: pii}
 }
 
 // ListLogs godoc
@@ -59,3 +63,74 @@ func (h *AuditHandler) ListLogs(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, logs)
 }
+
+// IdentityAccessReport godoc
+// @Summary Who accessed an identity
+// @Description Lists the users who viewed an identity, its faces, recognition or attendance records, or exported it, with a per-user summary. Reads are written asynchronously, so the last few seconds may be missing. Requires the identities:pii:read permission.
+// @Tags audit
+// @Produce json
+// @Param id path string true "Identity ID"
+// @Param from query string false "From Date (RFC3339)"
+// @Param to query string false "To Date (RFC3339)"
+// @Param user_id query string false "Only entries by this user"
+// @Param limit query int false "Limit"
+// @Param offset query int false "Offset"
+// @Success 200 {object} domain.IdentityAccessReport
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /identities/{id}/access-report [get]
+func (h *AuditHandler) IdentityAccessReport(c *gin.Context) {
+	if !h.pii.Allowed(c, domain.PermissionIdentityPIIRead) {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: domain.ErrPermissionDenied.Error()})
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid ID format"})
+		return
+	}
+
+	filter := &ports.AccessLogFilter{
+		Limit:  100,
+		Offset: 0,
+	}
+	if uid := c.Query("user_id"); uid != "" {
+		if userID, err := uuid.Parse(uid); err == nil {
+			filter.UserID = &userID
+		}
+	}
+	if from := c.Query("from"); from != "" {
+		t, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid from date"})
+			return
+		}
+		filter.FromDate = &t
+	}
+	if to := c.Query("to"); to != "" {
+		t, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid to date"})
+			return
+		}
+		filter.ToDate = &t
+	}
+	if l := c.Query("limit"); l != "" {
+		if val, err := strconv.Atoi(l); err == nil {
+			filter.Limit = int32(val)
+		}
+	}
+	if o := c.Query("offset"); o != "" {
+		if val, err := strconv.Atoi(o); err == nil {
+			filter.Offset = int32(val)
+		}
+	}
+
+	report, err := h.access.IdentityAccessReport(c.Request.Context(), id, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -1,6 +1,7 @@
 package http
 
 import (
+	"errors"
 	"net/http"
 
 	"app/internal/core/domain"
@@ -11,17 +12,21 @@ import (
 
 type CameraHandler struct {
 	service ports.CameraService
+	pii     *PIIPresenter
+	scoper  *CameraScoper
 }
 
-func NewCameraHandler(service ports.CameraService) *CameraHandler {
+func NewCameraHandler(service ports.CameraService, pii *PIIPresenter, scoper *CameraScoper) *CameraHandler {
 	return &CameraHandler{
 		service: service,
+		pii:     pii,
+		scoper:  scoper,
 	}
 }
 
 // CreateCamera godoc
 // @Summary Create a new camera
-// @Description Create a new camera in the system
+// @Description Create a new camera in the system. Credentials embedded in rtsp_url are stored encrypted and removed from the URL.
 // @Tags cameras
 // @Accept json
 // @Produce json
@@ -40,6 +45,10 @@ func (h *CameraHandler) CreateCamera(c *gin.Context) {
 	}
 
 	camera, err := h.service.CreateCamera(c.Request.Context(), &req)
+	if errors.Is(err, domain.ErrInvalidStreamURL) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create camera"})
 		return
@@ -53,7 +62,7 @@ func (h *CameraHandler) CreateCamera(c *gin.Context) {
 
 // ListCameras godoc
 // @Summary List cameras
-// @Description List cameras, optionally filtered by zone_id
+// @Description List the cameras the caller may see, optionally filtered by zone_id. A zone filter includes the zones below it.
 // @Tags cameras
 // @Produce json
 // @Param zone_id query string false "Filter by Zone ID"
@@ -62,9 +71,15 @@ func (h *CameraHandler) CreateCamera(c *gin.Context) {
 // @Security BearerAuth
 // @Router /cameras [get]
 func (h *CameraHandler) ListCameras(c *gin.Context) {
-	zoneID := c.Query("zone_id")
-	search := c.Query("q")
-	cameras, err := h.service.ListCameras(c.Request.Context(), zoneID, search)
+	scope, ok := h.scoper.Scope(c)
+	if !ok {
+		return
+	}
+	cameras, err := h.service.ListCameras(c.Request.Context(), &ports.CameraFilter{
+		ZoneID: c.Query("zone_id"),
+		Search: c.Query("q"),
+		Scope:  scope,
+	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -82,8 +97,12 @@ func (h *CameraHandler) ListCameras(c *gin.Context) {
 // @Security BearerAuth
 // @Router /cameras/{id} [get]
 func (h *CameraHandler) GetCamera(c *gin.Context) {
+	scope, ok := h.scoper.Scope(c)
+	if !ok {
+		return
+	}
 	id := c.Param("id")
-	camera, err := h.service.GetCamera(c.Request.Context(), id)
+	camera, err := h.service.GetCamera(c.Request.Context(), id, scope)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -103,6 +122,7 @@ func (h *CameraHandler) GetCamera(c *gin.Context) {
 // @Param id path string true "Camera ID"
 // @Param camera body domain.UpdateCameraRequest true "Update Info"
 // @Success 200 {object} domain.Camera
+// @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Security BearerAuth
 // @Router /cameras/{id} [put]
@@ -114,6 +134,10 @@ func (h *CameraHandler) UpdateCamera(c *gin.Context) {
 		return
 	}
 	camera, err := h.service.UpdateCamera(c.Request.Context(), id, &req)
+	if errors.Is(err, domain.ErrInvalidStreamURL) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -140,3 +164,35 @@ func (h *CameraHandler) DeleteCamera(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "Camera deleted"})
 }
+
+// RevealCredentials godoc
+// @Summary Reveal camera credentials
+// @Description Returns the stored RTSP username and password and the full stream URL. Requires the cameras:credentials:reveal permission; every reveal is audited.
+// @Tags cameras
+// @Produce json
+// @Param id path string true "Camera ID"
+// @Success 200 {object} domain.CameraCredentials
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /cameras/{id}/credentials/reveal [post]
+func (h *CameraHandler) RevealCredentials(c *gin.Context) {
+	if !h.pii.Allowed(c, domain.PermissionCameraCredentials) {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: domain.ErrPermissionDenied.Error()})
+		return
+	}
+	creds, err := h.service.RevealCredentials(c.Request.Context(), c.Param("id"), requestUserID(c))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, domain.ErrCameraNotFound) {
+			status = http.StatusNotFound
+		} else if errors.Is(err, domain.ErrCameraCredentialsUnreadable) {
+			status = http.StatusConflict
+		}
+		c.JSON(status, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, creds)
+}
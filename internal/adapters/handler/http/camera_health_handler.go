@@ -0,0 +1,90 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type CameraHealthHandler struct {
+	service ports.CameraHealthService
+}
+
+func NewCameraHealthHandler(service ports.CameraHealthService) *CameraHealthHandler {
+	return &CameraHealthHandler{service: service}
+}
+
+// ListStatusHistory godoc
+// @Summary List a camera's status transitions
+// @Description Changes made by the health monitor and by hand, newest first
+// @Tags cameras
+// @Produce json
+// @Param id path string true "Camera ID"
+// @Param from query string false "From (RFC3339)"
+// @Param to query string false "To (RFC3339)"
+// @Param page query int false "Page number"
+// @Param limit query int false "Limit"
+// @Success 200 {object} PaginatedResponse{data=[]domain.CameraStatusChange}
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /cameras/{id}/status-history [get]
+func (h *CameraHealthHandler) ListStatusHistory(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid ID format"})
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+
+	filter := &ports.CameraStatusHistoryFilter{
+		CameraID: id,
+		Limit:    int32(limit),
+		Offset:   int32((page - 1) * limit),
+	}
+	if from := c.Query("from"); from != "" {
+		if t, err := time.Parse(time.RFC3339, from); err == nil {
+			filter.FromDate = &t
+		}
+	}
+	if to := c.Query("to"); to != "" {
+		if t, err := time.Parse(time.RFC3339, to); err == nil {
+			filter.ToDate = &t
+		}
+	}
+
+	changes, total, err := h.service.ListStatusHistory(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(cameraErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, PaginatedResponse{
+		Data:  changes,
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	})
+}
+
+func cameraErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrCameraNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,40 @@
+package http
+
+import (
+	"net/http"
+
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+	"app/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// CameraScoper works out which cameras the current user may see from the
+// camera and zone grants made through /permissions. Users holding
+// cameras:all, and users with no grants at all, are unrestricted.
+type CameraScoper struct {
+	pii   *PIIPresenter
+	perms ports.PermissionService
+}
+
+func NewCameraScoper(pii *PIIPresenter, perms ports.PermissionService) *CameraScoper {
+	return &CameraScoper{pii: pii, perms: perms}
+}
+
+// Scope fails closed: when the grants cannot be loaded it responds with an
+// error and returns false.
+func (s *CameraScoper) Scope(c *gin.Context) (*domain.CameraScope, bool) {
+	if s.pii.Allowed(c, domain.PermissionCamerasAll) {
+		return nil, true
+	}
+	userID, _ := currentUserID(c)
+	scope, err := s.perms.CameraScope(c.Request.Context(), userID)
+	if err != nil {
+		logger.Error("Failed to load camera scope", zap.String("user_id", userID.String()), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return nil, false
+	}
+	return scope, true
+}
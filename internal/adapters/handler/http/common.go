@@ -13,6 +13,10 @@ type PaginatedResponse struct {
 	Limit int         `json:"limit"`
 }
 
+type ReviewReasonRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
 type IdentityResponse struct {
 	Data domain.Identity `json:"data"`
 }
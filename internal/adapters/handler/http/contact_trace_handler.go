@@ -0,0 +1,158 @@
+package http
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ContactTraceHandler struct {
+	service ports.ContactTraceService
+	pii     *PIIPresenter
+	access  *AccessRecorder
+}
+
+func NewContactTraceHandler(service ports.ContactTraceService, pii *PIIPresenter, access *AccessRecorder) *ContactTraceHandler {
+	return &ContactTraceHandler{service: service, pii: pii, access: access}
+}
+
+// TraceContacts godoc
+// @Summary List who was co-located with an identity
+// @Description Identities seen in the same zone (or on the same camera when it has no zone) within window_minutes of the identity's sightings, ranked by overlap duration then number of encounters. Requires the contacts:trace permission.
+// @Tags identities
+// @Produce json
+// @Param id path string true "Identity ID"
+// @Param from query string false "From (RFC3339), default lookback_days before to"
+// @Param to query string false "To (RFC3339), default now"
+// @Param window_minutes query int false "Max minutes between two sightings that count as contact"
+// @Param min_encounters query int false "Drop contacts with fewer encounters"
+// @Param limit query int false "Top contacts to return"
+// @Success 200 {object} domain.ContactTrace
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /identities/{id}/contacts [get]
+func (h *ContactTraceHandler) TraceContacts(c *gin.Context) {
+	trace, ok := h.trace(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, trace)
+}
+
+// ExportContacts godoc
+// @Summary Export a contact trace as CSV
+// @Description One row per contact with the same filters and ranking as the JSON trace. Requires the contacts:trace permission.
+// @Tags identities
+// @Produce text/csv
+// @Param id path string true "Identity ID"
+// @Param from query string false "From (RFC3339), default lookback_days before to"
+// @Param to query string false "To (RFC3339), default now"
+// @Param window_minutes query int false "Max minutes between two sightings that count as contact"
+// @Param min_encounters query int false "Drop contacts with fewer encounters"
+// @Param limit query int false "Top contacts to return"
+// @Success 200 {file} file
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /identities/{id}/contacts/export [get]
+func (h *ContactTraceHandler) ExportContacts(c *gin.Context) {
+	trace, ok := h.trace(c)
+	if !ok {
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=contacts-%s-%s.csv", trace.IdentityID, time.Now().Format("20060102")))
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	_ = w.Write([]string{"rank", "identity_id", "code", "full_name", "encounters", "overlap_minutes", "first_contact_at", "last_contact_at", "zones"})
+	for i, contact := range trace.Contacts {
+		_ = w.Write([]string{
+			strconv.Itoa(i + 1),
+			contact.IdentityID.String(),
+			contact.Code,
+			contact.FullName,
+			strconv.Itoa(contact.Encounters),
+			strconv.FormatFloat(contact.OverlapSeconds/60, 'f', 1, 64),
+			contact.FirstContactAt.Format(time.RFC3339),
+			contact.LastContactAt.Format(time.RFC3339),
+			strings.Join(contact.Zones, "; "),
+		})
+	}
+	w.Flush()
+}
+
+// trace runs the permission check and the trace, and records the access.
+// It writes the error response itself and reports whether to continue.
+func (h *ContactTraceHandler) trace(c *gin.Context) (*domain.ContactTrace, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid ID format"})
+		return nil, false
+	}
+	if !h.pii.Allowed(c, domain.PermissionContactTrace) {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: domain.ErrPermissionDenied.Error()})
+		return nil, false
+	}
+
+	filter := &ports.ContactTraceFilter{RequestedBy: requestUserID(c)}
+	if from := c.Query("from"); from != "" {
+		t, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid from date"})
+			return nil, false
+		}
+		filter.FromDate = &t
+	}
+	if to := c.Query("to"); to != "" {
+		t, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid to date"})
+			return nil, false
+		}
+		filter.ToDate = &t
+	}
+	if w := c.Query("window_minutes"); w != "" {
+		if val, err := strconv.Atoi(w); err == nil {
+			filter.Window = time.Duration(val) * time.Minute
+		}
+	}
+	filter.MinEncounters, _ = strconv.Atoi(c.Query("min_encounters"))
+	filter.Limit, _ = strconv.Atoi(c.Query("limit"))
+
+	trace, err := h.service.TraceContacts(c.Request.Context(), id, filter)
+	if err != nil {
+		c.JSON(identityErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return nil, false
+	}
+
+	ids := []uuid.UUID{id}
+	for _, contact := range trace.Contacts {
+		ids = append(ids, contact.IdentityID)
+	}
+	h.access.Record(c, &domain.AccessLog{
+		ResourceType: domain.AccessResourceContactTrace,
+		ResourceID:   id.String(),
+		IdentityIDs:  uniqueIdentityIDs(ids),
+		Filters: map[string]any{
+			"from":           trace.From,
+			"to":             trace.To,
+			"window_seconds": trace.WindowSeconds,
+			"min_encounters": filter.MinEncounters,
+			"limit":          filter.Limit,
+		},
+	})
+	return trace, true
+}
@@ -0,0 +1,53 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DatasetHandler struct {
+	service ports.DatasetService
+}
+
+func NewDatasetHandler(service ports.DatasetService) *DatasetHandler {
+	return &DatasetHandler{service: service}
+}
+
+// ExportDataset godoc
+// @Summary Export reviewed detections as a training dataset
+// @Description Builds a ZIP of snapshots with COCO annotations and/or YOLO labels in the background, split into train and val. AI events need a verdict; recognitions need a review. False positives become images without boxes. The archive URL is set as the job's result_url.
+// @Tags datasets
+// @Accept json
+// @Produce json
+// @Param request body ports.DatasetExportRequest true "Filters, format and split"
+// @Success 202 {object} domain.Job
+// @Failure 400 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /datasets/export [post]
+func (h *DatasetHandler) ExportDataset(c *gin.Context) {
+	var req ports.DatasetExportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	req.RequestedBy = requestUserID(c)
+
+	job, err := h.service.StartExport(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(datasetErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, job)
+}
+
+func datasetErrorStatus(err error) int {
+	if errors.Is(err, domain.ErrInvalidDatasetFormat) || errors.Is(err, domain.ErrInvalidValRatio) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,111 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type DiscoveryHandler struct {
+	service ports.DiscoveryService
+}
+
+func NewDiscoveryHandler(service ports.DiscoveryService) *DiscoveryHandler {
+	return &DiscoveryHandler{service: service}
+}
+
+// StartDiscovery godoc
+// @Summary Discover ONVIF cameras on the local network
+// @Description Starts a background job that sends a WS-Discovery probe, then reads device information and media profiles, with proposed RTSP URLs, from each device that answers. Poll GET /jobs/{id} and list the devices once it completes. The credentials are used to query devices and are not stored.
+// @Tags cameras
+// @Accept json
+// @Produce json
+// @Param request body ports.DiscoveryRequest false "Device credentials"
+// @Success 202 {object} domain.Job
+// @Failure 400 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /cameras/discovery [post]
+func (h *DiscoveryHandler) StartDiscovery(c *gin.Context) {
+	var req ports.DiscoveryRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+	}
+	req.RequestedBy = requestUserID(c)
+
+	job, err := h.service.StartDiscovery(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(discoveryErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, job)
+}
+
+// ListDiscoveredDevices godoc
+// @Summary List devices found by a discovery job
+// @Tags cameras
+// @Produce json
+// @Param job_id path string true "Discovery job ID"
+// @Success 200 {array} domain.DiscoveredDevice
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /cameras/discovery/{job_id}/devices [get]
+func (h *DiscoveryHandler) ListDiscoveredDevices(c *gin.Context) {
+	jobID, err := uuid.Parse(c.Param("job_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid job ID"})
+		return
+	}
+	devices, err := h.service.ListDevices(c.Request.Context(), jobID)
+	if err != nil {
+		c.JSON(discoveryErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, devices)
+}
+
+// AdoptDevices godoc
+// @Summary Adopt discovered devices as cameras
+// @Description Creates one camera per device from the chosen profile's stream URI, all in the given zone. Devices that cannot be adopted are listed in errors; the rest are still created.
+// @Tags cameras
+// @Accept json
+// @Produce json
+// @Param request body ports.AdoptDevicesRequest true "Devices to adopt"
+// @Success 200 {object} ports.AdoptDevicesResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /cameras/discovery/adopt [post]
+func (h *DiscoveryHandler) AdoptDevices(c *gin.Context) {
+	var req ports.AdoptDevicesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	req.RequestedBy = requestUserID(c)
+
+	result, err := h.service.AdoptDevices(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(discoveryErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func discoveryErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrJobNotFound), errors.Is(err, domain.ErrZoneNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrNoDevicesToAdopt):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,280 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const floorPlanMaxBytes = 20 << 20
+
+type FloorPlanHandler struct {
+	service ports.FloorPlanService
+	scoper  *CameraScoper
+}
+
+func NewFloorPlanHandler(service ports.FloorPlanService, scoper *CameraScoper) *FloorPlanHandler {
+	return &FloorPlanHandler{service: service, scoper: scoper}
+}
+
+// UploadFloorPlan godoc
+// @Summary Upload a zone's floor plan
+// @Description Sets or replaces the zone's plan image. Plan coordinates are image pixels from the top-left corner; existing placements and areas keep theirs.
+// @Tags floor-plans
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Zone ID"
+// @Param image formData file true "PNG, JPEG or GIF image"
+// @Success 200 {object} domain.FloorPlan
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Router /zones/{id}/floor-plan [put]
+func (h *FloorPlanHandler) UploadFloorPlan(c *gin.Context) {
+	zoneID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid ID"})
+		return
+	}
+	file, _, err := c.Request.FormFile("image")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "No image provided"})
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, floorPlanMaxBytes+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	if len(data) > floorPlanMaxBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, ErrorResponse{Error: "Image is too large"})
+		return
+	}
+
+	plan, err := h.service.UploadPlan(c.Request.Context(), &ports.UploadFloorPlanRequest{
+		ZoneID:     zoneID,
+		Image:      data,
+		UploadedBy: requestUserID(c),
+	})
+	if err != nil {
+		c.JSON(floorPlanErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, plan)
+}
+
+// GetFloorPlan godoc
+// @Summary Get a zone's floor plan
+// @Tags floor-plans
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Zone ID"
+// @Success 200 {object} domain.FloorPlan
+// @Failure 404 {object} ErrorResponse
+// @Router /zones/{id}/floor-plan [get]
+func (h *FloorPlanHandler) GetFloorPlan(c *gin.Context) {
+	zoneID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid ID"})
+		return
+	}
+	plan, err := h.service.GetPlan(c.Request.Context(), zoneID)
+	if err != nil {
+		c.JSON(floorPlanErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, plan)
+}
+
+// DeleteFloorPlan godoc
+// @Summary Delete a zone's floor plan
+// @Description Removes the plan image along with every placement and area drawn on it.
+// @Tags floor-plans
+// @Security BearerAuth
+// @Param id path string true "Zone ID"
+// @Success 204
+// @Failure 404 {object} ErrorResponse
+// @Router /zones/{id}/floor-plan [delete]
+func (h *FloorPlanHandler) DeleteFloorPlan(c *gin.Context) {
+	zoneID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid ID"})
+		return
+	}
+	if err := h.service.DeletePlan(c.Request.Context(), zoneID, requestUserID(c)); err != nil {
+		c.JSON(floorPlanErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetZoneMap godoc
+// @Summary Get a zone's floor plan map
+// @Description Returns the plan's zone areas, cameras and their field-of-view cones as a GeoJSON FeatureCollection in plan coordinates, with AI event counts over the window. Cameras outside the caller's grants are left out.
+// @Tags floor-plans
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Zone ID"
+// @Param window_minutes query int false "Event count window in minutes (default 60)"
+// @Success 200 {object} domain.FloorPlanMap
+// @Failure 404 {object} ErrorResponse
+// @Router /zones/{id}/map [get]
+func (h *FloorPlanHandler) GetZoneMap(c *gin.Context) {
+	zoneID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid ID"})
+		return
+	}
+	var window time.Duration
+	if v := c.Query("window_minutes"); v != "" {
+		minutes, err := strconv.Atoi(v)
+		if err != nil || minutes <= 0 {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "window_minutes must be a positive whole number"})
+			return
+		}
+		window = time.Duration(minutes) * time.Minute
+	}
+	scope, ok := h.scoper.Scope(c)
+	if !ok {
+		return
+	}
+
+	m, err := h.service.Map(c.Request.Context(), zoneID, window, scope)
+	if err != nil {
+		c.JSON(floorPlanErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, m)
+}
+
+// SetZoneArea godoc
+// @Summary Draw a zone on a floor plan
+// @Description Outlines the zone as a polygon on the plan of a zone above it, by default its parent.
+// @Tags floor-plans
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Zone ID"
+// @Param area body ports.ZoneAreaRequest true "Polygon in plan coordinates"
+// @Success 200 {object} domain.ZoneArea
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /zones/{id}/area [put]
+func (h *FloorPlanHandler) SetZoneArea(c *gin.Context) {
+	zoneID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid ID"})
+		return
+	}
+	var req ports.ZoneAreaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	area, err := h.service.SetZoneArea(c.Request.Context(), zoneID, &req)
+	if err != nil {
+		c.JSON(floorPlanErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, area)
+}
+
+// RemoveZoneArea godoc
+// @Summary Remove a zone's floor plan area
+// @Tags floor-plans
+// @Security BearerAuth
+// @Param id path string true "Zone ID"
+// @Success 204
+// @Failure 404 {object} ErrorResponse
+// @Router /zones/{id}/area [delete]
+func (h *FloorPlanHandler) RemoveZoneArea(c *gin.Context) {
+	zoneID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid ID"})
+		return
+	}
+	if err := h.service.RemoveZoneArea(c.Request.Context(), zoneID); err != nil {
+		c.JSON(floorPlanErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// PlaceCamera godoc
+// @Summary Place a camera on a floor plan
+// @Description Sets the camera's position, heading (degrees clockwise from plan up), field of view and range on the plan of its zone or a zone above it.
+// @Tags floor-plans
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Camera ID"
+// @Param placement body ports.CameraPlacementRequest true "Placement in plan coordinates"
+// @Success 200 {object} domain.CameraPlacement
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /cameras/{id}/placement [put]
+func (h *FloorPlanHandler) PlaceCamera(c *gin.Context) {
+	cameraID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid ID"})
+		return
+	}
+	var req ports.CameraPlacementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	placement, err := h.service.PlaceCamera(c.Request.Context(), cameraID, &req)
+	if err != nil {
+		c.JSON(floorPlanErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, placement)
+}
+
+// RemovePlacement godoc
+// @Summary Remove a camera from its floor plan
+// @Tags floor-plans
+// @Security BearerAuth
+// @Param id path string true "Camera ID"
+// @Success 204
+// @Failure 404 {object} ErrorResponse
+// @Router /cameras/{id}/placement [delete]
+func (h *FloorPlanHandler) RemovePlacement(c *gin.Context) {
+	cameraID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid ID"})
+		return
+	}
+	if err := h.service.RemovePlacement(c.Request.Context(), cameraID); err != nil {
+		c.JSON(floorPlanErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func floorPlanErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrZoneNotFound), errors.Is(err, domain.ErrCameraNotFound),
+		errors.Is(err, domain.ErrFloorPlanNotFound), errors.Is(err, domain.ErrPlacementNotFound),
+		errors.Is(err, domain.ErrZoneAreaNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrFloorPlanImage), errors.Is(err, domain.ErrPlacementPlan),
+		errors.Is(err, domain.ErrInvalidPlacement), errors.Is(err, domain.ErrOutsideFloorPlan),
+		errors.Is(err, domain.ErrZoneAreaPlan), errors.Is(err, domain.ErrInvalidZoneArea):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
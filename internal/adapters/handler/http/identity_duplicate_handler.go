@@ -0,0 +1,165 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type IdentityDuplicateHandler struct {
+	service ports.IdentityDuplicateService
+	access  *AccessRecorder
+}
+
+func NewIdentityDuplicateHandler(service ports.IdentityDuplicateService, access *AccessRecorder) *IdentityDuplicateHandler {
+	return &IdentityDuplicateHandler{service: service, access: access}
+}
+
+// ScanDuplicates godoc
+// @Summary Scan for duplicate identities
+// @Description Compares identities by CCCD, phone, face similarity and normalized name in the background. Open candidates are replaced by the result; dismissed pairs stay dismissed.
+// @Tags identities
+// @Produce json
+// @Success 202 {object} domain.Job
+// @Security BearerAuth
+// @Router /identities/duplicates/scan [post]
+func (h *IdentityDuplicateHandler) ScanDuplicates(c *gin.Context) {
+	job, err := h.service.StartScan(c.Request.Context(), requestUserID(c))
+	if err != nil {
+		c.JSON(duplicateErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, job)
+}
+
+// ListDuplicates godoc
+// @Summary List duplicate identity candidates
+// @Tags identities
+// @Produce json
+// @Param status query string false "open (default), dismissed or merged"
+// @Param identity_id query string false "Only pairs involving this identity"
+// @Param page query int false "Page number"
+// @Param limit query int false "Limit"
+// @Success 200 {object} PaginatedResponse{data=[]domain.DuplicateCandidate}
+// @Security BearerAuth
+// @Router /identities/duplicates [get]
+func (h *IdentityDuplicateHandler) ListDuplicates(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+
+	status := domain.DuplicateStatus(c.DefaultQuery("status", string(domain.DuplicateStatusOpen)))
+	filter := &ports.DuplicateFilter{
+		Status: &status,
+		Limit:  int32(limit),
+		Offset: int32((page - 1) * limit),
+	}
+	if id := c.Query("identity_id"); id != "" {
+		if uid, err := uuid.Parse(id); err == nil {
+			filter.IdentityID = &uid
+		}
+	}
+
+	candidates, total, err := h.service.ListCandidates(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(duplicateErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	ids := make([]uuid.UUID, 0, len(candidates)*2)
+	for _, cand := range candidates {
+		ids = append(ids, cand.IdentityA, cand.IdentityB)
+	}
+	h.access.Record(c, &domain.AccessLog{
+		ResourceType: domain.AccessResourceIdentity,
+		IdentityIDs:  uniqueIdentityIDs(ids),
+	})
+	c.JSON(http.StatusOK, PaginatedResponse{
+		Data:  candidates,
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	})
+}
+
+// DismissDuplicate godoc
+// @Summary Dismiss a duplicate candidate
+// @Description Marks the pair as not the same person so later scans do not report it again
+// @Tags identities
+// @Param candidate_id path string true "Candidate ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /identities/duplicates/{candidate_id}/dismiss [post]
+func (h *IdentityDuplicateHandler) DismissDuplicate(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("candidate_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid ID format"})
+		return
+	}
+	if err := h.service.DismissCandidate(c.Request.Context(), id, requestUserID(c)); err != nil {
+		c.JSON(duplicateErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// MergeIdentity godoc
+// @Summary Merge a duplicate identity into this one
+// @Description Moves faces, recognition logs and attendance records from source_id to this identity, reconciling days both have attendance for, and soft deletes the source.
+// @Tags identities
+// @Accept json
+// @Produce json
+// @Param id path string true "Surviving identity ID"
+// @Param request body ports.MergeIdentitiesRequest true "Source identity and reason"
+// @Success 200 {object} domain.IdentityMerge
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /identities/{id}/merge [post]
+func (h *IdentityDuplicateHandler) MergeIdentity(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid ID format"})
+		return
+	}
+
+	var req ports.MergeIdentitiesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	req.MergedBy = requestUserID(c)
+
+	result, err := h.service.MergeIdentities(c.Request.Context(), id, &req)
+	if err != nil {
+		c.JSON(duplicateErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func duplicateErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrIdentityNotFound), errors.Is(err, domain.ErrDuplicateCandidateNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrDuplicateNotOpen):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrMergeSameIdentity), errors.Is(err, domain.ErrMergeReasonRequired):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -1,8 +1,11 @@
 package http
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"app/internal/core/domain"
 	"app/internal/core/ports"
@@ -13,11 +16,13 @@ import (
 
 type IdentityHandler struct {
 	service ports.IdentityService
+	pii     *PIIPresenter
 }
 
-func NewIdentityHandler(service ports.IdentityService) *IdentityHandler {
+func NewIdentityHandler(service ports.IdentityService, pii *PIIPresenter) *IdentityHandler {
 	return &IdentityHandler{
 		service: service,
+		pii:     pii,
 	}
 }
 
@@ -49,6 +54,7 @@ func (h *IdentityHandler) CreateIdentity(c *gin.Context) {
 		return
 	}
 
+	h.pii.Identities(c, identity)
 	c.JSON(http.StatusCreated, identity)
 }
 
@@ -87,7 +93,9 @@ func (h *IdentityHandler) ListIdentities(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path string true "Identity ID"
+// @Param as_of query string false "Return the identity as it was at this time (RFC3339)"
 // @Success 200 {object} domain.Identity
+// @Failure 404 {object} ErrorResponse
 // @Router /identities/{id} [get]
 func (h *IdentityHandler) GetIdentity(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
@@ -96,9 +104,19 @@ func (h *IdentityHandler) GetIdentity(c *gin.Context) {
 		return
 	}
 
-	identity, err := h.service.GetIdentity(c.Request.Context(), id)
+	var identity *domain.Identity
+	if asOf := c.Query("as_of"); asOf != "" {
+		t, perr := time.Parse(time.RFC3339, asOf)
+		if perr != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid as_of time"})
+			return
+		}
+		identity, err = h.service.GetIdentityAsOf(c.Request.Context(), id, t)
+	} else {
+		identity, err = h.service.GetIdentity(c.Request.Context(), id)
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		c.JSON(identityErrorStatus(err), ErrorResponse{Error: err.Error()})
 		return
 	}
 	if identity == nil {
@@ -106,6 +124,7 @@ func (h *IdentityHandler) GetIdentity(c *gin.Context) {
 		return
 	}
 
+	h.pii.Identities(c, identity)
 	c.JSON(http.StatusOK, identity)
 }
 
@@ -131,50 +150,128 @@ func (h *IdentityHandler) UpdateIdentity(c *gin.Context) {
 		return
 	}
 
+	req.UpdatedBy = requestUserID(c)
 	identity, err := h.service.UpdateIdentity(c.Request.Context(), id, &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		c.JSON(identityErrorStatus(err), ErrorResponse{Error: err.Error()})
 		return
 	}
 
+	h.pii.Identities(c, identity)
 	c.JSON(http.StatusOK, identity)
 }
 
 // UpdateStatus godoc
-// @Summary Update identity status
+// @Summary Review identity status
+// @Description Approves (status "active") or rejects (status "rejected") a pending identity
 // @Tags identities
 // @Accept json
 // @Produce json
 // @Param id path string true "Identity ID"
-// @Param request body object true "Status object"
+// @Param request body ports.ReviewIdentityRequest true "Decision and reason"
 // @Success 200 {object} domain.Identity
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
 // @Router /identities/{id}/status [patch]
 func (h *IdentityHandler) UpdateStatus(c *gin.Context) {
-	id, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid ID format"})
+	var req ports.ReviewIdentityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
 		return
 	}
+	h.review(c, &req)
+}
+
+// ListPending godoc
+// @Summary List identities waiting for review
+// @Tags identities
+// @Produce json
+// @Param page query int false "Page number"
+// @Param limit query int false "Limit"
+// @Success 200 {object} PaginatedResponse
+// @Router /identities/pending [get]
+func (h *IdentityHandler) ListPending(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 
-	var req struct {
-		Status string `json:"status" binding:"required"`
+	items, total, err := h.service.ListPending(c.Request.Context(), page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
 	}
-	if err := c.ShouldBindJSON(&req); err != nil {
+
+	c.JSON(http.StatusOK, PaginatedResponse{
+		Data:  items,
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	})
+}
+
+// ApproveIdentity godoc
+// @Summary Approve a pending identity
+// @Tags identities
+// @Accept json
+// @Produce json
+// @Param id path string true "Identity ID"
+// @Param request body ReviewReasonRequest true "Approval reason"
+// @Success 200 {object} domain.Identity
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /identities/{id}/approve [post]
+func (h *IdentityHandler) ApproveIdentity(c *gin.Context) {
+	h.reviewWithStatus(c, domain.IdentityStatusActive)
+}
+
+// RejectIdentity godoc
+// @Summary Reject a pending identity
+// @Tags identities
+// @Accept json
+// @Produce json
+// @Param id path string true "Identity ID"
+// @Param request body ReviewReasonRequest true "Rejection reason"
+// @Success 200 {object} domain.Identity
+// @Failure 409 {object} ErrorResponse
+// @Router /identities/{id}/reject [post]
+func (h *IdentityHandler) RejectIdentity(c *gin.Context) {
+	h.reviewWithStatus(c, domain.IdentityStatusRejected)
+}
+
+func (h *IdentityHandler) reviewWithStatus(c *gin.Context, status domain.IdentityStatus) {
+	var body ReviewReasonRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
 		return
 	}
+	h.review(c, &ports.ReviewIdentityRequest{Status: status, Reason: body.Reason})
+}
 
-	identity, err := h.service.UpdateStatus(c.Request.Context(), id, domain.IdentityStatus(req.Status))
+func (h *IdentityHandler) review(c *gin.Context, req *ports.ReviewIdentityRequest) {
+	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid ID format"})
 		return
 	}
+	reviewerID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Invalid user"})
+		return
+	}
+	req.ReviewerID = reviewerID
 
+	identity, err := h.service.ReviewIdentity(c.Request.Context(), id, req)
+	if err != nil {
+		c.JSON(identityErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	h.pii.Identities(c, identity)
 	c.JSON(http.StatusOK, identity)
 }
 
 // DeleteIdentity godoc
 // @Summary Delete an identity
+// @Description Soft delete; faces and history are kept. Use /identities/{id}/erase for a data subject erasure request.
 // @Tags identities
 // @Param id path string true "Identity ID"
 // @Success 204 "No Content"
@@ -186,7 +283,7 @@ func (h *IdentityHandler) DeleteIdentity(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.DeleteIdentity(c.Request.Context(), id); err != nil {
+	if err := h.service.DeleteIdentity(c.Request.Context(), id, requestUserID(c)); err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
 		return
 	}
@@ -194,29 +291,105 @@ func (h *IdentityHandler) DeleteIdentity(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+// ListHistory godoc
+// @Summary List an identity's change history
+// @Description Newest first. Each version holds the full state after the change and the fields that changed.
+// @Tags identities
+// @Produce json
+// @Param id path string true "Identity ID"
+// @Param page query int false "Page number"
+// @Param limit query int false "Limit"
+// @Success 200 {object} PaginatedResponse{data=[]domain.IdentityVersion}
+// @Security BearerAuth
+// @Router /identities/{id}/history [get]
+func (h *IdentityHandler) ListHistory(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid ID format"})
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+
+	versions, total, err := h.service.ListHistory(c.Request.Context(), id, int32(limit), int32((page-1)*limit))
+	if err != nil {
+		c.JSON(identityErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	h.pii.Versions(c, id, versions)
+	c.JSON(http.StatusOK, PaginatedResponse{
+		Data:  versions,
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	})
+}
+
+// RevertIdentity godoc
+// @Summary Revert an identity to a previous version
+// @Description Restores name, type, contact, department, metadata, note and face image from the version. Status is not changed.
+// @Tags identities
+// @Accept json
+// @Produce json
+// @Param id path string true "Identity ID"
+// @Param request body ports.RevertIdentityRequest true "Version and reason"
+// @Success 200 {object} domain.Identity
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /identities/{id}/revert [post]
+func (h *IdentityHandler) RevertIdentity(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid ID format"})
+		return
+	}
+
+	var req ports.RevertIdentityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	req.RevertedBy = requestUserID(c)
+
+	identity, err := h.service.RevertIdentity(c.Request.Context(), id, &req)
+	if err != nil {
+		c.JSON(identityErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	h.pii.Identities(c, identity)
+	c.JSON(http.StatusOK, identity)
+}
+
 // EnrollFace godoc
 // @Summary Enroll a new face for an identity
 // @Tags identities
 // @Accept json
 // @Produce json
-// @Param request body object true "Face Info"
+// @Param request body ports.EnrollFaceRequest true "Face Info"
 // @Success 200 {object} domain.IdentityFace
+// @Failure 400 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
 // @Router /identities/enroll-face [post]
 func (h *IdentityHandler) EnrollFace(c *gin.Context) {
-	var req struct {
-		IdentityID string `json:"identity_id" binding:"required"`
-		ImageURL   string `json:"image_url" binding:"required"`
-		IsPrimary  bool   `json:"is_primary"`
-	}
+	var req ports.EnrollFaceRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	uid, _ := uuid.Parse(req.IdentityID)
-	face, err := h.service.EnrollFace(c.Request.Context(), uid, req.ImageURL, req.IsPrimary)
+	req.EnrolledBy = requestUserID(c)
+	face, err := h.service.EnrollFace(c.Request.Context(), &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		c.JSON(identityErrorStatus(err), ErrorResponse{Error: err.Error()})
 		return
 	}
 	c.JSON(http.StatusOK, face)
@@ -230,9 +403,70 @@ func (h *IdentityHandler) EnrollFace(c *gin.Context) {
 // @Router /identities/faces/{face_id} [delete]
 func (h *IdentityHandler) DeleteFace(c *gin.Context) {
 	id, _ := uuid.Parse(c.Param("face_id"))
-	if err := h.service.DeleteFace(c.Request.Context(), id); err != nil {
+	if err := h.service.DeleteFace(c.Request.Context(), id, requestUserID(c)); err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
 		return
 	}
 	c.Status(http.StatusNoContent)
 }
+
+// SearchFaces godoc
+// @Summary Search identities by face similarity
+// @Description Accepts a JSON body with an embedding, or a multipart form with an "image" file when an embedding provider is configured
+// @Tags identities
+// @Accept json,mpfd
+// @Produce json
+// @Param request body ports.FaceSearchRequest false "Embedding search"
+// @Param image formData file false "Face image"
+// @Param top_k formData int false "Number of identities to return"
+// @Success 200 {array} domain.FaceMatch
+// @Failure 400 {object} ErrorResponse
+// @Failure 501 {object} ErrorResponse
+// @Router /faces/search [post]
+func (h *IdentityHandler) SearchFaces(c *gin.Context) {
+	var req ports.FaceSearchRequest
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		file, _, err := c.Request.FormFile("image")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "No image provided"})
+			return
+		}
+		defer file.Close()
+		req.Image = file
+		req.TopK, _ = strconv.Atoi(c.PostForm("top_k"))
+	} else if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	matches, err := h.service.SearchFaces(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(identityErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	h.pii.FaceMatches(c, matches, map[string]any{"top_k": req.TopK, "min_score": req.MinScore})
+	c.JSON(http.StatusOK, matches)
+}
+
+func identityErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrIdentityNotFound), errors.Is(err, domain.ErrIdentityVersionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrIdentityNotPending):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrSelfApproval), errors.Is(err, domain.ErrPermissionDenied):
+		return http.StatusForbidden
+	case errors.Is(err, domain.ErrReviewReasonRequired), errors.Is(err, domain.ErrInvalidReviewStatus),
+		errors.Is(err, domain.ErrErasureReasonMissing), errors.Is(err, domain.ErrRevertReasonRequired),
+		errors.Is(err, domain.ErrVersionNotRevertible):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrFaceImageUnreadable), errors.Is(err, domain.ErrInvalidEmbedding):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrFaceQualityTooLow):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, domain.ErrNoEmbeddingProvider):
+		return http.StatusNotImplemented
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,142 @@
+package http
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// importMaxSheetBytes caps the spreadsheet kept in memory while parsing.
+const importMaxSheetBytes = 20 << 20
+
+type IdentityImportHandler struct {
+	service ports.IdentityImportService
+	jobs    ports.JobService
+	pii     *PIIPresenter
+	access  *AccessRecorder
+}
+
+func NewIdentityImportHandler(service ports.IdentityImportService, jobs ports.JobService, pii *PIIPresenter, access *AccessRecorder) *IdentityImportHandler {
+	return &IdentityImportHandler{service: service, jobs: jobs, pii: pii, access: access}
+}
+
+// ImportIdentities godoc
+// @Summary Bulk import identities
+// @Description Imports identities from a CSV or XLSX sheet with an optional ZIP of face photos named by code (CODE.jpg, CODE_2.jpg). Runs in the background; poll the returned job for progress.
+// @Tags identities
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "CSV or XLSX sheet"
+// @Param photos formData file false "ZIP of face photos"
+// @Param mode formData string false "all_or_nothing (default) or skip_invalid"
+// @Success 202 {object} domain.Job
+// @Failure 400 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /identities/import [post]
+func (h *IdentityImportHandler) ImportIdentities(c *gin.Context) {
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "No file provided"})
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, importMaxSheetBytes+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	if len(data) > importMaxSheetBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, ErrorResponse{Error: "Sheet is too large"})
+		return
+	}
+
+	req := &ports.IdentityImportRequest{
+		SheetName: header.Filename,
+		Sheet:     data,
+		Mode:      ports.IdentityImportMode(c.PostForm("mode")),
+	}
+	if userID, ok := currentUserID(c); ok {
+		req.CreatedBy = &userID
+	}
+
+	// The photo archive outlives the request, so it is copied to a temp file
+	// that the import job removes when it finishes
+	if photos, err := c.FormFile("photos"); err == nil {
+		tmp, err := os.CreateTemp("", "identity-import-*.zip")
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+			return
+		}
+		tmp.Close()
+		if err := c.SaveUploadedFile(photos, tmp.Name()); err != nil {
+			os.Remove(tmp.Name())
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+			return
+		}
+		req.PhotosPath = tmp.Name()
+	}
+
+	job, err := h.service.StartImport(c.Request.Context(), req)
+	if err != nil {
+		c.JSON(jobErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, job)
+}
+
+// ImportReport godoc
+// @Summary Download the rejected rows of an identity import
+// @Tags identities
+// @Produce text/csv
+// @Param job_id path string true "Import job ID"
+// @Success 200 {file} file
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /identities/import/{job_id}/report [get]
+func (h *IdentityImportHandler) ImportReport(c *gin.Context) {
+	jobID, err := uuid.Parse(c.Param("job_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid ID"})
+		return
+	}
+
+	job, err := h.jobs.GetJob(c.Request.Context(), jobID)
+	if err == nil && !jobVisible(c, h.pii, job) {
+		err = domain.ErrJobNotFound
+	}
+	if err != nil {
+		c.JSON(jobErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	rowErrors, err := h.service.ImportReport(c.Request.Context(), jobID)
+	if err != nil {
+		c.JSON(jobErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	h.access.Record(c, &domain.AccessLog{
+		ResourceType: domain.AccessResourceImportReport,
+		ResourceID:   jobID.String(),
+	})
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=import-%s-errors.csv", jobID))
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	_ = w.Write([]string{"row", "code", "errors"})
+	for _, e := range rowErrors {
+		_ = w.Write([]string{strconv.Itoa(e.Row), e.Code, strings.Join(e.Errors, "; ")})
+	}
+	w.Flush()
+}
@@ -0,0 +1,115 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+	"app/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type IdentityPrivacyHandler struct {
+	service ports.IdentityPrivacyService
+	pii     *PIIPresenter
+	access  *AccessRecorder
+}
+
+func NewIdentityPrivacyHandler(service ports.IdentityPrivacyService, pii *PIIPresenter, access *AccessRecorder) *IdentityPrivacyHandler {
+	return &IdentityPrivacyHandler{service: service, pii: pii, access: access}
+}
+
+// ExportIdentity godoc
+// @Summary Export all personal data held about an identity
+// @Description ZIP with the identity record, face images and embeddings, recognition logs, attendance records and related audit entries
+// @Tags identities
+// @Produce application/zip
+// @Param id path string true "Identity ID"
+// @Success 200 {file} file
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /identities/{id}/export [get]
+func (h *IdentityPrivacyHandler) ExportIdentity(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid ID format"})
+		return
+	}
+
+	// The export is unmasked by nature
+	if !h.pii.CanRead(c) {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: domain.ErrPermissionDenied.Error()})
+		return
+	}
+	var requestedBy *uuid.UUID
+	if userID, ok := currentUserID(c); ok {
+		requestedBy = &userID
+	}
+
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=identity-%s.zip", id))
+	err = h.service.ExportIdentity(c.Request.Context(), id, requestedBy, c.Writer)
+	if c.Writer.Written() {
+		h.access.Record(c, &domain.AccessLog{
+			ResourceType: domain.AccessResourceIdentityExport,
+			ResourceID:   id.String(),
+			IdentityIDs:  []uuid.UUID{id},
+			PIIUnmasked:  true,
+		})
+	}
+	if err != nil {
+		if c.Writer.Written() {
+			logger.Error("Identity export aborted", zap.String("identity_id", id.String()), zap.Error(err))
+			return
+		}
+		c.Writer.Header().Del("Content-Disposition")
+		c.JSON(identityErrorStatus(err), ErrorResponse{Error: err.Error()})
+	}
+}
+
+// EraseIdentity godoc
+// @Summary Permanently erase an identity's personal data
+// @Description Removes faces, embeddings, media and PII. Attendance and recognition counts are kept against an anonymous record. This cannot be undone. Requires the identities:erase permission.
+// @Tags identities
+// @Accept json
+// @Produce json
+// @Param id path string true "Identity ID"
+// @Param request body ports.EraseIdentityRequest true "Reason for the erasure request"
+// @Success 200 {object} domain.IdentityErasure
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /identities/{id}/erase [post]
+func (h *IdentityPrivacyHandler) EraseIdentity(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid ID format"})
+		return
+	}
+
+	if !h.pii.Allowed(c, domain.PermissionIdentityErase) {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: domain.ErrPermissionDenied.Error()})
+		return
+	}
+	var req ports.EraseIdentityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	if userID, ok := currentUserID(c); ok {
+		req.RequestedBy = &userID
+	}
+
+	result, err := h.service.EraseIdentity(c.Request.Context(), id, &req)
+	if err != nil {
+		c.JSON(identityErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,103 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type JobHandler struct {
+	service ports.JobService
+	pii     *PIIPresenter
+}
+
+func NewJobHandler(service ports.JobService, pii *PIIPresenter) *JobHandler {
+	return &JobHandler{service: service, pii: pii}
+}
+
+// ListJobs godoc
+// @Summary List background jobs started by the current user
+// @Tags jobs
+// @Produce json
+// @Param type query string false "Job type, e.g. identity_import"
+// @Param limit query int false "Limit"
+// @Param offset query int false "Offset"
+// @Success 200 {array} domain.Job
+// @Security BearerAuth
+// @Router /jobs [get]
+func (h *JobHandler) ListJobs(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Invalid user"})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	filter := &ports.JobFilter{CreatedBy: &userID, Limit: int32(limit), Offset: int32(offset)}
+	if jobType := c.Query("type"); jobType != "" {
+		filter.Type = &jobType
+	}
+
+	jobs, err := h.service.ListJobs(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, jobs)
+}
+
+// GetJob godoc
+// @Summary Get a background job with its progress
+// @Description Only the user who started the job, or an admin, can see it.
+// @Tags jobs
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} domain.Job
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /jobs/{id} [get]
+func (h *JobHandler) GetJob(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid ID"})
+		return
+	}
+
+	job, err := h.service.GetJob(c.Request.Context(), id)
+	if err == nil && !jobVisible(c, h.pii, job) {
+		err = domain.ErrJobNotFound
+	}
+	if err != nil {
+		c.JSON(jobErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// jobVisible reports whether the caller may see the job. Results carry
+// import row errors, export URLs and the like, so other users' jobs are
+// hidden unless the caller holds every permission.
+func jobVisible(c *gin.Context, pii *PIIPresenter, job *domain.Job) bool {
+	if userID, ok := currentUserID(c); ok && job.CreatedBy != nil && *job.CreatedBy == userID {
+		return true
+	}
+	return pii.Allowed(c, domain.PermissionAll)
+}
+
+func jobErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrJobNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrInvalidJobInput):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
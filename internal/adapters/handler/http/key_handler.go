@@ -0,0 +1,99 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type KeyHandler struct {
+	service ports.KeyService
+	pii     *PIIPresenter
+}
+
+func NewKeyHandler(service ports.KeyService, pii *PIIPresenter) *KeyHandler {
+	return &KeyHandler{service: service, pii: pii}
+}
+
+// ListKeys godoc
+// @Summary List field encryption data keys
+// @Description Key metadata only; key material is never returned
+// @Tags security
+// @Produce json
+// @Success 200 {array} domain.DataKey
+// @Failure 409 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /security/keys [get]
+func (h *KeyHandler) ListKeys(c *gin.Context) {
+	keys, err := h.service.ListKeys(c.Request.Context())
+	if err != nil {
+		c.JSON(keyErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, keys)
+}
+
+// RotateKey godoc
+// @Summary Rotate the data encryption key
+// @Description Activates a new data key and re-encrypts existing PII in the background. Requires the security:keys:manage permission.
+// @Tags security
+// @Produce json
+// @Success 202 {object} domain.Job
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /security/keys/rotate [post]
+func (h *KeyHandler) RotateKey(c *gin.Context) {
+	if !h.pii.Allowed(c, domain.PermissionSecurityKeys) {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: domain.ErrPermissionDenied.Error()})
+		return
+	}
+	job, err := h.service.RotateKey(c.Request.Context(), requestUserID(c))
+	if err != nil {
+		c.JSON(keyErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, job)
+}
+
+// Reencrypt godoc
+// @Summary Re-encrypt PII under the active data key
+// @Description Encrypts rows written before encryption was enabled and moves rows off retired keys. Requires the security:keys:manage permission.
+// @Tags security
+// @Produce json
+// @Success 202 {object} domain.Job
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /security/keys/reencrypt [post]
+func (h *KeyHandler) Reencrypt(c *gin.Context) {
+	if !h.pii.Allowed(c, domain.PermissionSecurityKeys) {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: domain.ErrPermissionDenied.Error()})
+		return
+	}
+	job, err := h.service.Reencrypt(c.Request.Context(), requestUserID(c))
+	if err != nil {
+		c.JSON(keyErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, job)
+}
+
+func requestUserID(c *gin.Context) *uuid.UUID {
+	if userID, ok := currentUserID(c); ok {
+		return &userID
+	}
+	return nil
+}
+
+func keyErrorStatus(err error) int {
+	if errors.Is(err, domain.ErrEncryptionDisabled) {
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,205 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type MaintenanceHandler struct {
+	service ports.MaintenanceService
+}
+
+func NewMaintenanceHandler(service ports.MaintenanceService) *MaintenanceHandler {
+	return &MaintenanceHandler{service: service}
+}
+
+// CreateWindow godoc
+// @Summary Schedule a maintenance window
+// @Description For one camera or every camera in a zone and the zones below it. While a window is open its cameras are in maintenance, offline alerts are suppressed and AI events are dropped or tagged per event_policy. Recurring windows repeat daily or weekly in their timezone.
+// @Tags maintenance
+// @Accept json
+// @Produce json
+// @Param request body ports.MaintenanceWindowRequest true "Window"
+// @Success 201 {object} domain.MaintenanceWindow
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /maintenance-windows [post]
+func (h *MaintenanceHandler) CreateWindow(c *gin.Context) {
+	var req ports.MaintenanceWindowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	req.RequestedBy = requestUserID(c)
+
+	window, err := h.service.CreateWindow(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(maintenanceErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, window)
+}
+
+// ListWindows godoc
+// @Summary List maintenance windows
+// @Tags maintenance
+// @Produce json
+// @Param camera_id query string false "Windows covering this camera, directly or through its zone or a zone above it"
+// @Param zone_id query string false "Zone ID"
+// @Param active_at query string false "Only windows open at this time (RFC3339), or 'now'"
+// @Param page query int false "Page number"
+// @Param limit query int false "Limit"
+// @Success 200 {object} PaginatedResponse{data=[]domain.MaintenanceWindow}
+// @Failure 400 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /maintenance-windows [get]
+func (h *MaintenanceHandler) ListWindows(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+
+	filter := &ports.MaintenanceWindowFilter{
+		Limit:  int32(limit),
+		Offset: int32((page - 1) * limit),
+	}
+	if cid := c.Query("camera_id"); cid != "" {
+		if uid, err := uuid.Parse(cid); err == nil {
+			filter.CameraID = &uid
+		}
+	}
+	if zid := c.Query("zone_id"); zid != "" {
+		if uid, err := uuid.Parse(zid); err == nil {
+			filter.ZoneID = &uid
+		}
+	}
+	if at := c.Query("active_at"); at != "" {
+		t := time.Now()
+		if at != "now" {
+			var err error
+			if t, err = time.Parse(time.RFC3339, at); err != nil {
+				c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid active_at"})
+				return
+			}
+		}
+		filter.ActiveAt = &t
+	}
+
+	windows, total, err := h.service.ListWindows(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(maintenanceErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, PaginatedResponse{
+		Data:  windows,
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	})
+}
+
+// GetWindow godoc
+// @Summary Get a maintenance window
+// @Tags maintenance
+// @Produce json
+// @Param id path string true "Window ID"
+// @Success 200 {object} domain.MaintenanceWindow
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /maintenance-windows/{id} [get]
+func (h *MaintenanceHandler) GetWindow(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid ID format"})
+		return
+	}
+	window, err := h.service.GetWindow(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(maintenanceErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, window)
+}
+
+// UpdateWindow godoc
+// @Summary Replace a maintenance window
+// @Description Takes effect on the scheduler's next pass; cameras are released if the window no longer covers them.
+// @Tags maintenance
+// @Accept json
+// @Produce json
+// @Param id path string true "Window ID"
+// @Param request body ports.MaintenanceWindowRequest true "Window"
+// @Success 200 {object} domain.MaintenanceWindow
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /maintenance-windows/{id} [put]
+func (h *MaintenanceHandler) UpdateWindow(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid ID format"})
+		return
+	}
+	var req ports.MaintenanceWindowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	req.RequestedBy = requestUserID(c)
+
+	window, err := h.service.UpdateWindow(c.Request.Context(), id, &req)
+	if err != nil {
+		c.JSON(maintenanceErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, window)
+}
+
+// DeleteWindow godoc
+// @Summary Delete a maintenance window
+// @Description Cameras it holds in maintenance return to monitoring on the scheduler's next pass.
+// @Tags maintenance
+// @Param id path string true "Window ID"
+// @Success 204
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /maintenance-windows/{id} [delete]
+func (h *MaintenanceHandler) DeleteWindow(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid ID format"})
+		return
+	}
+	if err := h.service.DeleteWindow(c.Request.Context(), id, requestUserID(c)); err != nil {
+		c.JSON(maintenanceErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func maintenanceErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrMaintenanceWindowNotFound), errors.Is(err, domain.ErrCameraNotFound),
+		errors.Is(err, domain.ErrZoneNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrMaintenanceTarget), errors.Is(err, domain.ErrMaintenancePeriod),
+		errors.Is(err, domain.ErrMaintenanceRecurrence), errors.Is(err, domain.ErrMaintenanceEventPolicy),
+		errors.Is(err, domain.ErrMaintenanceTimezone):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,191 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+
+	"github.com/gin-gonic/gin"
+)
+
+const manifestMaxBytes = 10 << 20
+
+type ManifestHandler struct {
+	service ports.ManifestService
+	pii     *PIIPresenter
+}
+
+func NewManifestHandler(service ports.ManifestService, pii *PIIPresenter) *ManifestHandler {
+	return &ManifestHandler{service: service, pii: pii}
+}
+
+// ManifestErrorResponse lists every problem found in an uploaded manifest.
+type ManifestErrorResponse struct {
+	Error    string   `json:"error"`
+	Problems []string `json:"problems"`
+}
+
+// ExportManifest godoc
+// @Summary Export zones, cameras and AI configs as a site manifest
+// @Description Downloads the site as YAML (default) or CSV. Camera passwords are left out unless credentials=true, which needs the cameras:credentials:reveal permission and is audited.
+// @Tags cameras
+// @Produce application/yaml,text/csv
+// @Param format query string false "yaml (default) or csv"
+// @Param credentials query bool false "Include camera passwords"
+// @Success 200 {file} file
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /site-manifest [get]
+func (h *ManifestHandler) ExportManifest(c *gin.Context) {
+	format := c.DefaultQuery("format", domain.ManifestFormatYAML)
+	if format != domain.ManifestFormatYAML && format != domain.ManifestFormatCSV {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: domain.ErrManifestFormat.Error()})
+		return
+	}
+	req := &ports.ManifestExportRequest{
+		IncludeCredentials: c.Query("credentials") == "true",
+		RequestedBy:        requestUserID(c),
+	}
+	if req.IncludeCredentials && !h.pii.Allowed(c, domain.PermissionCameraCredentials) {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: domain.ErrPermissionDenied.Error()})
+		return
+	}
+
+	manifest, err := h.service.Export(c.Request.Context(), req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	data, err := h.service.Encode(format, manifest)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	contentType := "application/yaml; charset=utf-8"
+	if format == domain.ManifestFormatCSV {
+		contentType = "text/csv; charset=utf-8"
+	}
+	if req.IncludeCredentials {
+		c.Header("Cache-Control", "no-store")
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=site-%s.%s", time.Now().Format("20060102"), format))
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// PlanManifest godoc
+// @Summary Preview the changes a site manifest would make
+// @Description Matches zones by path, such as "Building A/Floor 1", and cameras by name, and lists what applying the manifest would create, update and, with prune, delete. Nothing is changed.
+// @Tags cameras
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "YAML or CSV manifest"
+// @Param format formData string false "yaml or csv; taken from the file extension when omitted"
+// @Param prune formData bool false "Delete cameras and zones missing from the manifest"
+// @Success 200 {object} domain.ManifestPlan
+// @Failure 400 {object} ManifestErrorResponse
+// @Security BearerAuth
+// @Router /site-manifest/plan [post]
+func (h *ManifestHandler) PlanManifest(c *gin.Context) {
+	manifest, prune, ok := h.readManifest(c)
+	if !ok {
+		return
+	}
+	plan, err := h.service.Plan(c.Request.Context(), manifest, prune)
+	if err != nil {
+		respondManifestError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, plan)
+}
+
+// ApplyManifest godoc
+// @Summary Apply a site manifest
+// @Description Makes the site match the manifest. Applying the same manifest again changes nothing. Changes run in plan order and stop at the first failure, which is reported on the failing change with a 422; earlier changes are kept.
+// @Tags cameras
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "YAML or CSV manifest"
+// @Param format formData string false "yaml or csv; taken from the file extension when omitted"
+// @Param prune formData bool false "Delete cameras and zones missing from the manifest"
+// @Success 200 {object} domain.ManifestPlan
+// @Failure 400 {object} ManifestErrorResponse
+// @Failure 422 {object} domain.ManifestPlan
+// @Security BearerAuth
+// @Router /site-manifest/apply [post]
+func (h *ManifestHandler) ApplyManifest(c *gin.Context) {
+	manifest, prune, ok := h.readManifest(c)
+	if !ok {
+		return
+	}
+	plan, err := h.service.Apply(c.Request.Context(), &ports.ManifestApplyRequest{
+		Manifest:    manifest,
+		Prune:       prune,
+		RequestedBy: requestUserID(c),
+	})
+	if err != nil {
+		respondManifestError(c, err)
+		return
+	}
+	status := http.StatusOK
+	if !plan.Applied {
+		status = http.StatusUnprocessableEntity
+	}
+	c.JSON(status, plan)
+}
+
+func (h *ManifestHandler) readManifest(c *gin.Context) (*domain.SiteManifest, bool, bool) {
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "No file provided"})
+		return nil, false, false
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, manifestMaxBytes+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return nil, false, false
+	}
+	if len(data) > manifestMaxBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, ErrorResponse{Error: "Manifest is too large"})
+		return nil, false, false
+	}
+
+	format := c.PostForm("format")
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(header.Filename)) {
+		case ".yaml", ".yml":
+			format = domain.ManifestFormatYAML
+		case ".csv":
+			format = domain.ManifestFormatCSV
+		}
+	}
+	manifest, err := h.service.Decode(format, data)
+	if err != nil {
+		respondManifestError(c, err)
+		return nil, false, false
+	}
+	prune := c.PostForm("prune") == "true" || c.Query("prune") == "true"
+	return manifest, prune, true
+}
+
+func respondManifestError(c *gin.Context, err error) {
+	var invalid *domain.ManifestError
+	switch {
+	case errors.As(err, &invalid):
+		c.JSON(http.StatusBadRequest, ManifestErrorResponse{Error: "Invalid manifest", Problems: invalid.Problems})
+	case errors.Is(err, domain.ErrManifestFormat), errors.Is(err, domain.ErrManifestEmpty):
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	}
+}
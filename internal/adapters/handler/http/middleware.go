@@ -6,6 +6,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 // TODO: Load from config
@@ -55,3 +56,16 @@ func AuthMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// currentUserID returns the authenticated user set by AuthMiddleware.
+func currentUserID(c *gin.Context) (uuid.UUID, bool) {
+	val, exists := c.Get("userID")
+	if !exists {
+		return uuid.Nil, false
+	}
+	id, err := uuid.Parse(val.(string))
+	if err != nil {
+		return uuid.Nil, false
+	}
+	return id, true
+}
@@ -45,6 +45,7 @@ func (h *PermissionHandler) UpdateCameraPermissions(c *gin.Context) {
 
 // UpdateZonePermissions godoc
 // @Summary Update user zone permissions
+// @Description A zone grant covers every camera in the zone and in the zones below it. Users with camera or zone grants only see the cameras they cover, unless they hold cameras:all.
 // @Tags permissions
 // @Accept json
 // @Produce json
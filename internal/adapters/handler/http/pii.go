@@ -0,0 +1,121 @@
+package http
+
+import (
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// PIIPresenter prepares identities for a response: callers without
+// identities:pii:read get masked phone, CCCD and metadata. Every presented
+// identity is recorded in the access log, flagged when PII was shown unmasked.
+type PIIPresenter struct {
+	service ports.PIIService
+	access  *AccessRecorder
+}
+
+func NewPIIPresenter(service ports.PIIService, access *AccessRecorder) *PIIPresenter {
+	return &PIIPresenter{service: service, access: access}
+}
+
+// CanRead reports whether the current user may see unmasked PII.
+func (p *PIIPresenter) CanRead(c *gin.Context) bool {
+	userID, ok := currentUserID(c)
+	return ok && p.service.CanReadPII(c.Request.Context(), userID)
+}
+
+// Allowed reports whether the current user holds permission.
+func (p *PIIPresenter) Allowed(c *gin.Context, permission string) bool {
+	userID, ok := currentUserID(c)
+	return ok && p.service.HasPermission(c.Request.Context(), userID, permission)
+}
+
+func (p *PIIPresenter) Identities(c *gin.Context, identities ...*domain.Identity) {
+	p.present(c, nil, identities, true)
+}
+
+// FaceMatches presents the identities attached to search results. filters
+// describes the search, which arrives in the body rather than the query.
+func (p *PIIPresenter) FaceMatches(c *gin.Context, matches []*domain.FaceMatch, filters map[string]any) {
+	var identities []*domain.Identity
+	var ids []uuid.UUID
+	for _, m := range matches {
+		ids = append(ids, m.IdentityID)
+		if m.Identity != nil {
+			identities = append(identities, m.Identity)
+		}
+	}
+	p.access.Record(c, &domain.AccessLog{
+		ResourceType: domain.AccessResourceFaceImage,
+		IdentityIDs:  uniqueIdentityIDs(ids),
+		Filters:      filters,
+	})
+	p.present(c, filters, identities, false)
+}
+
+// Versions presents an identity's history entries.
+func (p *PIIPresenter) Versions(c *gin.Context, identityID uuid.UUID, versions []*domain.IdentityVersion) {
+	canRead := p.CanRead(c)
+	unmasked := false
+	for _, v := range versions {
+		if !canRead {
+			p.service.MaskVersion(v)
+		} else if v.Identity != nil && hasPII(v.Identity) {
+			unmasked = true
+		}
+	}
+	p.access.Record(c, &domain.AccessLog{
+		ResourceType: domain.AccessResourceIdentityHistory,
+		ResourceID:   identityID.String(),
+		IdentityIDs:  []uuid.UUID{identityID},
+		PIIUnmasked:  unmasked,
+	})
+}
+
+// present masks identities and records the read. recordFaces adds a face image
+// entry for identities that carry face URLs.
+func (p *PIIPresenter) present(c *gin.Context, filters map[string]any, identities []*domain.Identity, recordFaces bool) {
+	canRead := p.CanRead(c)
+	unmasked := false
+	var ids, withFaces []uuid.UUID
+	for _, identity := range identities {
+		if identity == nil {
+			continue
+		}
+		ids = append(ids, identity.ID)
+		if len(identity.Faces) > 0 || identity.FaceImageURL != "" {
+			withFaces = append(withFaces, identity.ID)
+		}
+		if !canRead {
+			p.service.MaskIdentity(identity)
+		} else if hasPII(identity) {
+			unmasked = true
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+
+	log := &domain.AccessLog{
+		ResourceType: domain.AccessResourceIdentity,
+		IdentityIDs:  uniqueIdentityIDs(ids),
+		Filters:      filters,
+		PIIUnmasked:  unmasked,
+	}
+	if len(ids) == 1 {
+		log.ResourceID = ids[0].String()
+	}
+	p.access.Record(c, log)
+	if recordFaces && len(withFaces) > 0 {
+		p.access.Record(c, &domain.AccessLog{
+			ResourceType: domain.AccessResourceFaceImage,
+			IdentityIDs:  uniqueIdentityIDs(withFaces),
+		})
+	}
+}
+
+func hasPII(identity *domain.Identity) bool {
+	return identity.PhoneNumber != "" || identity.IdentityCardNumber != "" || len(identity.Metadata) > 0
+}
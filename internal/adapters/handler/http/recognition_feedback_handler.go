@@ -0,0 +1,188 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+	"app/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type RecognitionFeedbackHandler struct {
+	service ports.RecognitionFeedbackService
+	pii     *PIIPresenter
+	access  *AccessRecorder
+}
+
+func NewRecognitionFeedbackHandler(service ports.RecognitionFeedbackService, pii *PIIPresenter, access *AccessRecorder) *RecognitionFeedbackHandler {
+	return &RecognitionFeedbackHandler{service: service, pii: pii, access: access}
+}
+
+// SubmitFeedback godoc
+// @Summary Confirm, reject or reassign a recognition
+// @Description Reject turns the log into a stranger log; reassign moves it to identity_id. Both need a reason and rebuild attendance for the affected identities on that day.
+// @Tags recognition
+// @Accept json
+// @Produce json
+// @Param id path string true "Recognition log ID"
+// @Param request body ports.RecognitionFeedbackRequest true "Verdict"
+// @Success 200 {object} domain.RecognitionReview
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /recognition/logs/{id}/feedback [post]
+func (h *RecognitionFeedbackHandler) SubmitFeedback(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid ID format"})
+		return
+	}
+
+	var req ports.RecognitionFeedbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	req.ReviewedBy = requestUserID(c)
+
+	review, err := h.service.SubmitFeedback(c.Request.Context(), id, &req)
+	if err != nil {
+		c.JSON(feedbackErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, review)
+}
+
+// ListFeedback godoc
+// @Summary List recognition feedback
+// @Tags recognition
+// @Produce json
+// @Param action query string false "confirm, reject or reassign"
+// @Param identity_id query string false "Original or corrected identity"
+// @Param camera_id query string false "Camera ID"
+// @Param from query string false "Reviewed from (RFC3339)"
+// @Param to query string false "Reviewed to (RFC3339)"
+// @Param page query int false "Page number"
+// @Param limit query int false "Limit"
+// @Success 200 {object} PaginatedResponse{data=[]domain.RecognitionFeedback}
+// @Security BearerAuth
+// @Router /recognition/feedback [get]
+func (h *RecognitionFeedbackHandler) ListFeedback(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+
+	filter := feedbackFilter(c)
+	filter.Limit = int32(limit)
+	filter.Offset = int32((page - 1) * limit)
+
+	feedback, total, err := h.service.ListFeedback(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(feedbackErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, PaginatedResponse{
+		Data:  feedback,
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	})
+}
+
+// ExportFeedbackDataset godoc
+// @Summary Export the labeled feedback dataset
+// @Description JSON lines, one per reviewed log with its latest verdict: predicted and corrected identity, crop, embedding and confidence. Requires the recognition:feedback:export permission.
+// @Tags recognition
+// @Produce application/x-ndjson
+// @Param action query string false "confirm, reject or reassign"
+// @Param identity_id query string false "Original or corrected identity"
+// @Param camera_id query string false "Camera ID"
+// @Param from query string false "Reviewed from (RFC3339)"
+// @Param to query string false "Reviewed to (RFC3339)"
+// @Success 200 {file} file
+// @Failure 403 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /recognition/feedback/export [get]
+func (h *RecognitionFeedbackHandler) ExportFeedbackDataset(c *gin.Context) {
+	// Embeddings are biometric data, so the dataset is never open to everyone
+	if !h.pii.Allowed(c, domain.PermissionFeedbackExport) {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: domain.ErrPermissionDenied.Error()})
+		return
+	}
+	filter := feedbackFilter(c)
+
+	c.Header("Content-Type", "application/x-ndjson")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=recognition-feedback-%s.jsonl", time.Now().Format("20060102")))
+	count, err := h.service.ExportDataset(c.Request.Context(), filter, requestUserID(c), c.Writer)
+	if c.Writer.Written() {
+		h.access.Record(c, &domain.AccessLog{ResourceType: domain.AccessResourceFeedbackDataset})
+	}
+	if err != nil {
+		if c.Writer.Written() {
+			logger.Error("Feedback export aborted", zap.Int("rows", count), zap.Error(err))
+			return
+		}
+		c.Writer.Header().Del("Content-Disposition")
+		c.JSON(feedbackErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+func feedbackFilter(c *gin.Context) *ports.FeedbackFilter {
+	filter := &ports.FeedbackFilter{}
+	if a := c.Query("action"); a != "" {
+		action := domain.FeedbackAction(a)
+		filter.Action = &action
+	}
+	if id := c.Query("identity_id"); id != "" {
+		if uid, err := uuid.Parse(id); err == nil {
+			filter.IdentityID = &uid
+		}
+	}
+	if cid := c.Query("camera_id"); cid != "" {
+		if uid, err := uuid.Parse(cid); err == nil {
+			filter.CameraID = &uid
+		}
+	}
+	if from := c.Query("from"); from != "" {
+		if t, err := time.Parse(time.RFC3339, from); err == nil {
+			filter.FromDate = &t
+		}
+	}
+	if to := c.Query("to"); to != "" {
+		if t, err := time.Parse(time.RFC3339, to); err == nil {
+			filter.ToDate = &t
+		}
+	}
+	return filter
+}
+
+func feedbackErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrRecognitionLogNotFound), errors.Is(err, domain.ErrIdentityNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrInvalidFeedbackAction), errors.Is(err, domain.ErrFeedbackReasonRequired),
+		errors.Is(err, domain.ErrFeedbackIdentityRequired), errors.Is(err, domain.ErrFeedbackNoIdentity),
+		errors.Is(err, domain.ErrFeedbackSameIdentity):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrFeedbackStale):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
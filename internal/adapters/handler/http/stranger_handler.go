@@ -0,0 +1,204 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type StrangerHandler struct {
+	service ports.StrangerService
+	access  *AccessRecorder
+}
+
+func NewStrangerHandler(service ports.StrangerService, access *AccessRecorder) *StrangerHandler {
+	return &StrangerHandler{service: service, access: access}
+}
+
+// ClusterStrangers godoc
+// @Summary Cluster unrecognized faces
+// @Description Groups stranger recognition logs by face embedding into "Unknown person #N" clusters in the background. Existing open clusters keep growing.
+// @Tags recognition
+// @Produce json
+// @Success 202 {object} domain.Job
+// @Security BearerAuth
+// @Router /recognition/strangers/cluster [post]
+func (h *StrangerHandler) ClusterStrangers(c *gin.Context) {
+	job, err := h.service.StartClustering(c.Request.Context(), requestUserID(c))
+	if err != nil {
+		c.JSON(strangerErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, job)
+}
+
+// ListStrangerClusters godoc
+// @Summary List stranger clusters
+// @Tags recognition
+// @Produce json
+// @Param status query string false "open or promoted"
+// @Param camera_id query string false "Only clusters seen by this camera"
+// @Param page query int false "Page number"
+// @Param limit query int false "Limit"
+// @Success 200 {object} PaginatedResponse{data=[]domain.StrangerCluster}
+// @Security BearerAuth
+// @Router /recognition/strangers [get]
+func (h *StrangerHandler) ListStrangerClusters(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+
+	filter := &ports.StrangerClusterFilter{
+		Limit:  int32(limit),
+		Offset: int32((page - 1) * limit),
+	}
+	if st := c.Query("status"); st != "" {
+		status := domain.StrangerClusterStatus(st)
+		filter.Status = &status
+	}
+	if cid := c.Query("camera_id"); cid != "" {
+		if uid, err := uuid.Parse(cid); err == nil {
+			filter.CameraID = &uid
+		}
+	}
+
+	clusters, total, err := h.service.ListClusters(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(strangerErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, PaginatedResponse{
+		Data:  clusters,
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	})
+}
+
+// GetStrangerCluster godoc
+// @Summary Get a stranger cluster
+// @Tags recognition
+// @Produce json
+// @Param id path string true "Cluster ID"
+// @Success 200 {object} domain.StrangerCluster
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /recognition/strangers/{id} [get]
+func (h *StrangerHandler) GetStrangerCluster(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid ID format"})
+		return
+	}
+	cluster, err := h.service.GetCluster(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(strangerErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, cluster)
+}
+
+// ListStrangerLogs godoc
+// @Summary List the recognition logs of a stranger cluster
+// @Tags recognition
+// @Produce json
+// @Param id path string true "Cluster ID"
+// @Param page query int false "Page number"
+// @Param limit query int false "Limit"
+// @Success 200 {object} PaginatedResponse{data=[]domain.RecognitionLog}
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /recognition/strangers/{id}/logs [get]
+func (h *StrangerHandler) ListStrangerLogs(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid ID format"})
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+
+	logs, total, err := h.service.ListClusterLogs(c.Request.Context(), id, int32(limit), int32((page-1)*limit))
+	if err != nil {
+		c.JSON(strangerErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	h.access.Record(c, &domain.AccessLog{
+		ResourceType: domain.AccessResourceRecognitionLog,
+		ResourceID:   id.String(),
+	})
+	c.JSON(http.StatusOK, PaginatedResponse{
+		Data:  logs,
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	})
+}
+
+// PromoteStranger godoc
+// @Summary Turn a stranger cluster into a pending identity
+// @Description Creates a pending identity, links the cluster's recognition logs to it and enrolls the best crops as faces. Crops failing the quality gate are skipped and reported.
+// @Tags recognition
+// @Accept json
+// @Produce json
+// @Param id path string true "Cluster ID"
+// @Param request body ports.PromoteStrangerRequest false "Identity details; code and name default to the cluster label"
+// @Success 201 {object} domain.StrangerPromotion
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /recognition/strangers/{id}/promote [post]
+func (h *StrangerHandler) PromoteStranger(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid ID format"})
+		return
+	}
+
+	var req ports.PromoteStrangerRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+	}
+	req.PromotedBy = requestUserID(c)
+
+	result, err := h.service.PromoteCluster(c.Request.Context(), id, &req)
+	if err != nil {
+		c.JSON(strangerErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, result)
+}
+
+func strangerErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrStrangerClusterNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrStrangerClusterPromoted):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrStrangerNoCrops):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
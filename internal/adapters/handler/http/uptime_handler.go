@@ -0,0 +1,183 @@
+package http
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type UptimeHandler struct {
+	service ports.UptimeService
+}
+
+func NewUptimeHandler(service ports.UptimeService) *UptimeHandler {
+	return &UptimeHandler{service: service}
+}
+
+// GetUptimeReport godoc
+// @Summary Camera availability report
+// @Description Uptime percentage, outage count, mean time to recovery and longest outage per camera, per zone and overall. Time in maintenance is excluded from uptime.
+// @Tags reports
+// @Produce json
+// @Param month query string false "Month (YYYY-MM, UTC), default current month"
+// @Param from query string false "From (RFC3339), overrides month"
+// @Param to query string false "To (RFC3339), overrides month"
+// @Param zone_id query string false "Zone ID, including the zones below it"
+// @Success 200 {object} domain.UptimeReport
+// @Failure 400 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /reports/uptime [get]
+func (h *UptimeHandler) GetUptimeReport(c *gin.Context) {
+	filter, ok := uptimeFilter(c)
+	if !ok {
+		return
+	}
+	report, err := h.service.GetUptimeReport(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(uptimeErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// ExportUptimeReport godoc
+// @Summary Export the camera availability report as CSV
+// @Description One row per camera, or per zone with group=zone. Same filters as the JSON report.
+// @Tags reports
+// @Produce text/csv
+// @Param month query string false "Month (YYYY-MM, UTC), default current month"
+// @Param from query string false "From (RFC3339), overrides month"
+// @Param to query string false "To (RFC3339), overrides month"
+// @Param zone_id query string false "Zone ID, including the zones below it"
+// @Param group query string false "camera (default) or zone"
+// @Success 200 {file} file
+// @Failure 400 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /reports/uptime/export [get]
+func (h *UptimeHandler) ExportUptimeReport(c *gin.Context) {
+	filter, ok := uptimeFilter(c)
+	if !ok {
+		return
+	}
+	report, err := h.service.GetUptimeReport(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(uptimeErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	byZone := c.Query("group") == "zone"
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=uptime-%s-%s.csv",
+		report.From.Format("20060102"), report.To.Format("20060102")))
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	stats := []string{"uptime_percent", "outages", "mttr_minutes", "longest_outage_minutes", "offline_minutes", "maintenance_minutes"}
+	if byZone {
+		_ = w.Write(append([]string{"zone_id", "zone_name", "cameras"}, stats...))
+		for _, z := range report.Zones {
+			_ = w.Write(append([]string{optionalUUID(z.ZoneID), z.ZoneName, strconv.Itoa(z.Cameras)}, uptimeColumns(z.UptimeStats)...))
+		}
+	} else {
+		_ = w.Write(append([]string{"camera_id", "camera_name", "zone_id", "zone_name"}, stats...))
+		for _, cam := range report.Cameras {
+			_ = w.Write(append([]string{cam.CameraID.String(), cam.CameraName, optionalUUID(cam.ZoneID), cam.ZoneName}, uptimeColumns(cam.UptimeStats)...))
+		}
+	}
+	w.Flush()
+}
+
+// WorstCameras godoc
+// @Summary Dashboard widget: cameras with the lowest uptime
+// @Tags ai
+// @Produce json
+// @Param days query int false "Look back this many days (default 30)"
+// @Param limit query int false "Cameras to list (default 5)"
+// @Success 200 {array} domain.CameraUptime
+// @Security BearerAuth
+// @Router /stats/worst-cameras [get]
+func (h *UptimeHandler) WorstCameras(c *gin.Context) {
+	days, _ := strconv.Atoi(c.DefaultQuery("days", "30"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "5"))
+
+	cameras, err := h.service.WorstCameras(c.Request.Context(), days, limit)
+	if err != nil {
+		c.JSON(uptimeErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, cameras)
+}
+
+func uptimeFilter(c *gin.Context) (*ports.UptimeFilter, bool) {
+	now := time.Now().UTC()
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if m := c.Query("month"); m != "" {
+		t, err := time.Parse("2006-01", m)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid month, expected YYYY-MM"})
+			return nil, false
+		}
+		month = t
+	}
+	filter := &ports.UptimeFilter{FromDate: month, ToDate: month.AddDate(0, 1, 0)}
+
+	if from := c.Query("from"); from != "" {
+		t, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid from date"})
+			return nil, false
+		}
+		filter.FromDate = t
+	}
+	if to := c.Query("to"); to != "" {
+		t, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid to date"})
+			return nil, false
+		}
+		filter.ToDate = t
+	}
+	if zid := c.Query("zone_id"); zid != "" {
+		if uid, err := uuid.Parse(zid); err == nil {
+			filter.ZoneID = &uid
+		}
+	}
+	return filter, true
+}
+
+func uptimeColumns(s domain.UptimeStats) []string {
+	minutes := func(sec float64) string { return strconv.FormatFloat(sec/60, 'f', 1, 64) }
+	return []string{
+		strconv.FormatFloat(s.UptimePercent, 'f', 3, 64),
+		strconv.Itoa(s.Outages),
+		minutes(s.MTTRSeconds),
+		minutes(s.LongestOutageSeconds),
+		minutes(s.OfflineSeconds),
+		minutes(s.MaintenanceSeconds),
+	}
+}
+
+func optionalUUID(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
+
+func uptimeErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrInvalidReportPeriod):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
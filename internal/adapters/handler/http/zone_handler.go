@@ -1,6 +1,7 @@
 package http
 
 import (
+	"errors"
 	"net/http"
 
 	"app/internal/core/domain"
@@ -11,14 +12,16 @@ import (
 
 type ZoneHandler struct {
 	service ports.ZoneService
+	scoper  *CameraScoper
 }
 
-func NewZoneHandler(service ports.ZoneService) *ZoneHandler {
-	return &ZoneHandler{service: service}
+func NewZoneHandler(service ports.ZoneService, scoper *CameraScoper) *ZoneHandler {
+	return &ZoneHandler{service: service, scoper: scoper}
 }
 
 // CreateZone godoc
 // @Summary Create a new zone
+// @Description Creates a top-level zone, or one nested under parent_id.
 // @Tags zones
 // @Accept json
 // @Produce json
@@ -35,7 +38,7 @@ func (h *ZoneHandler) CreateZone(c *gin.Context) {
 	}
 	zone, err := h.service.CreateZone(c.Request.Context(), &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(zoneErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 	c.JSON(http.StatusCreated, zone)
@@ -59,6 +62,27 @@ func (h *ZoneHandler) ListZones(c *gin.Context) {
 	c.JSON(http.StatusOK, zones)
 }
 
+// ZoneTree godoc
+// @Summary Get the zone hierarchy
+// @Description Every zone nested under its parent, with the number of cameras the caller may see in each zone and in its whole subtree.
+// @Tags zones
+// @Produce json
+// @Success 200 {array} domain.ZoneNode
+// @Security BearerAuth
+// @Router /zones/tree [get]
+func (h *ZoneHandler) ZoneTree(c *gin.Context) {
+	scope, ok := h.scoper.Scope(c)
+	if !ok {
+		return
+	}
+	tree, err := h.service.ZoneTree(c.Request.Context(), scope)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, tree)
+}
+
 // GetZone godoc
 // @Summary Get a zone by ID
 // @Tags zones
@@ -84,13 +108,16 @@ func (h *ZoneHandler) GetZone(c *gin.Context) {
 
 // UpdateZone godoc
 // @Summary Update a zone
+// @Description Setting parent_id moves the zone with its whole subtree; an empty parent_id makes it top-level. A zone cannot be moved under itself or its descendants.
 // @Tags zones
 // @Accept json
 // @Produce json
 // @Param id path string true "Zone ID"
 // @Param zone body domain.UpdateZoneRequest true "Update Info"
 // @Success 200 {object} domain.Zone
+// @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Security BearerAuth
 // @Router /zones/{id} [put]
 func (h *ZoneHandler) UpdateZone(c *gin.Context) {
@@ -102,7 +129,7 @@ func (h *ZoneHandler) UpdateZone(c *gin.Context) {
 	}
 	zone, err := h.service.UpdateZone(c.Request.Context(), id, &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(zoneErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 	if zone == nil {
@@ -114,16 +141,31 @@ func (h *ZoneHandler) UpdateZone(c *gin.Context) {
 
 // DeleteZone godoc
 // @Summary Delete a zone
+// @Description Zones with child zones cannot be deleted.
 // @Tags zones
 // @Param id path string true "Zone ID"
 // @Success 200 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Security BearerAuth
 // @Router /zones/{id} [delete]
 func (h *ZoneHandler) DeleteZone(c *gin.Context) {
 	id := c.Param("id")
 	if err := h.service.DeleteZone(c.Request.Context(), id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(zoneErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "Zone deleted"})
 }
+
+func zoneErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrZoneNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrZoneParentNotFound), errors.Is(err, domain.ErrInvalidZoneKind):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrZoneCycle), errors.Is(err, domain.ErrZoneHasChildren):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
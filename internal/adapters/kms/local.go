@@ -0,0 +1,139 @@
+package kms
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"app/config"
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+	"app/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// keyRingFile is the on-disk format of the local master key ring:
+// {"active": "k2", "keys": {"k1": "<base64>", "k2": "<base64>"}}.
+// Old keys stay in the file so data keys wrapped with them can be unwrapped
+// and re-wrapped after a master key rotation.
+type keyRingFile struct {
+	Active string            `json:"active"`
+	Keys   map[string]string `json:"keys"`
+}
+
+// LocalKeyManager is a file based stand-in for a KMS. It wraps data keys
+// with AES-256-GCM under the active master key.
+type LocalKeyManager struct {
+	active string
+	keys   map[string]cipher.AEAD
+}
+
+func NewLocalKeyManager(cfg config.EncryptionConfig) (ports.KeyManager, error) {
+	ring, err := loadKeyRing(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &LocalKeyManager{active: ring.Active, keys: make(map[string]cipher.AEAD, len(ring.Keys))}
+	for id, encoded := range ring.Keys {
+		raw, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(raw) != 32 {
+			return nil, fmt.Errorf("master key %s must be 32 bytes of base64", id)
+		}
+		aead, err := newGCM(raw)
+		if err != nil {
+			return nil, err
+		}
+		m.keys[id] = aead
+	}
+	if _, ok := m.keys[m.active]; !ok {
+		return nil, fmt.Errorf("%w: active key %q", domain.ErrMasterKeyNotFound, m.active)
+	}
+	return m, nil
+}
+
+func loadKeyRing(cfg config.EncryptionConfig) (*keyRingFile, error) {
+	if cfg.MasterKeyFile == "" {
+		if cfg.MasterKey == "" {
+			return nil, errors.New("encryption is enabled but neither master_key_file nor master_key is set")
+		}
+		return &keyRingFile{Active: "config", Keys: map[string]string{"config": cfg.MasterKey}}, nil
+	}
+
+	data, err := os.ReadFile(cfg.MasterKeyFile)
+	if os.IsNotExist(err) {
+		return createKeyRing(cfg.MasterKeyFile)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var ring keyRingFile
+	if err := json.Unmarshal(data, &ring); err != nil {
+		return nil, fmt.Errorf("invalid master key file: %w", err)
+	}
+	return &ring, nil
+}
+
+// createKeyRing bootstraps a key ring with one random master key. Losing
+// this file makes every encrypted column unreadable, so it must be backed up.
+func createKeyRing(path string) (*keyRingFile, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	ring := &keyRingFile{Active: "k1", Keys: map[string]string{"k1": base64.StdEncoding.EncodeToString(key)}}
+
+	data, err := json.MarshalIndent(ring, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return nil, err
+	}
+	logger.Info("Created local master key file, back it up", zap.String("path", path))
+	return ring, nil
+}
+
+func (m *LocalKeyManager) ActiveKeyID() string {
+	return m.active
+}
+
+func (m *LocalKeyManager) Wrap(ctx context.Context, plaintext []byte) ([]byte, string, error) {
+	aead := m.keys[m.active]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, "", err
+	}
+	return aead.Seal(nonce, nonce, plaintext, []byte(m.active)), m.active, nil
+}
+
+func (m *LocalKeyManager) Unwrap(ctx context.Context, masterKeyID string, wrapped []byte) ([]byte, error) {
+	aead, ok := m.keys[masterKeyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", domain.ErrMasterKeyNotFound, masterKeyID)
+	}
+	if len(wrapped) < aead.NonceSize() {
+		return nil, errors.New("wrapped key is too short")
+	}
+	nonce, sealed := wrapped[:aead.NonceSize()], wrapped[aead.NonceSize():]
+	return aead.Open(nil, nonce, sealed, []byte(masterKeyID))
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,146 @@
+package onvif
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"app/config"
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultMulticastAddress = "239.255.255.250:3702"
+	networkVideoTransmitter = "NetworkVideoTransmitter"
+)
+
+const probeTemplate = `<?xml version="1.0" encoding="UTF-8"?>
+<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope" xmlns:a="http://schemas.xmlsoap.org/ws/2004/08/addressing" xmlns:d="http://schemas.xmlsoap.org/ws/2005/04/discovery" xmlns:dn="http://www.onvif.org/ver10/network/wsdl">
+<s:Header>
+<a:Action s:mustUnderstand="1">http://schemas.xmlsoap.org/ws/2005/04/discovery/Probe</a:Action>
+<a:MessageID>uuid:%s</a:MessageID>
+<a:ReplyTo><a:Address>http://schemas.xmlsoap.org/ws/2004/08/addressing/role/anonymous</a:Address></a:ReplyTo>
+<a:To s:mustUnderstand="1">urn:schemas-xmlsoap-org:ws:2005:04:discovery</a:To>
+</s:Header>
+<s:Body><d:Probe><d:Types>dn:NetworkVideoTransmitter</d:Types></d:Probe></s:Body>
+</s:Envelope>`
+
+// Client discovers devices with WS-Discovery and queries them over the
+// ONVIF device and media SOAP services.
+type Client struct {
+	multicast string
+	wait      time.Duration
+	http      *http.Client
+}
+
+func NewClient(cfg config.DiscoveryConfig) ports.DeviceDiscoverer {
+	c := &Client{
+		multicast: cfg.MulticastAddress,
+		wait:      time.Duration(cfg.ProbeSeconds) * time.Second,
+		http:      &http.Client{Timeout: time.Duration(cfg.RequestTimeoutSeconds) * time.Second},
+	}
+	if c.multicast == "" {
+		c.multicast = defaultMulticastAddress
+	}
+	if c.wait <= 0 {
+		c.wait = 3 * time.Second
+	}
+	if c.http.Timeout <= 0 {
+		c.http.Timeout = 5 * time.Second
+	}
+	return c
+}
+
+type probeMatchEnvelope struct {
+	Header struct {
+		RelatesTo string `xml:"RelatesTo"`
+	} `xml:"Header"`
+	Body struct {
+		ProbeMatches struct {
+			ProbeMatch []struct {
+				EndpointReference struct {
+					Address string `xml:"Address"`
+				} `xml:"EndpointReference"`
+				Types  string `xml:"Types"`
+				Scopes string `xml:"Scopes"`
+				XAddrs string `xml:"XAddrs"`
+			} `xml:"ProbeMatch"`
+		} `xml:"ProbeMatches"`
+	} `xml:"Body"`
+}
+
+// Discover multicasts one Probe and collects matches until the probe wait
+// elapses. A device answering more than once is reported once.
+func (c *Client) Discover(ctx context.Context) ([]domain.ONVIFEndpoint, error) {
+	group, err := net.ResolveUDPAddr("udp4", c.multicast)
+	if err != nil {
+		return nil, fmt.Errorf("resolve multicast address: %w", err)
+	}
+	conn, err := net.ListenUDP("udp4", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	messageID := uuid.NewString()
+	if _, err := conn.WriteToUDP([]byte(fmt.Sprintf(probeTemplate, messageID)), group); err != nil {
+		return nil, fmt.Errorf("send probe: %w", err)
+	}
+
+	deadline := time.Now().Add(c.wait)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = conn.SetReadDeadline(deadline)
+
+	endpoints := []domain.ONVIFEndpoint{}
+	seen := map[string]bool{}
+	buf := make([]byte, 64*1024)
+	for {
+		n, _, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Timeout() {
+				return endpoints, nil
+			}
+			return endpoints, err
+		}
+		if ctx.Err() != nil {
+			return endpoints, ctx.Err()
+		}
+
+		var env probeMatchEnvelope
+		if err := xml.Unmarshal(buf[:n], &env); err != nil {
+			continue
+		}
+		// Replies to other clients' probes share the multicast group
+		if env.Header.RelatesTo != "" && !strings.HasSuffix(env.Header.RelatesTo, messageID) {
+			continue
+		}
+		for _, m := range env.Body.ProbeMatches.ProbeMatch {
+			ref := strings.TrimSpace(m.EndpointReference.Address)
+			xaddrs := strings.Fields(m.XAddrs)
+			if len(xaddrs) == 0 || !strings.Contains(m.Types, networkVideoTransmitter) {
+				continue
+			}
+			if ref == "" {
+				ref = xaddrs[0]
+			}
+			if seen[ref] {
+				continue
+			}
+			seen[ref] = true
+			endpoints = append(endpoints, domain.ONVIFEndpoint{
+				EndpointRef: ref,
+				XAddrs:      xaddrs,
+				Scopes:      strings.Fields(m.Scopes),
+			})
+		}
+	}
+}
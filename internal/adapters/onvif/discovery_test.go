@@ -0,0 +1,93 @@
+package onvif
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+)
+
+const probeMatchTemplate = `<?xml version="1.0" encoding="UTF-8"?>
+<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope" xmlns:a="http://schemas.xmlsoap.org/ws/2004/08/addressing" xmlns:d="http://schemas.xmlsoap.org/ws/2005/04/discovery" xmlns:dn="http://www.onvif.org/ver10/network/wsdl">
+<s:Header><a:RelatesTo>uuid:%s</a:RelatesTo></s:Header>
+<s:Body><d:ProbeMatches><d:ProbeMatch>
+<a:EndpointReference><a:Address>%s</a:Address></a:EndpointReference>
+<d:Types>%s</d:Types>
+<d:Scopes>onvif://www.onvif.org/name/Lobby onvif://www.onvif.org/hardware/X1</d:Scopes>
+<d:XAddrs>%s</d:XAddrs>
+</d:ProbeMatch></d:ProbeMatches></s:Body>
+</s:Envelope>`
+
+var messageIDPattern = regexp.MustCompile(`<a:MessageID>uuid:([^<]+)</a:MessageID>`)
+
+// respondToProbe stands in for the multicast group: it reads one Probe and
+// sends each reply built from its message ID back to the prober.
+func respondToProbe(t *testing.T, replies func(messageID string) []string) string {
+	t.Helper()
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 64*1024)
+		n, from, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			return
+		}
+		m := messageIDPattern.FindSubmatch(buf[:n])
+		if m == nil {
+			return
+		}
+		for _, reply := range replies(string(m[1])) {
+			_, _ = conn.WriteToUDP([]byte(reply), from)
+		}
+	}()
+	return conn.LocalAddr().String()
+}
+
+func TestDiscoverFiltersReplies(t *testing.T) {
+	addr := respondToProbe(t, func(messageID string) []string {
+		const nvt = "dn:NetworkVideoTransmitter"
+		return []string{
+			// An answer to another client's probe
+			fmt.Sprintf(probeMatchTemplate, "00000000-0000-0000-0000-000000000000", "urn:uuid:other", nvt, "http://10.0.0.9/onvif/device_service"),
+			fmt.Sprintf(probeMatchTemplate, messageID, "urn:uuid:cam-1", nvt, "http://10.0.0.5/onvif/device_service http://[fe80::1]/onvif/device_service"),
+			// The same device answering twice
+			fmt.Sprintf(probeMatchTemplate, messageID, "urn:uuid:cam-1", nvt, "http://10.0.0.5/onvif/device_service"),
+			// Not a camera
+			fmt.Sprintf(probeMatchTemplate, messageID, "urn:uuid:printer", "dn:Device", "http://10.0.0.7/onvif/device_service"),
+			"not xml",
+		}
+	})
+
+	client := &Client{multicast: addr, wait: 500 * time.Millisecond, http: http.DefaultClient}
+	endpoints, err := client.Discover(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(endpoints) != 1 {
+		t.Fatalf("got %d endpoints, want only cam-1: %+v", len(endpoints), endpoints)
+	}
+	ep := endpoints[0]
+	if ep.EndpointRef != "urn:uuid:cam-1" || len(ep.XAddrs) != 2 || ep.XAddrs[0] != "http://10.0.0.5/onvif/device_service" {
+		t.Fatalf("endpoint = %+v", ep)
+	}
+	if len(ep.Scopes) != 2 {
+		t.Fatalf("scopes = %v, want 2", ep.Scopes)
+	}
+}
+
+func TestDiscoverNoReplies(t *testing.T) {
+	addr := respondToProbe(t, func(string) []string { return nil })
+
+	client := &Client{multicast: addr, wait: 200 * time.Millisecond, http: http.DefaultClient}
+	endpoints, err := client.Discover(context.Background())
+	if err != nil || len(endpoints) != 0 {
+		t.Fatalf("Discover = %v, %v, want no endpoints once the wait elapses", endpoints, err)
+	}
+}
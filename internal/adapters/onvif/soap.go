@@ -0,0 +1,251 @@
+package onvif
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"app/internal/core/domain"
+)
+
+const envelopeTemplate = `<?xml version="1.0" encoding="UTF-8"?>
+<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope" xmlns:tds="http://www.onvif.org/ver10/device/wsdl" xmlns:trt="http://www.onvif.org/ver10/media/wsdl" xmlns:tt="http://www.onvif.org/ver10/schema">
+<s:Header>%s</s:Header>
+<s:Body>%s</s:Body>
+</s:Envelope>`
+
+const securityTemplate = `<wsse:Security s:mustUnderstand="1" xmlns:wsse="http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-wssecurity-secext-1.0.xsd" xmlns:wsu="http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-wssecurity-utility-1.0.xsd">
+<wsse:UsernameToken>
+<wsse:Username>%s</wsse:Username>
+<wsse:Password Type="http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-username-token-profile-1.0#PasswordDigest">%s</wsse:Password>
+<wsse:Nonce EncodingType="http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-soap-message-security-1.0#Base64Binary">%s</wsse:Nonce>
+<wsu:Created>%s</wsu:Created>
+</wsse:UsernameToken>
+</wsse:Security>`
+
+type soapFault struct {
+	Code struct {
+		Value   string `xml:"Value"`
+		Subcode struct {
+			Value string `xml:"Value"`
+		} `xml:"Subcode"`
+	} `xml:"Code"`
+	Reason struct {
+		Text string `xml:"Text"`
+	} `xml:"Reason"`
+}
+
+func (f *soapFault) Error() string {
+	code := f.Code.Subcode.Value
+	if code == "" {
+		code = f.Code.Value
+	}
+	if i := strings.IndexByte(code, ':'); i >= 0 {
+		code = code[i+1:]
+	}
+	if f.Reason.Text == "" {
+		return code
+	}
+	return code + ": " + strings.TrimSpace(f.Reason.Text)
+}
+
+type responseEnvelope struct {
+	Body struct {
+		Fault   *soapFault `xml:"Fault"`
+		Content []byte     `xml:",innerxml"`
+	} `xml:"Body"`
+}
+
+// session queries one device. Its clock offset keeps WS-Security timestamps
+// in step with the device, which rejects tokens created too far from its
+// own time.
+type session struct {
+	client   *Client
+	username string
+	password string
+	offset   time.Duration
+}
+
+func (s *session) call(ctx context.Context, endpoint, body string, out any) error {
+	header := ""
+	if s.username != "" {
+		header = s.security()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint,
+		strings.NewReader(fmt.Sprintf(envelopeTemplate, header, body)))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/soap+xml; charset=utf-8")
+
+	res, err := s.client.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(res.Body, 4<<20))
+	if err != nil {
+		return err
+	}
+	if res.StatusCode == http.StatusUnauthorized {
+		return errors.New("unauthorized")
+	}
+
+	var env responseEnvelope
+	if err := xml.Unmarshal(data, &env); err != nil {
+		if res.StatusCode != http.StatusOK {
+			return fmt.Errorf("http %d", res.StatusCode)
+		}
+		return fmt.Errorf("invalid soap response: %w", err)
+	}
+	if env.Body.Fault != nil {
+		return env.Body.Fault
+	}
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("http %d", res.StatusCode)
+	}
+	return xml.Unmarshal(env.Body.Content, out)
+}
+
+func (s *session) security() string {
+	nonce := make([]byte, 16)
+	_, _ = rand.Read(nonce)
+	created := time.Now().Add(s.offset).UTC().Format("2006-01-02T15:04:05.000Z")
+	digest := sha1.Sum(append(append(append([]byte{}, nonce...), created...), s.password...))
+	return fmt.Sprintf(securityTemplate, escape(s.username),
+		base64.StdEncoding.EncodeToString(digest[:]), base64.StdEncoding.EncodeToString(nonce), created)
+}
+
+func escape(s string) string {
+	var b bytes.Buffer
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+type dateTimeResponse struct {
+	SystemDateAndTime struct {
+		UTCDateTime struct {
+			Date struct {
+				Year  int `xml:"Year"`
+				Month int `xml:"Month"`
+				Day   int `xml:"Day"`
+			} `xml:"Date"`
+			Time struct {
+				Hour   int `xml:"Hour"`
+				Minute int `xml:"Minute"`
+				Second int `xml:"Second"`
+			} `xml:"Time"`
+		} `xml:"UTCDateTime"`
+	} `xml:"SystemDateAndTime"`
+}
+
+type deviceInformationResponse struct {
+	Manufacturer    string `xml:"Manufacturer"`
+	Model           string `xml:"Model"`
+	FirmwareVersion string `xml:"FirmwareVersion"`
+	SerialNumber    string `xml:"SerialNumber"`
+	HardwareID      string `xml:"HardwareId"`
+}
+
+type capabilitiesResponse struct {
+	Capabilities struct {
+		Media struct {
+			XAddr string `xml:"XAddr"`
+		} `xml:"Media"`
+	} `xml:"Capabilities"`
+}
+
+type profilesResponse struct {
+	Profiles []struct {
+		Token string `xml:"token,attr"`
+		Name  string `xml:"Name"`
+		Video struct {
+			Encoding   string `xml:"Encoding"`
+			Resolution struct {
+				Width  int `xml:"Width"`
+				Height int `xml:"Height"`
+			} `xml:"Resolution"`
+		} `xml:"VideoEncoderConfiguration"`
+	} `xml:"Profiles"`
+}
+
+type streamURIResponse struct {
+	MediaURI struct {
+		URI string `xml:"Uri"`
+	} `xml:"MediaUri"`
+}
+
+// Describe reads device information, finds the media service and asks for
+// an RTSP unicast URI for every profile. A profile whose URI cannot be read
+// is still listed, without one.
+func (c *Client) Describe(ctx context.Context, xaddr, username, password string) (*domain.DiscoveredDevice, error) {
+	u, err := url.Parse(xaddr)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid device address %q", xaddr)
+	}
+	device := &domain.DiscoveredDevice{XAddr: xaddr, IPAddress: u.Hostname()}
+	if ip := net.ParseIP(device.IPAddress); ip == nil {
+		if addrs, err := net.DefaultResolver.LookupHost(ctx, device.IPAddress); err == nil && len(addrs) > 0 {
+			device.IPAddress = addrs[0]
+		}
+	}
+
+	s := &session{client: c, username: username, password: password}
+	var clock dateTimeResponse
+	if err := s.call(ctx, xaddr, `<tds:GetSystemDateAndTime/>`, &clock); err == nil {
+		d, t := clock.SystemDateAndTime.UTCDateTime.Date, clock.SystemDateAndTime.UTCDateTime.Time
+		if d.Year > 0 {
+			s.offset = time.Until(time.Date(d.Year, time.Month(d.Month), d.Day, t.Hour, t.Minute, t.Second, 0, time.UTC))
+		}
+	}
+
+	var info deviceInformationResponse
+	if err := s.call(ctx, xaddr, `<tds:GetDeviceInformation/>`, &info); err != nil {
+		return device, fmt.Errorf("get device information: %w", err)
+	}
+	device.Manufacturer = info.Manufacturer
+	device.Model = info.Model
+	device.FirmwareVersion = info.FirmwareVersion
+	device.SerialNumber = info.SerialNumber
+	device.HardwareID = info.HardwareID
+
+	media := xaddr
+	var caps capabilitiesResponse
+	if err := s.call(ctx, xaddr, `<tds:GetCapabilities><tds:Category>Media</tds:Category></tds:GetCapabilities>`, &caps); err == nil && caps.Capabilities.Media.XAddr != "" {
+		media = caps.Capabilities.Media.XAddr
+	}
+
+	var profiles profilesResponse
+	if err := s.call(ctx, media, `<trt:GetProfiles/>`, &profiles); err != nil {
+		return device, fmt.Errorf("get profiles: %w", err)
+	}
+	device.Profiles = []domain.DeviceProfile{}
+	for _, p := range profiles.Profiles {
+		profile := domain.DeviceProfile{
+			Token:    p.Token,
+			Name:     p.Name,
+			Encoding: p.Video.Encoding,
+			Width:    p.Video.Resolution.Width,
+			Height:   p.Video.Resolution.Height,
+		}
+		var stream streamURIResponse
+		body := `<trt:GetStreamUri><trt:StreamSetup><tt:Stream>RTP-Unicast</tt:Stream><tt:Transport><tt:Protocol>RTSP</tt:Protocol></tt:Transport></trt:StreamSetup><trt:ProfileToken>` +
+			escape(p.Token) + `</trt:ProfileToken></trt:GetStreamUri>`
+		if err := s.call(ctx, media, body, &stream); err == nil {
+			profile.RTSPURL = strings.TrimSpace(stream.MediaURI.URI)
+		}
+		device.Profiles = append(device.Profiles, profile)
+	}
+	return device, nil
+}
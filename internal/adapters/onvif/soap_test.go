@@ -0,0 +1,141 @@
+package onvif
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+const responseTemplate = `<?xml version="1.0" encoding="UTF-8"?>
+<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope" xmlns:tds="http://www.onvif.org/ver10/device/wsdl" xmlns:trt="http://www.onvif.org/ver10/media/wsdl" xmlns:tt="http://www.onvif.org/ver10/schema" xmlns:ter="http://www.onvif.org/ver10/error">
+<s:Body>%s</s:Body>
+</s:Envelope>`
+
+const faultBody = `<s:Fault><s:Code><s:Value>s:Sender</s:Value><s:Subcode><s:Value>ter:%s</s:Value></s:Subcode></s:Code><s:Reason><s:Text xml:lang="en">%s</s:Text></s:Reason></s:Fault>`
+
+var tokenPattern = regexp.MustCompile(`<wsse:Password[^>]*>([^<]+)</wsse:Password>\s*<wsse:Nonce[^>]*>([^<]+)</wsse:Nonce>\s*<wsu:Created>([^<]+)</wsu:Created>`)
+
+// fakeDevice answers the ONVIF calls Describe makes. Requests without a
+// valid UsernameToken for admin/secret get a NotAuthorized fault.
+func fakeDevice(t *testing.T) *httptest.Server {
+	t.Helper()
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		req := string(data)
+		reply := func(status int, body string) {
+			w.Header().Set("Content-Type", "application/soap+xml")
+			w.WriteHeader(status)
+			fmt.Fprintf(w, responseTemplate, body)
+		}
+
+		if strings.Contains(req, "<tds:GetSystemDateAndTime/>") {
+			now := time.Now().UTC()
+			reply(http.StatusOK, fmt.Sprintf(`<tds:GetSystemDateAndTimeResponse><tds:SystemDateAndTime><tt:UTCDateTime>
+<tt:Date><tt:Year>%d</tt:Year><tt:Month>%d</tt:Month><tt:Day>%d</tt:Day></tt:Date>
+<tt:Time><tt:Hour>%d</tt:Hour><tt:Minute>%d</tt:Minute><tt:Second>%d</tt:Second></tt:Time>
+</tt:UTCDateTime></tds:SystemDateAndTime></tds:GetSystemDateAndTimeResponse>`,
+				now.Year(), now.Month(), now.Day(), now.Hour(), now.Minute(), now.Second()))
+			return
+		}
+		if !validToken(req, "admin", "secret") {
+			reply(http.StatusBadRequest, fmt.Sprintf(faultBody, "NotAuthorized", "Sender not authorized"))
+			return
+		}
+
+		switch {
+		case strings.Contains(req, "<tds:GetDeviceInformation/>"):
+			reply(http.StatusOK, `<tds:GetDeviceInformationResponse><tds:Manufacturer>Acme</tds:Manufacturer><tds:Model>X1</tds:Model>
+<tds:FirmwareVersion>2.1</tds:FirmwareVersion><tds:SerialNumber>SN42</tds:SerialNumber><tds:HardwareId>HW7</tds:HardwareId></tds:GetDeviceInformationResponse>`)
+		case strings.Contains(req, "<tds:GetCapabilities>"):
+			reply(http.StatusOK, `<tds:GetCapabilitiesResponse><tds:Capabilities><tt:Media><tt:XAddr>`+server.URL+`/onvif/media</tt:XAddr></tt:Media></tds:Capabilities></tds:GetCapabilitiesResponse>`)
+		case strings.Contains(req, "<trt:GetProfiles/>") && r.URL.Path == "/onvif/media":
+			reply(http.StatusOK, `<trt:GetProfilesResponse>
+<trt:Profiles token="main"><tt:Name>Main</tt:Name><tt:VideoEncoderConfiguration><tt:Encoding>H264</tt:Encoding><tt:Resolution><tt:Width>1920</tt:Width><tt:Height>1080</tt:Height></tt:Resolution></tt:VideoEncoderConfiguration></trt:Profiles>
+<trt:Profiles token="sub"><tt:Name>Sub</tt:Name><tt:VideoEncoderConfiguration><tt:Encoding>H264</tt:Encoding><tt:Resolution><tt:Width>640</tt:Width><tt:Height>360</tt:Height></tt:Resolution></tt:VideoEncoderConfiguration></trt:Profiles>
+</trt:GetProfilesResponse>`)
+		case strings.Contains(req, "<trt:ProfileToken>main</trt:ProfileToken>"):
+			reply(http.StatusOK, `<trt:GetStreamUriResponse><trt:MediaUri><tt:Uri> rtsp://10.0.0.5:554/main </tt:Uri></trt:MediaUri></trt:GetStreamUriResponse>`)
+		case strings.Contains(req, "<trt:ProfileToken>sub</trt:ProfileToken>"):
+			reply(http.StatusInternalServerError, fmt.Sprintf(faultBody, "NoProfile", "Profile has no stream"))
+		default:
+			reply(http.StatusBadRequest, fmt.Sprintf(faultBody, "ActionNotSupported", "Unexpected request"))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func validToken(req, username, password string) bool {
+	if !strings.Contains(req, "<wsse:Username>"+username+"</wsse:Username>") {
+		return false
+	}
+	m := tokenPattern.FindStringSubmatch(req)
+	if m == nil {
+		return false
+	}
+	nonce, err := base64.StdEncoding.DecodeString(m[2])
+	if err != nil {
+		return false
+	}
+	digest := sha1.Sum([]byte(string(nonce) + m[3] + password))
+	return m[1] == base64.StdEncoding.EncodeToString(digest[:])
+}
+
+func TestDescribe(t *testing.T) {
+	server := fakeDevice(t)
+	client := &Client{http: server.Client()}
+
+	device, err := client.Describe(context.Background(), server.URL+"/onvif/device_service", "admin", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if device.Manufacturer != "Acme" || device.Model != "X1" || device.SerialNumber != "SN42" || device.HardwareID != "HW7" {
+		t.Fatalf("device = %+v", device)
+	}
+	if device.IPAddress != "127.0.0.1" {
+		t.Fatalf("IPAddress = %q", device.IPAddress)
+	}
+	if len(device.Profiles) != 2 {
+		t.Fatalf("got %d profiles, want 2", len(device.Profiles))
+	}
+	main, sub := device.Profiles[0], device.Profiles[1]
+	if main.Token != "main" || main.Width != 1920 || main.Encoding != "H264" || main.RTSPURL != "rtsp://10.0.0.5:554/main" {
+		t.Fatalf("main profile = %+v", main)
+	}
+	// A fault on one profile's stream URI leaves it listed without a URL
+	if sub.Token != "sub" || sub.RTSPURL != "" {
+		t.Fatalf("sub profile = %+v, want no stream URL", sub)
+	}
+}
+
+func TestDescribeFault(t *testing.T) {
+	server := fakeDevice(t)
+	client := &Client{http: server.Client()}
+
+	device, err := client.Describe(context.Background(), server.URL+"/onvif/device_service", "admin", "wrong")
+	if err == nil {
+		t.Fatal("Describe succeeded with the wrong password")
+	}
+	if !strings.Contains(err.Error(), "get device information: NotAuthorized: Sender not authorized") {
+		t.Fatalf("err = %v, want the device's fault", err)
+	}
+	if device == nil || device.IPAddress != "127.0.0.1" {
+		t.Fatalf("device = %+v, want the address even on failure", device)
+	}
+}
+
+func TestDescribeInvalidAddress(t *testing.T) {
+	client := &Client{http: http.DefaultClient}
+	if _, err := client.Describe(context.Background(), "not a url", "", ""); err == nil {
+		t.Fatal("Describe accepted an address without a host")
+	}
+}
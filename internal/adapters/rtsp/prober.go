@@ -0,0 +1,195 @@
+package rtsp
+
+import (
+	"bufio"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+)
+
+const (
+	defaultPort = "554"
+	userAgent   = "ai-camera-monitor"
+)
+
+// Prober checks a camera with a TCP connect followed by an RTSP OPTIONS and
+// DESCRIBE handshake, answering a Basic or Digest challenge with the
+// credentials embedded in the URL.
+type Prober struct {
+	timeout time.Duration
+}
+
+func NewProber(timeout time.Duration) ports.CameraProber {
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	return &Prober{timeout: timeout}
+}
+
+func (p *Prober) Probe(ctx context.Context, rtspURL string) *domain.CameraProbe {
+	start := time.Now()
+	probe := &domain.CameraProbe{ProbedAt: start}
+	defer func() { probe.Latency = time.Since(start) }()
+
+	u, err := url.Parse(rtspURL)
+	if err != nil || u.Scheme != "rtsp" || u.Hostname() == "" {
+		probe.Error = "invalid rtsp url"
+		return probe
+	}
+	port := u.Port()
+	if port == "" {
+		port = defaultPort
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(u.Hostname(), port))
+	if err != nil {
+		probe.Error = "connect: " + err.Error()
+		return probe
+	}
+	defer conn.Close()
+	probe.Reachable = true
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	s := &session{conn: conn, reader: textproto.NewReader(bufio.NewReader(conn)), target: requestURL(u)}
+	if _, err := s.do("OPTIONS", ""); err != nil {
+		probe.Error = "options: " + err.Error()
+		return probe
+	}
+
+	res, err := s.do("DESCRIBE", "")
+	if err == nil && res.code == 401 && u.User != nil {
+		var auth string
+		auth, err = authorization(res.header.Get("WWW-Authenticate"), u.User, "DESCRIBE", s.target)
+		if err == nil {
+			res, err = s.do("DESCRIBE", auth)
+		}
+	}
+	if err != nil {
+		probe.Error = "describe: " + err.Error()
+		return probe
+	}
+	probe.StatusCode = res.code
+	switch {
+	case res.code == 200:
+		probe.Healthy = true
+	case res.code == 401:
+		probe.Error = "authentication failed"
+	default:
+		probe.Error = fmt.Sprintf("describe: %d %s", res.code, res.reason)
+	}
+	return probe
+}
+
+// requestURL is the stream URL without credentials, as sent on the wire.
+func requestURL(u *url.URL) string {
+	clean := *u
+	clean.User = nil
+	return clean.String()
+}
+
+type response struct {
+	code   int
+	reason string
+	header textproto.MIMEHeader
+}
+
+type session struct {
+	conn   net.Conn
+	reader *textproto.Reader
+	target string
+	cseq   int
+}
+
+func (s *session) do(method, auth string) (*response, error) {
+	s.cseq++
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s RTSP/1.0\r\nCSeq: %d\r\nUser-Agent: %s\r\n", method, s.target, s.cseq, userAgent)
+	if method == "DESCRIBE" {
+		b.WriteString("Accept: application/sdp\r\n")
+	}
+	if auth != "" {
+		fmt.Fprintf(&b, "Authorization: %s\r\n", auth)
+	}
+	b.WriteString("\r\n")
+	if _, err := io.WriteString(s.conn, b.String()); err != nil {
+		return nil, err
+	}
+
+	line, err := s.reader.ReadLine()
+	if err != nil {
+		return nil, err
+	}
+	proto, status, ok := strings.Cut(line, " ")
+	if !ok || !strings.HasPrefix(proto, "RTSP/") {
+		return nil, fmt.Errorf("not an rtsp response: %q", line)
+	}
+	codeStr, reason, _ := strings.Cut(status, " ")
+	code, err := strconv.Atoi(codeStr)
+	if err != nil {
+		return nil, fmt.Errorf("bad status line: %q", line)
+	}
+	header, err := s.reader.ReadMIMEHeader()
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	// Drain the body (the SDP) so the next request starts on a fresh line
+	if n, _ := strconv.Atoi(header.Get("Content-Length")); n > 0 {
+		if _, err := io.CopyN(io.Discard, s.reader.R, int64(n)); err != nil {
+			return nil, err
+		}
+	}
+	return &response{code: code, reason: reason, header: header}, nil
+}
+
+// authorization answers a WWW-Authenticate challenge. Digest is RFC 2617
+// without qop, which is what RTSP cameras use.
+func authorization(challenge string, user *url.Userinfo, method, uri string) (string, error) {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	password, _ := user.Password()
+	switch strings.ToLower(scheme) {
+	case "basic":
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(user.Username()+":"+password)), nil
+	case "digest":
+		fields := parseChallenge(params)
+		realm, nonce := fields["realm"], fields["nonce"]
+		ha1 := md5Hex(user.Username() + ":" + realm + ":" + password)
+		ha2 := md5Hex(method + ":" + uri)
+		return fmt.Sprintf(`Digest username="%s", realm="%s", nonce="%s", uri="%s", response="%s"`,
+			user.Username(), realm, nonce, uri, md5Hex(ha1+":"+nonce+":"+ha2)), nil
+	default:
+		return "", fmt.Errorf("unsupported auth scheme %q", scheme)
+	}
+}
+
+func parseChallenge(params string) map[string]string {
+	fields := map[string]string{}
+	for _, part := range strings.Split(params, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if ok {
+			fields[strings.ToLower(key)] = strings.Trim(value, `"`)
+		}
+	}
+	return fields
+}
+
+func md5Hex(s string) string {
+	sum := md5.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,147 @@
+package rtsp
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/textproto"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+type request struct {
+	method string
+	header textproto.MIMEHeader
+}
+
+// serveRTSP accepts one connection on a local port and answers each request
+// with reply's status line, extra header lines and body. It returns the
+// rtsp:// address and the requests it saw once the probe hangs up.
+func serveRTSP(t *testing.T, reply func(req request) (status, header, body string)) (string, <-chan []request) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	seen := make(chan []request, 1)
+	go func() {
+		var reqs []request
+		defer func() { seen <- reqs }()
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := textproto.NewReader(bufio.NewReader(conn))
+		for {
+			line, err := reader.ReadLine()
+			if err != nil {
+				return
+			}
+			header, err := reader.ReadMIMEHeader()
+			if err != nil {
+				return
+			}
+			req := request{method: strings.Fields(line)[0], header: header}
+			reqs = append(reqs, req)
+			status, extra, body := reply(req)
+			if body != "" {
+				extra += fmt.Sprintf("Content-Length: %d\r\n", len(body))
+			}
+			fmt.Fprintf(conn, "RTSP/1.0 %s\r\nCSeq: %s\r\n%s\r\n%s", status, header.Get("CSeq"), extra, body)
+		}
+	}()
+	return "rtsp://" + ln.Addr().String() + "/stream1", seen
+}
+
+func TestProbeHealthy(t *testing.T) {
+	sdp := "v=0\r\no=- 0 0 IN IP4 127.0.0.1\r\n"
+	addr, seen := serveRTSP(t, func(req request) (string, string, string) {
+		if req.method == "DESCRIBE" {
+			return "200 OK", "Content-Type: application/sdp\r\n", sdp
+		}
+		return "200 OK", "Public: OPTIONS, DESCRIBE\r\n", ""
+	})
+
+	probe := NewProber(time.Second).Probe(context.Background(), addr)
+	if !probe.Healthy || !probe.Reachable || probe.StatusCode != 200 || probe.Error != "" {
+		t.Fatalf("probe = %+v, want healthy", probe)
+	}
+	reqs := <-seen
+	if len(reqs) != 2 || reqs[0].method != "OPTIONS" || reqs[1].method != "DESCRIBE" {
+		t.Fatalf("requests = %v, want OPTIONS then DESCRIBE", reqs)
+	}
+}
+
+func TestProbeDigestRetry(t *testing.T) {
+	const challenge = `Digest realm="cam", nonce="abc123"`
+	addr, seen := serveRTSP(t, func(req request) (string, string, string) {
+		if req.method != "DESCRIBE" {
+			return "200 OK", "", ""
+		}
+		if req.header.Get("Authorization") == "" {
+			return "401 Unauthorized", "WWW-Authenticate: " + challenge + "\r\n", ""
+		}
+		return "200 OK", "", ""
+	})
+	u, _ := url.Parse(addr)
+	u.User = url.UserPassword("admin", "secret")
+
+	probe := NewProber(time.Second).Probe(context.Background(), u.String())
+	if !probe.Healthy || probe.StatusCode != 200 {
+		t.Fatalf("probe = %+v, want healthy after the digest retry", probe)
+	}
+	reqs := <-seen
+	if len(reqs) != 3 {
+		t.Fatalf("got %d requests, want OPTIONS, DESCRIBE and the authorized DESCRIBE", len(reqs))
+	}
+	want, _ := authorization(challenge, u.User, "DESCRIBE", addr)
+	if got := reqs[2].header.Get("Authorization"); got != want {
+		t.Fatalf("Authorization = %q, want %q", got, want)
+	}
+	if strings.Contains(reqs[2].header.Get("Authorization"), "secret") {
+		t.Fatal("digest authorization leaked the password")
+	}
+}
+
+func TestProbeWrongCredentials(t *testing.T) {
+	addr, _ := serveRTSP(t, func(req request) (string, string, string) {
+		if req.method == "DESCRIBE" {
+			return "401 Unauthorized", "WWW-Authenticate: Basic realm=\"cam\"\r\n", ""
+		}
+		return "200 OK", "", ""
+	})
+	u, _ := url.Parse(addr)
+	u.User = url.UserPassword("admin", "wrong")
+
+	probe := NewProber(time.Second).Probe(context.Background(), u.String())
+	if probe.Healthy || probe.StatusCode != 401 || probe.Error != "authentication failed" {
+		t.Fatalf("probe = %+v, want authentication failed", probe)
+	}
+}
+
+func TestProbeConnectionRefused(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	probe := NewProber(time.Second).Probe(context.Background(), "rtsp://"+addr+"/stream1")
+	if probe.Healthy || probe.Reachable || !strings.HasPrefix(probe.Error, "connect: ") {
+		t.Fatalf("probe = %+v, want an unreachable connect error", probe)
+	}
+}
+
+func TestProbeInvalidURL(t *testing.T) {
+	probe := NewProber(time.Second).Probe(context.Background(), "http://camera.local/stream")
+	if probe.Error != "invalid rtsp url" {
+		t.Fatalf("Error = %q, want invalid rtsp url", probe.Error)
+	}
+}
@@ -2,6 +2,7 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -50,7 +51,33 @@ func (s *LocalStorage) SaveFile(ctx context.Context, filename string, reader io.
 	return fmt.Sprintf("%s/%s", s.baseURL, filepath.ToSlash(filename)), nil
 }
 
+func (s *LocalStorage) OpenFile(ctx context.Context, fileURL string) (io.ReadCloser, error) {
+	fullPath, err := s.resolvePath(fileURL)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(fullPath)
+}
+
+// resolvePath maps a URL returned by SaveFile back to a path under rootPath.
+func (s *LocalStorage) resolvePath(fileURL string) (string, error) {
+	if !strings.HasPrefix(fileURL, s.baseURL+"/") {
+		return "", fmt.Errorf("file %s is not served by local storage", fileURL)
+	}
+	rel := filepath.Clean(filepath.FromSlash(strings.TrimPrefix(fileURL, s.baseURL+"/")))
+	if rel == "." || strings.HasPrefix(rel, "..") || filepath.IsAbs(rel) {
+		return "", errors.New("invalid file path")
+	}
+	return filepath.Join(s.rootPath, rel), nil
+}
+
 func (s *LocalStorage) DeleteFile(ctx context.Context, fileURL string) error {
-	// Logic to extract relative path from URL and delete
+	fullPath, err := s.resolvePath(fileURL)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(fullPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
 	return nil
 }
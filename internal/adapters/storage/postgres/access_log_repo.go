@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type AccessLogRepository struct {
+	db *PostgresDB
+}
+
+func NewAccessLogRepository(db *PostgresDB) ports.AccessLogRepository {
+	return &AccessLogRepository{db: db}
+}
+
+func (r *AccessLogRepository) CreateAccessLogs(ctx context.Context, logs []*domain.AccessLog) error {
+	if len(logs) == 0 {
+		return nil
+	}
+	query := `INSERT INTO access_logs (user_id, resource_type, resource_id, identity_ids, route, filters, pii_unmasked, ip_address, user_agent, created_at)
+	          VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+
+	batch := &pgx.Batch{}
+	for _, l := range logs {
+		ids := l.IdentityIDs
+		if ids == nil {
+			ids = []uuid.UUID{}
+		}
+		batch.Queue(query, l.UserID, l.ResourceType, l.ResourceID, ids, l.Route, l.Filters, l.PIIUnmasked, l.IPAddress, l.UserAgent, l.CreatedAt)
+	}
+	return r.db.Pool.SendBatch(ctx, batch).Close()
+}
+
+func (r *AccessLogRepository) ListAccessLogs(ctx context.Context, filter *ports.AccessLogFilter) ([]*domain.AccessLog, error) {
+	query := `SELECT al.id, al.user_id, al.resource_type, COALESCE(al.resource_id, ''), al.identity_ids, COALESCE(al.route, ''), al.filters,
+	                 al.pii_unmasked, COALESCE(al.ip_address, ''), COALESCE(al.user_agent, ''), al.created_at, COALESCE(u.username, '')
+	          FROM access_logs al
+	          LEFT JOIN users u ON al.user_id = u.id
+	          WHERE ($1::uuid IS NULL OR $1 = ANY(al.identity_ids))
+	            AND ($2::uuid IS NULL OR al.user_id = $2)
+	            AND ($3::timestamptz IS NULL OR al.created_at >= $3)
+	            AND ($4::timestamptz IS NULL OR al.created_at < $4)
+	          ORDER BY al.created_at DESC
+	          LIMIT $5 OFFSET $6`
+
+	rows, err := r.db.Pool.Query(ctx, query, filter.IdentityID, filter.UserID, filter.FromDate, filter.ToDate, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	logs := []*domain.AccessLog{}
+	for rows.Next() {
+		l := &domain.AccessLog{}
+		if err := rows.Scan(&l.ID, &l.UserID, &l.ResourceType, &l.ResourceID, &l.IdentityIDs, &l.Route, &l.Filters,
+			&l.PIIUnmasked, &l.IPAddress, &l.UserAgent, &l.CreatedAt, &l.Username); err != nil {
+			return nil, err
+		}
+		logs = append(logs, l)
+	}
+	return logs, rows.Err()
+}
+
+func (r *AccessLogRepository) SummarizeIdentityAccess(ctx context.Context, identityID uuid.UUID, from, to *time.Time) ([]*domain.IdentityAccessor, error) {
+	query := `SELECT al.user_id, COALESCE(u.username, ''), COUNT(*), COUNT(*) FILTER (WHERE al.pii_unmasked),
+	                 MIN(al.created_at), MAX(al.created_at)
+	          FROM access_logs al
+	          LEFT JOIN users u ON al.user_id = u.id
+	          WHERE $1 = ANY(al.identity_ids)
+	            AND ($2::timestamptz IS NULL OR al.created_at >= $2)
+	            AND ($3::timestamptz IS NULL OR al.created_at < $3)
+	          GROUP BY al.user_id, u.username
+	          ORDER BY MAX(al.created_at) DESC`
+
+	rows, err := r.db.Pool.Query(ctx, query, identityID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	accessors := []*domain.IdentityAccessor{}
+	for rows.Next() {
+		a := &domain.IdentityAccessor{}
+		if err := rows.Scan(&a.UserID, &a.Username, &a.Accesses, &a.PIIUnmasked, &a.FirstAt, &a.LastAt); err != nil {
+			return nil, err
+		}
+		accessors = append(accessors, a)
+	}
+	return accessors, rows.Err()
+}
@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"app/internal/core/domain"
@@ -19,17 +20,24 @@ func NewAIRepository(db *PostgresDB) ports.AIRepository {
 	return &AIRepository{db: db}
 }
 
-func (r *AIRepository) GetConfigByCamera(ctx context.Context, cameraID uuid.UUID) (*domain.AIConfig, error) {
-	query := `SELECT id, camera_id, ai_enabled, ai_types, roi_zones, active_hours, sensitivity, min_confidence, created_at, updated_at 
-	          FROM ai_configs WHERE camera_id = $1`
+const aiConfigColumns = `id, camera_id, ai_enabled, ai_types, roi_zones, active_hours, sensitivity, min_confidence,
+	version, template_id, overrides, created_at, updated_at`
 
+func scanAIConfig(row pgx.Row) (*domain.AIConfig, error) {
 	config := &domain.AIConfig{}
-	err := r.db.Pool.QueryRow(ctx, query, cameraID).Scan(
+	err := row.Scan(
 		&config.ID, &config.CameraID, &config.AIEnabled, &config.AITypes,
 		&config.ROIZones, &config.ActiveHours, &config.Sensitivity,
-		&config.MinConfidence, &config.CreatedAt, &config.UpdatedAt,
+		&config.MinConfidence, &config.Version, &config.TemplateID, &config.Overrides,
+		&config.CreatedAt, &config.UpdatedAt,
 	)
+	return config, err
+}
 
+func (r *AIRepository) GetConfigByCamera(ctx context.Context, cameraID uuid.UUID) (*domain.AIConfig, error) {
+	query := `SELECT ` + aiConfigColumns + ` FROM ai_configs WHERE camera_id = $1`
+
+	config, err := scanAIConfig(r.db.Pool.QueryRow(ctx, query, cameraID))
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, nil
@@ -39,12 +47,21 @@ func (r *AIRepository) GetConfigByCamera(ctx context.Context, cameraID uuid.UUID
 	return config, nil
 }
 
+// SaveConfig bumps the version under the upsert's row lock, so concurrent
+// saves of one camera get consecutive versions.
 func (r *AIRepository) SaveConfig(ctx context.Context, config *domain.AIConfig) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
 	query := `
 		INSERT INTO ai_configs (
-			camera_id, ai_enabled, ai_types, roi_zones, active_hours, sensitivity, min_confidence, updated_at
+			camera_id, ai_enabled, ai_types, roi_zones, active_hours, sensitivity, min_confidence,
+			template_id, overrides, version, updated_at
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, NOW()
+			$1, $2, $3, $4, $5, $6, $7, (SELECT id FROM ai_config_templates WHERE id = $8), $9, 1, NOW()
 		)
 		ON CONFLICT (camera_id) DO UPDATE SET
 			ai_enabled = EXCLUDED.ai_enabled,
@@ -53,15 +70,98 @@ func (r *AIRepository) SaveConfig(ctx context.Context, config *domain.AIConfig)
 			active_hours = EXCLUDED.active_hours,
 			sensitivity = EXCLUDED.sensitivity,
 			min_confidence = EXCLUDED.min_confidence,
+			template_id = EXCLUDED.template_id,
+			overrides = EXCLUDED.overrides,
+			version = ai_configs.version + 1,
 			updated_at = NOW()
-		RETURNING id, created_at, updated_at`
+		RETURNING id, version, template_id, created_at, updated_at`
 
-	err := r.db.Pool.QueryRow(ctx, query,
+	err = tx.QueryRow(ctx, query,
 		config.CameraID, config.AIEnabled, config.AITypes, config.ROIZones,
 		config.ActiveHours, config.Sensitivity, config.MinConfidence,
-	).Scan(&config.ID, &config.CreatedAt, &config.UpdatedAt)
+		config.TemplateID, config.Overrides,
+	).Scan(&config.ID, &config.Version, &config.TemplateID, &config.CreatedAt, &config.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	source := config.Source
+	if source == "" {
+		source = domain.AIConfigSourceManual
+	}
+	_, err = tx.Exec(ctx, `
+		INSERT INTO ai_config_versions (
+			camera_id, version, ai_enabled, ai_types, roi_zones, active_hours, sensitivity, min_confidence,
+			template_id, overrides, source, created_by
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+		config.CameraID, config.Version, config.AIEnabled, config.AITypes, config.ROIZones,
+		config.ActiveHours, config.Sensitivity, config.MinConfidence,
+		config.TemplateID, config.Overrides, source, config.UpdatedBy,
+	)
+	if err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+const aiConfigVersionColumns = `id, camera_id, version, ai_enabled, ai_types, roi_zones, active_hours, sensitivity,
+	min_confidence, template_id, overrides, source, created_by, created_at`
+
+func scanAIConfigVersion(row pgx.Row) (*domain.AIConfigVersion, error) {
+	v := &domain.AIConfigVersion{}
+	err := row.Scan(&v.ID, &v.CameraID, &v.Version, &v.AIEnabled, &v.AITypes, &v.ROIZones, &v.ActiveHours,
+		&v.Sensitivity, &v.MinConfidence, &v.TemplateID, &v.Overrides, &v.Source, &v.CreatedBy, &v.CreatedAt)
+	return v, err
+}
+
+func (r *AIRepository) ListConfigVersions(ctx context.Context, cameraID uuid.UUID) ([]*domain.AIConfigVersion, error) {
+	query := `SELECT ` + aiConfigVersionColumns + ` FROM ai_config_versions WHERE camera_id = $1 ORDER BY version DESC`
+	rows, err := r.db.Pool.Query(ctx, query, cameraID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := []*domain.AIConfigVersion{}
+	for rows.Next() {
+		v, err := scanAIConfigVersion(rows)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, v)
+	}
+	return versions, rows.Err()
+}
+
+func (r *AIRepository) GetConfigVersion(ctx context.Context, cameraID uuid.UUID, version int) (*domain.AIConfigVersion, error) {
+	query := `SELECT ` + aiConfigVersionColumns + ` FROM ai_config_versions WHERE camera_id = $1 AND version = $2`
+	v, err := scanAIConfigVersion(r.db.Pool.QueryRow(ctx, query, cameraID, version))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return v, nil
+}
+
+func (r *AIRepository) ListConfigsByTemplate(ctx context.Context, templateID uuid.UUID) ([]*domain.AIConfig, error) {
+	query := `SELECT ` + aiConfigColumns + ` FROM ai_configs WHERE template_id = $1`
+	rows, err := r.db.Pool.Query(ctx, query, templateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-	return err
+	configs := []*domain.AIConfig{}
+	for rows.Next() {
+		config, err := scanAIConfig(rows)
+		if err != nil {
+			return nil, err
+		}
+		configs = append(configs, config)
+	}
+	return configs, rows.Err()
 }
 
 func (r *AIRepository) CreateEvent(ctx context.Context, event *domain.AIEvent) (*domain.AIEvent, error) {
@@ -84,7 +184,7 @@ func (r *AIRepository) CreateEvent(ctx context.Context, event *domain.AIEvent) (
 }
 
 func (r *AIRepository) ListEvents(ctx context.Context, cameraID *uuid.UUID, eventType *domain.EventType, status *domain.EventStatus, from, to *time.Time, limit, offset int32) ([]*domain.AIEvent, error) {
-	query := `SELECT id, camera_id, event_type, confidence, snapshot_url, metadata, status, resolved_by, created_at, updated_at
+	query := `SELECT id, camera_id, event_type, confidence, snapshot_url, metadata, status, verdict, resolved_by, created_at, updated_at
 	          FROM ai_events
 	          WHERE ($1::uuid IS NULL OR camera_id = $1)
 	            AND ($2::event_type IS NULL OR event_type = $2)
@@ -105,7 +205,7 @@ func (r *AIRepository) ListEvents(ctx context.Context, cameraID *uuid.UUID, even
 		event := &domain.AIEvent{}
 		err := rows.Scan(
 			&event.ID, &event.CameraID, &event.EventType, &event.Confidence,
-			&event.SnapshotURL, &event.Metadata, &event.Status, &event.ResolvedBy,
+			&event.SnapshotURL, &event.Metadata, &event.Status, &event.Verdict, &event.ResolvedBy,
 			&event.CreatedAt, &event.UpdatedAt,
 		)
 		if err != nil {
@@ -116,30 +216,86 @@ func (r *AIRepository) ListEvents(ctx context.Context, cameraID *uuid.UUID, even
 	return events, nil
 }
 
-func (r *AIRepository) UpdateEventStatus(ctx context.Context, id uuid.UUID, status domain.EventStatus, resolvedBy *uuid.UUID) (*domain.AIEvent, error) {
-	query := `UPDATE ai_events SET status = $2, resolved_by = $3, updated_at = NOW() WHERE id = $1 RETURNING updated_at`
-	// Return the whole object or just enough to confirm. Better return all for consistent API.
-	_, err := r.db.Pool.Exec(ctx, query, id, status, resolvedBy)
+// UpdateEventStatus changes the status and/or verdict; nil leaves a field as is.
+func (r *AIRepository) UpdateEventStatus(ctx context.Context, id uuid.UUID, status *domain.EventStatus, verdict *domain.EventVerdict, resolvedBy *uuid.UUID) (*domain.AIEvent, error) {
+	query := `UPDATE ai_events SET status = COALESCE($2, status), verdict = COALESCE($3, verdict), resolved_by = $4, updated_at = NOW()
+	          WHERE id = $1
+	          RETURNING id, camera_id, event_type, confidence, snapshot_url, metadata, status, verdict, resolved_by, created_at, updated_at`
+	event := &domain.AIEvent{}
+	err := r.db.Pool.QueryRow(ctx, query, id, status, verdict, resolvedBy).Scan(
+		&event.ID, &event.CameraID, &event.EventType, &event.Confidence,
+		&event.SnapshotURL, &event.Metadata, &event.Status, &event.Verdict, &event.ResolvedBy,
+		&event.CreatedAt, &event.UpdatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, domain.ErrEventNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
-	// Return from get (simplified)
-	return nil, nil // Or implement GetEvent
+	return event, nil
+}
+
+// dashboardCameras matches the cameras table the filter rolls up over.
+func dashboardCameras(table string, filter *ports.DashboardFilter, args *[]any) string {
+	condition := cameraScopeCondition(table, filter.Scope, args)
+	if filter.ZoneID != nil {
+		condition += ` AND ` + zoneSubtreeCondition(table+".zone_id", *filter.ZoneID, args)
+	}
+	return condition
 }
 
-func (r *AIRepository) GetDashboardStats(ctx context.Context) (total, online, offline, maintenance int64, err error) {
+func (r *AIRepository) GetDashboardStats(ctx context.Context, filter *ports.DashboardFilter) (total, online, offline, maintenance int64, err error) {
+	var args []any
 	query := `SELECT 
 				COUNT(id) as total_cameras,
 				COUNT(CASE WHEN status = 'online' THEN 1 END) as online_cameras,
 				COUNT(CASE WHEN status = 'offline' THEN 1 END) as offline_cameras,
 				COUNT(CASE WHEN status = 'maintenance' THEN 1 END) as maintenance_cameras
-			FROM cameras`
-	err = r.db.Pool.QueryRow(ctx, query).Scan(&total, &online, &offline, &maintenance)
+			FROM cameras WHERE ` + dashboardCameras("cameras", filter, &args)
+	err = r.db.Pool.QueryRow(ctx, query, args...).Scan(&total, &online, &offline, &maintenance)
 	return
 }
 
-func (r *AIRepository) GetTodayEventsCount(ctx context.Context) (int64, error) {
+func (r *AIRepository) GetTodayEventsCount(ctx context.Context, filter *ports.DashboardFilter) (int64, error) {
+	var args []any
+	query := `SELECT COUNT(*) FROM ai_events WHERE created_at >= CURRENT_DATE`
+	if filter.Scope != nil || filter.ZoneID != nil {
+		query += ` AND camera_id IN (SELECT c.id FROM cameras c WHERE ` + dashboardCameras("c", filter, &args) + `)`
+	}
 	var count int64
-	err := r.db.Pool.QueryRow(ctx, "SELECT COUNT(*) FROM ai_events WHERE created_at >= CURRENT_DATE").Scan(&count)
+	err := r.db.Pool.QueryRow(ctx, query, args...).Scan(&count)
 	return count, err
 }
+
+func (r *AIRepository) ListRecentEvents(ctx context.Context, filter *ports.DashboardFilter, limit int32) ([]*domain.AIEvent, error) {
+	var args []any
+	query := `SELECT id, camera_id, event_type, confidence, snapshot_url, metadata, status, verdict, resolved_by, created_at, updated_at
+	          FROM ai_events`
+	if filter.Scope != nil || filter.ZoneID != nil {
+		query += ` WHERE camera_id IN (SELECT c.id FROM cameras c WHERE ` + dashboardCameras("c", filter, &args) + `)`
+	}
+	args = append(args, limit)
+	query += fmt.Sprintf(` ORDER BY created_at DESC LIMIT $%d`, len(args))
+
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*domain.AIEvent
+	for rows.Next() {
+		event := &domain.AIEvent{}
+		err := rows.Scan(
+			&event.ID, &event.CameraID, &event.EventType, &event.Confidence,
+			&event.SnapshotURL, &event.Metadata, &event.Status, &event.Verdict, &event.ResolvedBy,
+			&event.CreatedAt, &event.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
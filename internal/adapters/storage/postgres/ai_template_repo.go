@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"context"
+
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type AITemplateRepository struct {
+	db *PostgresDB
+}
+
+func NewAITemplateRepository(db *PostgresDB) ports.AITemplateRepository {
+	return &AITemplateRepository{db: db}
+}
+
+const aiTemplateColumns = `t.id, t.name, COALESCE(t.description, ''), t.ai_enabled, t.ai_types, t.roi_zones, t.active_hours,
+	t.sensitivity, t.min_confidence, t.created_by, t.created_at, t.updated_at,
+	(SELECT COUNT(*) FROM ai_configs c WHERE c.template_id = t.id)`
+
+func scanAITemplate(row pgx.Row) (*domain.AIConfigTemplate, error) {
+	t := &domain.AIConfigTemplate{}
+	err := row.Scan(&t.ID, &t.Name, &t.Description, &t.AIEnabled, &t.AITypes, &t.ROIZones, &t.ActiveHours,
+		&t.Sensitivity, &t.MinConfidence, &t.CreatedBy, &t.CreatedAt, &t.UpdatedAt, &t.Cameras)
+	return t, err
+}
+
+func (r *AITemplateRepository) Save(ctx context.Context, t *domain.AIConfigTemplate) error {
+	query := `INSERT INTO ai_config_templates (name, description, ai_enabled, ai_types, roi_zones, active_hours,
+	              sensitivity, min_confidence, created_by)
+	          VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	          RETURNING id, created_at, updated_at`
+	return r.db.Pool.QueryRow(ctx, query, t.Name, t.Description, t.AIEnabled, t.AITypes, t.ROIZones, t.ActiveHours,
+		t.Sensitivity, t.MinConfidence, t.CreatedBy).Scan(&t.ID, &t.CreatedAt, &t.UpdatedAt)
+}
+
+func (r *AITemplateRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.AIConfigTemplate, error) {
+	query := `SELECT ` + aiTemplateColumns + ` FROM ai_config_templates t WHERE t.id = $1`
+	t, err := scanAITemplate(r.db.Pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return t, nil
+}
+
+func (r *AITemplateRepository) GetByName(ctx context.Context, name string) (*domain.AIConfigTemplate, error) {
+	query := `SELECT ` + aiTemplateColumns + ` FROM ai_config_templates t WHERE t.name = $1`
+	t, err := scanAITemplate(r.db.Pool.QueryRow(ctx, query, name))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return t, nil
+}
+
+func (r *AITemplateRepository) List(ctx context.Context) ([]*domain.AIConfigTemplate, error) {
+	query := `SELECT ` + aiTemplateColumns + ` FROM ai_config_templates t ORDER BY t.name`
+	rows, err := r.db.Pool.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	templates := []*domain.AIConfigTemplate{}
+	for rows.Next() {
+		t, err := scanAITemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, t)
+	}
+	return templates, rows.Err()
+}
+
+func (r *AITemplateRepository) Update(ctx context.Context, t *domain.AIConfigTemplate) error {
+	query := `UPDATE ai_config_templates SET name = $2, description = $3, ai_enabled = $4, ai_types = $5,
+	              roi_zones = $6, active_hours = $7, sensitivity = $8, min_confidence = $9, updated_at = NOW()
+	          WHERE id = $1
+	          RETURNING updated_at`
+	err := r.db.Pool.QueryRow(ctx, query, t.ID, t.Name, t.Description, t.AIEnabled, t.AITypes, t.ROIZones,
+		t.ActiveHours, t.Sensitivity, t.MinConfidence).Scan(&t.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return domain.ErrAITemplateNotFound
+	}
+	return err
+}
+
+func (r *AITemplateRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.db.Pool.Exec(ctx, `DELETE FROM ai_config_templates WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrAITemplateNotFound
+	}
+	return nil
+}
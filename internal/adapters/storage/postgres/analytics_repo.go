@@ -19,25 +19,33 @@ func NewAnalyticsRepository(db *PostgresDB) ports.AnalyticsRepository {
 }
 
 func (r *AnalyticsRepository) CreateRecognitionLog(ctx context.Context, log *domain.RecognitionLog) error {
-	query := `INSERT INTO recognition_logs (camera_id, identity_id, snapshot_url, face_crop_url, confidence, label, occurred_at) 
-	          VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id, created_at`
-	return r.db.Pool.QueryRow(ctx, query, log.CameraID, log.IdentityID, log.SnapshotURL, log.FaceCropURL, log.Confidence, log.Label, log.OccurredAt).
+	query := `INSERT INTO recognition_logs (camera_id, identity_id, snapshot_url, face_crop_url, confidence, label, metadata, embedding, occurred_at) 
+	          VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, created_at`
+	var embedding []float32
+	if log.IdentityID == nil && len(log.Embedding) > 0 {
+		embedding = log.Embedding
+	}
+	return r.db.Pool.QueryRow(ctx, query, log.CameraID, log.IdentityID, log.SnapshotURL, log.FaceCropURL, log.Confidence, log.Label, log.Metadata, embedding, log.OccurredAt).
 		Scan(&log.ID, &log.CreatedAt)
 }
 
-func (r *AnalyticsRepository) ListRecognitionLogs(ctx context.Context, identityID *uuid.UUID, cameraID *uuid.UUID, from, to *time.Time, limit, offset int32) ([]*domain.RecognitionLog, error) {
-	query := `SELECT rl.id, rl.camera_id, rl.identity_id, rl.snapshot_url, rl.face_crop_url, rl.confidence, rl.label, rl.occurred_at, rl.created_at, i.full_name as identity_name, c.name as camera_name
+// ListRecognitionLogs includes stranger logs, which have no identity to join.
+func (r *AnalyticsRepository) ListRecognitionLogs(ctx context.Context, filter *ports.RecognitionFilter) ([]*domain.RecognitionLog, error) {
+	query := `SELECT rl.id, rl.camera_id, rl.identity_id, rl.stranger_cluster_id, COALESCE(rl.snapshot_url, ''), COALESCE(rl.face_crop_url, ''),
+	                 COALESCE(rl.confidence, 0), COALESCE(rl.label, ''), COALESCE(rl.review_status, ''), rl.occurred_at, rl.created_at,
+	                 COALESCE(i.full_name, '') as identity_name, COALESCE(c.name, '') as camera_name
 	          FROM recognition_logs rl
-	          JOIN identities i ON rl.identity_id = i.id
-	          JOIN cameras c ON rl.camera_id = c.id
+	          LEFT JOIN identities i ON rl.identity_id = i.id
+	          LEFT JOIN cameras c ON rl.camera_id = c.id
 	          WHERE ($1::uuid IS NULL OR rl.identity_id = $1)
 	            AND ($2::uuid IS NULL OR rl.camera_id = $2)
-	            AND ($3::timestamp IS NULL OR rl.occurred_at >= $3)
-	            AND ($4::timestamp IS NULL OR rl.occurred_at <= $4)
+	            AND (NOT $3::boolean OR rl.identity_id IS NULL)
+	            AND ($4::timestamp IS NULL OR rl.occurred_at >= $4)
+	            AND ($5::timestamp IS NULL OR rl.occurred_at <= $5)
 	          ORDER BY rl.occurred_at DESC
-	          LIMIT $5 OFFSET $6`
+	          LIMIT $6 OFFSET $7`
 
-	rows, err := r.db.Pool.Query(ctx, query, identityID, cameraID, from, to, limit, offset)
+	rows, err := r.db.Pool.Query(ctx, query, filter.IdentityID, filter.CameraID, filter.StrangersOnly, filter.FromDate, filter.ToDate, filter.Limit, filter.Offset)
 	if err != nil {
 		return nil, err
 	}
@@ -47,8 +55,8 @@ func (r *AnalyticsRepository) ListRecognitionLogs(ctx context.Context, identityI
 	for rows.Next() {
 		log := &domain.RecognitionLog{}
 		err := rows.Scan(
-			&log.ID, &log.CameraID, &log.IdentityID,
-			&log.SnapshotURL, &log.FaceCropURL, &log.Confidence, &log.Label,
+			&log.ID, &log.CameraID, &log.IdentityID, &log.StrangerClusterID,
+			&log.SnapshotURL, &log.FaceCropURL, &log.Confidence, &log.Label, &log.ReviewStatus,
 			&log.OccurredAt, &log.CreatedAt,
 			&log.IdentityName, &log.CameraName,
 		)
@@ -60,6 +68,76 @@ func (r *AnalyticsRepository) ListRecognitionLogs(ctx context.Context, identityI
 	return logs, nil
 }
 
+func (r *AnalyticsRepository) ListSightings(ctx context.Context, identityID uuid.UUID, from, to time.Time, limit int) ([]*domain.Sighting, error) {
+	query := `SELECT rl.id, rl.camera_id, COALESCE(c.name, ''), c.zone_id, COALESCE(z.name, ''),
+	                 COALESCE(rl.snapshot_url, ''), COALESCE(rl.confidence, 0), rl.occurred_at
+	          FROM recognition_logs rl
+	          LEFT JOIN cameras c ON rl.camera_id = c.id
+	          LEFT JOIN zones z ON c.zone_id = z.id
+	          WHERE rl.identity_id = $1 AND rl.occurred_at >= $2 AND rl.occurred_at <= $3
+	          ORDER BY rl.occurred_at, rl.id
+	          LIMIT $4`
+
+	rows, err := r.db.Pool.Query(ctx, query, identityID, from, to, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sightings := []*domain.Sighting{}
+	for rows.Next() {
+		s := &domain.Sighting{}
+		if err := rows.Scan(&s.LogID, &s.CameraID, &s.CameraName, &s.ZoneID, &s.ZoneName, &s.SnapshotURL, &s.Confidence, &s.OccurredAt); err != nil {
+			return nil, err
+		}
+		sightings = append(sightings, s)
+	}
+	return sightings, rows.Err()
+}
+
+func (r *AnalyticsRepository) ListContactSightings(ctx context.Context, identityID uuid.UUID, from, to time.Time, window time.Duration, limit int) ([]*domain.ContactSighting, error) {
+	query := `WITH target AS (
+	              SELECT rl.camera_id, c.zone_id, rl.occurred_at
+	              FROM recognition_logs rl
+	              LEFT JOIN cameras c ON rl.camera_id = c.id
+	              WHERE rl.identity_id = $1 AND rl.occurred_at >= $2 AND rl.occurred_at <= $3
+	          )
+	          SELECT rl.identity_id, COALESCE(i.code, ''), COALESCE(i.full_name, ''),
+	                 rl.id, rl.camera_id, COALESCE(c.name, ''), c.zone_id, COALESCE(z.name, ''),
+	                 COALESCE(rl.snapshot_url, ''), COALESCE(rl.confidence, 0), rl.occurred_at
+	          FROM recognition_logs rl
+	          LEFT JOIN cameras c ON rl.camera_id = c.id
+	          LEFT JOIN zones z ON c.zone_id = z.id
+	          LEFT JOIN identities i ON rl.identity_id = i.id
+	          WHERE rl.identity_id IS NOT NULL AND rl.identity_id <> $1
+	            AND rl.occurred_at >= $2::timestamptz - $4 * interval '1 second'
+	            AND rl.occurred_at <= $3::timestamptz + $4 * interval '1 second'
+	            AND EXISTS (
+	                SELECT 1 FROM target t
+	                WHERE (t.zone_id = c.zone_id OR (c.zone_id IS NULL AND t.zone_id IS NULL AND t.camera_id = rl.camera_id))
+	                  AND rl.occurred_at BETWEEN t.occurred_at - $4 * interval '1 second' AND t.occurred_at + $4 * interval '1 second'
+	            )
+	          ORDER BY rl.identity_id, rl.occurred_at, rl.id
+	          LIMIT $5`
+
+	rows, err := r.db.Pool.Query(ctx, query, identityID, from, to, window.Seconds(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sightings := []*domain.ContactSighting{}
+	for rows.Next() {
+		s := &domain.ContactSighting{}
+		if err := rows.Scan(&s.IdentityID, &s.IdentityCode, &s.IdentityName,
+			&s.LogID, &s.CameraID, &s.CameraName, &s.ZoneID, &s.ZoneName, &s.SnapshotURL, &s.Confidence, &s.OccurredAt); err != nil {
+			return nil, err
+		}
+		sightings = append(sightings, s)
+	}
+	return sightings, rows.Err()
+}
+
 func (r *AnalyticsRepository) ListAttendanceRecords(ctx context.Context, identityID *uuid.UUID, from, to *time.Time, status *domain.AttendanceStatus, limit, offset int32) ([]*domain.AttendanceRecord, error) {
 	query := `SELECT ar.id, ar.identity_id, ar.date, ar.check_in, ar.check_out, ar.work_hours, ar.status, ar.created_at, ar.updated_at, i.full_name as identity_name
 	          FROM attendance_records ar
@@ -0,0 +1,146 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+	"app/pkg/logger"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type CameraHealthRepository struct {
+	db     *PostgresDB
+	cipher ports.FieldCipher
+}
+
+func NewCameraHealthRepository(db *PostgresDB, cipher ports.FieldCipher) ports.CameraHealthRepository {
+	return &CameraHealthRepository{db: db, cipher: cipher}
+}
+
+func (r *CameraHealthRepository) ListMonitored(ctx context.Context) ([]*domain.CameraHealth, error) {
+	query := `SELECT id, name, rtsp_url, COALESCE(rtsp_username, ''), COALESCE(rtsp_password, ''),
+	                 COALESCE(status, 'online'), probe_failures, probe_successes, last_seen_at, COALESCE(last_probe_error, '')
+	          FROM cameras
+	          WHERE COALESCE(status, 'online') <> 'maintenance'
+	          ORDER BY name`
+
+	rows, err := r.db.Pool.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cameras := []*domain.CameraHealth{}
+	for rows.Next() {
+		h := &domain.CameraHealth{}
+		var rtspURL, username, password string
+		if err := rows.Scan(&h.CameraID, &h.Name, &rtspURL, &username, &password, &h.Status, &h.Failures, &h.Successes, &h.LastSeenAt, &h.LastProbeError); err != nil {
+			return nil, err
+		}
+		// Probing without the credentials would mark the camera offline, so
+		// an undecryptable one is left out of this round instead
+		var userErr, passwordErr error
+		username, userErr = r.cipher.Decrypt(username)
+		password, passwordErr = r.cipher.Decrypt(password)
+		if err := errors.Join(userErr, passwordErr); err != nil {
+			logger.Error("Skipping camera with undecryptable credentials", zap.String("camera_id", h.CameraID.String()), zap.Error(err))
+			continue
+		}
+		h.StreamURL = domain.ComposeStreamURL(rtspURL, username, password)
+		cameras = append(cameras, h)
+	}
+	return cameras, rows.Err()
+}
+
+func (r *CameraHealthRepository) SaveProbe(ctx context.Context, health *domain.CameraHealth, probe *domain.CameraProbe) error {
+	query := `UPDATE cameras
+	          SET last_probe_at = $2,
+	              last_seen_at = CASE WHEN $3 THEN $2 ELSE last_seen_at END,
+	              last_probe_error = NULLIF($4, ''),
+	              probe_failures = $5, probe_successes = $6
+	          WHERE id = $1`
+	_, err := r.db.Pool.Exec(ctx, query, health.CameraID, probe.ProbedAt, probe.Healthy, probe.Error, health.Failures, health.Successes)
+	return err
+}
+
+func (r *CameraHealthRepository) ChangeStatus(ctx context.Context, change *domain.CameraStatusChange) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// The monitor, the maintenance scheduler and manual edits race; only the
+	// writer that still sees the status it started from wins
+	query := `UPDATE cameras
+	          SET status = $2, maintenance_window_id = $3, probe_failures = 0, probe_successes = 0, updated_at = NOW()
+	          WHERE id = $1 AND COALESCE(status, 'online') = COALESCE(NULLIF($4, ''), 'online')`
+	tag, err := tx.Exec(ctx, query, change.CameraID, change.ToStatus, change.MaintenanceWindowID, change.FromStatus)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrCameraStatusChanged
+	}
+	if err := r.insert(ctx, tx, change); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *CameraHealthRepository) RecordStatusChange(ctx context.Context, change *domain.CameraStatusChange) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := r.insert(ctx, tx, change); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *CameraHealthRepository) insert(ctx context.Context, tx pgx.Tx, change *domain.CameraStatusChange) error {
+	query := `INSERT INTO camera_status_history (camera_id, from_status, to_status, source, reason, latency_ms, maintenance_window_id)
+	          VALUES ($1, NULLIF($2, ''), $3, $4, NULLIF($5, ''), $6, $7)
+	          RETURNING id, created_at`
+	return tx.QueryRow(ctx, query, change.CameraID, change.FromStatus, change.ToStatus, change.Source, change.Reason, change.LatencyMs, change.MaintenanceWindowID).
+		Scan(&change.ID, &change.CreatedAt)
+}
+
+func (r *CameraHealthRepository) ListStatusHistory(ctx context.Context, filter *ports.CameraStatusHistoryFilter) ([]*domain.CameraStatusChange, int64, error) {
+	where := `WHERE camera_id = $1
+	            AND ($2::timestamptz IS NULL OR created_at >= $2)
+	            AND ($3::timestamptz IS NULL OR created_at <= $3)`
+
+	var total int64
+	if err := r.db.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM camera_status_history `+where,
+		filter.CameraID, filter.FromDate, filter.ToDate).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT id, camera_id, COALESCE(from_status, ''), to_status, source, COALESCE(reason, ''), latency_ms, maintenance_window_id, created_at
+	          FROM camera_status_history ` + where + `
+	          ORDER BY created_at DESC
+	          LIMIT $4 OFFSET $5`
+	rows, err := r.db.Pool.Query(ctx, query, filter.CameraID, filter.FromDate, filter.ToDate, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	changes := []*domain.CameraStatusChange{}
+	for rows.Next() {
+		c := &domain.CameraStatusChange{}
+		if err := rows.Scan(&c.ID, &c.CameraID, &c.FromStatus, &c.ToStatus, &c.Source, &c.Reason, &c.LatencyMs, &c.MaintenanceWindowID, &c.CreatedAt); err != nil {
+			return nil, 0, err
+		}
+		changes = append(changes, c)
+	}
+	return changes, total, rows.Err()
+}
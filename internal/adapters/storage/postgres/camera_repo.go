@@ -2,34 +2,94 @@ package postgres
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"app/internal/core/domain"
 	"app/internal/core/ports"
+	"app/pkg/logger"
 
 	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
 )
 
+// CameraRepository keeps RTSP credentials in their own encrypted columns;
+// rtsp_url is stored without them.
 type CameraRepository struct {
-	db *PostgresDB
+	db     *PostgresDB
+	cipher ports.FieldCipher
 }
 
-func NewCameraRepository(db *PostgresDB) ports.CameraRepository {
-	return &CameraRepository{db: db}
+func NewCameraRepository(db *PostgresDB, cipher ports.FieldCipher) *CameraRepository {
+	return &CameraRepository{db: db, cipher: cipher}
+}
+
+const cameraColumns = `id, zone_id, name, ip_address, rtsp_url, COALESCE(rtsp_username, ''), COALESCE(rtsp_password, ''),
+	status, ai_enabled, created_at, updated_at`
+
+// scanCamera decrypts the credentials. Rows written before the credential
+// columns existed still carry them in rtsp_url; they are split out here so
+// they are never returned, and moved for good by ReencryptPII.
+func (r *CameraRepository) scanCamera(row pgx.Row) (*domain.Camera, error) {
+	camera := &domain.Camera{}
+	var username, password string
+	if err := row.Scan(&camera.ID, &camera.ZoneID, &camera.Name, &camera.IPAddress, &camera.RTSPURL, &username, &password,
+		&camera.Status, &camera.AIEnabled, &camera.CreatedAt, &camera.UpdatedAt); err != nil {
+		return nil, err
+	}
+	// One undecryptable camera must not take every camera listing down with it
+	var userErr, passwordErr error
+	camera.Username, userErr = r.cipher.Decrypt(username)
+	camera.Password, passwordErr = r.cipher.Decrypt(password)
+	if err := errors.Join(userErr, passwordErr); err != nil {
+		logger.Error("Failed to decrypt camera credentials", zap.String("camera_id", camera.ID), zap.Error(err))
+		camera.Username, camera.Password = "", ""
+		camera.CredentialsUnreadable = true
+		camera.HasCredentials = true
+		return camera, nil
+	}
+	rtspURL, legacyUser, legacyPassword, err := domain.SplitStreamURL(camera.RTSPURL)
+	if err != nil {
+		// Never hand out a URL whose credentials could not be separated
+		rtspURL, legacyUser, legacyPassword = domain.StripStreamUserInfo(camera.RTSPURL)
+	}
+	if err != nil || legacyUser != "" {
+		camera.RTSPURL = rtspURL
+		if camera.Username == "" {
+			camera.Username, camera.Password = legacyUser, legacyPassword
+		}
+	}
+	camera.HasCredentials = camera.Username != "" || camera.Password != ""
+	return camera, nil
+}
+
+func (r *CameraRepository) sealCredentials(camera *domain.Camera) (*string, *string, error) {
+	username, err := r.cipher.Encrypt(camera.Username)
+	if err != nil {
+		return nil, nil, err
+	}
+	password, err := r.cipher.Encrypt(camera.Password)
+	if err != nil {
+		return nil, nil, err
+	}
+	camera.HasCredentials = camera.Username != "" || camera.Password != ""
+	return nullableString(username), nullableString(password), nil
 }
 
 func (r *CameraRepository) Save(ctx context.Context, camera *domain.Camera) error {
-	query := `INSERT INTO cameras (zone_id, name, ip_address, rtsp_url, status, ai_enabled, updated_at) 
-	          VALUES ($1, $2, $3, $4, $5, $6, NOW()) RETURNING id, created_at, updated_at`
-	return r.db.Pool.QueryRow(ctx, query, camera.ZoneID, camera.Name, camera.IPAddress, camera.RTSPURL, camera.Status, camera.AIEnabled).
+	username, password, err := r.sealCredentials(camera)
+	if err != nil {
+		return err
+	}
+	query := `INSERT INTO cameras (zone_id, name, ip_address, rtsp_url, rtsp_username, rtsp_password, status, ai_enabled, updated_at)
+	          VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW()) RETURNING id, created_at, updated_at`
+	return r.db.Pool.QueryRow(ctx, query, camera.ZoneID, camera.Name, camera.IPAddress, camera.RTSPURL, username, password, camera.Status, camera.AIEnabled).
 		Scan(&camera.ID, &camera.CreatedAt, &camera.UpdatedAt)
 }
 
 func (r *CameraRepository) GetByID(ctx context.Context, id string) (*domain.Camera, error) {
-	query := `SELECT id, zone_id, name, ip_address, rtsp_url, status, ai_enabled, created_at, updated_at FROM cameras WHERE id = $1`
-	camera := &domain.Camera{}
-	err := r.db.Pool.QueryRow(ctx, query, id).Scan(
-		&camera.ID, &camera.ZoneID, &camera.Name, &camera.IPAddress, &camera.RTSPURL, &camera.Status, &camera.AIEnabled, &camera.CreatedAt, &camera.UpdatedAt,
-	)
+	query := `SELECT ` + cameraColumns + ` FROM cameras WHERE id = $1`
+	camera, err := r.scanCamera(r.db.Pool.QueryRow(ctx, query, id))
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, nil
@@ -40,7 +100,7 @@ func (r *CameraRepository) GetByID(ctx context.Context, id string) (*domain.Came
 }
 
 func (r *CameraRepository) List(ctx context.Context, search string) ([]*domain.Camera, error) {
-	query := `SELECT id, zone_id, name, ip_address, rtsp_url, status, ai_enabled, created_at, updated_at FROM cameras`
+	query := `SELECT ` + cameraColumns + ` FROM cameras`
 	var args []interface{}
 
 	if search != "" {
@@ -49,36 +109,26 @@ func (r *CameraRepository) List(ctx context.Context, search string) ([]*domain.C
 	}
 
 	query += ` ORDER BY created_at DESC`
-
-	rows, err := r.db.Pool.Query(ctx, query, args...)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	cameras := []*domain.Camera{}
-	for rows.Next() {
-		camera := &domain.Camera{}
-		err := rows.Scan(&camera.ID, &camera.ZoneID, &camera.Name, &camera.IPAddress, &camera.RTSPURL, &camera.Status, &camera.AIEnabled, &camera.CreatedAt, &camera.UpdatedAt)
-		if err != nil {
-			return nil, err
-		}
-		cameras = append(cameras, camera)
-	}
-	return cameras, nil
+	return r.query(ctx, query, args...)
 }
 
-func (r *CameraRepository) ListByZone(ctx context.Context, zoneID string, search string) ([]*domain.Camera, error) {
-	query := `SELECT id, zone_id, name, ip_address, rtsp_url, status, ai_enabled, created_at, updated_at FROM cameras WHERE zone_id = $1`
-	args := []interface{}{zoneID}
+func (r *CameraRepository) Find(ctx context.Context, filter *ports.CameraFilter) ([]*domain.Camera, error) {
+	var args []interface{}
+	query := `SELECT ` + cameraColumns + ` FROM cameras WHERE ` + cameraScopeCondition("cameras", filter.Scope, &args)
 
-	if search != "" {
-		query += ` AND (name ILIKE $2 OR ip_address ILIKE $2)`
-		args = append(args, "%"+search+"%")
+	if filter.ZoneID != "" {
+		query += ` AND ` + zoneSubtreeCondition("zone_id", filter.ZoneID, &args)
+	}
+	if filter.Search != "" {
+		args = append(args, "%"+filter.Search+"%")
+		query += fmt.Sprintf(` AND (name ILIKE $%d OR ip_address ILIKE $%d)`, len(args), len(args))
 	}
 
 	query += ` ORDER BY created_at DESC`
+	return r.query(ctx, query, args...)
+}
 
+func (r *CameraRepository) query(ctx context.Context, query string, args ...interface{}) ([]*domain.Camera, error) {
 	rows, err := r.db.Pool.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
@@ -87,19 +137,31 @@ func (r *CameraRepository) ListByZone(ctx context.Context, zoneID string, search
 
 	cameras := []*domain.Camera{}
 	for rows.Next() {
-		camera := &domain.Camera{}
-		err := rows.Scan(&camera.ID, &camera.ZoneID, &camera.Name, &camera.IPAddress, &camera.RTSPURL, &camera.Status, &camera.AIEnabled, &camera.CreatedAt, &camera.UpdatedAt)
+		camera, err := r.scanCamera(rows)
 		if err != nil {
 			return nil, err
 		}
 		cameras = append(cameras, camera)
 	}
-	return cameras, nil
+	return cameras, rows.Err()
 }
 
 func (r *CameraRepository) Update(ctx context.Context, camera *domain.Camera) error {
-	query := `UPDATE cameras SET zone_id = $2, name = $3, ip_address = $4, rtsp_url = $5, status = $6, ai_enabled = $7, updated_at = NOW() WHERE id = $1`
-	_, err := r.db.Pool.Exec(ctx, query, camera.ID, camera.ZoneID, camera.Name, camera.IPAddress, camera.RTSPURL, camera.Status, camera.AIEnabled)
+	// Unreadable credentials stay as stored unless new ones were set
+	keep := camera.CredentialsUnreadable && camera.Username == "" && camera.Password == ""
+	username, password, err := r.sealCredentials(camera)
+	if err != nil {
+		return err
+	}
+	if keep {
+		camera.HasCredentials = true
+	}
+	query := `UPDATE cameras SET zone_id = $2, name = $3, ip_address = $4, rtsp_url = $5,
+	              rtsp_username = CASE WHEN $10 THEN rtsp_username ELSE $6 END,
+	              rtsp_password = CASE WHEN $10 THEN rtsp_password ELSE $7 END,
+	              status = $8, ai_enabled = $9, updated_at = NOW() WHERE id = $1`
+	_, err = r.db.Pool.Exec(ctx, query, camera.ID, camera.ZoneID, camera.Name, camera.IPAddress, camera.RTSPURL, username, password,
+		camera.Status, camera.AIEnabled, keep)
 	return err
 }
 
@@ -107,3 +169,81 @@ func (r *CameraRepository) Delete(ctx context.Context, id string) error {
 	_, err := r.db.Pool.Exec(ctx, "DELETE FROM cameras WHERE id = $1", id)
 	return err
 }
+
+func (r *CameraRepository) CountPII(ctx context.Context) (int, error) {
+	var count int
+	err := r.db.Pool.QueryRow(ctx, "SELECT COUNT(*) FROM cameras").Scan(&count)
+	return count, err
+}
+
+// ReencryptPII rewrites camera credentials under the active key and moves
+// any still embedded in rtsp_url into the credential columns.
+func (r *CameraRepository) ReencryptPII(ctx context.Context, step func(ok bool)) error {
+	rows, err := r.db.Pool.Query(ctx, `SELECT id, rtsp_url, COALESCE(rtsp_username, ''), COALESCE(rtsp_password, '') FROM cameras ORDER BY id`)
+	if err != nil {
+		return err
+	}
+	type stored struct{ id, url, username, password string }
+	var cameras []stored
+	for rows.Next() {
+		var c stored
+		if err := rows.Scan(&c.id, &c.url, &c.username, &c.password); err != nil {
+			rows.Close()
+			return err
+		}
+		cameras = append(cameras, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, c := range cameras {
+		_, legacyUser, _, err := domain.SplitStreamURL(c.url)
+		if err == nil && legacyUser == "" && r.cipher.IsCurrent(c.username) && r.cipher.IsCurrent(c.password) {
+			step(true)
+			continue
+		}
+		step(r.reencryptCredentials(ctx, c.id) == nil)
+	}
+	return nil
+}
+
+func (r *CameraRepository) reencryptCredentials(ctx context.Context, id string) error {
+	camera, err := r.GetByID(ctx, id)
+	if err != nil || camera == nil {
+		return err
+	}
+	if camera.CredentialsUnreadable {
+		return domain.ErrCameraCredentialsUnreadable
+	}
+	username, password, err := r.sealCredentials(camera)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.Pool.Exec(ctx, "UPDATE cameras SET rtsp_url = $2, rtsp_username = $3, rtsp_password = $4 WHERE id = $1",
+		id, camera.RTSPURL, username, password)
+	return err
+}
+
+// cameraScopeCondition restricts the cameras referenced as table to scope,
+// appending its arguments; a nil scope matches every camera. A granted zone
+// covers a camera when it is on the path of the camera's zone.
+func cameraScopeCondition(table string, scope *domain.CameraScope, args *[]any) string {
+	if scope == nil {
+		return "TRUE"
+	}
+	*args = append(*args, scope.CameraIDs, scope.ZoneIDs)
+	return fmt.Sprintf(`(%[1]s.id = ANY($%[2]d) OR EXISTS (
+	              SELECT 1 FROM zones sz WHERE sz.id = %[1]s.zone_id
+	                AND string_to_array(trim(both '/' from sz.path), '/')::uuid[] && $%[3]d::uuid[]))`,
+		table, len(*args)-1, len(*args))
+}
+
+// zoneSubtreeCondition matches rows whose zone column is zoneID or one of
+// its descendants.
+func zoneSubtreeCondition(column string, zoneID any, args *[]any) string {
+	*args = append(*args, zoneID)
+	return fmt.Sprintf(`%s IN (SELECT id FROM zones WHERE path LIKE (SELECT path FROM zones WHERE id = $%d) || '%%')`,
+		column, len(*args))
+}
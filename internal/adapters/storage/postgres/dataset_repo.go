@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"context"
+
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+)
+
+type DatasetRepository struct {
+	db *PostgresDB
+}
+
+func NewDatasetRepository(db *PostgresDB) ports.DatasetRepository {
+	return &DatasetRepository{db: db}
+}
+
+// EachSample treats confirmed and reassigned recognitions as true face
+// detections and rejected ones as false positives.
+func (r *DatasetRepository) EachSample(ctx context.Context, filter *ports.DatasetExportRequest, fn func(*domain.DatasetSample) error) error {
+	query := `SELECT 'ai_event', id, camera_id, event_type::text, verdict = 'true_positive', snapshot_url, metadata, created_at AS occurred_at
+	          FROM ai_events
+	          WHERE verdict IS NOT NULL AND snapshot_url IS NOT NULL AND snapshot_url <> ''
+	            AND ($1::uuid IS NULL OR camera_id = $1)
+	            AND (COALESCE(cardinality($2::text[]), 0) = 0 OR event_type::text = ANY($2))
+	            AND ($3::timestamptz IS NULL OR created_at >= $3)
+	            AND ($4::timestamptz IS NULL OR created_at <= $4)
+	          UNION ALL
+	          SELECT 'recognition_log', id, camera_id, 'face', review_status <> 'rejected', snapshot_url, metadata, occurred_at
+	          FROM recognition_logs
+	          WHERE review_status IS NOT NULL AND snapshot_url IS NOT NULL AND snapshot_url <> ''
+	            AND ($1::uuid IS NULL OR camera_id = $1)
+	            AND (COALESCE(cardinality($2::text[]), 0) = 0 OR 'face' = ANY($2))
+	            AND ($3::timestamptz IS NULL OR occurred_at >= $3)
+	            AND ($4::timestamptz IS NULL OR occurred_at <= $4)
+	          ORDER BY occurred_at`
+
+	eventTypes := make([]string, len(filter.EventTypes))
+	for i, t := range filter.EventTypes {
+		eventTypes[i] = string(t)
+	}
+	rows, err := r.db.Pool.Query(ctx, query, filter.CameraID, eventTypes, filter.FromDate, filter.ToDate)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		s := &domain.DatasetSample{}
+		if err := rows.Scan(&s.Source, &s.ID, &s.CameraID, &s.Category, &s.Positive, &s.SnapshotURL, &s.Metadata, &s.OccurredAt); err != nil {
+			return err
+		}
+		if filter.SkipNegatives && !s.Positive {
+			continue
+		}
+		if err := fn(s); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
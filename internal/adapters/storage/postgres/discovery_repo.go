@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type DiscoveryRepository struct {
+	db *PostgresDB
+}
+
+func NewDiscoveryRepository(db *PostgresDB) ports.DiscoveryRepository {
+	return &DiscoveryRepository{db: db}
+}
+
+const discoveredDeviceColumns = `id, job_id, endpoint_ref, xaddr, COALESCE(ip_address, ''), COALESCE(name, ''),
+	COALESCE(manufacturer, ''), COALESCE(model, ''), COALESCE(firmware_version, ''), COALESCE(serial_number, ''),
+	COALESCE(hardware_id, ''), COALESCE(scopes, '{}'), profiles, COALESCE(error, ''), camera_id, discovered_at, adopted_at`
+
+func scanDiscoveredDevice(row pgx.Row) (*domain.DiscoveredDevice, error) {
+	d := &domain.DiscoveredDevice{}
+	var profiles []byte
+	if err := row.Scan(&d.ID, &d.JobID, &d.EndpointRef, &d.XAddr, &d.IPAddress, &d.Name, &d.Manufacturer, &d.Model,
+		&d.FirmwareVersion, &d.SerialNumber, &d.HardwareID, &d.Scopes, &profiles, &d.Error, &d.CameraID,
+		&d.DiscoveredAt, &d.AdoptedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(profiles, &d.Profiles); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (r *DiscoveryRepository) SaveDevice(ctx context.Context, d *domain.DiscoveredDevice) error {
+	if d.Profiles == nil {
+		d.Profiles = []domain.DeviceProfile{}
+	}
+	profiles, err := json.Marshal(d.Profiles)
+	if err != nil {
+		return err
+	}
+	query := `INSERT INTO discovered_devices (job_id, endpoint_ref, xaddr, ip_address, name, manufacturer, model,
+	              firmware_version, serial_number, hardware_id, scopes, profiles, error)
+	          VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	          RETURNING id, discovered_at`
+	return r.db.Pool.QueryRow(ctx, query, d.JobID, d.EndpointRef, d.XAddr, nullableString(d.IPAddress), nullableString(d.Name),
+		nullableString(d.Manufacturer), nullableString(d.Model), nullableString(d.FirmwareVersion),
+		nullableString(d.SerialNumber), nullableString(d.HardwareID), d.Scopes, profiles, nullableString(d.Error)).
+		Scan(&d.ID, &d.DiscoveredAt)
+}
+
+func (r *DiscoveryRepository) GetDevice(ctx context.Context, id uuid.UUID) (*domain.DiscoveredDevice, error) {
+	query := `SELECT ` + discoveredDeviceColumns + ` FROM discovered_devices WHERE id = $1`
+	d, err := scanDiscoveredDevice(r.db.Pool.QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	return d, err
+}
+
+func (r *DiscoveryRepository) ListDevices(ctx context.Context, jobID uuid.UUID) ([]*domain.DiscoveredDevice, error) {
+	query := `SELECT ` + discoveredDeviceColumns + ` FROM discovered_devices WHERE job_id = $1 ORDER BY ip_address, endpoint_ref`
+	rows, err := r.db.Pool.Query(ctx, query, jobID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	devices := []*domain.DiscoveredDevice{}
+	for rows.Next() {
+		d, err := scanDiscoveredDevice(rows)
+		if err != nil {
+			return nil, err
+		}
+		devices = append(devices, d)
+	}
+	return devices, rows.Err()
+}
+
+func (r *DiscoveryRepository) MarkAdopted(ctx context.Context, id uuid.UUID, cameraID uuid.UUID) error {
+	tag, err := r.db.Pool.Exec(ctx, `UPDATE discovered_devices SET camera_id = $2, adopted_at = NOW()
+	                                 WHERE id = $1 AND adopted_at IS NULL`, id, cameraID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrDeviceAlreadyAdopted
+	}
+	return nil
+}
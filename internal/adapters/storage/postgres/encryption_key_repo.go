@@ -0,0 +1,67 @@
+package postgres
+
+import (
+	"context"
+
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+
+	"github.com/google/uuid"
+)
+
+type DataKeyRepository struct {
+	db *PostgresDB
+}
+
+func NewDataKeyRepository(db *PostgresDB) ports.DataKeyRepository {
+	return &DataKeyRepository{db: db}
+}
+
+func (r *DataKeyRepository) ListKeys(ctx context.Context, tenant string) ([]*domain.DataKey, error) {
+	query := `SELECT id, tenant, purpose, version, master_key_id, wrapped_key, status, created_at, retired_at
+	          FROM encryption_keys WHERE tenant = $1 ORDER BY purpose, version`
+
+	rows, err := r.db.Pool.Query(ctx, query, tenant)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []*domain.DataKey{}
+	for rows.Next() {
+		k := &domain.DataKey{}
+		if err := rows.Scan(&k.ID, &k.Tenant, &k.Purpose, &k.Version, &k.MasterKeyID, &k.WrappedKey, &k.Status, &k.CreatedAt, &k.RetiredAt); err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	return keys, rows.Err()
+}
+
+func (r *DataKeyRepository) CreateKey(ctx context.Context, key *domain.DataKey) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `UPDATE encryption_keys SET status = 'retired', retired_at = NOW()
+	                       WHERE tenant = $1 AND purpose = $2 AND status = 'active'`, key.Tenant, key.Purpose)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO encryption_keys (tenant, purpose, version, master_key_id, wrapped_key, status)
+	          VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at`
+	err = tx.QueryRow(ctx, query, key.Tenant, key.Purpose, key.Version, key.MasterKeyID, key.WrappedKey, key.Status).
+		Scan(&key.ID, &key.CreatedAt)
+	if err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *DataKeyRepository) UpdateWrappedKey(ctx context.Context, id uuid.UUID, masterKeyID string, wrapped []byte) error {
+	_, err := r.db.Pool.Exec(ctx, `UPDATE encryption_keys SET master_key_id = $2, wrapped_key = $3 WHERE id = $1`, id, masterKeyID, wrapped)
+	return err
+}
@@ -0,0 +1,140 @@
+package postgres
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// PgVectorFaceIndex searches identity_faces.embedding with pgvector. The
+// embeddings are written by IdentityFaceRepository, so Upsert/Remove are no-ops.
+type PgVectorFaceIndex struct {
+	db *PostgresDB
+}
+
+func NewPgVectorFaceIndex(db *PostgresDB) ports.FaceIndex {
+	return &PgVectorFaceIndex{db: db}
+}
+
+// HasPgVector reports whether the vector extension is installed.
+func HasPgVector(ctx context.Context, db *PostgresDB) bool {
+	var exists bool
+	err := db.Pool.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'vector')").Scan(&exists)
+	return err == nil && exists
+}
+
+func (r *PgVectorFaceIndex) Upsert(ctx context.Context, face *domain.IdentityFace) error {
+	return nil
+}
+
+func (r *PgVectorFaceIndex) Remove(ctx context.Context, faceID uuid.UUID) error {
+	return nil
+}
+
+func (r *PgVectorFaceIndex) RemoveIdentity(ctx context.Context, identityID uuid.UUID) error {
+	return nil
+}
+
+// faceIndexDims is the embedding size the HNSW index from migration 000011
+// is built for.
+const faceIndexDims = 512
+
+func (r *PgVectorFaceIndex) Search(ctx context.Context, embedding []float32, topK int, minScore float64) ([]*domain.FaceMatch, error) {
+	if len(embedding) == 0 {
+		return nil, domain.ErrInvalidEmbedding
+	}
+	if len(embedding) != faceIndexDims {
+		return r.scan(ctx, embedding, topK, minScore)
+	}
+
+	// The HNSW index only serves an ORDER BY on the indexed expression under
+	// a LIMIT. An identity with several faces can take more than one of the
+	// nearest rows, so extra candidates are fetched and reduced to each
+	// identity's best face outside. <=> is cosine distance so similarity =
+	// 1 - distance
+	candidates := max(topK*4, 40)
+	query := `SELECT identity_id, face_id, score FROM (
+	              SELECT DISTINCT ON (identity_id) identity_id, face_id, score FROM (
+	                  SELECT f.identity_id, f.id AS face_id,
+	                         1 - (f.embedding::vector(512) <=> $1::vector(512)) AS score
+	                  FROM identity_faces f
+	                  JOIN identities i ON i.id = f.identity_id
+	                  WHERE f.embedding IS NOT NULL AND i.deleted_at IS NULL
+	                    AND array_length(f.embedding, 1) = 512
+	                  ORDER BY f.embedding::vector(512) <=> $1::vector(512)
+	                  LIMIT $4
+	              ) nearest
+	              ORDER BY identity_id, score DESC
+	          ) best
+	          WHERE score >= $2
+	          ORDER BY score DESC
+	          LIMIT $3`
+
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+	// An HNSW scan returns at most ef_search rows
+	if _, err := tx.Exec(ctx, `SELECT set_config('hnsw.ef_search', $1, true)`, strconv.Itoa(candidates)); err != nil {
+		return nil, err
+	}
+	rows, err := tx.Query(ctx, query, vectorLiteral(embedding), minScore, topK, candidates)
+	if err != nil {
+		return nil, err
+	}
+	matches, err := scanFaceMatches(rows)
+	if err != nil {
+		return nil, err
+	}
+	return matches, tx.Commit(ctx)
+}
+
+// scan compares every face exactly, for embeddings the index does not cover.
+func (r *PgVectorFaceIndex) scan(ctx context.Context, embedding []float32, topK int, minScore float64) ([]*domain.FaceMatch, error) {
+	query := `SELECT identity_id, face_id, score FROM (
+	              SELECT DISTINCT ON (f.identity_id) f.identity_id, f.id AS face_id,
+	                     1 - (f.embedding::vector <=> $1::vector) AS score
+	              FROM identity_faces f
+	              JOIN identities i ON i.id = f.identity_id
+	              WHERE f.embedding IS NOT NULL AND i.deleted_at IS NULL
+	                AND array_length(f.embedding, 1) = $4
+	              ORDER BY f.identity_id, f.embedding::vector <=> $1::vector
+	          ) best
+	          WHERE score >= $2
+	          ORDER BY score DESC
+	          LIMIT $3`
+
+	rows, err := r.db.Pool.Query(ctx, query, vectorLiteral(embedding), minScore, topK, len(embedding))
+	if err != nil {
+		return nil, err
+	}
+	return scanFaceMatches(rows)
+}
+
+func scanFaceMatches(rows pgx.Rows) ([]*domain.FaceMatch, error) {
+	defer rows.Close()
+	matches := []*domain.FaceMatch{}
+	for rows.Next() {
+		m := &domain.FaceMatch{}
+		if err := rows.Scan(&m.IdentityID, &m.FaceID, &m.Score); err != nil {
+			return nil, err
+		}
+		matches = append(matches, m)
+	}
+	return matches, rows.Err()
+}
+
+func vectorLiteral(v []float32) string {
+	parts := make([]string, len(v))
+	for i, x := range v {
+		parts[i] = strconv.FormatFloat(float64(x), 'f', -1, 32)
+	}
+	return "[" + strings.Join(parts, ",") + "]"
+}
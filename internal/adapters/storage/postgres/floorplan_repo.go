@@ -0,0 +1,165 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type FloorPlanRepository struct {
+	db *PostgresDB
+}
+
+func NewFloorPlanRepository(db *PostgresDB) ports.FloorPlanRepository {
+	return &FloorPlanRepository{db: db}
+}
+
+func (r *FloorPlanRepository) SavePlan(ctx context.Context, plan *domain.FloorPlan) error {
+	query := `INSERT INTO floor_plans (zone_id, image_url, width, height)
+	          VALUES ($1, $2, $3, $4)
+	          ON CONFLICT (zone_id) DO UPDATE SET
+	              image_url = EXCLUDED.image_url, width = EXCLUDED.width, height = EXCLUDED.height, updated_at = NOW()
+	          RETURNING created_at, updated_at`
+	return r.db.Pool.QueryRow(ctx, query, plan.ZoneID, plan.ImageURL, plan.Width, plan.Height).
+		Scan(&plan.CreatedAt, &plan.UpdatedAt)
+}
+
+func (r *FloorPlanRepository) GetPlan(ctx context.Context, zoneID uuid.UUID) (*domain.FloorPlan, error) {
+	query := `SELECT zone_id, image_url, width, height, created_at, updated_at FROM floor_plans WHERE zone_id = $1`
+	plan := &domain.FloorPlan{}
+	err := r.db.Pool.QueryRow(ctx, query, zoneID).Scan(&plan.ZoneID, &plan.ImageURL, &plan.Width, &plan.Height,
+		&plan.CreatedAt, &plan.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+func (r *FloorPlanRepository) DeletePlan(ctx context.Context, zoneID uuid.UUID) error {
+	tag, err := r.db.Pool.Exec(ctx, `DELETE FROM floor_plans WHERE zone_id = $1`, zoneID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrFloorPlanNotFound
+	}
+	return nil
+}
+
+func (r *FloorPlanRepository) SavePlacement(ctx context.Context, p *domain.CameraPlacement) error {
+	query := `INSERT INTO camera_placements (camera_id, plan_zone_id, x, y, heading, fov, range)
+	          VALUES ($1, $2, $3, $4, $5, $6, $7)
+	          ON CONFLICT (camera_id) DO UPDATE SET
+	              plan_zone_id = EXCLUDED.plan_zone_id, x = EXCLUDED.x, y = EXCLUDED.y, heading = EXCLUDED.heading,
+	              fov = EXCLUDED.fov, range = EXCLUDED.range, updated_at = NOW()
+	          RETURNING updated_at`
+	return r.db.Pool.QueryRow(ctx, query, p.CameraID, p.PlanZoneID, p.X, p.Y, p.Heading, p.FOV, p.Range).Scan(&p.UpdatedAt)
+}
+
+func (r *FloorPlanRepository) DeletePlacement(ctx context.Context, cameraID uuid.UUID) error {
+	tag, err := r.db.Pool.Exec(ctx, `DELETE FROM camera_placements WHERE camera_id = $1`, cameraID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrPlacementNotFound
+	}
+	return nil
+}
+
+func (r *FloorPlanRepository) SaveArea(ctx context.Context, area *domain.ZoneArea) error {
+	polygon, err := json.Marshal(area.Polygon)
+	if err != nil {
+		return err
+	}
+	query := `INSERT INTO zone_areas (zone_id, plan_zone_id, polygon)
+	          VALUES ($1, $2, $3)
+	          ON CONFLICT (zone_id) DO UPDATE SET
+	              plan_zone_id = EXCLUDED.plan_zone_id, polygon = EXCLUDED.polygon, updated_at = NOW()
+	          RETURNING updated_at`
+	return r.db.Pool.QueryRow(ctx, query, area.ZoneID, area.PlanZoneID, polygon).Scan(&area.UpdatedAt)
+}
+
+func (r *FloorPlanRepository) DeleteArea(ctx context.Context, zoneID uuid.UUID) error {
+	tag, err := r.db.Pool.Exec(ctx, `DELETE FROM zone_areas WHERE zone_id = $1`, zoneID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrZoneAreaNotFound
+	}
+	return nil
+}
+
+func (r *FloorPlanRepository) ListPlacedCameras(ctx context.Context, planZoneID uuid.UUID, since time.Time, scope *domain.CameraScope) ([]*domain.PlacedCamera, error) {
+	args := []any{planZoneID, since}
+	query := `SELECT p.camera_id, p.plan_zone_id, p.x, p.y, p.heading, p.fov, p.range, p.updated_at,
+	                 c.name, COALESCE(c.status, 'online'),
+	                 (SELECT COUNT(*) FROM ai_events e WHERE e.camera_id = c.id AND e.created_at >= $2)
+	          FROM camera_placements p
+	          JOIN cameras c ON c.id = p.camera_id
+	          WHERE p.plan_zone_id = $1 AND ` + cameraScopeCondition("c", scope, &args) + `
+	          ORDER BY c.name`
+
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cameras := []*domain.PlacedCamera{}
+	for rows.Next() {
+		c := &domain.PlacedCamera{}
+		if err := rows.Scan(&c.CameraID, &c.PlanZoneID, &c.X, &c.Y, &c.Heading, &c.FOV, &c.Range, &c.UpdatedAt,
+			&c.Name, &c.Status, &c.EventCount); err != nil {
+			return nil, err
+		}
+		cameras = append(cameras, c)
+	}
+	return cameras, rows.Err()
+}
+
+// ListAreaStats rolls cameras and events up over each area's zone subtree.
+func (r *FloorPlanRepository) ListAreaStats(ctx context.Context, planZoneID uuid.UUID, since time.Time, scope *domain.CameraScope) ([]*domain.ZoneAreaStats, error) {
+	args := []any{planZoneID, since}
+	query := `SELECT a.zone_id, a.plan_zone_id, a.polygon, a.updated_at, z.name, COALESCE(z.kind, ''),
+	                 COUNT(DISTINCT c.id),
+	                 COALESCE(SUM((SELECT COUNT(*) FROM ai_events e WHERE e.camera_id = c.id AND e.created_at >= $2)), 0)
+	          FROM zone_areas a
+	          JOIN zones z ON z.id = a.zone_id
+	          LEFT JOIN cameras c ON c.zone_id IN (SELECT d.id FROM zones d WHERE d.path LIKE z.path || '%')
+	              AND ` + cameraScopeCondition("c", scope, &args) + `
+	          WHERE a.plan_zone_id = $1
+	          GROUP BY a.zone_id, a.plan_zone_id, a.polygon, a.updated_at, z.name, z.kind
+	          ORDER BY z.name`
+
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	areas := []*domain.ZoneAreaStats{}
+	for rows.Next() {
+		a := &domain.ZoneAreaStats{}
+		var polygon []byte
+		if err := rows.Scan(&a.ZoneID, &a.PlanZoneID, &polygon, &a.UpdatedAt, &a.Name, &a.Kind,
+			&a.Cameras, &a.EventCount); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(polygon, &a.Polygon); err != nil {
+			return nil, err
+		}
+		areas = append(areas, a)
+	}
+	return areas, rows.Err()
+}
@@ -0,0 +1,271 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type IdentityDuplicateRepository struct {
+	db     *PostgresDB
+	cipher ports.FieldCipher
+}
+
+func NewIdentityDuplicateRepository(db *PostgresDB, cipher ports.FieldCipher) ports.IdentityDuplicateRepository {
+	return &IdentityDuplicateRepository{db: db, cipher: cipher}
+}
+
+// ListMatchKeys loads every live, non-rejected identity. Phone and CCCD are
+// turned into keys here so plaintext never leaves the repository.
+func (r *IdentityDuplicateRepository) ListMatchKeys(ctx context.Context) ([]*ports.IdentityMatchKey, error) {
+	query := `SELECT id, COALESCE(code, ''), COALESCE(full_name, ''), COALESCE(phone_number, ''), COALESCE(identity_card_number, '')
+	          FROM identities
+	          WHERE deleted_at IS NULL AND erased_at IS NULL AND COALESCE(status, 'active') <> $1`
+	rows, err := r.db.Pool.Query(ctx, query, domain.IdentityStatusRejected)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []*ports.IdentityMatchKey{}
+	for rows.Next() {
+		k := &ports.IdentityMatchKey{}
+		var phone, card string
+		if err := rows.Scan(&k.ID, &k.Code, &k.FullName, &phone, &card); err != nil {
+			return nil, err
+		}
+		if k.PhoneKey, err = r.matchKey(phone); err != nil {
+			return nil, err
+		}
+		if k.CardKey, err = r.matchKey(card); err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	return keys, rows.Err()
+}
+
+func (r *IdentityDuplicateRepository) matchKey(stored string) (string, error) {
+	value, err := r.cipher.Decrypt(stored)
+	if err != nil {
+		return "", err
+	}
+	if r.cipher.Enabled() {
+		return r.cipher.BlindIndex(value), nil
+	}
+	return strings.Map(func(c rune) rune {
+		if unicode.IsLetter(c) || unicode.IsDigit(c) {
+			return unicode.ToLower(c)
+		}
+		return -1
+	}, value), nil
+}
+
+func (r *IdentityDuplicateRepository) ReplaceOpenCandidates(ctx context.Context, candidates []*domain.DuplicateCandidate) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM identity_duplicate_candidates WHERE status = $1`, domain.DuplicateStatusOpen); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO identity_duplicate_candidates (identity_a, identity_b, score, reasons, face_score, status)
+	          VALUES ($1, $2, $3, $4, $5, $6)
+	          ON CONFLICT (identity_a, identity_b) DO NOTHING`
+	batch := &pgx.Batch{}
+	for _, c := range candidates {
+		batch.Queue(query, c.IdentityA, c.IdentityB, c.Score, c.Reasons, c.FaceScore, domain.DuplicateStatusOpen)
+	}
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+const candidateColumns = `c.id, c.identity_a, c.identity_b, c.score, c.reasons, c.face_score, c.status, c.resolved_by, c.resolved_at, c.created_at,
+	COALESCE(a.code, ''), COALESCE(a.full_name, ''), COALESCE(b.code, ''), COALESCE(b.full_name, '')`
+
+const candidateFrom = ` FROM identity_duplicate_candidates c
+	JOIN identities a ON a.id = c.identity_a
+	JOIN identities b ON b.id = c.identity_b `
+
+func (r *IdentityDuplicateRepository) ListCandidates(ctx context.Context, filter *ports.DuplicateFilter) ([]*domain.DuplicateCandidate, int64, error) {
+	where := `WHERE ($1::text IS NULL OR c.status = $1)
+	            AND ($2::uuid IS NULL OR c.identity_a = $2 OR c.identity_b = $2)`
+
+	var total int64
+	if err := r.db.Pool.QueryRow(ctx, `SELECT COUNT(*)`+candidateFrom+where, filter.Status, filter.IdentityID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT ` + candidateColumns + candidateFrom + where + ` ORDER BY c.score DESC, c.created_at DESC LIMIT $3 OFFSET $4`
+	rows, err := r.db.Pool.Query(ctx, query, filter.Status, filter.IdentityID, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	candidates := []*domain.DuplicateCandidate{}
+	for rows.Next() {
+		c, err := scanCandidate(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		candidates = append(candidates, c)
+	}
+	return candidates, total, rows.Err()
+}
+
+func (r *IdentityDuplicateRepository) GetCandidate(ctx context.Context, id uuid.UUID) (*domain.DuplicateCandidate, error) {
+	query := `SELECT ` + candidateColumns + candidateFrom + `WHERE c.id = $1`
+	c, err := scanCandidate(r.db.Pool.QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	return c, err
+}
+
+func scanCandidate(row pgx.Row) (*domain.DuplicateCandidate, error) {
+	c := &domain.DuplicateCandidate{}
+	err := row.Scan(&c.ID, &c.IdentityA, &c.IdentityB, &c.Score, &c.Reasons, &c.FaceScore, &c.Status,
+		&c.ResolvedBy, &c.ResolvedAt, &c.CreatedAt, &c.CodeA, &c.NameA, &c.CodeB, &c.NameB)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (r *IdentityDuplicateRepository) ResolveCandidate(ctx context.Context, id uuid.UUID, status domain.DuplicateStatus, resolvedBy *uuid.UUID) error {
+	tag, err := r.db.Pool.Exec(ctx, `UPDATE identity_duplicate_candidates SET status = $2, resolved_by = $3, resolved_at = NOW()
+	                                 WHERE id = $1 AND status = $4`, id, status, resolvedBy, domain.DuplicateStatusOpen)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrDuplicateNotOpen
+	}
+	return nil
+}
+
+// MergeIdentities folds mergedID into survivorID. On days both have an
+// attendance record, the survivor keeps the earliest check-in and latest
+// check-out, the status of whichever record checked in first, and work hours
+// recomputed from the combined span.
+func (r *IdentityDuplicateRepository) MergeIdentities(ctx context.Context, survivorID, mergedID uuid.UUID, mergedBy *uuid.UUID) (*domain.IdentityMerge, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Lock both rows in a fixed order so concurrent merges cannot deadlock
+	rows, err := tx.Query(ctx, `SELECT id FROM identities WHERE id = ANY($1) AND deleted_at IS NULL AND erased_at IS NULL
+	                            ORDER BY id FOR UPDATE`, []uuid.UUID{survivorID, mergedID})
+	if err != nil {
+		return nil, err
+	}
+	locked := 0
+	for rows.Next() {
+		locked++
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if locked != 2 {
+		return nil, domain.ErrIdentityNotFound
+	}
+
+	result := &domain.IdentityMerge{SurvivorID: survivorID, MergedID: mergedID}
+
+	faceRows, err := tx.Query(ctx, `UPDATE identity_faces SET identity_id = $1, is_primary = false WHERE identity_id = $2 RETURNING id`, survivorID, mergedID)
+	if err != nil {
+		return nil, err
+	}
+	for faceRows.Next() {
+		var faceID uuid.UUID
+		if err := faceRows.Scan(&faceID); err != nil {
+			faceRows.Close()
+			return nil, err
+		}
+		result.FaceIDs = append(result.FaceIDs, faceID)
+	}
+	faceRows.Close()
+	if err := faceRows.Err(); err != nil {
+		return nil, err
+	}
+	result.FacesMoved = len(result.FaceIDs)
+
+	tag, err := tx.Exec(ctx, `UPDATE recognition_logs SET identity_id = $1 WHERE identity_id = $2`, survivorID, mergedID)
+	if err != nil {
+		return nil, err
+	}
+	result.RecognitionLogsMoved = tag.RowsAffected()
+
+	// Keep feedback labels pointing at the surviving identity
+	if _, err := tx.Exec(ctx, `UPDATE recognition_feedback SET corrected_identity_id = $1 WHERE corrected_identity_id = $2`, survivorID, mergedID); err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(ctx, `UPDATE recognition_feedback SET original_identity_id = $1 WHERE original_identity_id = $2`, survivorID, mergedID); err != nil {
+		return nil, err
+	}
+
+	tag, err = tx.Exec(ctx, `
+		UPDATE attendance_records s SET
+		    check_in = LEAST(s.check_in, m.check_in),
+		    check_out = GREATEST(s.check_out, m.check_out),
+		    status = CASE WHEN m.check_in IS NOT NULL AND (s.check_in IS NULL OR m.check_in < s.check_in) THEN m.status ELSE s.status END,
+		    work_hours = COALESCE(
+		        EXTRACT(EPOCH FROM GREATEST(s.check_out, m.check_out) - LEAST(s.check_in, m.check_in)) / 3600,
+		        GREATEST(s.work_hours, m.work_hours))
+		FROM attendance_records m
+		WHERE s.identity_id = $1 AND m.identity_id = $2 AND m.date = s.date`, survivorID, mergedID)
+	if err != nil {
+		return nil, err
+	}
+	result.AttendanceDaysReconciled = tag.RowsAffected()
+
+	if _, err := tx.Exec(ctx, `DELETE FROM attendance_records m WHERE m.identity_id = $2
+	                           AND EXISTS (SELECT 1 FROM attendance_records s WHERE s.identity_id = $1 AND s.date = m.date)`,
+		survivorID, mergedID); err != nil {
+		return nil, err
+	}
+	tag, err = tx.Exec(ctx, `UPDATE attendance_records SET identity_id = $1 WHERE identity_id = $2`, survivorID, mergedID)
+	if err != nil {
+		return nil, err
+	}
+	result.AttendanceRecordsMoved = tag.RowsAffected()
+
+	// face_image_url now points at a survivor face; erasing the merged record
+	// later must not delete it
+	err = tx.QueryRow(ctx, `UPDATE identities SET merged_into = $1, face_image_url = '', deleted_at = NOW(), updated_at = NOW()
+	                        WHERE id = $2 RETURNING deleted_at`, survivorID, mergedID).Scan(&result.MergedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE identity_duplicate_candidates SET status = $3, resolved_by = $4, resolved_at = NOW()
+	                           WHERE status = $5 AND (identity_a = $1 AND identity_b = $2 OR identity_a = $2 AND identity_b = $1)`,
+		survivorID, mergedID, domain.DuplicateStatusMerged, mergedBy, domain.DuplicateStatusOpen); err != nil {
+		return nil, err
+	}
+	// Other pairs involving the merged identity are moot now
+	if _, err := tx.Exec(ctx, `DELETE FROM identity_duplicate_candidates WHERE status = $2 AND (identity_a = $1 OR identity_b = $1)`,
+		mergedID, domain.DuplicateStatusOpen); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,285 @@
+package postgres
+
+import (
+	"context"
+	"slices"
+
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+
+	"github.com/google/uuid"
+)
+
+type IdentityPrivacyRepository struct {
+	db     *PostgresDB
+	cipher ports.FieldCipher
+}
+
+func NewIdentityPrivacyRepository(db *PostgresDB, cipher ports.FieldCipher) ports.IdentityPrivacyRepository {
+	return &IdentityPrivacyRepository{db: db, cipher: cipher}
+}
+
+// GetIdentity returns soft-deleted identities too; only erased ones are gone.
+func (r *IdentityPrivacyRepository) GetIdentity(ctx context.Context, id uuid.UUID) (*domain.Identity, error) {
+	query := `SELECT ` + identityColumns + ` FROM identities WHERE id = $1 AND erased_at IS NULL`
+	return scanIdentity(r.db.Pool.QueryRow(ctx, query, id), r.cipher)
+}
+
+func (r *IdentityPrivacyRepository) ListRecognitionLogs(ctx context.Context, identityID uuid.UUID) ([]*domain.RecognitionLog, error) {
+	query := `SELECT rl.id, rl.camera_id, rl.identity_id, COALESCE(rl.snapshot_url, ''), COALESCE(rl.face_crop_url, ''),
+	                 COALESCE(rl.confidence, 0), COALESCE(rl.label, ''), rl.occurred_at, rl.created_at, COALESCE(c.name, '')
+	          FROM recognition_logs rl
+	          LEFT JOIN cameras c ON rl.camera_id = c.id
+	          WHERE rl.identity_id = $1
+	          ORDER BY rl.occurred_at`
+
+	rows, err := r.db.Pool.Query(ctx, query, identityID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	logs := []*domain.RecognitionLog{}
+	for rows.Next() {
+		log := &domain.RecognitionLog{}
+		err := rows.Scan(
+			&log.ID, &log.CameraID, &log.IdentityID,
+			&log.SnapshotURL, &log.FaceCropURL, &log.Confidence, &log.Label,
+			&log.OccurredAt, &log.CreatedAt, &log.CameraName,
+		)
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, log)
+	}
+	return logs, rows.Err()
+}
+
+func (r *IdentityPrivacyRepository) ListAttendanceRecords(ctx context.Context, identityID uuid.UUID) ([]*domain.AttendanceRecord, error) {
+	query := `SELECT id, identity_id, date, check_in, check_out, COALESCE(work_hours, 0), status, created_at, updated_at
+	          FROM attendance_records
+	          WHERE identity_id = $1
+	          ORDER BY date`
+
+	rows, err := r.db.Pool.Query(ctx, query, identityID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := []*domain.AttendanceRecord{}
+	for rows.Next() {
+		record := &domain.AttendanceRecord{}
+		err := rows.Scan(
+			&record.ID, &record.IdentityID, &record.Date, &record.CheckIn,
+			&record.CheckOut, &record.WorkHours, &record.Status,
+			&record.CreatedAt, &record.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+// ListAuditLogs returns audit entries about the identity or any of its faces.
+func (r *IdentityPrivacyRepository) ListAuditLogs(ctx context.Context, identityID uuid.UUID, faceIDs []uuid.UUID) ([]*domain.AuditLog, error) {
+	query := `SELECT al.id, al.user_id, al.action, COALESCE(al.table_name, ''), COALESCE(al.record_id, ''), al.old_value, al.new_value,
+	                 COALESCE(al.ip_address, ''), COALESCE(al.user_agent, ''), al.created_at, COALESCE(u.username, '')
+	          FROM audit_logs al
+	          LEFT JOIN users u ON al.user_id = u.id
+	          WHERE (al.table_name = 'identities' AND al.record_id = $1)
+	             OR (al.table_name = 'identity_faces' AND al.record_id = ANY($2))
+	          ORDER BY al.created_at`
+
+	rows, err := r.db.Pool.Query(ctx, query, identityID.String(), uuidStrings(faceIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	logs := []*domain.AuditLog{}
+	for rows.Next() {
+		log := &domain.AuditLog{}
+		err := rows.Scan(
+			&log.ID, &log.UserID, &log.Action, &log.TableName,
+			&log.RecordID, &log.OldValue, &log.NewValue,
+			&log.IPAddress, &log.UserAgent, &log.CreatedAt, &log.Username,
+		)
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, log)
+	}
+	return logs, rows.Err()
+}
+
+// EraseIdentity removes biometrics and personal data in one transaction. The
+// identity row is kept as an anonymous tombstone so attendance records and
+// recognition counts survive without pointing at a person.
+func (r *IdentityPrivacyRepository) EraseIdentity(ctx context.Context, id uuid.UUID) (*domain.IdentityErasure, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	result := &domain.IdentityErasure{IdentityID: id}
+
+	var faceImage string
+	err = tx.QueryRow(ctx, `SELECT COALESCE(face_image_url, '') FROM identities WHERE id = $1 AND erased_at IS NULL FOR UPDATE`, id).Scan(&faceImage)
+	if err != nil {
+		return nil, err
+	}
+	if faceImage != "" {
+		result.MediaURLs = append(result.MediaURLs, faceImage)
+	}
+
+	rows, err := tx.Query(ctx, `DELETE FROM identity_faces WHERE identity_id = $1 RETURNING id, image_url`, id)
+	if err != nil {
+		return nil, err
+	}
+	var faceIDs []uuid.UUID
+	for rows.Next() {
+		var faceID uuid.UUID
+		var url string
+		if err := rows.Scan(&faceID, &url); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		faceIDs = append(faceIDs, faceID)
+		if url != "" && url != faceImage {
+			result.MediaURLs = append(result.MediaURLs, url)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	result.FacesDeleted = len(faceIDs)
+
+	// Recognition snapshots and crops show the person, so the media goes while
+	// the rows stay for per-camera counts
+	rows, err = tx.Query(ctx, `SELECT COALESCE(snapshot_url, ''), COALESCE(face_crop_url, '') FROM recognition_logs
+	                           WHERE identity_id = $1 AND (snapshot_url IS NOT NULL OR face_crop_url IS NOT NULL)`, id)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var snapshot, crop string
+		if err := rows.Scan(&snapshot, &crop); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		for _, url := range []string{snapshot, crop} {
+			if url != "" {
+				result.MediaURLs = append(result.MediaURLs, url)
+			}
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	tag, err := tx.Exec(ctx, `UPDATE recognition_logs SET snapshot_url = NULL, face_crop_url = NULL, embedding = NULL
+	                          WHERE identity_id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	result.RecognitionLogsAnonymized = tag.RowsAffected()
+
+	// A stranger cluster promoted to this person keeps their centroid and best
+	// crop, so it goes too and its logs lose the link
+	rows, err = tx.Query(ctx, `DELETE FROM stranger_clusters WHERE promoted_identity_id = $1
+	                           RETURNING id, COALESCE(best_crop_url, '')`, id)
+	if err != nil {
+		return nil, err
+	}
+	var clusterIDs []uuid.UUID
+	for rows.Next() {
+		var clusterID uuid.UUID
+		var crop string
+		if err := rows.Scan(&clusterID, &crop); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		clusterIDs = append(clusterIDs, clusterID)
+		if crop != "" && !slices.Contains(result.MediaURLs, crop) {
+			result.MediaURLs = append(result.MediaURLs, crop)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	result.StrangerClustersDeleted = len(clusterIDs)
+	if len(clusterIDs) > 0 {
+		if _, err := tx.Exec(ctx, `UPDATE recognition_logs SET stranger_cluster_id = NULL WHERE stranger_cluster_id = ANY($1)`, clusterIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	tag, err = tx.Exec(ctx, `UPDATE audit_logs SET old_value = NULL, new_value = '{"redacted": "identity erased"}'::jsonb
+	                         WHERE (table_name = 'identities' AND record_id = $1)
+	                            OR (table_name = 'identity_faces' AND record_id = ANY($2))`,
+		id.String(), uuidStrings(faceIDs))
+	if err != nil {
+		return nil, err
+	}
+	result.AuditLogsRedacted = tag.RowsAffected()
+
+	tag, err = tx.Exec(ctx, `DELETE FROM identity_versions WHERE identity_id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	result.VersionsDeleted = tag.RowsAffected()
+
+	// Feedback labeled as this person holds their face; where they were only
+	// the wrong prediction, the sample is someone else and stays unlinked
+	tag, err = tx.Exec(ctx, `DELETE FROM recognition_feedback WHERE corrected_identity_id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	result.FeedbackDeleted = tag.RowsAffected()
+	if _, err := tx.Exec(ctx, `UPDATE recognition_feedback SET original_identity_id = NULL WHERE original_identity_id = $1`, id); err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE notifications SET title = 'Identity erased', message = NULL
+	                       WHERE resource_type = 'identity' AND resource_id = $1`, id.String())
+	if err != nil {
+		return nil, err
+	}
+
+	// Type is kept as a coarse category for aggregate reports
+	err = tx.QueryRow(ctx, `UPDATE identities SET
+	                            code = 'erased-' || id::text, full_name = 'Erased identity', phone_number = '',
+	                            identity_card_number = '', face_image_url = '', department = '', metadata = NULL,
+	                            metadata_enc = NULL, phone_number_bidx = NULL, identity_card_number_bidx = NULL,
+	                            note = '', review_reason = NULL, user_account_id = NULL,
+	                            deleted_at = COALESCE(deleted_at, NOW()), erased_at = NOW(), updated_at = NOW()
+	                        WHERE id = $1
+	                        RETURNING erased_at`, id).Scan(&result.ErasedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM attendance_records WHERE identity_id = $1`, id).Scan(&result.AttendanceRecordsKept); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func uuidStrings(ids []uuid.UUID) []string {
+	out := make([]string, len(ids))
+	for i, id := range ids {
+		out[i] = id.String()
+	}
+	return out
+}
@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"app/internal/core/domain"
@@ -12,37 +13,114 @@ import (
 )
 
 type IdentityRepository struct {
-	db *PostgresDB
+	db     *PostgresDB
+	cipher ports.FieldCipher
 }
 
-func NewIdentityRepository(db *PostgresDB) ports.IdentityRepository {
+func NewIdentityRepository(db *PostgresDB, cipher ports.FieldCipher) *IdentityRepository {
 	return &IdentityRepository{
-		db: db,
+		db:     db,
+		cipher: cipher,
+	}
+}
+
+// identityPII holds the column values of the encrypted identity fields.
+type identityPII struct {
+	phone       string
+	card        string
+	phoneIdx    *string
+	cardIdx     *string
+	metadata    map[string]any
+	metadataEnc *string
+}
+
+// sealIdentityPII encrypts phone, CCCD and metadata. With encryption disabled
+// the values are returned unchanged and metadata stays in the JSONB column.
+func sealIdentityPII(c ports.FieldCipher, identity *domain.Identity) (*identityPII, error) {
+	pii := &identityPII{metadata: identity.Metadata}
+	var err error
+	if pii.phone, err = c.Encrypt(identity.PhoneNumber); err != nil {
+		return nil, err
+	}
+	if pii.card, err = c.Encrypt(identity.IdentityCardNumber); err != nil {
+		return nil, err
+	}
+	pii.phoneIdx = nullableString(c.BlindIndex(identity.PhoneNumber))
+	pii.cardIdx = nullableString(c.BlindIndex(identity.IdentityCardNumber))
+
+	if c.Enabled() && identity.Metadata != nil {
+		data, err := json.Marshal(identity.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		enc, err := c.Encrypt(string(data))
+		if err != nil {
+			return nil, err
+		}
+		pii.metadata, pii.metadataEnc = nil, &enc
+	}
+	return pii, nil
+}
+
+// openIdentityPII decrypts the fields loaded by scanIdentity in place.
+func openIdentityPII(c ports.FieldCipher, identity *domain.Identity, metadataEnc string) error {
+	var err error
+	if identity.PhoneNumber, err = c.Decrypt(identity.PhoneNumber); err != nil {
+		return err
+	}
+	if identity.IdentityCardNumber, err = c.Decrypt(identity.IdentityCardNumber); err != nil {
+		return err
+	}
+	if metadataEnc != "" {
+		data, err := c.Decrypt(metadataEnc)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal([]byte(data), &identity.Metadata); err != nil {
+			return fmt.Errorf("invalid identity metadata: %w", err)
+		}
+	}
+	return nil
+}
+
+func nullableString(s string) *string {
+	if s == "" {
+		return nil
 	}
+	return &s
 }
 
 func (r *IdentityRepository) CreateIdentity(ctx context.Context, identity *domain.Identity) (*domain.Identity, error) {
+	pii, err := sealIdentityPII(r.cipher, identity)
+	if err != nil {
+		return nil, err
+	}
+
 	query := `
 		INSERT INTO identities (
-			code, full_name, type, phone_number, identity_card_number, face_image_url, department, metadata, status, note, created_by, approved_by, user_account_id
+			code, full_name, type, phone_number, identity_card_number, face_image_url, department, metadata, status, note, created_by, approved_by, user_account_id,
+			metadata_enc, phone_number_bidx, identity_card_number_bidx
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16
 		) RETURNING id, created_at, updated_at`
 
-	err := r.db.Pool.QueryRow(ctx, query,
+	err = r.db.Pool.QueryRow(ctx, query,
 		identity.Code,
 		identity.FullName,
 		identity.Type,
-		identity.PhoneNumber,
-		identity.IdentityCardNumber,
+		pii.phone,
+		pii.card,
 		identity.FaceImageURL,
 		identity.Department,
-		identity.Metadata,
+		pii.metadata,
 		identity.Status,
 		identity.Note,
 		identity.CreatedBy,
 		identity.ApprovedBy,
 		identity.UserAccountID,
+		pii.metadataEnc,
+		pii.phoneIdx,
+		pii.cardIdx,
 	).Scan(&identity.ID, &identity.CreatedAt, &identity.UpdatedAt)
 
 	if err != nil {
@@ -52,39 +130,83 @@ func (r *IdentityRepository) CreateIdentity(ctx context.Context, identity *domai
 	return identity, nil
 }
 
+const identityColumns = `id, COALESCE(code, ''), COALESCE(full_name, ''), COALESCE(type, ''), phone_number, identity_card_number, face_image_url, COALESCE(department, ''), metadata, COALESCE(status, 'active'), note, created_by, approved_by, approved_at, COALESCE(review_reason, ''), user_account_id, created_at, updated_at, deleted_at, merged_into, COALESCE(metadata_enc, '')`
+
 func (r *IdentityRepository) GetIdentity(ctx context.Context, id uuid.UUID) (*domain.Identity, error) {
-	query := `SELECT id, COALESCE(code, ''), COALESCE(full_name, ''), COALESCE(type, ''), phone_number, identity_card_number, face_image_url, COALESCE(department, ''), metadata, COALESCE(status, 'active'), note, created_by, approved_by, user_account_id, created_at, updated_at, deleted_at 
-	          FROM identities WHERE id = $1 AND deleted_at IS NULL`
+	query := `SELECT ` + identityColumns + ` FROM identities WHERE id = $1 AND deleted_at IS NULL`
+	return scanIdentity(r.db.Pool.QueryRow(ctx, query, id), r.cipher)
+}
 
-	identity := &domain.Identity{}
-	err := r.db.Pool.QueryRow(ctx, query, id).Scan(
-		&identity.ID, &identity.Code, &identity.FullName, &identity.Type,
-		&identity.PhoneNumber, &identity.IdentityCardNumber, &identity.FaceImageURL, &identity.Department,
-		&identity.Metadata, &identity.Status, &identity.Note, &identity.CreatedBy,
-		&identity.ApprovedBy, &identity.UserAccountID,
-		&identity.CreatedAt, &identity.UpdatedAt, &identity.DeletedAt,
-	)
+func (r *IdentityRepository) GetIdentityByCode(ctx context.Context, code string) (*domain.Identity, error) {
+	query := `SELECT ` + identityColumns + ` FROM identities WHERE code = $1 AND deleted_at IS NULL`
+	return scanIdentity(r.db.Pool.QueryRow(ctx, query, code), r.cipher)
+}
+
+// GetIdentityByCardNumber matches the blind index, falling back to the plain
+// column for rows written before encryption was enabled.
+func (r *IdentityRepository) GetIdentityByCardNumber(ctx context.Context, cardNumber string) (*domain.Identity, error) {
+	query := `SELECT ` + identityColumns + ` FROM identities
+	          WHERE (identity_card_number_bidx = $1 OR identity_card_number = $2) AND deleted_at IS NULL LIMIT 1`
+	return scanIdentity(r.db.Pool.QueryRow(ctx, query, nullableString(r.cipher.BlindIndex(cardNumber)), cardNumber), r.cipher)
+}
 
+// ImportIdentities inserts identities together with their faces in a single
+// transaction, so a failure leaves nothing behind.
+func (r *IdentityRepository) ImportIdentities(ctx context.Context, identities []*domain.Identity) error {
+	tx, err := r.db.Pool.Begin(ctx)
 	if err != nil {
-		if err == pgx.ErrNoRows {
-			return nil, nil
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	identityQuery := `
+		INSERT INTO identities (
+			code, full_name, type, phone_number, identity_card_number, face_image_url, department, metadata, status, note, created_by,
+			metadata_enc, phone_number_bidx, identity_card_number_bidx
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14
+		) RETURNING id, created_at, updated_at`
+	faceQuery := `INSERT INTO identity_faces (identity_id, image_url, is_primary, quality_score, blur_score, quality_details, quality_flagged, quality_reason, embedding)
+	              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, created_at`
+
+	for _, identity := range identities {
+		pii, err := sealIdentityPII(r.cipher, identity)
+		if err != nil {
+			return err
+		}
+		err = tx.QueryRow(ctx, identityQuery,
+			identity.Code, identity.FullName, identity.Type, pii.phone, pii.card,
+			identity.FaceImageURL, identity.Department, pii.metadata, identity.Status, identity.Note, identity.CreatedBy,
+			pii.metadataEnc, pii.phoneIdx, pii.cardIdx,
+		).Scan(&identity.ID, &identity.CreatedAt, &identity.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to import identity %s: %w", identity.Code, err)
+		}
+
+		for i := range identity.Faces {
+			face := &identity.Faces[i]
+			face.IdentityID = identity.ID
+			err := tx.QueryRow(ctx, faceQuery, face.IdentityID, face.ImageURL, face.IsPrimary, face.QualityScore, face.BlurScore,
+				face.Quality, face.QualityFlagged, face.QualityReason, face.Embedding).
+				Scan(&face.ID, &face.CreatedAt)
+			if err != nil {
+				return fmt.Errorf("failed to import face for %s: %w", identity.Code, err)
+			}
 		}
-		return nil, err
 	}
-	return identity, nil
-}
 
-func (r *IdentityRepository) GetIdentityByCode(ctx context.Context, code string) (*domain.Identity, error) {
-	query := `SELECT id, COALESCE(code, ''), COALESCE(full_name, ''), COALESCE(type, ''), phone_number, identity_card_number, face_image_url, COALESCE(department, ''), metadata, COALESCE(status, 'active'), note, created_by, approved_by, user_account_id, created_at, updated_at, deleted_at 
-	          FROM identities WHERE code = $1 AND deleted_at IS NULL`
+	return tx.Commit(ctx)
+}
 
+func scanIdentity(row pgx.Row, cipher ports.FieldCipher) (*domain.Identity, error) {
 	identity := &domain.Identity{}
-	err := r.db.Pool.QueryRow(ctx, query, code).Scan(
+	var metadataEnc string
+	err := row.Scan(
 		&identity.ID, &identity.Code, &identity.FullName, &identity.Type,
 		&identity.PhoneNumber, &identity.IdentityCardNumber, &identity.FaceImageURL, &identity.Department,
 		&identity.Metadata, &identity.Status, &identity.Note, &identity.CreatedBy,
-		&identity.ApprovedBy, &identity.UserAccountID,
-		&identity.CreatedAt, &identity.UpdatedAt, &identity.DeletedAt,
+		&identity.ApprovedBy, &identity.ApprovedAt, &identity.ReviewReason, &identity.UserAccountID,
+		&identity.CreatedAt, &identity.UpdatedAt, &identity.DeletedAt, &identity.MergedInto, &metadataEnc,
 	)
 
 	if err != nil {
@@ -93,21 +215,31 @@ func (r *IdentityRepository) GetIdentityByCode(ctx context.Context, code string)
 		}
 		return nil, err
 	}
+	if err := openIdentityPII(cipher, identity, metadataEnc); err != nil {
+		return nil, err
+	}
 	return identity, nil
 }
 
 func (r *IdentityRepository) ListIdentities(ctx context.Context, page, limit int, search string) ([]*domain.Identity, int64, error) {
-	offset := (page - 1) * limit
-
 	whereClause := "WHERE deleted_at IS NULL"
 	var args []interface{}
-	argIdx := 1
 
 	if search != "" {
-		whereClause += fmt.Sprintf(" AND full_name ILIKE $%d", argIdx)
-		args = append(args, "%"+search+"%")
-		argIdx++
+		// Phone and CCCD are encrypted, so they only match exactly via the blind index
+		whereClause += " AND (full_name ILIKE $1 OR code ILIKE $1 OR phone_number_bidx = $2 OR identity_card_number_bidx = $2 OR phone_number = $3 OR identity_card_number = $3)"
+		args = append(args, "%"+search+"%", nullableString(r.cipher.BlindIndex(search)), search)
 	}
+	return r.listIdentities(ctx, whereClause, args, page, limit)
+}
+
+func (r *IdentityRepository) ListIdentitiesByStatus(ctx context.Context, status domain.IdentityStatus, page, limit int) ([]*domain.Identity, int64, error) {
+	return r.listIdentities(ctx, "WHERE deleted_at IS NULL AND status = $1", []interface{}{status}, page, limit)
+}
+
+func (r *IdentityRepository) listIdentities(ctx context.Context, whereClause string, args []interface{}, page, limit int) ([]*domain.Identity, int64, error) {
+	offset := (page - 1) * limit
+	argIdx := len(args) + 1
 
 	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM identities %s", whereClause)
 	var total int64
@@ -117,7 +249,7 @@ func (r *IdentityRepository) ListIdentities(ctx context.Context, page, limit int
 	}
 
 	query := fmt.Sprintf(`
-		SELECT id, COALESCE(code, ''), COALESCE(full_name, ''), COALESCE(type, ''), COALESCE(department, ''), COALESCE(status, 'active'), created_at, updated_at
+		SELECT id, COALESCE(code, ''), COALESCE(full_name, ''), COALESCE(type, ''), COALESCE(department, ''), COALESCE(status, 'active'), created_by, created_at, updated_at
 		FROM identities
 		%s
 		ORDER BY created_at DESC
@@ -137,7 +269,7 @@ func (r *IdentityRepository) ListIdentities(ctx context.Context, page, limit int
 		identity := &domain.Identity{}
 		err := rows.Scan(
 			&identity.ID, &identity.Code, &identity.FullName, &identity.Type,
-			&identity.Department, &identity.Status,
+			&identity.Department, &identity.Status, &identity.CreatedBy,
 			&identity.CreatedAt, &identity.UpdatedAt,
 		)
 		if err != nil {
@@ -156,15 +288,22 @@ func (r *IdentityRepository) CountIdentities(ctx context.Context) (int64, error)
 }
 
 func (r *IdentityRepository) UpdateIdentity(ctx context.Context, identity *domain.Identity) (*domain.Identity, error) {
+	pii, err := sealIdentityPII(r.cipher, identity)
+	if err != nil {
+		return nil, err
+	}
+
 	query := `
 		UPDATE identities
-		SET full_name = $2, type = $3, phone_number = $4, identity_card_number = $5, face_image_url = $6, department = $7, metadata = $8, note = $9, updated_at = NOW()
+		SET full_name = $2, type = $3, phone_number = $4, identity_card_number = $5, face_image_url = $6, department = $7, metadata = $8, note = $9,
+		    metadata_enc = $10, phone_number_bidx = $11, identity_card_number_bidx = $12, updated_at = NOW()
 		WHERE id = $1 AND deleted_at IS NULL
 		RETURNING updated_at`
 
-	err := r.db.Pool.QueryRow(ctx, query,
-		identity.ID, identity.FullName, identity.Type, identity.PhoneNumber,
-		identity.IdentityCardNumber, identity.FaceImageURL, identity.Department, identity.Metadata, identity.Note,
+	err = r.db.Pool.QueryRow(ctx, query,
+		identity.ID, identity.FullName, identity.Type, pii.phone,
+		pii.card, identity.FaceImageURL, identity.Department, pii.metadata, identity.Note,
+		pii.metadataEnc, pii.phoneIdx, pii.cardIdx,
 	).Scan(&identity.UpdatedAt)
 
 	if err != nil {
@@ -173,16 +312,20 @@ func (r *IdentityRepository) UpdateIdentity(ctx context.Context, identity *domai
 	return identity, nil
 }
 
-func (r *IdentityRepository) UpdateIdentityStatus(ctx context.Context, id uuid.UUID, status domain.IdentityStatus) (*domain.Identity, error) {
+func (r *IdentityRepository) ReviewIdentity(ctx context.Context, id uuid.UUID, status domain.IdentityStatus, reviewerID uuid.UUID, reason string) (*domain.Identity, error) {
+	// Two reviewers deciding at once: the second finds it no longer pending
 	query := `
 		UPDATE identities
-		SET status = $2, updated_at = NOW()
-		WHERE id = $1 AND deleted_at IS NULL`
+		SET status = $2, approved_by = $3, approved_at = NOW(), review_reason = $4, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL AND status = $5`
 
-	_, err := r.db.Pool.Exec(ctx, query, id, status)
+	tag, err := r.db.Pool.Exec(ctx, query, id, status, reviewerID, reason, domain.IdentityStatusPending)
 	if err != nil {
 		return nil, err
 	}
+	if tag.RowsAffected() == 0 {
+		return nil, domain.ErrIdentityNotPending
+	}
 	return r.GetIdentity(ctx, id)
 }
 
@@ -192,6 +335,82 @@ func (r *IdentityRepository) DeleteIdentity(ctx context.Context, id uuid.UUID) e
 	return err
 }
 
+// CountPII counts the identity rows the re-encryption job walks through.
+func (r *IdentityRepository) CountPII(ctx context.Context) (int, error) {
+	var count int
+	err := r.db.Pool.QueryRow(ctx, "SELECT COUNT(*) FROM identities WHERE erased_at IS NULL").Scan(&count)
+	return count, err
+}
+
+// ReencryptPII rewrites every identity whose PII is plaintext or encrypted
+// with a retired data key. Rows are visited in id order in small batches so
+// the job does not hold long transactions.
+func (r *IdentityRepository) ReencryptPII(ctx context.Context, step func(ok bool)) error {
+	const batchSize = 200
+	query := `SELECT id, COALESCE(phone_number, ''), COALESCE(identity_card_number, ''), metadata, COALESCE(metadata_enc, '')
+	          FROM identities WHERE erased_at IS NULL AND id > $1 ORDER BY id LIMIT $2`
+
+	type piiRow struct {
+		id          uuid.UUID
+		phone       string
+		card        string
+		metadata    map[string]any
+		metadataEnc string
+	}
+
+	var after uuid.UUID
+	for {
+		rows, err := r.db.Pool.Query(ctx, query, after, batchSize)
+		if err != nil {
+			return err
+		}
+		var batch []piiRow
+		for rows.Next() {
+			var row piiRow
+			if err := rows.Scan(&row.id, &row.phone, &row.card, &row.metadata, &row.metadataEnc); err != nil {
+				rows.Close()
+				return err
+			}
+			batch = append(batch, row)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+
+		for _, row := range batch {
+			after = row.id
+			current := r.cipher.IsCurrent(row.phone) && r.cipher.IsCurrent(row.card) &&
+				r.cipher.IsCurrent(row.metadataEnc) && (row.metadata == nil || !r.cipher.Enabled())
+			if current {
+				step(true)
+				continue
+			}
+			step(r.reencryptRow(ctx, row.id, row.phone, row.card, row.metadata, row.metadataEnc) == nil)
+		}
+	}
+}
+
+func (r *IdentityRepository) reencryptRow(ctx context.Context, id uuid.UUID, phone, card string, metadata map[string]any, metadataEnc string) error {
+	identity := &domain.Identity{PhoneNumber: phone, IdentityCardNumber: card, Metadata: metadata}
+	if err := openIdentityPII(r.cipher, identity, metadataEnc); err != nil {
+		return err
+	}
+	pii, err := sealIdentityPII(r.cipher, identity)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.Pool.Exec(ctx, `UPDATE identities
+	                              SET phone_number = $2, identity_card_number = $3, metadata = $4, metadata_enc = $5,
+	                                  phone_number_bidx = $6, identity_card_number_bidx = $7
+	                              WHERE id = $1`,
+		id, pii.phone, pii.card, pii.metadata, pii.metadataEnc, pii.phoneIdx, pii.cardIdx)
+	return err
+}
+
 type IdentityFaceRepository struct {
 	db *PostgresDB
 }
@@ -201,18 +420,21 @@ func NewIdentityFaceRepository(db *PostgresDB) ports.IdentityFaceRepository {
 }
 
 func (r *IdentityFaceRepository) CreateFace(ctx context.Context, face *domain.IdentityFace) (*domain.IdentityFace, error) {
-	query := `INSERT INTO identity_faces (identity_id, image_url, is_primary, quality_score, blur_score) 
-	          VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at`
-	err := r.db.Pool.QueryRow(ctx, query, face.IdentityID, face.ImageURL, face.IsPrimary, face.QualityScore, face.BlurScore).
+	query := `INSERT INTO identity_faces (identity_id, image_url, is_primary, quality_score, blur_score, quality_details, quality_flagged, quality_reason, embedding) 
+	          VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, created_at`
+	err := r.db.Pool.QueryRow(ctx, query, face.IdentityID, face.ImageURL, face.IsPrimary, face.QualityScore, face.BlurScore,
+		face.Quality, face.QualityFlagged, face.QualityReason, face.Embedding).
 		Scan(&face.ID, &face.CreatedAt)
 	if err != nil {
 		return nil, err
 	}
+	face.HasEmbedding = len(face.Embedding) > 0
 	return face, nil
 }
 
 func (r *IdentityFaceRepository) ListFaces(ctx context.Context, identityID uuid.UUID) ([]*domain.IdentityFace, error) {
-	query := `SELECT id, identity_id, image_url, is_primary, quality_score, blur_score, created_at 
+	query := `SELECT id, identity_id, image_url, is_primary, quality_score, blur_score, created_at,
+	                 quality_details, COALESCE(quality_flagged, false), COALESCE(quality_reason, ''), embedding
 	          FROM identity_faces WHERE identity_id = $1`
 	rows, err := r.db.Pool.Query(ctx, query, identityID)
 	if err != nil {
@@ -223,10 +445,49 @@ func (r *IdentityFaceRepository) ListFaces(ctx context.Context, identityID uuid.
 	faces := []*domain.IdentityFace{}
 	for rows.Next() {
 		face := &domain.IdentityFace{}
-		err := rows.Scan(&face.ID, &face.IdentityID, &face.ImageURL, &face.IsPrimary, &face.QualityScore, &face.BlurScore, &face.CreatedAt)
+		err := rows.Scan(&face.ID, &face.IdentityID, &face.ImageURL, &face.IsPrimary, &face.QualityScore, &face.BlurScore, &face.CreatedAt,
+			&face.Quality, &face.QualityFlagged, &face.QualityReason, &face.Embedding)
 		if err != nil {
 			return nil, err
 		}
+		face.HasEmbedding = len(face.Embedding) > 0
+		faces = append(faces, face)
+	}
+	return faces, nil
+}
+
+func (r *IdentityFaceRepository) GetFace(ctx context.Context, id uuid.UUID) (*domain.IdentityFace, error) {
+	query := `SELECT id, identity_id, image_url, is_primary, quality_score, blur_score, created_at FROM identity_faces WHERE id = $1`
+	face := &domain.IdentityFace{}
+	err := r.db.Pool.QueryRow(ctx, query, id).Scan(&face.ID, &face.IdentityID, &face.ImageURL, &face.IsPrimary, &face.QualityScore, &face.BlurScore, &face.CreatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return face, nil
+}
+
+// ListEmbeddings returns every face of a live identity that has an embedding,
+// used to warm the in-process face index.
+func (r *IdentityFaceRepository) ListEmbeddings(ctx context.Context) ([]*domain.IdentityFace, error) {
+	query := `SELECT f.id, f.identity_id, f.embedding
+	          FROM identity_faces f
+	          JOIN identities i ON i.id = f.identity_id
+	          WHERE f.embedding IS NOT NULL AND i.deleted_at IS NULL`
+	rows, err := r.db.Pool.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	faces := []*domain.IdentityFace{}
+	for rows.Next() {
+		face := &domain.IdentityFace{HasEmbedding: true}
+		if err := rows.Scan(&face.ID, &face.IdentityID, &face.Embedding); err != nil {
+			return nil, err
+		}
 		faces = append(faces, face)
 	}
 	return faces, nil
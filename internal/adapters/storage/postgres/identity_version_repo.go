@@ -0,0 +1,299 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// IdentityVersionRepository keeps snapshots and diffs as JSON text sealed with
+// the field cipher, since both carry the same PII as the identity row.
+type IdentityVersionRepository struct {
+	db     *PostgresDB
+	cipher ports.FieldCipher
+}
+
+func NewIdentityVersionRepository(db *PostgresDB, cipher ports.FieldCipher) *IdentityVersionRepository {
+	return &IdentityVersionRepository{db: db, cipher: cipher}
+}
+
+const versionColumns = `v.id, v.identity_id, v.version, v.entity_type, v.face_id, v.operation, v.snapshot, COALESCE(v.changes, ''),
+	v.revert_of, COALESCE(v.reason, ''), v.changed_by, v.changed_at, COALESCE(u.username, '')`
+
+const versionFrom = ` FROM identity_versions v LEFT JOIN users u ON u.id = v.changed_by `
+
+func (r *IdentityVersionRepository) RecordVersion(ctx context.Context, v *domain.IdentityVersion, baseline *domain.Identity) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Serialise version numbering per identity
+	if _, err := tx.Exec(ctx, `SELECT id FROM identities WHERE id = $1 FOR UPDATE`, v.IdentityID); err != nil {
+		return err
+	}
+
+	if baseline != nil {
+		var exists bool
+		if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM identity_versions WHERE identity_id = $1)`, v.IdentityID).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			base := &domain.IdentityVersion{
+				IdentityID: v.IdentityID,
+				EntityType: domain.VersionEntityIdentity,
+				Operation:  domain.VersionOpBaseline,
+				Identity:   baseline,
+				ChangedAt:  baseline.UpdatedAt,
+			}
+			if err := r.insert(ctx, tx, base); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := r.insert(ctx, tx, v); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *IdentityVersionRepository) RecordVersions(ctx context.Context, versions []*domain.IdentityVersion) error {
+	if len(versions) == 0 {
+		return nil
+	}
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	for _, v := range versions {
+		if err := r.insert(ctx, tx, v); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *IdentityVersionRepository) insert(ctx context.Context, tx pgx.Tx, v *domain.IdentityVersion) error {
+	snapshot, changes, err := r.seal(v)
+	if err != nil {
+		return err
+	}
+	var changedAt *time.Time
+	if !v.ChangedAt.IsZero() {
+		changedAt = &v.ChangedAt
+	}
+
+	query := `INSERT INTO identity_versions (identity_id, version, entity_type, face_id, operation, snapshot, changes, revert_of, reason, changed_by, changed_at)
+	          SELECT $1, COALESCE(MAX(version), 0) + 1, $2, $3, $4, $5, $6, $7, $8, $9, COALESCE($10, NOW())
+	          FROM identity_versions WHERE identity_id = $1
+	          RETURNING id, version, changed_at`
+	return tx.QueryRow(ctx, query, v.IdentityID, v.EntityType, v.FaceID, v.Operation, snapshot, changes,
+		v.RevertOf, nullableString(v.Reason), v.ChangedBy, changedAt).
+		Scan(&v.ID, &v.Version, &v.ChangedAt)
+}
+
+func (r *IdentityVersionRepository) seal(v *domain.IdentityVersion) (string, *string, error) {
+	var state any = v.Face
+	if v.EntityType == domain.VersionEntityIdentity {
+		identity := *v.Identity
+		identity.Faces = nil
+		state = &identity
+	}
+	raw, err := json.Marshal(state)
+	if err != nil {
+		return "", nil, err
+	}
+	snapshot, err := r.cipher.Encrypt(string(raw))
+	if err != nil {
+		return "", nil, err
+	}
+	if len(v.Changes) == 0 {
+		return snapshot, nil, nil
+	}
+	raw, err = json.Marshal(v.Changes)
+	if err != nil {
+		return "", nil, err
+	}
+	changes, err := r.cipher.Encrypt(string(raw))
+	if err != nil {
+		return "", nil, err
+	}
+	return snapshot, &changes, nil
+}
+
+func (r *IdentityVersionRepository) ListVersions(ctx context.Context, identityID uuid.UUID, limit, offset int32) ([]*domain.IdentityVersion, int64, error) {
+	var total int64
+	if err := r.db.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM identity_versions WHERE identity_id = $1`, identityID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT ` + versionColumns + versionFrom + `WHERE v.identity_id = $1 ORDER BY v.version DESC LIMIT $2 OFFSET $3`
+	versions, err := r.query(ctx, query, identityID, limit, offset)
+	return versions, total, err
+}
+
+func (r *IdentityVersionRepository) GetVersion(ctx context.Context, identityID uuid.UUID, version int) (*domain.IdentityVersion, error) {
+	query := `SELECT ` + versionColumns + versionFrom + `WHERE v.identity_id = $1 AND v.version = $2`
+	v, err := r.scan(r.db.Pool.QueryRow(ctx, query, identityID, version))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	return v, err
+}
+
+func (r *IdentityVersionRepository) IdentityAsOf(ctx context.Context, identityID uuid.UUID, t time.Time) (*domain.IdentityVersion, error) {
+	query := `SELECT ` + versionColumns + versionFrom + `
+	          WHERE v.identity_id = $1 AND v.entity_type = $2 AND v.changed_at <= $3
+	          ORDER BY v.version DESC LIMIT 1`
+	v, err := r.scan(r.db.Pool.QueryRow(ctx, query, identityID, domain.VersionEntityIdentity, t))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	return v, err
+}
+
+func (r *IdentityVersionRepository) FacesAsOf(ctx context.Context, identityID uuid.UUID, t time.Time) ([]*domain.IdentityVersion, error) {
+	query := `SELECT DISTINCT ON (v.face_id) ` + versionColumns + versionFrom + `
+	          WHERE v.identity_id = $1 AND v.entity_type = $2 AND v.changed_at <= $3
+	          ORDER BY v.face_id, v.version DESC`
+	return r.query(ctx, query, identityID, domain.VersionEntityFace, t)
+}
+
+func (r *IdentityVersionRepository) query(ctx context.Context, query string, args ...any) ([]*domain.IdentityVersion, error) {
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := []*domain.IdentityVersion{}
+	for rows.Next() {
+		v, err := r.scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, v)
+	}
+	return versions, rows.Err()
+}
+
+func (r *IdentityVersionRepository) scan(row pgx.Row) (*domain.IdentityVersion, error) {
+	v := &domain.IdentityVersion{}
+	var snapshot, changes string
+	err := row.Scan(&v.ID, &v.IdentityID, &v.Version, &v.EntityType, &v.FaceID, &v.Operation, &snapshot, &changes,
+		&v.RevertOf, &v.Reason, &v.ChangedBy, &v.ChangedAt, &v.ChangedByName)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := r.cipher.Decrypt(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	if v.EntityType == domain.VersionEntityFace {
+		v.Face = &domain.IdentityFace{}
+		err = json.Unmarshal([]byte(raw), v.Face)
+	} else {
+		v.Identity = &domain.Identity{}
+		err = json.Unmarshal([]byte(raw), v.Identity)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if changes != "" {
+		if raw, err = r.cipher.Decrypt(changes); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(raw), &v.Changes); err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+func (r *IdentityVersionRepository) CountPII(ctx context.Context) (int, error) {
+	var count int
+	err := r.db.Pool.QueryRow(ctx, "SELECT COUNT(*) FROM identity_versions").Scan(&count)
+	return count, err
+}
+
+// ReencryptPII reseals snapshots and diffs that are plaintext or use a
+// retired data key, in id order and small batches like the identity rows.
+func (r *IdentityVersionRepository) ReencryptPII(ctx context.Context, step func(ok bool)) error {
+	const batchSize = 200
+	query := `SELECT id, snapshot, COALESCE(changes, '') FROM identity_versions WHERE id > $1 ORDER BY id LIMIT $2`
+
+	type versionRow struct {
+		id       int64
+		snapshot string
+		changes  string
+	}
+
+	var after int64
+	for {
+		rows, err := r.db.Pool.Query(ctx, query, after, batchSize)
+		if err != nil {
+			return err
+		}
+		var batch []versionRow
+		for rows.Next() {
+			var row versionRow
+			if err := rows.Scan(&row.id, &row.snapshot, &row.changes); err != nil {
+				rows.Close()
+				return err
+			}
+			batch = append(batch, row)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+
+		for _, row := range batch {
+			after = row.id
+			if r.cipher.IsCurrent(row.snapshot) && (row.changes == "" || r.cipher.IsCurrent(row.changes)) {
+				step(true)
+				continue
+			}
+			step(r.reencryptRow(ctx, row.id, row.snapshot, row.changes) == nil)
+		}
+	}
+}
+
+func (r *IdentityVersionRepository) reencryptRow(ctx context.Context, id int64, snapshot, changes string) error {
+	reseal := func(value string) (*string, error) {
+		if value == "" {
+			return nil, nil
+		}
+		plain, err := r.cipher.Decrypt(value)
+		if err != nil {
+			return nil, err
+		}
+		sealed, err := r.cipher.Encrypt(plain)
+		return &sealed, err
+	}
+	newSnapshot, err := reseal(snapshot)
+	if err != nil {
+		return err
+	}
+	newChanges, err := reseal(changes)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.Pool.Exec(ctx, `UPDATE identity_versions SET snapshot = $2, changes = $3 WHERE id = $1`, id, *newSnapshot, newChanges)
+	return err
+}
@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type JobRepository struct {
+	db *PostgresDB
+}
+
+func NewJobRepository(db *PostgresDB) ports.JobRepository {
+	return &JobRepository{db: db}
+}
+
+const jobColumns = `id, type, status, total, processed, failed, params, result, COALESCE(result_url, ''), COALESCE(error, ''), created_by, created_at, started_at, finished_at`
+
+func (r *JobRepository) Create(ctx context.Context, job *domain.Job) error {
+	query := `INSERT INTO jobs (type, status, total, params, created_by) VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at`
+	return r.db.Pool.QueryRow(ctx, query, job.Type, job.Status, job.Total, job.Params, job.CreatedBy).
+		Scan(&job.ID, &job.CreatedAt)
+}
+
+func (r *JobRepository) Get(ctx context.Context, id uuid.UUID) (*domain.Job, error) {
+	query := `SELECT ` + jobColumns + ` FROM jobs WHERE id = $1`
+	job, err := scanJob(r.db.Pool.QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	return job, err
+}
+
+func (r *JobRepository) Update(ctx context.Context, job *domain.Job) error {
+	query := `UPDATE jobs SET status = $2, total = $3, processed = $4, failed = $5, result = $6, result_url = $7, error = $8, started_at = $9, finished_at = $10
+	          WHERE id = $1`
+	_, err := r.db.Pool.Exec(ctx, query, job.ID, job.Status, job.Total, job.Processed, job.Failed,
+		job.Result, job.ResultURL, job.Error, job.StartedAt, job.FinishedAt)
+	return err
+}
+
+func (r *JobRepository) List(ctx context.Context, jobType *string, createdBy *uuid.UUID, limit, offset int32) ([]*domain.Job, error) {
+	query := `SELECT ` + jobColumns + ` FROM jobs
+	          WHERE ($1::text IS NULL OR type = $1)
+	            AND ($2::uuid IS NULL OR created_by = $2)
+	          ORDER BY created_at DESC
+	          LIMIT $3 OFFSET $4`
+	rows, err := r.db.Pool.Query(ctx, query, jobType, createdBy, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobs := []*domain.Job{}
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// FailRunning marks jobs left running by a previous process as failed.
+func (r *JobRepository) FailRunning(ctx context.Context, reason string) (int64, error) {
+	tag, err := r.db.Pool.Exec(ctx, `UPDATE jobs SET status = 'failed', error = $1, finished_at = NOW() WHERE status IN ('queued', 'running')`, reason)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+func scanJob(row pgx.Row) (*domain.Job, error) {
+	job := &domain.Job{}
+	err := row.Scan(&job.ID, &job.Type, &job.Status, &job.Total, &job.Processed, &job.Failed,
+		&job.Params, &job.Result, &job.ResultURL, &job.Error, &job.CreatedBy, &job.CreatedAt, &job.StartedAt, &job.FinishedAt)
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
@@ -0,0 +1,157 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type MaintenanceRepository struct {
+	db *PostgresDB
+}
+
+func NewMaintenanceRepository(db *PostgresDB) ports.MaintenanceRepository {
+	return &MaintenanceRepository{db: db}
+}
+
+const maintenanceColumns = `w.id, w.name, w.camera_id, w.zone_id, w.starts_at, w.ends_at, w.recurrence, w.repeat_until,
+	w.timezone, w.event_policy, COALESCE(w.note, ''), w.created_by, w.created_at, w.updated_at`
+
+func scanMaintenanceWindow(row pgx.Row, extra ...any) (*domain.MaintenanceWindow, error) {
+	w := &domain.MaintenanceWindow{}
+	dest := append([]any{&w.ID, &w.Name, &w.CameraID, &w.ZoneID, &w.StartsAt, &w.EndsAt, &w.Recurrence, &w.RepeatUntil,
+		&w.Timezone, &w.EventPolicy, &w.Note, &w.CreatedBy, &w.CreatedAt, &w.UpdatedAt}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (r *MaintenanceRepository) Create(ctx context.Context, w *domain.MaintenanceWindow) error {
+	query := `INSERT INTO maintenance_windows (name, camera_id, zone_id, starts_at, ends_at, recurrence, repeat_until, timezone, event_policy, note, created_by)
+	          VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NULLIF($10, ''), $11)
+	          RETURNING id, created_at, updated_at`
+	return r.db.Pool.QueryRow(ctx, query, w.Name, w.CameraID, w.ZoneID, w.StartsAt, w.EndsAt, w.Recurrence, w.RepeatUntil,
+		w.Timezone, w.EventPolicy, w.Note, w.CreatedBy).Scan(&w.ID, &w.CreatedAt, &w.UpdatedAt)
+}
+
+func (r *MaintenanceRepository) Get(ctx context.Context, id uuid.UUID) (*domain.MaintenanceWindow, error) {
+	query := `SELECT ` + maintenanceColumns + ` FROM maintenance_windows w WHERE w.id = $1`
+	w, err := scanMaintenanceWindow(r.db.Pool.QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	return w, err
+}
+
+func (r *MaintenanceRepository) List(ctx context.Context, filter *ports.MaintenanceWindowFilter) ([]*domain.MaintenanceWindow, int64, error) {
+	where := `WHERE ($1::uuid IS NULL OR w.camera_id = $1 OR w.zone_id::text = ANY(
+	              SELECT unnest(string_to_array(trim(both '/' from z.path), '/'))
+	              FROM cameras c JOIN zones z ON z.id = c.zone_id WHERE c.id = $1))
+	            AND ($2::uuid IS NULL OR w.zone_id = $2)`
+
+	var total int64
+	if err := r.db.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM maintenance_windows w `+where,
+		filter.CameraID, filter.ZoneID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT ` + maintenanceColumns + ` FROM maintenance_windows w ` + where + `
+	          ORDER BY w.starts_at DESC
+	          LIMIT $3 OFFSET $4`
+	rows, err := r.db.Pool.Query(ctx, query, filter.CameraID, filter.ZoneID, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	windows := []*domain.MaintenanceWindow{}
+	for rows.Next() {
+		w, err := scanMaintenanceWindow(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		windows = append(windows, w)
+	}
+	return windows, total, rows.Err()
+}
+
+func (r *MaintenanceRepository) Update(ctx context.Context, w *domain.MaintenanceWindow) error {
+	query := `UPDATE maintenance_windows
+	          SET name = $2, camera_id = $3, zone_id = $4, starts_at = $5, ends_at = $6, recurrence = $7,
+	              repeat_until = $8, timezone = $9, event_policy = $10, note = NULLIF($11, ''), updated_at = NOW()
+	          WHERE id = $1
+	          RETURNING updated_at`
+	err := r.db.Pool.QueryRow(ctx, query, w.ID, w.Name, w.CameraID, w.ZoneID, w.StartsAt, w.EndsAt, w.Recurrence,
+		w.RepeatUntil, w.Timezone, w.EventPolicy, w.Note).Scan(&w.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return domain.ErrMaintenanceWindowNotFound
+	}
+	return err
+}
+
+func (r *MaintenanceRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.db.Pool.Exec(ctx, `DELETE FROM maintenance_windows WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrMaintenanceWindowNotFound
+	}
+	return nil
+}
+
+func (r *MaintenanceRepository) ListCandidates(ctx context.Context, at time.Time, cameraID *uuid.UUID) ([]*domain.MaintenanceWindow, error) {
+	query := `SELECT ` + maintenanceColumns + `, COALESCE(cams.ids, '{}')
+	          FROM maintenance_windows w
+	          LEFT JOIN LATERAL (
+	              SELECT array_agg(c.id) AS ids FROM cameras c
+	              WHERE c.id = w.camera_id
+	                 OR c.zone_id IN (SELECT z.id FROM zones z WHERE z.path LIKE (SELECT path FROM zones WHERE id = w.zone_id) || '%')
+	          ) cams ON TRUE
+	          WHERE w.starts_at <= $1
+	            AND (w.recurrence <> 'none' OR w.ends_at > $1)
+	            AND (w.repeat_until IS NULL OR w.repeat_until > $1 - (w.ends_at - w.starts_at))
+	            AND ($2::uuid IS NULL OR $2 = ANY(cams.ids))`
+
+	rows, err := r.db.Pool.Query(ctx, query, at, cameraID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	windows := []*domain.MaintenanceWindow{}
+	for rows.Next() {
+		var cameraIDs []uuid.UUID
+		w, err := scanMaintenanceWindow(rows, &cameraIDs)
+		if err != nil {
+			return nil, err
+		}
+		w.CameraIDs = cameraIDs
+		windows = append(windows, w)
+	}
+	return windows, rows.Err()
+}
+
+func (r *MaintenanceRepository) ListCameraMaintenance(ctx context.Context) ([]*domain.CameraMaintenance, error) {
+	rows, err := r.db.Pool.Query(ctx, `SELECT id, COALESCE(status, 'online'), maintenance_window_id FROM cameras`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cameras := []*domain.CameraMaintenance{}
+	for rows.Next() {
+		c := &domain.CameraMaintenance{}
+		if err := rows.Scan(&c.CameraID, &c.Status, &c.WindowID); err != nil {
+			return nil, err
+		}
+		cameras = append(cameras, c)
+	}
+	return cameras, rows.Err()
+}
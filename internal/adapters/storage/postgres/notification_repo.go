@@ -0,0 +1,56 @@
+package postgres
+
+import (
+	"context"
+
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+
+	"github.com/google/uuid"
+)
+
+type NotificationRepository struct {
+	db *PostgresDB
+}
+
+func NewNotificationRepository(db *PostgresDB) ports.NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+func (r *NotificationRepository) Create(ctx context.Context, n *domain.Notification) error {
+	query := `INSERT INTO notifications (user_id, type, title, message, resource_type, resource_id)
+	          VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at`
+	return r.db.Pool.QueryRow(ctx, query, n.UserID, n.Type, n.Title, n.Message, n.ResourceType, n.ResourceID).
+		Scan(&n.ID, &n.CreatedAt)
+}
+
+func (r *NotificationRepository) ListByUser(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit, offset int32) ([]*domain.Notification, error) {
+	query := `SELECT id, user_id, type, title, COALESCE(message, ''), COALESCE(resource_type, ''), COALESCE(resource_id, ''), read_at, created_at
+	          FROM notifications
+	          WHERE user_id = $1 AND (NOT $2 OR read_at IS NULL)
+	          ORDER BY created_at DESC
+	          LIMIT $3 OFFSET $4`
+
+	rows, err := r.db.Pool.Query(ctx, query, userID, unreadOnly, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notifications := []*domain.Notification{}
+	for rows.Next() {
+		n := &domain.Notification{}
+		err := rows.Scan(&n.ID, &n.UserID, &n.Type, &n.Title, &n.Message, &n.ResourceType, &n.ResourceID, &n.ReadAt, &n.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, n)
+	}
+	return notifications, nil
+}
+
+func (r *NotificationRepository) MarkRead(ctx context.Context, id, userID uuid.UUID) error {
+	query := `UPDATE notifications SET read_at = NOW() WHERE id = $1 AND user_id = $2 AND read_at IS NULL`
+	_, err := r.db.Pool.Exec(ctx, query, id, userID)
+	return err
+}
@@ -0,0 +1,196 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type RecognitionFeedbackRepository struct {
+	db *PostgresDB
+}
+
+func NewRecognitionFeedbackRepository(db *PostgresDB) ports.RecognitionFeedbackRepository {
+	return &RecognitionFeedbackRepository{db: db}
+}
+
+func (r *RecognitionFeedbackRepository) GetLog(ctx context.Context, id uuid.UUID) (*domain.RecognitionLog, error) {
+	query := `SELECT rl.id, rl.camera_id, rl.identity_id, rl.stranger_cluster_id, COALESCE(rl.snapshot_url, ''), COALESCE(rl.face_crop_url, ''),
+	                 COALESCE(rl.confidence, 0), COALESCE(rl.label, ''), COALESCE(rl.review_status, ''), rl.embedding,
+	                 rl.occurred_at, rl.created_at, COALESCE(i.full_name, ''), COALESCE(c.name, '')
+	          FROM recognition_logs rl
+	          LEFT JOIN identities i ON rl.identity_id = i.id
+	          LEFT JOIN cameras c ON rl.camera_id = c.id
+	          WHERE rl.id = $1`
+
+	log := &domain.RecognitionLog{}
+	err := r.db.Pool.QueryRow(ctx, query, id).Scan(
+		&log.ID, &log.CameraID, &log.IdentityID, &log.StrangerClusterID,
+		&log.SnapshotURL, &log.FaceCropURL, &log.Confidence, &log.Label, &log.ReviewStatus, &log.Embedding,
+		&log.OccurredAt, &log.CreatedAt, &log.IdentityName, &log.CameraName,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return log, nil
+}
+
+func (r *RecognitionFeedbackRepository) ApplyFeedback(ctx context.Context, fb *domain.RecognitionFeedback) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, `INSERT INTO recognition_feedback (log_id, log_occurred_at, camera_id, action, original_identity_id,
+	                            corrected_identity_id, confidence, face_crop_url, snapshot_url, embedding, reason, reviewed_by)
+	                        VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), NULLIF($9, ''), $10, NULLIF($11, ''), $12)
+	                        RETURNING id, created_at`,
+		fb.LogID, fb.LogOccurredAt, fb.CameraID, fb.Action, fb.OriginalIdentityID, fb.CorrectedIdentityID,
+		fb.Confidence, fb.FaceCropURL, fb.SnapshotURL, fb.Embedding, fb.Reason, fb.ReviewedBy).
+		Scan(&fb.ID, &fb.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	// The verdict was made on the identity the reviewer saw; a concurrent
+	// review that moved the log first wins
+	tag, err := tx.Exec(ctx, `UPDATE recognition_logs SET identity_id = $3, review_status = $4
+	                         WHERE id = $1 AND occurred_at = $2 AND identity_id IS NOT DISTINCT FROM $5`,
+		fb.LogID, fb.LogOccurredAt, fb.CorrectedIdentityID, fb.Action.ReviewStatus(), fb.OriginalIdentityID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrFeedbackStale
+	}
+	return tx.Commit(ctx)
+}
+
+const feedbackColumns = `f.id, f.log_id, f.log_occurred_at, f.camera_id, f.action, f.original_identity_id, f.corrected_identity_id,
+	COALESCE(f.confidence, 0), COALESCE(f.face_crop_url, ''), COALESCE(f.snapshot_url, ''), f.embedding, COALESCE(f.reason, ''),
+	f.reviewed_by, f.created_at`
+
+const feedbackWhere = `
+	WHERE ($1::text IS NULL OR f.action = $1)
+	  AND ($2::uuid IS NULL OR f.original_identity_id = $2 OR f.corrected_identity_id = $2)
+	  AND ($3::uuid IS NULL OR f.camera_id = $3)
+	  AND ($4::timestamptz IS NULL OR f.created_at >= $4)
+	  AND ($5::timestamptz IS NULL OR f.created_at <= $5)`
+
+func (r *RecognitionFeedbackRepository) ListFeedback(ctx context.Context, filter *ports.FeedbackFilter) ([]*domain.RecognitionFeedback, int64, error) {
+	args := []any{filter.Action, filter.IdentityID, filter.CameraID, filter.FromDate, filter.ToDate}
+
+	var total int64
+	if err := r.db.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM recognition_feedback f`+feedbackWhere, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT ` + feedbackColumns + ` FROM recognition_feedback f` + feedbackWhere + ` ORDER BY f.created_at DESC LIMIT $6 OFFSET $7`
+	rows, err := r.db.Pool.Query(ctx, query, append(args, filter.Limit, filter.Offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	feedback := []*domain.RecognitionFeedback{}
+	for rows.Next() {
+		fb, err := scanFeedback(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		fb.Embedding = nil
+		feedback = append(feedback, fb)
+	}
+	return feedback, total, rows.Err()
+}
+
+func (r *RecognitionFeedbackRepository) EachLatestFeedback(ctx context.Context, filter *ports.FeedbackFilter, fn func(*domain.RecognitionFeedback) error) error {
+	query := `SELECT * FROM (
+	              SELECT DISTINCT ON (f.log_id) ` + feedbackColumns + `
+	              FROM recognition_feedback f` + feedbackWhere + `
+	              ORDER BY f.log_id, f.created_at DESC
+	          ) latest ORDER BY created_at`
+
+	rows, err := r.db.Pool.Query(ctx, query, filter.Action, filter.IdentityID, filter.CameraID, filter.FromDate, filter.ToDate)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		fb, err := scanFeedback(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(fb); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func scanFeedback(row pgx.Row) (*domain.RecognitionFeedback, error) {
+	fb := &domain.RecognitionFeedback{}
+	err := row.Scan(&fb.ID, &fb.LogID, &fb.LogOccurredAt, &fb.CameraID, &fb.Action, &fb.OriginalIdentityID,
+		&fb.CorrectedIdentityID, &fb.Confidence, &fb.FaceCropURL, &fb.SnapshotURL, &fb.Embedding, &fb.Reason,
+		&fb.ReviewedBy, &fb.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return fb, nil
+}
+
+func (r *RecognitionFeedbackRepository) ListRecognitionTimes(ctx context.Context, identityID uuid.UUID, from, to time.Time) ([]time.Time, error) {
+	rows, err := r.db.Pool.Query(ctx, `SELECT occurred_at FROM recognition_logs
+	                                   WHERE identity_id = $1 AND occurred_at >= $2 AND occurred_at < $3
+	                                   ORDER BY occurred_at`, identityID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	times := []time.Time{}
+	for rows.Next() {
+		var t time.Time
+		if err := rows.Scan(&t); err != nil {
+			return nil, err
+		}
+		times = append(times, t)
+	}
+	return times, rows.Err()
+}
+
+func (r *RecognitionFeedbackRepository) SaveAttendance(ctx context.Context, record *domain.AttendanceRecord) error {
+	query := `INSERT INTO attendance_records (identity_id, date, check_in, check_out, work_hours, status)
+	          VALUES ($1, $2, $3, $4, $5, $6)
+	          ON CONFLICT (identity_id, date) DO UPDATE SET
+	              check_in = EXCLUDED.check_in, check_out = EXCLUDED.check_out,
+	              work_hours = EXCLUDED.work_hours, status = EXCLUDED.status
+	          RETURNING id, created_at, updated_at`
+	return r.db.Pool.QueryRow(ctx, query, record.IdentityID, record.Date, record.CheckIn, record.CheckOut, record.WorkHours, record.Status).
+		Scan(&record.ID, &record.CreatedAt, &record.UpdatedAt)
+}
+
+func (r *RecognitionFeedbackRepository) ClearAttendance(ctx context.Context, identityID uuid.UUID, date time.Time) (*domain.AttendanceRecord, error) {
+	record := &domain.AttendanceRecord{IdentityID: identityID, Date: date}
+	err := r.db.Pool.QueryRow(ctx, `UPDATE attendance_records SET check_in = NULL, check_out = NULL, work_hours = 0, status = $3
+	                                WHERE identity_id = $1 AND date = $2
+	                                RETURNING id, status, created_at, updated_at`, identityID, date, domain.AttendanceAbsent).
+		Scan(&record.ID, &record.Status, &record.CreatedAt, &record.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
@@ -75,3 +75,28 @@ func (r *RoleRepository) Delete(ctx context.Context, id string) error {
 	_, err := r.db.Pool.Exec(ctx, "DELETE FROM roles WHERE id = $1 AND is_system = FALSE", id)
 	return err
 }
+
+// GetUserPermissions returns the permissions of the user's role. Roles whose
+// permissions are not a JSON array grant nothing.
+func (r *RoleRepository) GetUserPermissions(ctx context.Context, userID string) ([]string, error) {
+	query := `SELECT jsonb_array_elements_text(r.permissions)
+	          FROM users u
+	          JOIN roles r ON r.id = u.role_id
+	          WHERE u.id = $1 AND jsonb_typeof(r.permissions) = 'array'`
+
+	rows, err := r.db.Pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	permissions := []string{}
+	for rows.Next() {
+		var p string
+		if err := rows.Scan(&p); err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, p)
+	}
+	return permissions, rows.Err()
+}
@@ -0,0 +1,235 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type StrangerRepository struct {
+	db *PostgresDB
+}
+
+func NewStrangerRepository(db *PostgresDB) ports.StrangerRepository {
+	return &StrangerRepository{db: db}
+}
+
+func (r *StrangerRepository) ListUnclustered(ctx context.Context, after time.Time, afterID uuid.UUID, limit int) ([]*domain.RecognitionLog, error) {
+	query := `SELECT id, camera_id, COALESCE(snapshot_url, ''), face_crop_url, COALESCE(confidence, 0), COALESCE(label, ''),
+	                 embedding, occurred_at, created_at
+	          FROM recognition_logs
+	          WHERE identity_id IS NULL AND stranger_cluster_id IS NULL
+	            AND face_crop_url IS NOT NULL AND face_crop_url <> ''
+	            AND (occurred_at, id) > ($1, $2)
+	          ORDER BY occurred_at, id
+	          LIMIT $3`
+
+	rows, err := r.db.Pool.Query(ctx, query, after, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	logs := []*domain.RecognitionLog{}
+	for rows.Next() {
+		log := &domain.RecognitionLog{}
+		err := rows.Scan(&log.ID, &log.CameraID, &log.SnapshotURL, &log.FaceCropURL, &log.Confidence, &log.Label,
+			&log.Embedding, &log.OccurredAt, &log.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, log)
+	}
+	return logs, rows.Err()
+}
+
+func (r *StrangerRepository) SetLogEmbedding(ctx context.Context, log *domain.RecognitionLog) error {
+	_, err := r.db.Pool.Exec(ctx, `UPDATE recognition_logs SET embedding = $3 WHERE id = $1 AND occurred_at = $2`,
+		log.ID, log.OccurredAt, log.Embedding)
+	return err
+}
+
+func (r *StrangerRepository) ListOpenCentroids(ctx context.Context) ([]*domain.StrangerCluster, error) {
+	rows, err := r.db.Pool.Query(ctx, `SELECT id, seq, centroid, log_count FROM stranger_clusters WHERE status = $1`, domain.StrangerClusterOpen)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	clusters := []*domain.StrangerCluster{}
+	for rows.Next() {
+		c := &domain.StrangerCluster{Status: domain.StrangerClusterOpen}
+		if err := rows.Scan(&c.ID, &c.Seq, &c.Centroid, &c.LogCount); err != nil {
+			return nil, err
+		}
+		c.Label = domain.StrangerLabel(c.Seq)
+		clusters = append(clusters, c)
+	}
+	return clusters, rows.Err()
+}
+
+// SaveCluster keeps the highest-confidence crop as the cluster's thumbnail.
+func (r *StrangerRepository) SaveCluster(ctx context.Context, cluster *domain.StrangerCluster, logIDs []uuid.UUID) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if cluster.ID == uuid.Nil {
+		err = tx.QueryRow(ctx, `INSERT INTO stranger_clusters (centroid) VALUES ($1) RETURNING id, seq, status, created_at`, cluster.Centroid).
+			Scan(&cluster.ID, &cluster.Seq, &cluster.Status, &cluster.CreatedAt)
+		if err != nil {
+			return err
+		}
+		cluster.Label = domain.StrangerLabel(cluster.Seq)
+	} else {
+		if _, err := tx.Exec(ctx, `UPDATE stranger_clusters SET centroid = $2 WHERE id = $1`, cluster.ID, cluster.Centroid); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE recognition_logs SET stranger_cluster_id = $1
+	                       WHERE id = ANY($2) AND identity_id IS NULL AND stranger_cluster_id IS NULL`, cluster.ID, logIDs)
+	if err != nil {
+		return err
+	}
+
+	err = tx.QueryRow(ctx, `UPDATE stranger_clusters s SET
+	                            log_count = agg.n, camera_ids = agg.cameras, first_seen_at = agg.first_seen,
+	                            last_seen_at = agg.last_seen, best_crop_url = agg.best_crop, updated_at = NOW()
+	                        FROM (SELECT COUNT(*) AS n, ARRAY_AGG(DISTINCT camera_id) AS cameras,
+	                                     MIN(occurred_at) AS first_seen, MAX(occurred_at) AS last_seen,
+	                                     (ARRAY_AGG(face_crop_url ORDER BY confidence DESC NULLS LAST, occurred_at DESC))[1] AS best_crop
+	                              FROM recognition_logs WHERE stranger_cluster_id = $1) agg
+	                        WHERE s.id = $1
+	                        RETURNING s.log_count, s.camera_ids, COALESCE(s.best_crop_url, ''), s.first_seen_at, s.last_seen_at, s.updated_at`, cluster.ID).
+		Scan(&cluster.LogCount, &cluster.CameraIDs, &cluster.BestCropURL, &cluster.FirstSeenAt, &cluster.LastSeenAt, &cluster.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+const strangerClusterColumns = `s.id, s.seq, s.log_count, s.camera_ids, COALESCE(s.best_crop_url, ''), s.first_seen_at, s.last_seen_at,
+	s.status, s.promoted_identity_id, s.promoted_by, s.promoted_at, s.created_at, s.updated_at,
+	ARRAY(SELECT c.name FROM cameras c WHERE c.id = ANY(s.camera_ids) ORDER BY c.name)`
+
+func (r *StrangerRepository) ListClusters(ctx context.Context, filter *ports.StrangerClusterFilter) ([]*domain.StrangerCluster, int64, error) {
+	where := ` FROM stranger_clusters s
+	          WHERE ($1::text IS NULL OR s.status = $1)
+	            AND ($2::uuid IS NULL OR $2 = ANY(s.camera_ids))`
+
+	var total int64
+	if err := r.db.Pool.QueryRow(ctx, `SELECT COUNT(*)`+where, filter.Status, filter.CameraID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT ` + strangerClusterColumns + where + ` ORDER BY s.last_seen_at DESC NULLS LAST LIMIT $3 OFFSET $4`
+	rows, err := r.db.Pool.Query(ctx, query, filter.Status, filter.CameraID, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	clusters := []*domain.StrangerCluster{}
+	for rows.Next() {
+		c, err := scanStrangerCluster(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		clusters = append(clusters, c)
+	}
+	return clusters, total, rows.Err()
+}
+
+func (r *StrangerRepository) GetCluster(ctx context.Context, id uuid.UUID) (*domain.StrangerCluster, error) {
+	query := `SELECT ` + strangerClusterColumns + ` FROM stranger_clusters s WHERE s.id = $1`
+	c, err := scanStrangerCluster(r.db.Pool.QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	return c, err
+}
+
+func scanStrangerCluster(row pgx.Row) (*domain.StrangerCluster, error) {
+	c := &domain.StrangerCluster{}
+	err := row.Scan(&c.ID, &c.Seq, &c.LogCount, &c.CameraIDs, &c.BestCropURL, &c.FirstSeenAt, &c.LastSeenAt,
+		&c.Status, &c.PromotedIdentityID, &c.PromotedBy, &c.PromotedAt, &c.CreatedAt, &c.UpdatedAt, &c.CameraNames)
+	if err != nil {
+		return nil, err
+	}
+	c.Label = domain.StrangerLabel(c.Seq)
+	return c, nil
+}
+
+// ListClusterLogs returns the cluster's logs newest first, with embeddings.
+func (r *StrangerRepository) ListClusterLogs(ctx context.Context, id uuid.UUID, limit, offset int32) ([]*domain.RecognitionLog, int64, error) {
+	var total int64
+	if err := r.db.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM recognition_logs WHERE stranger_cluster_id = $1`, id).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT rl.id, rl.camera_id, rl.identity_id, rl.stranger_cluster_id, COALESCE(rl.snapshot_url, ''), COALESCE(rl.face_crop_url, ''),
+	                 COALESCE(rl.confidence, 0), COALESCE(rl.label, ''), rl.embedding, rl.occurred_at, rl.created_at, COALESCE(c.name, '')
+	          FROM recognition_logs rl
+	          LEFT JOIN cameras c ON rl.camera_id = c.id
+	          WHERE rl.stranger_cluster_id = $1
+	          ORDER BY rl.occurred_at DESC
+	          LIMIT $2 OFFSET $3`
+
+	rows, err := r.db.Pool.Query(ctx, query, id, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	logs := []*domain.RecognitionLog{}
+	for rows.Next() {
+		log := &domain.RecognitionLog{}
+		err := rows.Scan(
+			&log.ID, &log.CameraID, &log.IdentityID, &log.StrangerClusterID,
+			&log.SnapshotURL, &log.FaceCropURL, &log.Confidence, &log.Label,
+			&log.Embedding, &log.OccurredAt, &log.CreatedAt, &log.CameraName,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		logs = append(logs, log)
+	}
+	return logs, total, rows.Err()
+}
+
+func (r *StrangerRepository) PromoteCluster(ctx context.Context, id, identityID uuid.UUID, promotedBy *uuid.UUID) (int64, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `UPDATE stranger_clusters SET status = $2, promoted_identity_id = $3, promoted_by = $4,
+	                              promoted_at = NOW(), updated_at = NOW()
+	                          WHERE id = $1 AND status = $5`,
+		id, domain.StrangerClusterPromoted, identityID, promotedBy, domain.StrangerClusterOpen)
+	if err != nil {
+		return 0, err
+	}
+	if tag.RowsAffected() == 0 {
+		return 0, domain.ErrStrangerClusterPromoted
+	}
+
+	// stranger_cluster_id stays set so the logs still show where they came from
+	tag, err = tx.Exec(ctx, `UPDATE recognition_logs SET identity_id = $2 WHERE stranger_cluster_id = $1 AND identity_id IS NULL`, id, identityID)
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+
+	"github.com/google/uuid"
+)
+
+type UptimeRepository struct {
+	db *PostgresDB
+}
+
+func NewUptimeRepository(db *PostgresDB) ports.UptimeRepository {
+	return &UptimeRepository{db: db}
+}
+
+func (r *UptimeRepository) ListCameras(ctx context.Context, zoneID *uuid.UUID) ([]*domain.UptimeCamera, error) {
+	query := `SELECT c.id, c.name, c.zone_id, COALESCE(z.name, ''), COALESCE(c.status, 'online'), c.created_at
+	          FROM cameras c
+	          LEFT JOIN zones z ON c.zone_id = z.id
+	          WHERE ($1::uuid IS NULL OR c.zone_id IN (
+	              SELECT id FROM zones WHERE path LIKE (SELECT path FROM zones WHERE id = $1) || '%'))
+	          ORDER BY z.name NULLS LAST, c.name`
+
+	rows, err := r.db.Pool.Query(ctx, query, zoneID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cameras := []*domain.UptimeCamera{}
+	for rows.Next() {
+		c := &domain.UptimeCamera{}
+		if err := rows.Scan(&c.ID, &c.Name, &c.ZoneID, &c.ZoneName, &c.Status, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		cameras = append(cameras, c)
+	}
+	return cameras, rows.Err()
+}
+
+func (r *UptimeRepository) ListStatusChanges(ctx context.Context, from, to time.Time, zoneID *uuid.UUID) ([]*domain.CameraStatusChange, error) {
+	query := `SELECT h.id, h.camera_id, COALESCE(h.from_status, ''), h.to_status, h.source, h.created_at
+	          FROM camera_status_history h
+	          JOIN cameras c ON h.camera_id = c.id
+	          WHERE ($3::uuid IS NULL OR c.zone_id IN (
+	              SELECT id FROM zones WHERE path LIKE (SELECT path FROM zones WHERE id = $3) || '%'))
+	            AND h.created_at < $2
+	            AND (h.created_at >= $1 OR h.id IN (
+	                SELECT DISTINCT ON (camera_id) id FROM camera_status_history
+	                WHERE created_at < $1
+	                ORDER BY camera_id, created_at DESC))
+	          ORDER BY h.camera_id, h.created_at, h.id`
+
+	rows, err := r.db.Pool.Query(ctx, query, from, to, zoneID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := []*domain.CameraStatusChange{}
+	for rows.Next() {
+		c := &domain.CameraStatusChange{}
+		if err := rows.Scan(&c.ID, &c.CameraID, &c.FromStatus, &c.ToStatus, &c.Source, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		changes = append(changes, c)
+	}
+	return changes, rows.Err()
+}
@@ -10,17 +10,22 @@ import (
 )
 
 type UserRepository struct {
-	db *PostgresDB
+	db     *PostgresDB
+	cipher ports.FieldCipher
 }
 
-func NewUserRepository(db *PostgresDB) ports.UserRepository {
-	return &UserRepository{db: db}
+func NewUserRepository(db *PostgresDB, cipher ports.FieldCipher) *UserRepository {
+	return &UserRepository{db: db, cipher: cipher}
 }
 
 func (r *UserRepository) Save(ctx context.Context, user *domain.User) error {
+	phone, err := r.cipher.Encrypt(user.Phone)
+	if err != nil {
+		return err
+	}
 	query := `INSERT INTO users (username, email, password_hash, full_name, phone, role_id, status, updated_at) 
 	          VALUES ($1, $2, $3, $4, $5, $6, $7, NOW()) RETURNING id, created_at, updated_at`
-	return r.db.Pool.QueryRow(ctx, query, user.Username, user.Email, user.PasswordHash, user.FullName, user.Phone, user.RoleID, user.Status).
+	return r.db.Pool.QueryRow(ctx, query, user.Username, user.Email, user.PasswordHash, user.FullName, phone, user.RoleID, user.Status).
 		Scan(&user.ID, &user.CreatedAt, &user.UpdatedAt)
 }
 
@@ -48,6 +53,9 @@ func (r *UserRepository) scanUser(row pgx.Row) (*domain.User, error) {
 		}
 		return nil, err
 	}
+	if user.Phone, err = r.cipher.Decrypt(user.Phone); err != nil {
+		return nil, err
+	}
 	return user, nil
 }
 
@@ -75,14 +83,21 @@ func (r *UserRepository) List(ctx context.Context, search string) ([]*domain.Use
 		if err != nil {
 			return nil, err
 		}
+		if user.Phone, err = r.cipher.Decrypt(user.Phone); err != nil {
+			return nil, err
+		}
 		users = append(users, user)
 	}
 	return users, nil
 }
 
 func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
+	phone, err := r.cipher.Encrypt(user.Phone)
+	if err != nil {
+		return err
+	}
 	query := `UPDATE users SET full_name = $2, phone = $3, role_id = $4, status = $5, updated_at = NOW(), password_hash = $6 WHERE id = $1`
-	_, err := r.db.Pool.Exec(ctx, query, user.ID, user.FullName, user.Phone, user.RoleID, user.Status, user.PasswordHash)
+	_, err = r.db.Pool.Exec(ctx, query, user.ID, user.FullName, phone, user.RoleID, user.Status, user.PasswordHash)
 	return err
 }
 
@@ -90,3 +105,55 @@ func (r *UserRepository) Delete(ctx context.Context, id string) error {
 	_, err := r.db.Pool.Exec(ctx, "DELETE FROM users WHERE id = $1", id)
 	return err
 }
+
+func (r *UserRepository) CountPII(ctx context.Context) (int, error) {
+	var count int
+	err := r.db.Pool.QueryRow(ctx, "SELECT COUNT(*) FROM users").Scan(&count)
+	return count, err
+}
+
+// ReencryptPII rewrites user phone numbers that are plaintext or encrypted
+// with a retired data key.
+func (r *UserRepository) ReencryptPII(ctx context.Context, step func(ok bool)) error {
+	rows, err := r.db.Pool.Query(ctx, "SELECT id, COALESCE(phone, '') FROM users ORDER BY id")
+	if err != nil {
+		return err
+	}
+	phones := make(map[string]string)
+	var ids []string
+	for rows.Next() {
+		var id, phone string
+		if err := rows.Scan(&id, &phone); err != nil {
+			rows.Close()
+			return err
+		}
+		ids = append(ids, id)
+		phones[id] = phone
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if r.cipher.IsCurrent(phones[id]) {
+			step(true)
+			continue
+		}
+		step(r.reencryptPhone(ctx, id, phones[id]) == nil)
+	}
+	return nil
+}
+
+func (r *UserRepository) reencryptPhone(ctx context.Context, id, value string) error {
+	plain, err := r.cipher.Decrypt(value)
+	if err != nil {
+		return err
+	}
+	phone, err := r.cipher.Encrypt(plain)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.Pool.Exec(ctx, "UPDATE users SET phone = $2 WHERE id = $1", id, phone)
+	return err
+}
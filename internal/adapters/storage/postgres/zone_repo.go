@@ -2,10 +2,12 @@ package postgres
 
 import (
 	"context"
+	"strings"
 
 	"app/internal/core/domain"
 	"app/internal/core/ports"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 )
 
@@ -17,15 +19,28 @@ func NewZoneRepository(db *PostgresDB) ports.ZoneRepository {
 	return &ZoneRepository{db: db}
 }
 
+const zoneColumns = `id, parent_id, name, COALESCE(description, ''), COALESCE(kind, ''), path, created_at`
+
+func scanZone(row pgx.Row) (*domain.Zone, error) {
+	zone := &domain.Zone{}
+	err := row.Scan(&zone.ID, &zone.ParentID, &zone.Name, &zone.Description, &zone.Kind, &zone.Path, &zone.CreatedAt)
+	return zone, err
+}
+
+// Save derives the path from the parent's, so the ID is chosen up front.
 func (r *ZoneRepository) Save(ctx context.Context, zone *domain.Zone) error {
-	query := `INSERT INTO zones (name, description) VALUES ($1, $2) RETURNING id, created_at`
-	return r.db.Pool.QueryRow(ctx, query, zone.Name, zone.Description).Scan(&zone.ID, &zone.CreatedAt)
+	zone.ID = uuid.NewString()
+	query := `INSERT INTO zones (id, parent_id, name, description, kind, path)
+	          SELECT $1::uuid, $2::uuid, $3, $4, NULLIF($5, ''),
+	                 COALESCE((SELECT path FROM zones WHERE id = $2::uuid), '/') || $1::text || '/'
+	          RETURNING path, created_at`
+	return r.db.Pool.QueryRow(ctx, query, zone.ID, zone.ParentID, zone.Name, zone.Description, zone.Kind).
+		Scan(&zone.Path, &zone.CreatedAt)
 }
 
 func (r *ZoneRepository) GetByID(ctx context.Context, id string) (*domain.Zone, error) {
-	query := `SELECT id, name, COALESCE(description, ''), created_at FROM zones WHERE id = $1`
-	zone := &domain.Zone{}
-	err := r.db.Pool.QueryRow(ctx, query, id).Scan(&zone.ID, &zone.Name, &zone.Description, &zone.CreatedAt)
+	query := `SELECT ` + zoneColumns + ` FROM zones WHERE id = $1`
+	zone, err := scanZone(r.db.Pool.QueryRow(ctx, query, id))
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, nil
@@ -36,7 +51,7 @@ func (r *ZoneRepository) GetByID(ctx context.Context, id string) (*domain.Zone,
 }
 
 func (r *ZoneRepository) List(ctx context.Context, search string) ([]*domain.Zone, error) {
-	query := `SELECT id, name, COALESCE(description, ''), created_at FROM zones`
+	query := `SELECT ` + zoneColumns + ` FROM zones`
 	var args []interface{}
 
 	if search != "" {
@@ -54,23 +69,97 @@ func (r *ZoneRepository) List(ctx context.Context, search string) ([]*domain.Zon
 
 	zones := []*domain.Zone{} // Initialize as empty slice
 	for rows.Next() {
-		zone := &domain.Zone{}
-		err := rows.Scan(&zone.ID, &zone.Name, &zone.Description, &zone.CreatedAt)
+		zone, err := scanZone(rows)
 		if err != nil {
 			return nil, err
 		}
 		zones = append(zones, zone)
 	}
-	return zones, nil
+	return zones, rows.Err()
 }
 
+// Update leaves the parent alone; Move changes it.
 func (r *ZoneRepository) Update(ctx context.Context, zone *domain.Zone) error {
-	query := `UPDATE zones SET name = $2, description = $3 WHERE id = $1`
-	_, err := r.db.Pool.Exec(ctx, query, zone.ID, zone.Name, zone.Description)
+	query := `UPDATE zones SET name = $2, description = $3, kind = NULLIF($4, '') WHERE id = $1`
+	_, err := r.db.Pool.Exec(ctx, query, zone.ID, zone.Name, zone.Description, zone.Kind)
 	return err
 }
 
+// Move holds an exclusive lock on zones for the transaction, so two moves
+// cannot race into a cycle; reads are not blocked.
+func (r *ZoneRepository) Move(ctx context.Context, id string, parentID *string) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `LOCK TABLE zones IN EXCLUSIVE MODE`); err != nil {
+		return err
+	}
+	var oldPath string
+	if err := tx.QueryRow(ctx, `SELECT path FROM zones WHERE id = $1`, id).Scan(&oldPath); err != nil {
+		if err == pgx.ErrNoRows {
+			return domain.ErrZoneNotFound
+		}
+		return err
+	}
+	newPath := "/" + id + "/"
+	if parentID != nil {
+		var parentPath string
+		if err := tx.QueryRow(ctx, `SELECT path FROM zones WHERE id = $1`, *parentID).Scan(&parentPath); err != nil {
+			if err == pgx.ErrNoRows {
+				return domain.ErrZoneParentNotFound
+			}
+			return err
+		}
+		if strings.HasPrefix(parentPath, oldPath) {
+			return domain.ErrZoneCycle
+		}
+		newPath = parentPath + id + "/"
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE zones SET parent_id = $2 WHERE id = $1`, id, parentID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `UPDATE zones SET path = $2 || substr(path, length($1) + 1) WHERE path LIKE $1 || '%'`,
+		oldPath, newPath); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
 func (r *ZoneRepository) Delete(ctx context.Context, id string) error {
+	var hasChildren bool
+	if err := r.db.Pool.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM zones WHERE parent_id = $1)`, id).Scan(&hasChildren); err != nil {
+		return err
+	}
+	if hasChildren {
+		return domain.ErrZoneHasChildren
+	}
 	_, err := r.db.Pool.Exec(ctx, "DELETE FROM zones WHERE id = $1", id)
 	return err
 }
+
+func (r *ZoneRepository) CameraCounts(ctx context.Context, scope *domain.CameraScope) (map[string]int, error) {
+	var args []any
+	query := `SELECT c.zone_id, COUNT(*) FROM cameras c
+	          WHERE c.zone_id IS NOT NULL AND ` + cameraScopeCondition("c", scope, &args) + `
+	          GROUP BY c.zone_id`
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := map[string]int{}
+	for rows.Next() {
+		var zoneID string
+		var count int
+		if err := rows.Scan(&zoneID, &count); err != nil {
+			return nil, err
+		}
+		counts[zoneID] = count
+	}
+	return counts, rows.Err()
+}
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
@@ -19,23 +20,56 @@ const (
 	EventTypeFire      EventType = "fire"
 	EventTypeOther     EventType = "other"
 
+	// Raised by the camera health monitor, not by a detector
+	EventTypeCameraOffline EventType = "camera_offline"
+
 	EventStatusNew        EventStatus = "new"
 	EventStatusProcessing EventStatus = "processing"
 	EventStatusResolved   EventStatus = "resolved"
 	EventStatusIgnored    EventStatus = "ignored"
 )
 
+// IsDetector reports whether t is a type a camera's detector can be
+// configured to raise.
+func (t EventType) IsDetector() bool {
+	switch t {
+	case EventTypePerson, EventTypeVehicle, EventTypeFace, EventTypeIntrusion,
+		EventTypeLoitering, EventTypeCrowd, EventTypeFire, EventTypeOther:
+		return true
+	}
+	return false
+}
+
+// EventVerdict is an operator's judgement of whether a detection was real.
+type EventVerdict string
+
+const (
+	EventVerdictTruePositive  EventVerdict = "true_positive"
+	EventVerdictFalsePositive EventVerdict = "false_positive"
+)
+
+var (
+	ErrEventNotFound       = errors.New("event not found")
+	ErrInvalidEventVerdict = errors.New("verdict must be true_positive or false_positive")
+	ErrEventUpdateEmpty    = errors.New("status or verdict is required")
+)
+
+// AIConfig is a camera's current detector settings. Every save adds a
+// version to its history; TemplateID links it to the template it was last
+// applied from, with the camera's Overrides on top.
 type AIConfig struct {
-	ID            uuid.UUID   `json:"id"`
-	CameraID      uuid.UUID   `json:"camera_id"`
-	AIEnabled     bool        `json:"ai_enabled"`
-	AITypes       []EventType `json:"ai_types"`
-	ROIZones      any         `json:"roi_zones"`    // JSONB
-	ActiveHours   any         `json:"active_hours"` // JSONB
-	Sensitivity   int         `json:"sensitivity"`
-	MinConfidence int         `json:"min_confidence"`
-	CreatedAt     time.Time   `json:"created_at"`
-	UpdatedAt     time.Time   `json:"updated_at"`
+	ID       uuid.UUID `json:"id"`
+	CameraID uuid.UUID `json:"camera_id"`
+	AISettings
+	Version    int                `json:"version"`
+	TemplateID *uuid.UUID         `json:"template_id"`
+	Overrides  *AIConfigOverrides `json:"overrides,omitempty"`
+	CreatedAt  time.Time          `json:"created_at"`
+	UpdatedAt  time.Time          `json:"updated_at"`
+
+	// Recorded on the version a save adds
+	Source    AIConfigSource `json:"-"`
+	UpdatedBy *uuid.UUID     `json:"-"`
 }
 
 type AIEvent struct {
@@ -46,6 +80,7 @@ type AIEvent struct {
 	SnapshotURL string         `json:"snapshot_url"`
 	Metadata    map[string]any `json:"metadata"`
 	Status      EventStatus    `json:"status"`
+	Verdict     *EventVerdict  `json:"verdict,omitempty"`
 	ResolvedBy  *uuid.UUID     `json:"resolved_by"`
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
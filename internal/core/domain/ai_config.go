@@ -0,0 +1,361 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AISettings is what a camera's detector runs with. Configs, their versions
+// and templates all carry one.
+type AISettings struct {
+	AIEnabled     bool         `json:"ai_enabled"`
+	AITypes       []EventType  `json:"ai_types"`
+	ROIZones      []ROIZone    `json:"roi_zones"`
+	ActiveHours   *ActiveHours `json:"active_hours"`
+	Sensitivity   int          `json:"sensitivity"`
+	MinConfidence int          `json:"min_confidence"`
+}
+
+// ROIMode says whether a region limits detection to its inside or masks it
+// out. Exclude regions win where they overlap include regions.
+type ROIMode string
+
+const (
+	ROIModeInclude ROIMode = "include"
+	ROIModeExclude ROIMode = "exclude"
+)
+
+// ROIZone is a region of the camera frame. Polygon points are normalized
+// [x, y] pairs, 0 to 1 from the top-left corner, so a region survives a
+// resolution change. EventTypes narrows the region to some of the config's
+// types; empty means all of them.
+type ROIZone struct {
+	Name       string      `json:"name" yaml:"name"`
+	Mode       ROIMode     `json:"mode" yaml:"mode"`
+	Polygon    []Point     `json:"polygon" yaml:"polygon"`
+	EventTypes []EventType `json:"event_types,omitempty" yaml:"event_types,omitempty"`
+}
+
+// ActiveHours is a weekly schedule in Timezone's wall-clock time. No
+// schedule means the detector is always active.
+type ActiveHours struct {
+	Timezone string              `json:"timezone" yaml:"timezone"`
+	Windows  []ActiveHoursWindow `json:"windows" yaml:"windows"`
+}
+
+// ActiveHoursWindow runs from Start to End, as "HH:MM", on each of Days
+// ("mon" to "sun"). End may be "24:00"; an End before Start runs past
+// midnight into the next day.
+type ActiveHoursWindow struct {
+	Days  []string `json:"days" yaml:"days"`
+	Start string   `json:"start" yaml:"start"`
+	End   string   `json:"end" yaml:"end"`
+}
+
+var weekdays = map[string]int{"mon": 0, "tue": 1, "wed": 2, "thu": 3, "fri": 4, "sat": 5, "sun": 6}
+
+const minutesPerWeek = 7 * 24 * 60
+
+// FieldError is one problem with one field of a request, named by its
+// JSON path such as "roi_zones[0].polygon".
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// AIConfigError lists everything wrong with an AI config, so a client can
+// mark each bad field at once.
+type AIConfigError struct {
+	Fields []FieldError `json:"fields"`
+}
+
+func (e *AIConfigError) Error() string {
+	parts := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		parts[i] = f.Field + ": " + f.Message
+	}
+	return "invalid AI config: " + strings.Join(parts, "; ")
+}
+
+// Normalize fills defaults and drops the closing point of polygons given
+// as closed rings, so equal configs store alike.
+func (c *AISettings) Normalize() {
+	if c.AITypes == nil {
+		c.AITypes = []EventType{}
+	}
+	if c.ROIZones == nil {
+		c.ROIZones = []ROIZone{}
+	}
+	for i := range c.ROIZones {
+		z := &c.ROIZones[i]
+		if z.Mode == "" {
+			z.Mode = ROIModeInclude
+		}
+		if n := len(z.Polygon); n > 1 && z.Polygon[0] == z.Polygon[n-1] {
+			z.Polygon = z.Polygon[:n-1]
+		}
+	}
+	if c.ActiveHours != nil {
+		for i := range c.ActiveHours.Windows {
+			w := &c.ActiveHours.Windows[i]
+			for j, d := range w.Days {
+				w.Days[j] = strings.ToLower(strings.TrimSpace(d))
+			}
+		}
+	}
+}
+
+// Validate returns an *AIConfigError listing every invalid field, or nil.
+func (c *AISettings) Validate() error {
+	var errs []FieldError
+	add := func(field, format string, args ...any) {
+		errs = append(errs, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	types := map[EventType]bool{}
+	for i, t := range c.AITypes {
+		if !t.IsDetector() {
+			add(fmt.Sprintf("ai_types[%d]", i), "unknown event type %q", t)
+		}
+		types[t] = true
+	}
+	if c.Sensitivity < 0 || c.Sensitivity > 100 {
+		add("sensitivity", "must be between 0 and 100")
+	}
+	if c.MinConfidence < 0 || c.MinConfidence > 100 {
+		add("min_confidence", "must be between 0 and 100")
+	}
+
+	names := map[string]bool{}
+	for i, z := range c.ROIZones {
+		field := fmt.Sprintf("roi_zones[%d]", i)
+		switch {
+		case z.Name == "":
+			add(field+".name", "is required")
+		case names[z.Name]:
+			add(field+".name", "%q is used by another region", z.Name)
+		}
+		names[z.Name] = true
+		if z.Mode != ROIModeInclude && z.Mode != ROIModeExclude {
+			add(field+".mode", "must be include or exclude")
+		}
+		if msg := polygonProblem(z.Polygon); msg != "" {
+			add(field+".polygon", "%s", msg)
+		}
+		for j, t := range z.EventTypes {
+			switch {
+			case !t.IsDetector():
+				add(fmt.Sprintf("%s.event_types[%d]", field, j), "unknown event type %q", t)
+			case !types[t]:
+				add(fmt.Sprintf("%s.event_types[%d]", field, j), "%q is not one of the config's ai_types", t)
+			}
+		}
+	}
+
+	if h := c.ActiveHours; h != nil {
+		if h.Timezone == "" {
+			add("active_hours.timezone", "is required")
+		} else if _, err := time.LoadLocation(h.Timezone); err != nil {
+			add("active_hours.timezone", "unknown timezone %q", h.Timezone)
+		}
+		if len(h.Windows) == 0 {
+			add("active_hours.windows", "at least one window is required; disable the config to turn detection off")
+		}
+		// Each window day becomes a span of minutes from Monday 00:00,
+		// split where it wraps past Sunday midnight
+		type span struct{ window, start, end int }
+		var spans []span
+		for i, w := range h.Windows {
+			field := fmt.Sprintf("active_hours.windows[%d]", i)
+			start, okStart := parseClock(w.Start, false)
+			end, okEnd := parseClock(w.End, true)
+			if !okStart {
+				add(field+".start", "must be a time from 00:00 to 23:59")
+			}
+			if !okEnd {
+				add(field+".end", "must be a time from 00:01 to 24:00")
+			}
+			if okStart && okEnd && start == end {
+				add(field+".end", "must differ from start")
+				okEnd = false
+			}
+			if len(w.Days) == 0 {
+				add(field+".days", "at least one day is required")
+			}
+			seen := map[string]bool{}
+			for j, d := range w.Days {
+				day, ok := weekdays[d]
+				switch {
+				case !ok:
+					add(fmt.Sprintf("%s.days[%d]", field, j), "must be one of mon, tue, wed, thu, fri, sat, sun")
+				case seen[d]:
+					add(fmt.Sprintf("%s.days[%d]", field, j), "%q is listed twice", d)
+				case okStart && okEnd:
+					from := day*24*60 + start
+					length := end - start
+					if length < 0 {
+						length += 24 * 60
+					}
+					if to := from + length; to > minutesPerWeek {
+						spans = append(spans, span{i, from, minutesPerWeek}, span{i, 0, to - minutesPerWeek})
+					} else {
+						spans = append(spans, span{i, from, to})
+					}
+				}
+				seen[d] = true
+			}
+		}
+		reported := map[[2]int]bool{}
+		for a := 0; a < len(spans); a++ {
+			for b := a + 1; b < len(spans); b++ {
+				x, y := spans[a], spans[b]
+				if x.window == y.window || x.start >= y.end || y.start >= x.end {
+					continue
+				}
+				pair := [2]int{min(x.window, y.window), max(x.window, y.window)}
+				if !reported[pair] {
+					reported[pair] = true
+					add(fmt.Sprintf("active_hours.windows[%d]", pair[1]), "overlaps windows[%d]", pair[0])
+				}
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return &AIConfigError{Fields: errs}
+	}
+	return nil
+}
+
+// Diff names the fields, by their JSON keys, where other differs from c.
+// Event types compare as sets and regions and schedules by their encoding.
+func (c *AISettings) Diff(other *AISettings) []string {
+	var fields []string
+	if c.AIEnabled != other.AIEnabled {
+		fields = append(fields, "ai_enabled")
+	}
+	a, b := slices.Clone(c.AITypes), slices.Clone(other.AITypes)
+	slices.Sort(a)
+	slices.Sort(b)
+	if !slices.Equal(a, b) {
+		fields = append(fields, "ai_types")
+	}
+	if !jsonEqual(c.ROIZones, other.ROIZones) {
+		fields = append(fields, "roi_zones")
+	}
+	if !jsonEqual(c.ActiveHours, other.ActiveHours) {
+		fields = append(fields, "active_hours")
+	}
+	if c.Sensitivity != other.Sensitivity {
+		fields = append(fields, "sensitivity")
+	}
+	if c.MinConfidence != other.MinConfidence {
+		fields = append(fields, "min_confidence")
+	}
+	return fields
+}
+
+func jsonEqual(a, b any) bool {
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(ja) == string(jb)
+}
+
+// parseClock reads "HH:MM" as minutes after midnight. "24:00" is only
+// accepted as an end.
+func parseClock(s string, end bool) (int, bool) {
+	if len(s) != 5 || s[2] != ':' {
+		return 0, false
+	}
+	// Atoi alone would take a sign, as in "+1:00"
+	for i, r := range s {
+		if i != 2 && (r < '0' || r > '9') {
+			return 0, false
+		}
+	}
+	h, _ := strconv.Atoi(s[:2])
+	m, _ := strconv.Atoi(s[3:])
+	if m > 59 {
+		return 0, false
+	}
+	minutes := h*60 + m
+	if end {
+		return minutes, minutes > 0 && minutes <= 24*60
+	}
+	return minutes, minutes < 24*60
+}
+
+// polygonProblem describes what is wrong with a normalized region outline,
+// or returns "".
+func polygonProblem(polygon []Point) string {
+	if len(polygon) < 3 {
+		return "must have at least 3 points"
+	}
+	for i, p := range polygon {
+		if math.IsNaN(p[0]) || math.IsNaN(p[1]) || p[0] < 0 || p[0] > 1 || p[1] < 0 || p[1] > 1 {
+			return fmt.Sprintf("point %d is outside the frame; coordinates run from 0 to 1", i)
+		}
+	}
+	n := len(polygon)
+	for i := 0; i < n; i++ {
+		if polygon[i] == polygon[(i+1)%n] {
+			return fmt.Sprintf("point %d repeats the point before it", (i+1)%n)
+		}
+	}
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			// Adjacent edges share a point, and only cross if they fold back
+			// onto each other
+			adjacent := j == i+1 || (i == 0 && j == n-1)
+			if segmentsIntersect(polygon[i], polygon[(i+1)%n], polygon[j], polygon[(j+1)%n], adjacent) {
+				return fmt.Sprintf("intersects itself: edges %d and %d cross", i, j)
+			}
+		}
+	}
+	var area float64
+	for i := 0; i < n; i++ {
+		a, b := polygon[i], polygon[(i+1)%n]
+		area += a[0]*b[1] - b[0]*a[1]
+	}
+	if math.Abs(area)/2 < 1e-9 {
+		return "has no area"
+	}
+	return ""
+}
+
+func segmentsIntersect(p1, p2, q1, q2 Point, adjacent bool) bool {
+	cross := func(o, a, b Point) float64 {
+		return (a[0]-o[0])*(b[1]-o[1]) - (a[1]-o[1])*(b[0]-o[0])
+	}
+	onSegment := func(a, b, p Point) bool {
+		return math.Min(a[0], b[0]) <= p[0] && p[0] <= math.Max(a[0], b[0]) &&
+			math.Min(a[1], b[1]) <= p[1] && p[1] <= math.Max(a[1], b[1])
+	}
+	d1, d2 := cross(q1, q2, p1), cross(q1, q2, p2)
+	d3, d4 := cross(p1, p2, q1), cross(p1, p2, q2)
+
+	if adjacent {
+		// Sharing an endpoint is expected; collinear edges that overlap
+		// beyond it fold the outline back on itself
+		if d1 != 0 || d2 != 0 {
+			return false
+		}
+		shared, other1, other2 := p2, p1, q2
+		if p1 == q2 {
+			shared, other1, other2 = p1, p2, q1
+		}
+		dot := (other1[0]-shared[0])*(other2[0]-shared[0]) + (other1[1]-shared[1])*(other2[1]-shared[1])
+		return dot > 0
+	}
+
+	if ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) && ((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0)) {
+		return true
+	}
+	return (d1 == 0 && onSegment(q1, q2, p1)) || (d2 == 0 && onSegment(q1, q2, p2)) ||
+		(d3 == 0 && onSegment(p1, p2, q1)) || (d4 == 0 && onSegment(p1, p2, q2))
+}
@@ -0,0 +1,171 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// square is a valid region covering the middle of the frame.
+var square = []Point{{0.25, 0.25}, {0.75, 0.25}, {0.75, 0.75}, {0.25, 0.75}}
+
+func TestPolygonProblem(t *testing.T) {
+	tests := []struct {
+		name    string
+		polygon []Point
+		want    string
+	}{
+		{"square", square, ""},
+		{"triangle on the frame edges", []Point{{0, 0}, {1, 0}, {0, 1}}, ""},
+		{"too few points", []Point{{0, 0}, {1, 1}}, "at least 3 points"},
+		{"bow-tie", []Point{{0, 0}, {1, 1}, {1, 0}, {0, 1}}, "intersects itself"},
+		{"collinear fold-back", []Point{{0.2, 0.2}, {0.8, 0.2}, {0.5, 0.2}, {0.5, 0.8}}, "intersects itself"},
+		{"x out of bounds", []Point{{0, 0}, {1.5, 0}, {0, 1}}, "point 1 is outside the frame"},
+		{"y out of bounds", []Point{{0, 0}, {1, 0}, {0, -0.1}}, "point 2 is outside the frame"},
+		{"repeated point", []Point{{0, 0}, {1, 0}, {1, 0}, {0, 1}}, "point 2 repeats"},
+		{"collinear points", []Point{{0, 0}, {0.5, 0.5}, {1, 1}}, "intersects itself"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := polygonProblem(tt.polygon)
+			switch {
+			case tt.want == "" && got != "":
+				t.Fatalf("polygonProblem = %q, want none", got)
+			case tt.want != "" && !strings.Contains(got, tt.want):
+				t.Fatalf("polygonProblem = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseClock(t *testing.T) {
+	tests := []struct {
+		in   string
+		end  bool
+		want int
+		ok   bool
+	}{
+		{"00:00", false, 0, true},
+		{"23:59", false, 23*60 + 59, true},
+		{"24:00", true, 24 * 60, true},
+		{"24:00", false, 0, false},
+		{"00:00", true, 0, false},
+		{"+1:00", false, 0, false},
+		{"-1:00", false, 0, false},
+		{"01:+5", false, 0, false},
+		{"1:00", false, 0, false},
+		{"12:60", false, 0, false},
+		{"25:00", true, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseClock(tt.in, tt.end)
+		if ok != tt.ok || ok && got != tt.want {
+			t.Errorf("parseClock(%q, %v) = %d, %v, want %d, %v", tt.in, tt.end, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestValidateActiveHours(t *testing.T) {
+	tests := []struct {
+		name    string
+		windows []ActiveHoursWindow
+		want    []FieldError
+	}{
+		{
+			name:    "24:00 end",
+			windows: []ActiveHoursWindow{{Days: []string{"mon"}, Start: "08:00", End: "24:00"}},
+		},
+		{
+			name: "back to back windows",
+			windows: []ActiveHoursWindow{
+				{Days: []string{"mon"}, Start: "08:00", End: "12:00"},
+				{Days: []string{"mon"}, Start: "12:00", End: "18:00"},
+			},
+		},
+		{
+			name: "overnight window overlaps the next day's",
+			windows: []ActiveHoursWindow{
+				{Days: []string{"mon"}, Start: "22:00", End: "06:00"},
+				{Days: []string{"tue"}, Start: "05:00", End: "09:00"},
+			},
+			want: []FieldError{{"active_hours.windows[1]", "overlaps windows[0]"}},
+		},
+		{
+			name: "sun wraps into mon",
+			windows: []ActiveHoursWindow{
+				{Days: []string{"sun"}, Start: "23:00", End: "02:00"},
+				{Days: []string{"mon"}, Start: "01:00", End: "03:00"},
+			},
+			want: []FieldError{{"active_hours.windows[1]", "overlaps windows[0]"}},
+		},
+		{
+			name:    "sun wrap clear of mon",
+			windows: []ActiveHoursWindow{{Days: []string{"sun"}, Start: "23:00", End: "02:00"}, {Days: []string{"mon"}, Start: "02:00", End: "04:00"}},
+		},
+		{
+			name:    "start equals end",
+			windows: []ActiveHoursWindow{{Days: []string{"mon"}, Start: "09:00", End: "09:00"}},
+			want:    []FieldError{{"active_hours.windows[0].end", "must differ from start"}},
+		},
+		{
+			name:    "bad day and clock",
+			windows: []ActiveHoursWindow{{Days: []string{"mon", "mon", "funday"}, Start: "+1:00", End: "24:00"}},
+			want: []FieldError{
+				{"active_hours.windows[0].start", "must be a time from 00:00 to 23:59"},
+				{"active_hours.windows[0].days[1]", `"mon" is listed twice`},
+				{"active_hours.windows[0].days[2]", "must be one of mon, tue, wed, thu, fri, sat, sun"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := &AISettings{
+				AITypes:     []EventType{EventTypePerson},
+				ROIZones:    []ROIZone{{Name: "door", Mode: ROIModeInclude, Polygon: square}},
+				ActiveHours: &ActiveHours{Timezone: "UTC", Windows: tt.windows},
+			}
+			err := settings.Validate()
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("Validate = %v, want nil", err)
+				}
+				return
+			}
+			var cfgErr *AIConfigError
+			if !errors.As(err, &cfgErr) {
+				t.Fatalf("Validate = %v, want an *AIConfigError", err)
+			}
+			if len(cfgErr.Fields) != len(tt.want) {
+				t.Fatalf("fields = %+v, want %+v", cfgErr.Fields, tt.want)
+			}
+			for i, f := range tt.want {
+				if cfgErr.Fields[i] != f {
+					t.Fatalf("fields[%d] = %+v, want %+v", i, cfgErr.Fields[i], f)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateRegions(t *testing.T) {
+	settings := &AISettings{
+		AITypes: []EventType{EventTypePerson},
+		ROIZones: []ROIZone{
+			{Name: "door", Mode: ROIModeInclude, Polygon: square},
+			{Name: "door", Mode: "inside", Polygon: []Point{{0, 0}, {1, 1}, {1, 0}, {0, 1}}, EventTypes: []EventType{EventTypeVehicle}},
+		},
+	}
+	var cfgErr *AIConfigError
+	if !errors.As(settings.Validate(), &cfgErr) {
+		t.Fatal("Validate accepted invalid regions")
+	}
+	want := []string{"roi_zones[1].name", "roi_zones[1].mode", "roi_zones[1].polygon", "roi_zones[1].event_types[0]"}
+	if len(cfgErr.Fields) != len(want) {
+		t.Fatalf("fields = %+v, want %v", cfgErr.Fields, want)
+	}
+	for i, field := range want {
+		if cfgErr.Fields[i].Field != field {
+			t.Fatalf("fields[%d] = %+v, want %s", i, cfgErr.Fields[i], field)
+		}
+	}
+}
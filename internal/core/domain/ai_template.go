@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrAIConfigNotFound        = errors.New("camera has no AI config")
+	ErrAIConfigVersionNotFound = errors.New("AI config version not found")
+	ErrAITemplateNotFound      = errors.New("AI config template not found")
+	ErrAITemplateNameTaken     = errors.New("an AI config template with this name already exists")
+	ErrAITemplateTargets       = errors.New("camera_ids or zone_id is required")
+	ErrAITemplateOverride      = errors.New("overrides must be keyed by the ID of a camera the template is applied to")
+)
+
+// AIConfigSource records what saved a config version.
+type AIConfigSource string
+
+const (
+	AIConfigSourceManual   AIConfigSource = "manual"
+	AIConfigSourceTemplate AIConfigSource = "template"
+	AIConfigSourceRollback AIConfigSource = "rollback"
+	AIConfigSourceManifest AIConfigSource = "manifest"
+)
+
+// AIConfigVersion is an immutable snapshot of a camera's config. Versions
+// count up from 1 per camera; a rollback adds a new version rather than
+// removing any.
+type AIConfigVersion struct {
+	ID       uuid.UUID `json:"id"`
+	CameraID uuid.UUID `json:"camera_id"`
+	Version  int       `json:"version"`
+	AISettings
+	TemplateID *uuid.UUID         `json:"template_id"`
+	Overrides  *AIConfigOverrides `json:"overrides,omitempty"`
+	Source     AIConfigSource     `json:"source"`
+	CreatedBy  *uuid.UUID         `json:"created_by"`
+	CreatedAt  time.Time          `json:"created_at"`
+}
+
+// AIConfigTemplate is a named set of settings applied to many cameras.
+// Changing a template does not touch the cameras it was applied to; they
+// show as drifting until it is applied again.
+type AIConfigTemplate struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	AISettings
+	CreatedBy *uuid.UUID `json:"created_by"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+
+	// Cameras linked to the template
+	Cameras int `json:"cameras"`
+}
+
+// AIConfigOverrides replaces the template's value for each field that is
+// set, for one camera.
+type AIConfigOverrides struct {
+	AIEnabled     *bool        `json:"ai_enabled,omitempty"`
+	AITypes       []EventType  `json:"ai_types,omitempty"`
+	ROIZones      []ROIZone    `json:"roi_zones,omitempty"`
+	ActiveHours   *ActiveHours `json:"active_hours,omitempty"`
+	Sensitivity   *int         `json:"sensitivity,omitempty"`
+	MinConfidence *int         `json:"min_confidence,omitempty"`
+}
+
+// Apply returns the template's settings with o on top. A nil o changes
+// nothing.
+func (o *AIConfigOverrides) Apply(template AISettings) AISettings {
+	s := template
+	s.AITypes = append([]EventType(nil), template.AITypes...)
+	s.ROIZones = append([]ROIZone(nil), template.ROIZones...)
+	if o == nil {
+		return s
+	}
+	if o.AIEnabled != nil {
+		s.AIEnabled = *o.AIEnabled
+	}
+	if o.AITypes != nil {
+		s.AITypes = o.AITypes
+	}
+	if o.ROIZones != nil {
+		s.ROIZones = o.ROIZones
+	}
+	if o.ActiveHours != nil {
+		s.ActiveHours = o.ActiveHours
+	}
+	if o.Sensitivity != nil {
+		s.Sensitivity = *o.Sensitivity
+	}
+	if o.MinConfidence != nil {
+		s.MinConfidence = *o.MinConfidence
+	}
+	return s
+}
+
+// IsEmpty reports whether o overrides nothing.
+func (o *AIConfigOverrides) IsEmpty() bool {
+	return o == nil || (o.AIEnabled == nil && o.AITypes == nil && o.ROIZones == nil &&
+		o.ActiveHours == nil && o.Sensitivity == nil && o.MinConfidence == nil)
+}
+
+// AIConfigDrift compares a linked camera's config with what its template
+// and overrides give today. Fields is empty when the camera is in sync.
+type AIConfigDrift struct {
+	CameraID   uuid.UUID `json:"camera_id"`
+	CameraName string    `json:"camera_name"`
+	Version    int       `json:"version"`
+	Drifted    bool      `json:"drifted"`
+	Fields     []string  `json:"fields"`
+}
+
+// AITemplateApplyResult reports each targeted camera. Cameras already on
+// the template's settings are left without a new version.
+type AITemplateApplyResult struct {
+	TemplateID uuid.UUID                `json:"template_id"`
+	Applied    []uuid.UUID              `json:"applied"`
+	Unchanged  []uuid.UUID              `json:"unchanged"`
+	Failed     []AITemplateApplyFailure `json:"failed"`
+}
+
+type AITemplateApplyFailure struct {
+	CameraID uuid.UUID    `json:"camera_id"`
+	Error    string       `json:"error"`
+	Fields   []FieldError `json:"fields,omitempty"`
+}
@@ -15,16 +15,22 @@ const (
 	AttendanceEarlyLeave AttendanceStatus = "early_leave"
 )
 
+// RecognitionLog is one face seen by a camera. IdentityID is nil for
+// strangers, who are grouped by StrangerClusterID once clustered.
 type RecognitionLog struct {
-	ID          uuid.UUID `json:"id"`
-	CameraID    uuid.UUID `json:"camera_id"`
-	IdentityID  uuid.UUID `json:"identity_id"`
-	SnapshotURL string    `json:"snapshot_url"`
-	FaceCropURL string    `json:"face_crop_url"`
-	Confidence  float64   `json:"confidence"`
-	Label       string    `json:"label"`
-	OccurredAt  time.Time `json:"occurred_at"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID                uuid.UUID      `json:"id"`
+	CameraID          uuid.UUID      `json:"camera_id"`
+	IdentityID        *uuid.UUID     `json:"identity_id"`
+	StrangerClusterID *uuid.UUID     `json:"stranger_cluster_id,omitempty"`
+	SnapshotURL       string         `json:"snapshot_url"`
+	FaceCropURL       string         `json:"face_crop_url"`
+	Confidence        float64        `json:"confidence"`
+	Label             string         `json:"label"`
+	ReviewStatus      string         `json:"review_status,omitempty"`
+	Metadata          map[string]any `json:"metadata,omitempty"` // Detector output such as bbox
+	Embedding         []float32      `json:"-"`
+	OccurredAt        time.Time      `json:"occurred_at"`
+	CreatedAt         time.Time      `json:"created_at"`
 
 	// Join fields
 	IdentityName string `json:"identity_name,omitempty"`
@@ -21,3 +21,55 @@ type AuditLog struct {
 	// Join fields
 	Username string `json:"username,omitempty"`
 }
+
+// Access log resource types
+const (
+	AccessResourceIdentity         = "identity"
+	AccessResourceIdentityHistory  = "identity_history"
+	AccessResourceFaceImage        = "face_image"
+	AccessResourceRecognitionLog   = "recognition_log"
+	AccessResourceAttendanceRecord = "attendance_record"
+	AccessResourceIdentityExport   = "identity_export"
+	AccessResourceImportReport     = "import_report"
+	AccessResourceFeedbackDataset  = "recognition_feedback_dataset"
+	AccessResourceIdentityTimeline = "identity_timeline"
+	AccessResourceContactTrace     = "contact_trace"
+)
+
+// AccessLog records a read of sensitive data. IdentityIDs lists every person
+// the response exposed, so "who looked at X" is a single lookup.
+type AccessLog struct {
+	ID           int64          `json:"id"`
+	UserID       *uuid.UUID     `json:"user_id"`
+	ResourceType string         `json:"resource_type"`
+	ResourceID   string         `json:"resource_id,omitempty"`
+	IdentityIDs  []uuid.UUID    `json:"identity_ids"`
+	Route        string         `json:"route"`
+	Filters      map[string]any `json:"filters,omitempty"`
+	PIIUnmasked  bool           `json:"pii_unmasked"`
+	IPAddress    string         `json:"ip_address"`
+	UserAgent    string         `json:"user_agent"`
+	CreatedAt    time.Time      `json:"created_at"`
+
+	// Join fields
+	Username string `json:"username,omitempty"`
+}
+
+// IdentityAccessor summarises one user's reads of an identity in a report.
+type IdentityAccessor struct {
+	UserID      *uuid.UUID `json:"user_id"`
+	Username    string     `json:"username"`
+	Accesses    int64      `json:"accesses"`
+	PIIUnmasked int64      `json:"pii_unmasked"`
+	FirstAt     time.Time  `json:"first_at"`
+	LastAt      time.Time  `json:"last_at"`
+}
+
+// IdentityAccessReport answers "who accessed person X" over a date range.
+type IdentityAccessReport struct {
+	IdentityID uuid.UUID           `json:"identity_id"`
+	From       *time.Time          `json:"from"`
+	To         *time.Time          `json:"to"`
+	Accessors  []*IdentityAccessor `json:"accessors"`
+	Entries    []*AccessLog        `json:"entries"`
+}
@@ -1,9 +1,17 @@
 package domain
 
 import (
+	"errors"
+	"net/url"
+	"strings"
 	"time"
 )
 
+var (
+	ErrCameraCredentialsUnreadable = errors.New("camera credentials cannot be decrypted")
+	ErrInvalidStreamURL            = errors.New("rtsp_url is not a valid URL")
+)
+
 type CameraStatus string
 
 const (
@@ -13,22 +21,98 @@ const (
 )
 
 type Camera struct {
-	ID        string       `json:"id"`
-	ZoneID    *string      `json:"zone_id"`
-	Name      string       `json:"name"`
-	IPAddress string       `json:"ip_address"`
-	RTSPURL   string       `json:"rtsp_url"`
-	Status    CameraStatus `json:"status"`
-	AIEnabled bool         `json:"ai_enabled"`
-	CreatedAt time.Time    `json:"created_at"`
-	UpdatedAt time.Time    `json:"updated_at"`
+	ID             string       `json:"id"`
+	ZoneID         *string      `json:"zone_id"`
+	Name           string       `json:"name"`
+	IPAddress      string       `json:"ip_address"`
+	RTSPURL        string       `json:"rtsp_url"` // Without credentials
+	HasCredentials bool         `json:"has_credentials"`
+	Status         CameraStatus `json:"status"`
+	AIEnabled      bool         `json:"ai_enabled"`
+	CreatedAt      time.Time    `json:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at"`
+
+	// Set when the stored credentials could not be decrypted; they read as
+	// empty and are kept as stored until new ones are set
+	CredentialsUnreadable bool `json:"credentials_unreadable,omitempty"`
+
+	// Stored encrypted and never serialized; see StreamURL
+	Username string `json:"-"`
+	Password string `json:"-"`
+}
+
+// StreamURL is the RTSP URL with credentials, for internal consumers that
+// connect to the camera. It must not be sent to API clients.
+func (c *Camera) StreamURL() string {
+	return ComposeStreamURL(c.RTSPURL, c.Username, c.Password)
+}
+
+// CameraCredentials is the result of an explicit, audited reveal.
+type CameraCredentials struct {
+	CameraID  string `json:"camera_id"`
+	Username  string `json:"username"`
+	Password  string `json:"password"`
+	StreamURL string `json:"stream_url"`
+}
+
+// SplitStreamURL removes the user info from an RTSP URL and returns it
+// separately. A URL that does not parse is rejected, since credentials in it
+// could not be separated; the parse error is dropped because it quotes them.
+func SplitStreamURL(raw string) (string, string, string, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", "", "", ErrInvalidStreamURL
+	}
+	if u.User == nil {
+		return raw, "", "", nil
+	}
+	username := u.User.Username()
+	password, _ := u.User.Password()
+	u.User = nil
+	return u.String(), username, password, nil
+}
+
+// StripStreamUserInfo cuts everything between the scheme and the last @
+// out of a stored URL that does not parse, returning it split at the first
+// colon. It is only for redacting legacy values; new URLs go through
+// SplitStreamURL.
+func StripStreamUserInfo(raw string) (string, string, string) {
+	scheme, rest, ok := strings.Cut(raw, "://")
+	at := strings.LastIndex(rest, "@")
+	if !ok || at < 0 {
+		return raw, "", ""
+	}
+	username, password, _ := strings.Cut(rest[:at], ":")
+	return scheme + "://" + rest[at+1:], username, password
+}
+
+// ComposeStreamURL puts credentials back into an RTSP URL. Without a
+// username the URL is returned as is.
+func ComposeStreamURL(raw, username, password string) string {
+	if username == "" {
+		return raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	if password == "" {
+		u.User = url.User(username)
+	} else {
+		u.User = url.UserPassword(username, password)
+	}
+	return u.String()
 }
 
+// Credentials in rtsp_url are moved to Username and Password; the explicit
+// fields win when both are given.
 type CreateCameraRequest struct {
 	ZoneID    *string `json:"zone_id"`
 	Name      string  `json:"name" binding:"required"`
 	IPAddress string  `json:"ip_address"`
 	RTSPURL   string  `json:"rtsp_url" binding:"required"`
+	Username  string  `json:"username"`
+	Password  string  `json:"password"`
 	AIEnabled bool    `json:"ai_enabled"`
 }
 
@@ -37,6 +121,8 @@ type UpdateCameraRequest struct {
 	Name      string       `json:"name"`
 	IPAddress string       `json:"ip_address"`
 	RTSPURL   string       `json:"rtsp_url"`
+	Username  *string      `json:"username"` // Empty string clears
+	Password  *string      `json:"password"` // Empty string clears
 	Status    CameraStatus `json:"status"`
 	AIEnabled *bool        `json:"ai_enabled"` // Pointer to distinguish false vs nil
 }
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CameraStatusSource tells who changed a camera's status.
+type CameraStatusSource string
+
+const (
+	CameraStatusSourceMonitor  CameraStatusSource = "monitor"
+	CameraStatusSourceManual   CameraStatusSource = "manual"
+	CameraStatusSourceSchedule CameraStatusSource = "schedule"
+)
+
+var (
+	ErrCameraNotFound = errors.New("camera not found")
+	// ErrCameraStatusChanged means the camera left the status a change was
+	// computed from before it could be written
+	ErrCameraStatusChanged = errors.New("camera status changed concurrently")
+)
+
+// CameraProbe is the result of one health check. The camera is healthy when
+// the RTSP DESCRIBE handshake succeeds, not merely when the port is open.
+type CameraProbe struct {
+	Reachable  bool          // TCP connect succeeded
+	Healthy    bool          // DESCRIBE answered 200
+	StatusCode int           // Last RTSP status code, 0 if none
+	Latency    time.Duration // Time to complete the probe
+	Error      string
+	ProbedAt   time.Time
+}
+
+// CameraHealth is a camera with its monitor state. Failures and Successes
+// count consecutive probe outcomes for hysteresis.
+type CameraHealth struct {
+	CameraID       uuid.UUID
+	Name           string
+	StreamURL      string // RTSP URL with credentials
+	Status         CameraStatus
+	Failures       int
+	Successes      int
+	LastSeenAt     *time.Time
+	LastProbeError string
+}
+
+type CameraStatusChange struct {
+	ID                  uuid.UUID          `json:"id"`
+	CameraID            uuid.UUID          `json:"camera_id"`
+	FromStatus          CameraStatus       `json:"from_status"`
+	ToStatus            CameraStatus       `json:"to_status"`
+	Source              CameraStatusSource `json:"source"`
+	Reason              string             `json:"reason,omitempty"`
+	LatencyMs           *int               `json:"latency_ms,omitempty"`
+	MaintenanceWindowID *uuid.UUID         `json:"maintenance_window_id,omitempty"` // Window behind a scheduled change
+	CreatedAt           time.Time          `json:"created_at"`
+}
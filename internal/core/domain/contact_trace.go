@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ContactSighting is another identity's recognition in a zone the traced
+// person was in at about the same time.
+type ContactSighting struct {
+	Sighting
+	IdentityID   uuid.UUID
+	IdentityCode string
+	IdentityName string
+}
+
+// ContactEncounter is one stretch of possible co-presence in a zone. Both
+// people are assumed present for the trace window around each sighting.
+type ContactEncounter struct {
+	ZoneID         *uuid.UUID `json:"zone_id"`
+	ZoneName       string     `json:"zone_name,omitempty"`
+	CameraName     string     `json:"camera_name"`
+	StartAt        time.Time  `json:"start_at"`
+	EndAt          time.Time  `json:"end_at"`
+	OverlapSeconds float64    `json:"overlap_seconds"`
+}
+
+type Contact struct {
+	IdentityID     uuid.UUID          `json:"identity_id"`
+	Code           string             `json:"code"`
+	FullName       string             `json:"full_name"`
+	Encounters     int                `json:"encounters"`
+	OverlapSeconds float64            `json:"overlap_seconds"`
+	FirstContactAt time.Time          `json:"first_contact_at"`
+	LastContactAt  time.Time          `json:"last_contact_at"`
+	Zones          []string           `json:"zones"`
+	Details        []ContactEncounter `json:"details"`
+}
+
+// ContactTrace lists everyone seen near an identity, most exposed first.
+type ContactTrace struct {
+	IdentityID    uuid.UUID `json:"identity_id"`
+	From          time.Time `json:"from"`
+	To            time.Time `json:"to"`
+	WindowSeconds float64   `json:"window_seconds"`
+	Sightings     int       `json:"sightings"`
+	Truncated     bool      `json:"truncated"` // Sightings were capped; narrow the range
+	Contacts      []Contact `json:"contacts"`
+}
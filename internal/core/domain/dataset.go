@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const JobTypeDatasetExport = "dataset_export"
+
+type DatasetFormat string
+
+const (
+	DatasetFormatCOCO DatasetFormat = "coco"
+	DatasetFormatYOLO DatasetFormat = "yolo"
+	DatasetFormatBoth DatasetFormat = "both"
+)
+
+// Sources a dataset sample can come from
+const (
+	DatasetSourceEvent       = "ai_event"
+	DatasetSourceRecognition = "recognition_log"
+)
+
+var (
+	ErrInvalidDatasetFormat = errors.New("format must be coco, yolo or both")
+	ErrInvalidValRatio      = errors.New("val_ratio must be between 0 and 1")
+)
+
+// DatasetSample is a reviewed detection. Positive samples carry their boxes;
+// false positives are exported as images without annotations.
+type DatasetSample struct {
+	Source      string
+	ID          uuid.UUID
+	CameraID    uuid.UUID
+	Category    string // Event type, or "face" for recognitions
+	Positive    bool
+	SnapshotURL string
+	Metadata    map[string]any
+	OccurredAt  time.Time
+}
@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const JobTypeCameraDiscovery = "camera_discovery"
+
+var (
+	ErrDiscoveredDeviceNotFound = errors.New("discovered device not found")
+	ErrDeviceAlreadyAdopted     = errors.New("device already adopted")
+	ErrDeviceProfileNotFound    = errors.New("device has no such media profile")
+	ErrDeviceNoStreamURI        = errors.New("device reported no stream uri for the profile")
+	ErrNoDevicesToAdopt         = errors.New("at least one device is required")
+)
+
+// ONVIFEndpoint is a device that answered a WS-Discovery probe.
+type ONVIFEndpoint struct {
+	EndpointRef string   `json:"endpoint_ref"`
+	XAddrs      []string `json:"xaddrs"`
+	Scopes      []string `json:"scopes"`
+}
+
+// DeviceProfile is an ONVIF media profile with the stream URI the device
+// reports for it.
+type DeviceProfile struct {
+	Token    string `json:"token"`
+	Name     string `json:"name"`
+	Encoding string `json:"encoding,omitempty"`
+	Width    int    `json:"width,omitempty"`
+	Height   int    `json:"height,omitempty"`
+	RTSPURL  string `json:"rtsp_url,omitempty"`
+}
+
+// DiscoveredDevice is one device found by a discovery job. Error is set when
+// the device answered the probe but could not be queried, usually because
+// it needs other credentials.
+type DiscoveredDevice struct {
+	ID              uuid.UUID       `json:"id"`
+	JobID           uuid.UUID       `json:"job_id"`
+	EndpointRef     string          `json:"endpoint_ref"`
+	XAddr           string          `json:"xaddr"`
+	IPAddress       string          `json:"ip_address"`
+	Name            string          `json:"name"`
+	Manufacturer    string          `json:"manufacturer"`
+	Model           string          `json:"model"`
+	FirmwareVersion string          `json:"firmware_version"`
+	SerialNumber    string          `json:"serial_number"`
+	HardwareID      string          `json:"hardware_id"`
+	Scopes          []string        `json:"scopes"`
+	Profiles        []DeviceProfile `json:"profiles"`
+	Error           string          `json:"error,omitempty"`
+	CameraID        *uuid.UUID      `json:"camera_id"`
+	DiscoveredAt    time.Time       `json:"discovered_at"`
+	AdoptedAt       *time.Time      `json:"adopted_at"`
+}
+
+// Profile returns the profile with the given token, or the first one when
+// token is empty.
+func (d *DiscoveredDevice) Profile(token string) (*DeviceProfile, bool) {
+	for i := range d.Profiles {
+		if token == "" || d.Profiles[i].Token == token {
+			return &d.Profiles[i], true
+		}
+	}
+	return nil, false
+}
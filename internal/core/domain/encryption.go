@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DataKeyPurposeData       = "data"
+	DataKeyPurposeBlindIndex = "blind_index"
+
+	DataKeyStatusActive  = "active"
+	DataKeyStatusRetired = "retired"
+
+	JobTypeKeyRotation = "key_rotation"
+)
+
+var (
+	ErrEncryptionDisabled = errors.New("field encryption is disabled")
+	ErrUnknownDataKey     = errors.New("ciphertext uses an unknown data key")
+	ErrMasterKeyNotFound  = errors.New("master key not found")
+)
+
+// DataKey is a per-tenant key wrapped by the KMS master key. The plaintext
+// key only ever lives in memory.
+type DataKey struct {
+	ID          uuid.UUID  `json:"id"`
+	Tenant      string     `json:"tenant"`
+	Purpose     string     `json:"purpose"`
+	Version     int        `json:"version"`
+	MasterKeyID string     `json:"master_key_id"`
+	WrappedKey  []byte     `json:"-"`
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"created_at"`
+	RetiredAt   *time.Time `json:"retired_at"`
+}
@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrFloorPlanNotFound = errors.New("zone has no floor plan")
+	ErrFloorPlanImage    = errors.New("floor plan must be a PNG, JPEG or GIF image")
+	ErrPlacementNotFound = errors.New("camera has no placement")
+	ErrPlacementPlan     = errors.New("a camera can only be placed on the plan of its zone or a zone above it")
+	ErrInvalidPlacement  = errors.New("heading must be in [0, 360), fov in (0, 360] and range positive")
+	ErrOutsideFloorPlan  = errors.New("position is outside the floor plan")
+	ErrZoneAreaNotFound  = errors.New("zone has no area")
+	ErrZoneAreaPlan      = errors.New("a zone's area can only be drawn on the plan of a zone above it")
+	ErrInvalidZoneArea   = errors.New("area must be a polygon of at least 3 points")
+)
+
+// FloorPlan is a zone's plan image. Plan coordinates are image pixels, x to
+// the right and y down from the top-left corner, so Width and Height bound
+// every position drawn on it.
+type FloorPlan struct {
+	ZoneID    uuid.UUID `json:"zone_id"`
+	ImageURL  string    `json:"image_url"`
+	Width     int       `json:"width"`
+	Height    int       `json:"height"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (p *FloorPlan) Contains(pt Point) bool {
+	return pt[0] >= 0 && pt[1] >= 0 && pt[0] <= float64(p.Width) && pt[1] <= float64(p.Height)
+}
+
+// Point is an [x, y] pair in plan coordinates.
+type Point [2]float64
+
+// CameraPlacement puts a camera on a plan. Heading is in degrees clockwise
+// from plan up, FOV is the cone's opening angle and Range its length in
+// plan units.
+type CameraPlacement struct {
+	CameraID   uuid.UUID `json:"camera_id"`
+	PlanZoneID uuid.UUID `json:"plan_zone_id"`
+	X          float64   `json:"x"`
+	Y          float64   `json:"y"`
+	Heading    float64   `json:"heading"`
+	FOV        float64   `json:"fov"`
+	Range      float64   `json:"range"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// fovSegments is how many straight edges approximate the cone's arc.
+const fovSegments = 16
+
+// FOVCone is the field of view as a closed polygon ring: the camera, the
+// arc at Range, and back. A 360 degree view is a circle around the camera.
+func (p *CameraPlacement) FOVCone() []Point {
+	var ring []Point
+	full := p.FOV >= 360
+	if !full {
+		ring = append(ring, Point{p.X, p.Y})
+	}
+	start := p.Heading - p.FOV/2
+	for i := 0; i <= fovSegments; i++ {
+		angle := (start + p.FOV*float64(i)/fovSegments) * math.Pi / 180
+		ring = append(ring, Point{
+			round2(p.X + p.Range*math.Sin(angle)),
+			round2(p.Y - p.Range*math.Cos(angle)),
+		})
+	}
+	return append(ring, ring[0])
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// ZoneArea outlines a zone on the plan of a zone above it, usually its
+// parent floor. The polygon is stored open; the map closes it.
+type ZoneArea struct {
+	ZoneID     uuid.UUID `json:"zone_id"`
+	PlanZoneID uuid.UUID `json:"plan_zone_id"`
+	Polygon    []Point   `json:"polygon"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// PlacedCamera is a placement with what the map shows for its camera.
+type PlacedCamera struct {
+	CameraPlacement
+	Name       string       `json:"name"`
+	Status     CameraStatus `json:"status"`
+	EventCount int          `json:"event_count"`
+}
+
+// ZoneAreaStats is an area with the cameras and recent events of its
+// zone's whole subtree.
+type ZoneAreaStats struct {
+	ZoneArea
+	Name       string   `json:"name"`
+	Kind       ZoneKind `json:"kind,omitempty"`
+	Cameras    int      `json:"cameras"`
+	EventCount int      `json:"event_count"`
+}
+
+// FloorPlanMap is a GeoJSON FeatureCollection in plan coordinates rather
+// than longitude and latitude, with the plan itself as a foreign member.
+// Features carry a "layer" property: zone, camera or camera_fov.
+type FloorPlanMap struct {
+	Type          string       `json:"type"`
+	FloorPlan     *FloorPlan   `json:"floor_plan"`
+	WindowMinutes int          `json:"window_minutes"`
+	Features      []MapFeature `json:"features"`
+}
+
+type MapFeature struct {
+	Type       string         `json:"type"`
+	ID         string         `json:"id"`
+	Geometry   MapGeometry    `json:"geometry"`
+	Properties map[string]any `json:"properties"`
+}
+
+type MapGeometry struct {
+	Type        string `json:"type"`
+	Coordinates any    `json:"coordinates"`
+}
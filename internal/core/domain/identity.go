@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
@@ -21,6 +22,20 @@ const (
 	PersonGroupOther     PersonGroup = "other"
 )
 
+var (
+	ErrFaceImageUnreadable = errors.New("face image could not be read")
+	ErrFaceQualityTooLow   = errors.New("face image quality below threshold")
+	ErrInvalidEmbedding    = errors.New("invalid face embedding")
+	ErrNoEmbeddingProvider = errors.New("no embedding provider configured")
+
+	ErrIdentityNotFound     = errors.New("identity not found")
+	ErrIdentityNotPending   = errors.New("identity is not pending review")
+	ErrSelfApproval         = errors.New("creator cannot approve their own identity")
+	ErrReviewReasonRequired = errors.New("review reason is required")
+	ErrInvalidReviewStatus  = errors.New("review decision must be active or rejected")
+	ErrErasureReasonMissing = errors.New("erasure reason is required")
+)
+
 type Identity struct {
 	ID                 uuid.UUID      `json:"id"`
 	Code               string         `json:"code"`
@@ -35,10 +50,13 @@ type Identity struct {
 	Note               string         `json:"note"`
 	CreatedBy          *uuid.UUID     `json:"created_by"`
 	ApprovedBy         *uuid.UUID     `json:"approved_by"`
+	ApprovedAt         *time.Time     `json:"approved_at"`
+	ReviewReason       string         `json:"review_reason,omitempty"`
 	UserAccountID      *uuid.UUID     `json:"user_account_id"`
 	CreatedAt          time.Time      `json:"created_at"`
 	UpdatedAt          time.Time      `json:"updated_at"`
 	DeletedAt          *time.Time     `json:"deleted_at,omitempty"`
+	MergedInto         *uuid.UUID     `json:"merged_into,omitempty"`
 	Faces              []IdentityFace `json:"faces,omitempty"`
 }
 
@@ -50,4 +68,80 @@ type IdentityFace struct {
 	QualityScore float64   `json:"quality_score"`
 	BlurScore    float64   `json:"blur_score"`
 	CreatedAt    time.Time `json:"created_at"`
+
+	Quality        *FaceQuality `json:"quality,omitempty"`
+	QualityFlagged bool         `json:"quality_flagged"`
+	QualityReason  string       `json:"quality_reason,omitempty"`
+
+	Embedding    []float32 `json:"-"`
+	HasEmbedding bool      `json:"has_embedding"`
+}
+
+// FaceMatch is a single similarity search hit, aggregated per identity.
+type FaceMatch struct {
+	IdentityID uuid.UUID `json:"identity_id"`
+	FaceID     uuid.UUID `json:"face_id"`
+	Score      float64   `json:"score"` // Cosine similarity, -1..1
+	Identity   *Identity `json:"identity,omitempty"`
+}
+
+// FaceQuality is the result of analysing an enrolled face image.
+type FaceQuality struct {
+	Sharpness   float64  `json:"sharpness"`  // Laplacian variance, higher is sharper
+	Brightness  float64  `json:"brightness"` // Mean luminance, 0-255
+	Contrast    float64  `json:"contrast"`   // Luminance standard deviation
+	Width       int      `json:"width"`
+	Height      int      `json:"height"`
+	AspectRatio float64  `json:"aspect_ratio"`
+	Issues      []string `json:"issues,omitempty"`
+}
+
+// ImportRowError describes why a row of a bulk identity import was rejected.
+// Row is the 1-based line number in the uploaded sheet, header included.
+type ImportRowError struct {
+	Row    int      `json:"row"`
+	Code   string   `json:"code"`
+	Errors []string `json:"errors"`
+}
+
+// IdentityErasure summarises a hard erase. It only carries counts so it can
+// be kept in the audit trail once the personal data is gone.
+type IdentityErasure struct {
+	IdentityID                uuid.UUID `json:"identity_id"`
+	FacesDeleted              int       `json:"faces_deleted"`
+	MediaDeleted              int       `json:"media_deleted"`
+	RecognitionLogsAnonymized int64     `json:"recognition_logs_anonymized"`
+	AttendanceRecordsKept     int64     `json:"attendance_records_kept"`
+	AuditLogsRedacted         int64     `json:"audit_logs_redacted"`
+	VersionsDeleted           int64     `json:"versions_deleted"`
+	FeedbackDeleted           int64     `json:"feedback_deleted"`
+	StrangerClustersDeleted   int       `json:"stranger_clusters_deleted"`
+	ErasedAt                  time.Time `json:"erased_at"`
+
+	// Files to remove from storage after the transaction commits
+	MediaURLs []string `json:"-"`
+}
+
+type EdgeSyncAction string
+
+const (
+	EdgeSyncUpsert EdgeSyncAction = "upsert"
+	EdgeSyncRemove EdgeSyncAction = "remove"
+)
+
+// EdgeIdentitySync is the message published to edge devices. Only active
+// identities are ever sent with EdgeSyncUpsert.
+type EdgeIdentitySync struct {
+	Action     EdgeSyncAction `json:"action"`
+	IdentityID uuid.UUID      `json:"identity_id"`
+	Code       string         `json:"code,omitempty"`
+	FullName   string         `json:"full_name,omitempty"`
+	Type       string         `json:"type,omitempty"`
+	Faces      []EdgeFace     `json:"faces,omitempty"`
+}
+
+type EdgeFace struct {
+	FaceID    uuid.UUID `json:"face_id"`
+	ImageURL  string    `json:"image_url"`
+	Embedding []float32 `json:"embedding,omitempty"`
 }
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const JobTypeDuplicateScan = "identity_duplicate_scan"
+
+// Signals that make two identities look like the same person
+const (
+	DuplicateReasonCardNumber = "identity_card_number"
+	DuplicateReasonPhone      = "phone_number"
+	DuplicateReasonFace       = "face"
+	DuplicateReasonName       = "name"
+)
+
+type DuplicateStatus string
+
+const (
+	DuplicateStatusOpen      DuplicateStatus = "open"
+	DuplicateStatusDismissed DuplicateStatus = "dismissed"
+	DuplicateStatusMerged    DuplicateStatus = "merged"
+)
+
+var (
+	ErrDuplicateCandidateNotFound = errors.New("duplicate candidate not found")
+	ErrDuplicateNotOpen           = errors.New("duplicate candidate is already resolved")
+	ErrMergeSameIdentity          = errors.New("cannot merge an identity into itself")
+	ErrMergeReasonRequired        = errors.New("merge reason is required")
+)
+
+// DuplicateCandidate is a pair of identities that may be the same person.
+// IdentityA sorts before IdentityB so each pair is stored once.
+type DuplicateCandidate struct {
+	ID         uuid.UUID       `json:"id"`
+	IdentityA  uuid.UUID       `json:"identity_a"`
+	IdentityB  uuid.UUID       `json:"identity_b"`
+	Score      float64         `json:"score"` // 0..1, combined from Reasons
+	Reasons    []string        `json:"reasons"`
+	FaceScore  *float64        `json:"face_score,omitempty"`
+	Status     DuplicateStatus `json:"status"`
+	ResolvedBy *uuid.UUID      `json:"resolved_by,omitempty"`
+	ResolvedAt *time.Time      `json:"resolved_at,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+
+	// Join fields
+	CodeA string `json:"code_a,omitempty"`
+	NameA string `json:"name_a,omitempty"`
+	CodeB string `json:"code_b,omitempty"`
+	NameB string `json:"name_b,omitempty"`
+}
+
+// IdentityMerge summarises folding one identity into another.
+type IdentityMerge struct {
+	SurvivorID               uuid.UUID `json:"survivor_id"`
+	MergedID                 uuid.UUID `json:"merged_id"`
+	FacesMoved               int       `json:"faces_moved"`
+	RecognitionLogsMoved     int64     `json:"recognition_logs_moved"`
+	AttendanceRecordsMoved   int64     `json:"attendance_records_moved"`
+	AttendanceDaysReconciled int64     `json:"attendance_days_reconciled"`
+	MergedAt                 time.Time `json:"merged_at"`
+
+	FaceIDs []uuid.UUID `json:"-"`
+}
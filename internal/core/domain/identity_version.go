@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	VersionEntityIdentity = "identity"
+	VersionEntityFace     = "face"
+)
+
+// Identity version operations
+const (
+	VersionOpBaseline   = "baseline" // State before history was first recorded
+	VersionOpCreate     = "create"
+	VersionOpImport     = "import"
+	VersionOpUpdate     = "update"
+	VersionOpReview     = "review"
+	VersionOpDelete     = "delete"
+	VersionOpRevert     = "revert"
+	VersionOpMerge      = "merge"
+	VersionOpFaceEnroll = "face_enroll"
+	VersionOpFaceDelete = "face_delete"
+)
+
+var (
+	ErrIdentityVersionNotFound = errors.New("identity version not found")
+	ErrVersionNotRevertible    = errors.New("only identity versions can be reverted to")
+	ErrRevertReasonRequired    = errors.New("revert reason is required")
+)
+
+// IdentityVersion is one entry in an identity's change history. Identity or
+// Face holds the full state after the change; Changes lists what differed
+// from the state before it.
+type IdentityVersion struct {
+	ID         int64         `json:"id"`
+	IdentityID uuid.UUID     `json:"identity_id"`
+	Version    int           `json:"version"`
+	EntityType string        `json:"entity_type"`
+	FaceID     *uuid.UUID    `json:"face_id,omitempty"`
+	Operation  string        `json:"operation"`
+	Identity   *Identity     `json:"identity,omitempty"`
+	Face       *IdentityFace `json:"face,omitempty"`
+	Changes    []FieldChange `json:"changes,omitempty"`
+	RevertOf   *int          `json:"revert_of,omitempty"`
+	Reason     string        `json:"reason,omitempty"`
+	ChangedBy  *uuid.UUID    `json:"changed_by"`
+	ChangedAt  time.Time     `json:"changed_at"`
+
+	// Join fields
+	ChangedByName string `json:"changed_by_name,omitempty"`
+}
+
+// FieldChange is a single changed field. Metadata keys are reported
+// individually as "metadata.<key>".
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   any    `json:"old"`
+	New   any    `json:"new"`
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type JobStatus string
+
+const (
+	JobStatusQueued    JobStatus = "queued"
+	JobStatusRunning   JobStatus = "running"
+	JobStatusCompleted JobStatus = "completed"
+	JobStatusFailed    JobStatus = "failed"
+)
+
+const (
+	JobTypeIdentityImport = "identity_import"
+)
+
+var (
+	ErrJobNotFound     = errors.New("job not found")
+	ErrInvalidJobInput = errors.New("invalid job input")
+)
+
+// Job tracks a long running background task such as an import or export.
+type Job struct {
+	ID         uuid.UUID      `json:"id"`
+	Type       string         `json:"type"`
+	Status     JobStatus      `json:"status"`
+	Total      int            `json:"total"`
+	Processed  int            `json:"processed"`
+	Failed     int            `json:"failed"`
+	Params     map[string]any `json:"params,omitempty"`
+	Result     map[string]any `json:"result,omitempty"`
+	ResultURL  string         `json:"result_url,omitempty"`
+	Error      string         `json:"error,omitempty"`
+	CreatedBy  *uuid.UUID     `json:"created_by"`
+	CreatedAt  time.Time      `json:"created_at"`
+	StartedAt  *time.Time     `json:"started_at"`
+	FinishedAt *time.Time     `json:"finished_at"`
+}
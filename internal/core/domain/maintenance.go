@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type MaintenanceRecurrence string
+
+const (
+	MaintenanceRecurrenceNone   MaintenanceRecurrence = "none"
+	MaintenanceRecurrenceDaily  MaintenanceRecurrence = "daily"
+	MaintenanceRecurrenceWeekly MaintenanceRecurrence = "weekly"
+)
+
+// MaintenanceEventPolicy decides what happens to AI events raised by a
+// camera during a window.
+type MaintenanceEventPolicy string
+
+const (
+	MaintenanceEventsDrop MaintenanceEventPolicy = "drop"
+	MaintenanceEventsTag  MaintenanceEventPolicy = "tag"
+)
+
+var (
+	ErrMaintenanceWindowNotFound = errors.New("maintenance window not found")
+	ErrMaintenanceTarget         = errors.New("exactly one of camera_id or zone_id is required")
+	ErrMaintenancePeriod         = errors.New("ends_at must be after starts_at")
+	ErrMaintenanceRecurrence     = errors.New("recurrence must be none, daily or weekly, and a window must be shorter than its repeat interval")
+	ErrMaintenanceEventPolicy    = errors.New("event_policy must be drop or tag")
+	ErrMaintenanceTimezone       = errors.New("unknown timezone")
+	ErrEventSuppressed           = errors.New("event dropped: camera is in a maintenance window")
+)
+
+type MaintenanceWindow struct {
+	ID          uuid.UUID              `json:"id"`
+	Name        string                 `json:"name"`
+	CameraID    *uuid.UUID             `json:"camera_id"`
+	ZoneID      *uuid.UUID             `json:"zone_id"`
+	StartsAt    time.Time              `json:"starts_at"`
+	EndsAt      time.Time              `json:"ends_at"`
+	Recurrence  MaintenanceRecurrence  `json:"recurrence"`
+	RepeatUntil *time.Time             `json:"repeat_until"`
+	Timezone    string                 `json:"timezone"`
+	EventPolicy MaintenanceEventPolicy `json:"event_policy"`
+	Note        string                 `json:"note"`
+	CreatedBy   *uuid.UUID             `json:"created_by"`
+	CreatedAt   time.Time              `json:"created_at"`
+	UpdatedAt   time.Time              `json:"updated_at"`
+
+	// Cameras the window covers: its camera, or every camera in its zone's
+	// subtree
+	CameraIDs []uuid.UUID `json:"camera_ids,omitempty"`
+	// Set when listing with at: the occurrence covering that moment
+	ActiveFrom  *time.Time `json:"active_from,omitempty"`
+	ActiveUntil *time.Time `json:"active_until,omitempty"`
+}
+
+// Interval returns the days between occurrences, 0 for a one-off window.
+func (r MaintenanceRecurrence) Interval() int {
+	switch r {
+	case MaintenanceRecurrenceDaily:
+		return 1
+	case MaintenanceRecurrenceWeekly:
+		return 7
+	default:
+		return 0
+	}
+}
+
+// OccurrenceAt returns the occurrence of the window that covers t. Recurring
+// occurrences keep their wall-clock time in the window's timezone across
+// DST changes.
+func (w *MaintenanceWindow) OccurrenceAt(t time.Time) (time.Time, time.Time, bool) {
+	if t.Before(w.StartsAt) {
+		return time.Time{}, time.Time{}, false
+	}
+	step := w.Recurrence.Interval()
+	if step == 0 {
+		return w.StartsAt, w.EndsAt, t.Before(w.EndsAt)
+	}
+
+	loc, err := time.LoadLocation(w.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	first := w.StartsAt.In(loc)
+	duration := w.EndsAt.Sub(w.StartsAt)
+	n := int(t.Sub(first).Hours() / 24 / float64(step))
+	for _, k := range []int{n + 1, n, n - 1} {
+		if k < 0 {
+			continue
+		}
+		start := first.AddDate(0, 0, k*step)
+		if w.RepeatUntil != nil && !start.Before(*w.RepeatUntil) {
+			continue
+		}
+		if end := start.Add(duration); !t.Before(start) && t.Before(end) {
+			return start, end, true
+		}
+	}
+	return time.Time{}, time.Time{}, false
+}
+
+// CameraMaintenance is the scheduler's view of a camera. WindowID is set
+// while the scheduler holds the camera in maintenance.
+type CameraMaintenance struct {
+	CameraID uuid.UUID
+	Status   CameraStatus
+	WindowID *uuid.UUID
+}
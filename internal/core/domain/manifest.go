@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	ManifestFormatYAML = "yaml"
+	ManifestFormatCSV  = "csv"
+
+	ManifestVersion = 1
+)
+
+type ManifestAction string
+
+const (
+	ManifestActionCreate ManifestAction = "create"
+	ManifestActionUpdate ManifestAction = "update"
+	ManifestActionDelete ManifestAction = "delete"
+)
+
+type ManifestKind string
+
+const (
+	ManifestKindZone     ManifestKind = "zone"
+	ManifestKindCamera   ManifestKind = "camera"
+	ManifestKindAIConfig ManifestKind = "ai_config"
+)
+
+var (
+	ErrManifestFormat = errors.New("format must be yaml or csv")
+	ErrManifestEmpty  = errors.New("manifest file is required")
+)
+
+// ManifestError lists everything wrong with a manifest, so it can be fixed
+// in one pass.
+type ManifestError struct {
+	Problems []string `json:"problems"`
+}
+
+func (e *ManifestError) Error() string {
+	return fmt.Sprintf("invalid manifest: %s", strings.Join(e.Problems, "; "))
+}
+
+// SiteManifest declares a site's zones and cameras. Zones are matched to
+// existing records by their path of names, such as "Building A/Floor 1",
+// and cameras by name, so a manifest exported from one deployment applies
+// cleanly to another.
+type SiteManifest struct {
+	Version int              `json:"version" yaml:"version"`
+	Zones   []ManifestZone   `json:"zones" yaml:"zones"`
+	Cameras []ManifestCamera `json:"cameras" yaml:"cameras"`
+}
+
+// ManifestZone refers to its parent by path, and the parent must be
+// declared in the same manifest; zones without one are top-level.
+type ManifestZone struct {
+	Name        string   `json:"name" yaml:"name"`
+	Parent      string   `json:"parent,omitempty" yaml:"parent,omitempty"`
+	Kind        ZoneKind `json:"kind,omitempty" yaml:"kind,omitempty"`
+	Description string   `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// ManifestCamera refers to its zone by path. It leaves stored credentials
+// alone when Password is omitted, and the AI config alone when AIConfig is
+// omitted.
+type ManifestCamera struct {
+	Name      string            `json:"name" yaml:"name"`
+	Zone      string            `json:"zone,omitempty" yaml:"zone,omitempty"`
+	IPAddress string            `json:"ip_address,omitempty" yaml:"ip_address,omitempty"`
+	RTSPURL   string            `json:"rtsp_url" yaml:"rtsp_url"`
+	Username  string            `json:"username,omitempty" yaml:"username,omitempty"`
+	Password  *string           `json:"password,omitempty" yaml:"password,omitempty"`
+	AIEnabled bool              `json:"ai_enabled" yaml:"ai_enabled"`
+	AIConfig  *ManifestAIConfig `json:"ai_config,omitempty" yaml:"ai_config,omitempty"`
+}
+
+type ManifestAIConfig struct {
+	Enabled       bool         `json:"enabled" yaml:"enabled"`
+	Types         []EventType  `json:"types" yaml:"types"`
+	ROIZones      []ROIZone    `json:"roi_zones,omitempty" yaml:"roi_zones,omitempty"`
+	ActiveHours   *ActiveHours `json:"active_hours,omitempty" yaml:"active_hours,omitempty"`
+	Sensitivity   int          `json:"sensitivity" yaml:"sensitivity"`
+	MinConfidence int          `json:"min_confidence" yaml:"min_confidence"`
+}
+
+// ManifestChange is one step of a plan. Fields names what an update
+// changes.
+type ManifestChange struct {
+	Action ManifestAction `json:"action"`
+	Kind   ManifestKind   `json:"kind"`
+	Name   string         `json:"name"`
+	ID     string         `json:"id,omitempty"`
+	Fields []string       `json:"fields,omitempty"`
+	Error  string         `json:"error,omitempty"`
+}
+
+type ManifestPlan struct {
+	Changes   []ManifestChange `json:"changes"`
+	Creates   int              `json:"creates"`
+	Updates   int              `json:"updates"`
+	Deletes   int              `json:"deletes"`
+	Unchanged int              `json:"unchanged"`
+	Prune     bool             `json:"prune"`
+	Applied   bool             `json:"applied"`
+}
+
+func (p *ManifestPlan) Add(change ManifestChange) {
+	p.Changes = append(p.Changes, change)
+	switch change.Action {
+	case ManifestActionCreate:
+		p.Creates++
+	case ManifestActionUpdate:
+		p.Updates++
+	case ManifestActionDelete:
+		p.Deletes++
+	}
+}
@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NotificationType string
+
+const (
+	NotificationIdentityApproved NotificationType = "identity_approved"
+	NotificationIdentityRejected NotificationType = "identity_rejected"
+)
+
+type Notification struct {
+	ID           uuid.UUID        `json:"id"`
+	UserID       uuid.UUID        `json:"user_id"`
+	Type         NotificationType `json:"type"`
+	Title        string           `json:"title"`
+	Message      string           `json:"message"`
+	ResourceType string           `json:"resource_type,omitempty"`
+	ResourceID   string           `json:"resource_id,omitempty"`
+	ReadAt       *time.Time       `json:"read_at"`
+	CreatedAt    time.Time        `json:"created_at"`
+}
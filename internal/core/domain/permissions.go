@@ -17,3 +17,35 @@ type UserZonePermission struct {
 	ZoneID    uuid.UUID `json:"zone_id"`
 	CreatedAt time.Time `json:"created_at"`
 }
+
+// CameraScope is the set of cameras a user may see: those granted directly
+// and every camera in the subtree of a granted zone. A nil scope is
+// unrestricted.
+type CameraScope struct {
+	CameraIDs []uuid.UUID `json:"camera_ids"`
+	ZoneIDs   []uuid.UUID `json:"zone_ids"`
+}
+
+// Allows reports whether the scope covers a camera placed in zone, which
+// may be nil.
+func (s *CameraScope) Allows(cameraID string, zone *Zone) bool {
+	if s == nil {
+		return true
+	}
+	for _, id := range s.CameraIDs {
+		if id.String() == cameraID {
+			return true
+		}
+	}
+	if zone == nil {
+		return false
+	}
+	for _, ancestor := range zone.AncestorIDs() {
+		for _, id := range s.ZoneIDs {
+			if id.String() == ancestor {
+				return true
+			}
+		}
+	}
+	return false
+}
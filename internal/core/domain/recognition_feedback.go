@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type FeedbackAction string
+
+const (
+	FeedbackConfirm  FeedbackAction = "confirm"
+	FeedbackReject   FeedbackAction = "reject"
+	FeedbackReassign FeedbackAction = "reassign"
+)
+
+// ReviewStatus is what the recognition log records after the action.
+func (a FeedbackAction) ReviewStatus() string {
+	switch a {
+	case FeedbackConfirm:
+		return "confirmed"
+	case FeedbackReject:
+		return "rejected"
+	case FeedbackReassign:
+		return "reassigned"
+	}
+	return ""
+}
+
+var (
+	ErrRecognitionLogNotFound   = errors.New("recognition log not found")
+	ErrInvalidFeedbackAction    = errors.New("action must be confirm, reject or reassign")
+	ErrFeedbackReasonRequired   = errors.New("reason is required to reject or reassign a recognition")
+	ErrFeedbackIdentityRequired = errors.New("identity_id is required to reassign a recognition")
+	ErrFeedbackNoIdentity       = errors.New("recognition log has no identity to confirm or reject")
+	ErrFeedbackSameIdentity     = errors.New("recognition log already belongs to this identity")
+	ErrFeedbackStale            = errors.New("recognition log was reassigned by another review; reload it")
+)
+
+// RecognitionFeedback is a reviewer's verdict on one recognition log and
+// doubles as a labeled sample for model tuning.
+type RecognitionFeedback struct {
+	ID                  uuid.UUID      `json:"id"`
+	LogID               uuid.UUID      `json:"log_id"`
+	LogOccurredAt       time.Time      `json:"log_occurred_at"`
+	CameraID            *uuid.UUID     `json:"camera_id"`
+	Action              FeedbackAction `json:"action"`
+	OriginalIdentityID  *uuid.UUID     `json:"original_identity_id"`
+	CorrectedIdentityID *uuid.UUID     `json:"corrected_identity_id"` // nil when rejected as a stranger
+	Confidence          float64        `json:"confidence"`
+	FaceCropURL         string         `json:"face_crop_url"`
+	SnapshotURL         string         `json:"snapshot_url"`
+	Embedding           []float32      `json:"embedding,omitempty"`
+	Reason              string         `json:"reason"`
+	ReviewedBy          *uuid.UUID     `json:"reviewed_by"`
+	CreatedAt           time.Time      `json:"created_at"`
+}
+
+// RecognitionReview is the outcome of a feedback submission, including the
+// attendance days it recomputed.
+type RecognitionReview struct {
+	Feedback   *RecognitionFeedback `json:"feedback"`
+	Log        *RecognitionLog      `json:"log"`
+	Attendance []*AttendanceRecord  `json:"attendance"`
+}
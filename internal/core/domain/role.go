@@ -1,6 +1,25 @@
 package domain
 
-import "time"
+import (
+	"errors"
+	"time"
+)
+
+// Permissions are stored as a JSON array of strings on the role. "*" grants
+// everything. PermissionCamerasAll lifts the camera and zone grants made
+// through /permissions.
+const (
+	PermissionAll               = "*"
+	PermissionIdentityPIIRead   = "identities:pii:read"
+	PermissionContactTrace      = "contacts:trace"
+	PermissionCameraCredentials = "cameras:credentials:reveal"
+	PermissionCamerasAll        = "cameras:all"
+	PermissionSecurityKeys      = "security:keys:manage"
+	PermissionIdentityErase     = "identities:erase"
+	PermissionFeedbackExport    = "recognition:feedback:export"
+)
+
+var ErrPermissionDenied = errors.New("permission denied")
 
 type Role struct {
 	ID          string    `json:"id"`
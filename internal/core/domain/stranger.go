@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const JobTypeStrangerClustering = "stranger_clustering"
+
+type StrangerClusterStatus string
+
+const (
+	StrangerClusterOpen     StrangerClusterStatus = "open"
+	StrangerClusterPromoted StrangerClusterStatus = "promoted"
+)
+
+var (
+	ErrStrangerClusterNotFound = errors.New("stranger cluster not found")
+	ErrStrangerClusterPromoted = errors.New("stranger cluster is already promoted")
+	ErrStrangerNoCrops         = errors.New("stranger cluster has no usable face crops")
+)
+
+// StrangerCluster groups recognition logs of the same unknown face.
+type StrangerCluster struct {
+	ID                 uuid.UUID             `json:"id"`
+	Seq                int                   `json:"seq"`
+	Label              string                `json:"label"` // "Unknown person #Seq"
+	LogCount           int                   `json:"log_count"`
+	CameraIDs          []uuid.UUID           `json:"camera_ids"`
+	BestCropURL        string                `json:"best_crop_url"`
+	FirstSeenAt        *time.Time            `json:"first_seen_at"`
+	LastSeenAt         *time.Time            `json:"last_seen_at"`
+	Status             StrangerClusterStatus `json:"status"`
+	PromotedIdentityID *uuid.UUID            `json:"promoted_identity_id,omitempty"`
+	PromotedBy         *uuid.UUID            `json:"promoted_by,omitempty"`
+	PromotedAt         *time.Time            `json:"promoted_at,omitempty"`
+	CreatedAt          time.Time             `json:"created_at"`
+	UpdatedAt          time.Time             `json:"updated_at"`
+
+	Centroid []float32 `json:"-"`
+
+	// Join fields
+	CameraNames []string `json:"camera_names,omitempty"`
+}
+
+func StrangerLabel(seq int) string {
+	return fmt.Sprintf("Unknown person #%d", seq)
+}
+
+// StrangerPromotion is the outcome of turning a cluster into an identity.
+type StrangerPromotion struct {
+	ClusterID    uuid.UUID      `json:"cluster_id"`
+	Identity     *Identity      `json:"identity"`
+	Faces        []IdentityFace `json:"faces"`
+	SkippedCrops []SkippedCrop  `json:"skipped_crops,omitempty"`
+	LogsLinked   int64          `json:"logs_linked"`
+}
+
+type SkippedCrop struct {
+	LogID  uuid.UUID `json:"log_id"`
+	URL    string    `json:"url"`
+	Reason string    `json:"reason"`
+}
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Sighting is one recognition of a person with its camera's zone.
+type Sighting struct {
+	LogID       uuid.UUID
+	CameraID    uuid.UUID
+	CameraName  string
+	ZoneID      *uuid.UUID
+	ZoneName    string
+	SnapshotURL string
+	Confidence  float64
+	OccurredAt  time.Time
+}
+
+// TimelineVisit collapses consecutive sightings on one camera into a dwell
+// interval.
+type TimelineVisit struct {
+	CameraID      uuid.UUID  `json:"camera_id"`
+	CameraName    string     `json:"camera_name"`
+	ZoneID        *uuid.UUID `json:"zone_id"`
+	ZoneName      string     `json:"zone_name,omitempty"`
+	StartAt       time.Time  `json:"start_at"`
+	EndAt         time.Time  `json:"end_at"`
+	DwellSeconds  float64    `json:"dwell_seconds"`
+	Sightings     int        `json:"sightings"`
+	MaxConfidence float64    `json:"max_confidence"`
+	Snapshots     []string   `json:"snapshots"`
+}
+
+// ZoneTransition is a move between zones from one visit to the next.
+type ZoneTransition struct {
+	FromZoneID   *uuid.UUID `json:"from_zone_id"`
+	FromZoneName string     `json:"from_zone_name,omitempty"`
+	ToZoneID     *uuid.UUID `json:"to_zone_id"`
+	ToZoneName   string     `json:"to_zone_name,omitempty"`
+	LeftAt       time.Time  `json:"left_at"`
+	EnteredAt    time.Time  `json:"entered_at"`
+}
+
+type ZoneSummary struct {
+	ZoneID       *uuid.UUID `json:"zone_id"`
+	ZoneName     string     `json:"zone_name,omitempty"`
+	Visits       int        `json:"visits"`
+	Sightings    int        `json:"sightings"`
+	DwellSeconds float64    `json:"dwell_seconds"`
+	FirstSeenAt  time.Time  `json:"first_seen_at"`
+	LastSeenAt   time.Time  `json:"last_seen_at"`
+}
+
+type PersonTimeline struct {
+	IdentityID  uuid.UUID        `json:"identity_id"`
+	From        time.Time        `json:"from"`
+	To          time.Time        `json:"to"`
+	Sightings   int              `json:"sightings"`
+	Truncated   bool             `json:"truncated"` // More sightings than the limit; narrow the range
+	Visits      []TimelineVisit  `json:"visits"`
+	Transitions []ZoneTransition `json:"transitions"`
+	Zones       []ZoneSummary    `json:"zones"`
+}
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidReportPeriod = errors.New("report period must end after it starts")
+
+// UptimeCamera is a camera as the uptime report needs it.
+type UptimeCamera struct {
+	ID        uuid.UUID
+	Name      string
+	ZoneID    *uuid.UUID
+	ZoneName  string
+	Status    CameraStatus
+	CreatedAt time.Time
+}
+
+// UptimeStats covers the time a camera was monitored in a period. Time in
+// maintenance is reported but excluded from the uptime percentage. MTTR
+// averages the outages that recovered within the period; one still ongoing
+// only counts towards the outage count and the longest outage.
+type UptimeStats struct {
+	UptimePercent        float64 `json:"uptime_percent"`
+	OnlineSeconds        float64 `json:"online_seconds"`
+	OfflineSeconds       float64 `json:"offline_seconds"`
+	MaintenanceSeconds   float64 `json:"maintenance_seconds"`
+	Outages              int     `json:"outages"`
+	MTTRSeconds          float64 `json:"mttr_seconds"`
+	LongestOutageSeconds float64 `json:"longest_outage_seconds"`
+}
+
+type CameraUptime struct {
+	CameraID   uuid.UUID  `json:"camera_id"`
+	CameraName string     `json:"camera_name"`
+	ZoneID     *uuid.UUID `json:"zone_id"`
+	ZoneName   string     `json:"zone_name,omitempty"`
+	UptimeStats
+}
+
+type ZoneUptime struct {
+	ZoneID   *uuid.UUID `json:"zone_id"`
+	ZoneName string     `json:"zone_name,omitempty"`
+	Cameras  int        `json:"cameras"`
+	UptimeStats
+}
+
+type UptimeReport struct {
+	From    time.Time      `json:"from"`
+	To      time.Time      `json:"to"`
+	Overall UptimeStats    `json:"overall"`
+	Cameras []CameraUptime `json:"cameras"`
+	Zones   []ZoneUptime   `json:"zones"`
+}
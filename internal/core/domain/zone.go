@@ -1,20 +1,83 @@
 package domain
 
-import "time"
+import (
+	"errors"
+	"strings"
+	"time"
+)
 
+var (
+	ErrZoneNotFound       = errors.New("zone not found")
+	ErrZoneParentNotFound = errors.New("parent zone not found")
+	ErrZoneCycle          = errors.New("a zone cannot be moved under itself or its descendants")
+	ErrZoneHasChildren    = errors.New("zone has child zones; move or delete them first")
+	ErrInvalidZoneKind    = errors.New("kind must be site, building, floor or area")
+)
+
+// ZoneKind labels a level of the hierarchy. It is descriptive only; any
+// zone may be nested under any other.
+type ZoneKind string
+
+const (
+	ZoneKindSite     ZoneKind = "site"
+	ZoneKindBuilding ZoneKind = "building"
+	ZoneKindFloor    ZoneKind = "floor"
+	ZoneKindArea     ZoneKind = "area"
+)
+
+func (k ZoneKind) Valid() bool {
+	switch k {
+	case "", ZoneKindSite, ZoneKindBuilding, ZoneKindFloor, ZoneKindArea:
+		return true
+	}
+	return false
+}
+
+// Zone is a node in the site hierarchy. Path is the materialized path of
+// IDs from the root down to the zone itself, "/<root>/.../<id>/", so a
+// subtree is every zone whose path starts with its root's path.
 type Zone struct {
 	ID          string    `json:"id"`
+	ParentID    *string   `json:"parent_id"`
 	Name        string    `json:"name"`
 	Description string    `json:"description"`
+	Kind        ZoneKind  `json:"kind,omitempty"`
+	Path        string    `json:"path"`
 	CreatedAt   time.Time `json:"created_at"`
 }
 
+// Contains reports whether other is z or one of its descendants.
+func (z *Zone) Contains(other *Zone) bool {
+	return strings.HasPrefix(other.Path, z.Path)
+}
+
+// AncestorIDs returns the IDs on the zone's path, root first and the zone
+// itself last.
+func (z *Zone) AncestorIDs() []string {
+	return strings.FieldsFunc(z.Path, func(r rune) bool { return r == '/' })
+}
+
+// ZoneNode is a zone in the tree view. Cameras counts the zone's own
+// cameras and TotalCameras rolls up the whole subtree.
+type ZoneNode struct {
+	Zone
+	Cameras      int         `json:"cameras"`
+	TotalCameras int         `json:"total_cameras"`
+	Children     []*ZoneNode `json:"children"`
+}
+
 type CreateZoneRequest struct {
-	Name        string `json:"name" binding:"required"`
-	Description string `json:"description"`
+	Name        string   `json:"name" binding:"required"`
+	Description string   `json:"description"`
+	ParentID    *string  `json:"parent_id"`
+	Kind        ZoneKind `json:"kind"`
 }
 
+// UpdateZoneRequest moves the zone, with its subtree, when ParentID is set;
+// an empty ParentID moves it to the top level.
 type UpdateZoneRequest struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	ParentID    *string  `json:"parent_id"`
+	Kind        ZoneKind `json:"kind"`
 }
@@ -11,25 +11,51 @@ import (
 
 type AIRepository interface {
 	GetConfigByCamera(ctx context.Context, cameraID uuid.UUID) (*domain.AIConfig, error)
+	// SaveConfig replaces the camera's config and adds it as the next
+	// version, in one transaction. A TemplateID whose template is gone is
+	// saved as none.
 	SaveConfig(ctx context.Context, config *domain.AIConfig) error
+	ListConfigVersions(ctx context.Context, cameraID uuid.UUID) ([]*domain.AIConfigVersion, error)
+	GetConfigVersion(ctx context.Context, cameraID uuid.UUID, version int) (*domain.AIConfigVersion, error)
+	ListConfigsByTemplate(ctx context.Context, templateID uuid.UUID) ([]*domain.AIConfig, error)
 
 	CreateEvent(ctx context.Context, event *domain.AIEvent) (*domain.AIEvent, error)
 	ListEvents(ctx context.Context, cameraID *uuid.UUID, eventType *domain.EventType, status *domain.EventStatus, from, to *time.Time, limit, offset int32) ([]*domain.AIEvent, error)
-	UpdateEventStatus(ctx context.Context, id uuid.UUID, status domain.EventStatus, resolvedBy *uuid.UUID) (*domain.AIEvent, error)
+	UpdateEventStatus(ctx context.Context, id uuid.UUID, status *domain.EventStatus, verdict *domain.EventVerdict, resolvedBy *uuid.UUID) (*domain.AIEvent, error)
 
-	GetDashboardStats(ctx context.Context) (total, online, offline, maintenance int64, err error)
-	GetTodayEventsCount(ctx context.Context) (int64, error)
+	GetDashboardStats(ctx context.Context, filter *DashboardFilter) (total, online, offline, maintenance int64, err error)
+	GetTodayEventsCount(ctx context.Context, filter *DashboardFilter) (int64, error)
+	ListRecentEvents(ctx context.Context, filter *DashboardFilter, limit int32) ([]*domain.AIEvent, error)
 }
 
 type AIService interface {
 	GetConfig(ctx context.Context, cameraID uuid.UUID) (*domain.AIConfig, error)
 	UpdateConfig(ctx context.Context, req *domain.AIConfig) error
+	ListConfigVersions(ctx context.Context, cameraID uuid.UUID) ([]*domain.AIConfigVersion, error)
+	GetConfigVersion(ctx context.Context, cameraID uuid.UUID, version int) (*domain.AIConfigVersion, error)
+	// RollbackConfig saves an earlier version's settings, and template link,
+	// as a new version.
+	RollbackConfig(ctx context.Context, cameraID uuid.UUID, version int, requestedBy *uuid.UUID) (*domain.AIConfig, error)
+	// DetachTemplate unlinks the camera from its template and drops its
+	// overrides, keeping its settings.
+	DetachTemplate(ctx context.Context, cameraID uuid.UUID, requestedBy *uuid.UUID) (*domain.AIConfig, error)
 
 	CreateEvent(ctx context.Context, event *domain.AIEvent) (*domain.AIEvent, error)
 	ListEvents(ctx context.Context, filter *EventFilter) ([]*domain.AIEvent, error)
-	UpdateEventStatus(ctx context.Context, id uuid.UUID, status domain.EventStatus, resolvedBy uuid.UUID) (*domain.AIEvent, error)
+	UpdateEventStatus(ctx context.Context, id uuid.UUID, req *UpdateEventRequest) (*domain.AIEvent, error)
 
-	GetDashboardStats(ctx context.Context) (*domain.DashboardStats, error)
+	GetDashboardStats(ctx context.Context, filter *DashboardFilter) (*domain.DashboardStats, error)
+}
+
+// DashboardFilter rolls the dashboard up over the subtree of ZoneID, within
+// Scope.
+type DashboardFilter struct {
+	ZoneID *uuid.UUID
+	Scope  *domain.CameraScope
+}
+
+type RollbackAIConfigRequest struct {
+	Version int `json:"version" binding:"required"`
 }
 
 type EventFilter struct {
@@ -41,3 +67,10 @@ type EventFilter struct {
 	Limit     int32
 	Offset    int32
 }
+
+// UpdateEventRequest sets the status, the true/false positive verdict, or both.
+type UpdateEventRequest struct {
+	Status     *domain.EventStatus  `json:"status"`
+	Verdict    *domain.EventVerdict `json:"verdict"`
+	ResolvedBy *uuid.UUID           `json:"-"`
+}
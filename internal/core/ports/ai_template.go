@@ -0,0 +1,48 @@
+package ports
+
+import (
+	"context"
+
+	"app/internal/core/domain"
+
+	"github.com/google/uuid"
+)
+
+type AITemplateRepository interface {
+	Save(ctx context.Context, template *domain.AIConfigTemplate) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.AIConfigTemplate, error)
+	GetByName(ctx context.Context, name string) (*domain.AIConfigTemplate, error)
+	List(ctx context.Context) ([]*domain.AIConfigTemplate, error)
+	Update(ctx context.Context, template *domain.AIConfigTemplate) error
+	// Delete unlinks the template's cameras; their settings stay.
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type AITemplateService interface {
+	CreateTemplate(ctx context.Context, req *AITemplateRequest) (*domain.AIConfigTemplate, error)
+	GetTemplate(ctx context.Context, id uuid.UUID) (*domain.AIConfigTemplate, error)
+	ListTemplates(ctx context.Context) ([]*domain.AIConfigTemplate, error)
+	UpdateTemplate(ctx context.Context, id uuid.UUID, req *AITemplateRequest) (*domain.AIConfigTemplate, error)
+	DeleteTemplate(ctx context.Context, id uuid.UUID, requestedBy *uuid.UUID) error
+
+	ApplyTemplate(ctx context.Context, id uuid.UUID, req *ApplyAITemplateRequest) (*domain.AITemplateApplyResult, error)
+	TemplateDrift(ctx context.Context, id uuid.UUID) ([]*domain.AIConfigDrift, error)
+}
+
+type AITemplateRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+	domain.AISettings
+	RequestedBy *uuid.UUID `json:"-"`
+}
+
+// ApplyAITemplateRequest targets the listed cameras and every camera in the
+// subtree of ZoneID. Overrides are keyed by camera ID and replace what the
+// camera had; a camera without an entry keeps its overrides when the same
+// template is applied again, and gets none otherwise.
+type ApplyAITemplateRequest struct {
+	CameraIDs   []uuid.UUID                         `json:"camera_ids"`
+	ZoneID      *uuid.UUID                          `json:"zone_id"`
+	Overrides   map[string]domain.AIConfigOverrides `json:"overrides"`
+	RequestedBy *uuid.UUID                          `json:"-"`
+}
@@ -11,7 +11,15 @@ import (
 
 type AnalyticsRepository interface {
 	CreateRecognitionLog(ctx context.Context, log *domain.RecognitionLog) error
-	ListRecognitionLogs(ctx context.Context, identityID *uuid.UUID, cameraID *uuid.UUID, from, to *time.Time, limit, offset int32) ([]*domain.RecognitionLog, error)
+	ListRecognitionLogs(ctx context.Context, filter *RecognitionFilter) ([]*domain.RecognitionLog, error)
+	// ListSightings returns the identity's recognitions in [from, to] in time
+	// order, at most limit of them.
+	ListSightings(ctx context.Context, identityID uuid.UUID, from, to time.Time, limit int) ([]*domain.Sighting, error)
+	// ListContactSightings returns other identities' recognitions within
+	// window of one of the identity's sightings in [from, to], in the same
+	// zone (or on the same camera when it has no zone). Ordered by identity,
+	// then time.
+	ListContactSightings(ctx context.Context, identityID uuid.UUID, from, to time.Time, window time.Duration, limit int) ([]*domain.ContactSighting, error)
 
 	ListAttendanceRecords(ctx context.Context, identityID *uuid.UUID, from, to *time.Time, status *domain.AttendanceStatus, limit, offset int32) ([]*domain.AttendanceRecord, error)
 	GetAttendanceStats(ctx context.Context, date time.Time) (map[string]int64, error)
@@ -21,15 +29,25 @@ type AnalyticsService interface {
 	ListRecognitionLogs(ctx context.Context, filter *RecognitionFilter) ([]*domain.RecognitionLog, error)
 	ListAttendance(ctx context.Context, filter *AttendanceFilter) ([]*domain.AttendanceRecord, error)
 	GetDailyAttendanceSummary(ctx context.Context, date time.Time) (any, error)
+	GetTimeline(ctx context.Context, identityID uuid.UUID, filter *TimelineFilter) (*domain.PersonTimeline, error)
+}
+
+// TimelineFilter bounds a person timeline. Sightings on the same camera at
+// most MaxGap apart belong to one visit.
+type TimelineFilter struct {
+	FromDate *time.Time
+	ToDate   *time.Time
+	MaxGap   time.Duration
 }
 
 type RecognitionFilter struct {
-	IdentityID *uuid.UUID
-	CameraID   *uuid.UUID
-	FromDate   *time.Time
-	ToDate     *time.Time
-	Limit      int32
-	Offset     int32
+	IdentityID    *uuid.UUID
+	CameraID      *uuid.UUID
+	StrangersOnly bool // Logs without an identity
+	FromDate      *time.Time
+	ToDate        *time.Time
+	Limit         int32
+	Offset        int32
 }
 
 type AttendanceFilter struct {
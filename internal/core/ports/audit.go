@@ -2,6 +2,7 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"app/internal/core/domain"
 
@@ -25,3 +26,27 @@ type AuditFilter struct {
 	Limit     int32
 	Offset    int32
 }
+
+type AccessLogRepository interface {
+	CreateAccessLogs(ctx context.Context, logs []*domain.AccessLog) error
+	ListAccessLogs(ctx context.Context, filter *AccessLogFilter) ([]*domain.AccessLog, error)
+	SummarizeIdentityAccess(ctx context.Context, identityID uuid.UUID, from, to *time.Time) ([]*domain.IdentityAccessor, error)
+}
+
+// AccessAuditService records reads of sensitive data. Record never blocks the
+// request: entries are queued and written in batches in the background.
+type AccessAuditService interface {
+	Record(log *domain.AccessLog)
+	IdentityAccessReport(ctx context.Context, identityID uuid.UUID, filter *AccessLogFilter) (*domain.IdentityAccessReport, error)
+	// Close flushes queued entries and stops the writer.
+	Close()
+}
+
+type AccessLogFilter struct {
+	IdentityID *uuid.UUID
+	UserID     *uuid.UUID
+	FromDate   *time.Time
+	ToDate     *time.Time
+	Limit      int32
+	Offset     int32
+}
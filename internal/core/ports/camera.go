@@ -4,21 +4,34 @@ import (
 	"context"
 
 	"app/internal/core/domain"
+
+	"github.com/google/uuid"
 )
 
 type CameraRepository interface {
 	Save(ctx context.Context, camera *domain.Camera) error
 	GetByID(ctx context.Context, id string) (*domain.Camera, error)
 	List(ctx context.Context, search string) ([]*domain.Camera, error)
-	ListByZone(ctx context.Context, zoneID string, search string) ([]*domain.Camera, error)
+	Find(ctx context.Context, filter *CameraFilter) ([]*domain.Camera, error)
 	Update(ctx context.Context, camera *domain.Camera) error
 	Delete(ctx context.Context, id string) error
 }
 
 type CameraService interface {
 	CreateCamera(ctx context.Context, req *domain.CreateCameraRequest) (*domain.Camera, error)
-	GetCamera(ctx context.Context, id string) (*domain.Camera, error)
-	ListCameras(ctx context.Context, zoneID string, search string) ([]*domain.Camera, error)
+	// GetCamera returns nil, like a missing camera, when scope does not
+	// cover it.
+	GetCamera(ctx context.Context, id string, scope *domain.CameraScope) (*domain.Camera, error)
+	ListCameras(ctx context.Context, filter *CameraFilter) ([]*domain.Camera, error)
 	UpdateCamera(ctx context.Context, id string, req *domain.UpdateCameraRequest) (*domain.Camera, error)
 	DeleteCamera(ctx context.Context, id string) error
+	RevealCredentials(ctx context.Context, id string, requestedBy *uuid.UUID) (*domain.CameraCredentials, error)
+}
+
+// CameraFilter matches cameras in ZoneID and every zone below it. A nil
+// Scope is unrestricted.
+type CameraFilter struct {
+	ZoneID string
+	Search string
+	Scope  *domain.CameraScope
 }
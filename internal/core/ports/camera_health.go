@@ -0,0 +1,48 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"app/internal/core/domain"
+
+	"github.com/google/uuid"
+)
+
+// CameraProber checks whether a camera's stream can be opened.
+type CameraProber interface {
+	Probe(ctx context.Context, rtspURL string) *domain.CameraProbe
+}
+
+type CameraHealthRepository interface {
+	// ListMonitored returns cameras the monitor should probe: all but those
+	// in maintenance.
+	ListMonitored(ctx context.Context) ([]*domain.CameraHealth, error)
+	// SaveProbe stores the latest probe and the consecutive counters.
+	SaveProbe(ctx context.Context, health *domain.CameraHealth, probe *domain.CameraProbe) error
+	// ChangeStatus sets the camera's status and maintenance window, restarts
+	// the probe counters and records the transition in one transaction. It
+	// returns domain.ErrCameraStatusChanged when the camera is no longer in
+	// the change's FromStatus.
+	ChangeStatus(ctx context.Context, change *domain.CameraStatusChange) error
+	// RecordStatusChange only appends to the history, for changes already
+	// written to the camera.
+	RecordStatusChange(ctx context.Context, change *domain.CameraStatusChange) error
+	ListStatusHistory(ctx context.Context, filter *CameraStatusHistoryFilter) ([]*domain.CameraStatusChange, int64, error)
+}
+
+type CameraHealthService interface {
+	// CheckAll probes every monitored camera once and applies transitions.
+	CheckAll(ctx context.Context) error
+	// Run calls CheckAll on the configured interval until ctx is done.
+	Run(ctx context.Context)
+	ListStatusHistory(ctx context.Context, filter *CameraStatusHistoryFilter) ([]*domain.CameraStatusChange, int64, error)
+}
+
+type CameraStatusHistoryFilter struct {
+	CameraID uuid.UUID
+	FromDate *time.Time
+	ToDate   *time.Time
+	Limit    int32
+	Offset   int32
+}
@@ -0,0 +1,27 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"app/internal/core/domain"
+
+	"github.com/google/uuid"
+)
+
+type ContactTraceService interface {
+	// TraceContacts ranks identities seen near the given one by overlap
+	// duration, then by number of encounters. Every trace is audited.
+	TraceContacts(ctx context.Context, identityID uuid.UUID, filter *ContactTraceFilter) (*domain.ContactTrace, error)
+}
+
+// ContactTraceFilter bounds a trace. Sightings in the same zone at most
+// Window apart count as contact; zero values fall back to config.
+type ContactTraceFilter struct {
+	FromDate      *time.Time
+	ToDate        *time.Time
+	Window        time.Duration
+	MinEncounters int
+	Limit         int // Top contacts returned, 0 for all
+	RequestedBy   *uuid.UUID
+}
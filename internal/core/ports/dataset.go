@@ -0,0 +1,34 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"app/internal/core/domain"
+
+	"github.com/google/uuid"
+)
+
+type DatasetRepository interface {
+	// EachSample streams reviewed events and recognitions with a snapshot,
+	// oldest first.
+	EachSample(ctx context.Context, filter *DatasetExportRequest, fn func(*domain.DatasetSample) error) error
+}
+
+type DatasetService interface {
+	StartExport(ctx context.Context, req *DatasetExportRequest) (*domain.Job, error)
+}
+
+// DatasetExportRequest selects reviewed samples for a training archive.
+// Recognitions are included when EventTypes is empty or contains "face".
+// ValRatio of the samples go to the validation split (default 0.2).
+type DatasetExportRequest struct {
+	Format        domain.DatasetFormat `json:"format"` // coco, yolo or both (default)
+	CameraID      *uuid.UUID           `json:"camera_id"`
+	EventTypes    []domain.EventType   `json:"event_types"`
+	FromDate      *time.Time           `json:"from"`
+	ToDate        *time.Time           `json:"to"`
+	ValRatio      *float64             `json:"val_ratio"`
+	SkipNegatives bool                 `json:"skip_negatives"` // Leave out false positives
+	RequestedBy   *uuid.UUID           `json:"-"`
+}
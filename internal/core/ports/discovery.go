@@ -0,0 +1,71 @@
+package ports
+
+import (
+	"context"
+
+	"app/internal/core/domain"
+
+	"github.com/google/uuid"
+)
+
+// DeviceDiscoverer finds ONVIF devices on the local network and reads their
+// identity and media profiles.
+type DeviceDiscoverer interface {
+	Discover(ctx context.Context) ([]domain.ONVIFEndpoint, error)
+	// Describe fills in device information and profiles, with stream URIs,
+	// from the device service at xaddr. On error it still returns what it
+	// learned before the failing call.
+	Describe(ctx context.Context, xaddr, username, password string) (*domain.DiscoveredDevice, error)
+}
+
+type DiscoveryRepository interface {
+	SaveDevice(ctx context.Context, device *domain.DiscoveredDevice) error
+	GetDevice(ctx context.Context, id uuid.UUID) (*domain.DiscoveredDevice, error)
+	ListDevices(ctx context.Context, jobID uuid.UUID) ([]*domain.DiscoveredDevice, error)
+	// MarkAdopted fails with ErrDeviceAlreadyAdopted if another request got
+	// there first.
+	MarkAdopted(ctx context.Context, id uuid.UUID, cameraID uuid.UUID) error
+}
+
+type DiscoveryService interface {
+	StartDiscovery(ctx context.Context, req *DiscoveryRequest) (*domain.Job, error)
+	ListDevices(ctx context.Context, jobID uuid.UUID) ([]*domain.DiscoveredDevice, error)
+	AdoptDevices(ctx context.Context, req *AdoptDevicesRequest) (*AdoptDevicesResult, error)
+}
+
+// DiscoveryRequest carries the credentials used to query devices after they
+// answer the probe. They are not stored with the job.
+type DiscoveryRequest struct {
+	Username    string     `json:"username"`
+	Password    string     `json:"password"`
+	RequestedBy *uuid.UUID `json:"-"`
+}
+
+type AdoptDevice struct {
+	DeviceID uuid.UUID `json:"device_id" binding:"required"`
+	// ProfileToken picks the stream; the device's first profile by default
+	ProfileToken string `json:"profile_token"`
+	// Name defaults to the device's ONVIF name, then its model
+	Name string `json:"name"`
+}
+
+// AdoptDevicesRequest turns discovered devices into cameras. Username and
+// Password, when set, are stored as each camera's RTSP credentials.
+type AdoptDevicesRequest struct {
+	Devices     []AdoptDevice `json:"devices" binding:"required"`
+	ZoneID      *string       `json:"zone_id"`
+	AIEnabled   bool          `json:"ai_enabled"`
+	Username    string        `json:"username"`
+	Password    string        `json:"password"`
+	RequestedBy *uuid.UUID    `json:"-"`
+}
+
+type AdoptDeviceError struct {
+	DeviceID uuid.UUID `json:"device_id"`
+	Error    string    `json:"error"`
+}
+
+type AdoptDevicesResult struct {
+	Cameras []*domain.Camera   `json:"cameras"`
+	Errors  []AdoptDeviceError `json:"errors"`
+}
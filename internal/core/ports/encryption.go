@@ -0,0 +1,58 @@
+package ports
+
+import (
+	"context"
+
+	"app/internal/core/domain"
+
+	"github.com/google/uuid"
+)
+
+// KeyManager wraps data keys with a master key it never reveals.
+type KeyManager interface {
+	Wrap(ctx context.Context, plaintext []byte) (wrapped []byte, masterKeyID string, err error)
+	Unwrap(ctx context.Context, masterKeyID string, wrapped []byte) ([]byte, error)
+	ActiveKeyID() string
+}
+
+type DataKeyRepository interface {
+	ListKeys(ctx context.Context, tenant string) ([]*domain.DataKey, error)
+	// CreateKey stores a new active key and retires the previous active key
+	// of the same tenant and purpose.
+	CreateKey(ctx context.Context, key *domain.DataKey) error
+	UpdateWrappedKey(ctx context.Context, id uuid.UUID, masterKeyID string, wrapped []byte) error
+}
+
+// FieldCipher encrypts individual column values. Ciphertext records the data
+// key version, so values stay readable after rotation, and values without the
+// ciphertext prefix are treated as legacy plaintext.
+type FieldCipher interface {
+	Enabled() bool
+	Encrypt(plaintext string) (string, error)
+	Decrypt(value string) (string, error)
+	// BlindIndex is a keyed hash for exact-match lookups on encrypted
+	// columns. It returns "" when encryption is disabled or value is empty.
+	BlindIndex(value string) string
+	// IsCurrent reports whether value is already encrypted with the active key.
+	IsCurrent(value string) bool
+}
+
+// KeyRing is a FieldCipher whose data keys can be rotated.
+type KeyRing interface {
+	FieldCipher
+	ListKeys(ctx context.Context) ([]*domain.DataKey, error)
+	Rotate(ctx context.Context) (*domain.DataKey, error)
+}
+
+// PIIStore is implemented by repositories with encrypted columns so that a
+// rotation job can rewrite their rows under the active key.
+type PIIStore interface {
+	CountPII(ctx context.Context) (int, error)
+	ReencryptPII(ctx context.Context, step func(ok bool)) error
+}
+
+type KeyService interface {
+	ListKeys(ctx context.Context) ([]*domain.DataKey, error)
+	RotateKey(ctx context.Context, requestedBy *uuid.UUID) (*domain.Job, error)
+	Reencrypt(ctx context.Context, requestedBy *uuid.UUID) (*domain.Job, error)
+}
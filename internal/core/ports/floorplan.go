@@ -0,0 +1,66 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"app/internal/core/domain"
+
+	"github.com/google/uuid"
+)
+
+type FloorPlanRepository interface {
+	SavePlan(ctx context.Context, plan *domain.FloorPlan) error
+	GetPlan(ctx context.Context, zoneID uuid.UUID) (*domain.FloorPlan, error)
+	// DeletePlan also removes the placements and areas drawn on the plan.
+	DeletePlan(ctx context.Context, zoneID uuid.UUID) error
+
+	SavePlacement(ctx context.Context, placement *domain.CameraPlacement) error
+	DeletePlacement(ctx context.Context, cameraID uuid.UUID) error
+	SaveArea(ctx context.Context, area *domain.ZoneArea) error
+	DeleteArea(ctx context.Context, zoneID uuid.UUID) error
+
+	// ListPlacedCameras and ListAreaStats count AI events since the given
+	// time; cameras outside scope are left out of both.
+	ListPlacedCameras(ctx context.Context, planZoneID uuid.UUID, since time.Time, scope *domain.CameraScope) ([]*domain.PlacedCamera, error)
+	ListAreaStats(ctx context.Context, planZoneID uuid.UUID, since time.Time, scope *domain.CameraScope) ([]*domain.ZoneAreaStats, error)
+}
+
+type FloorPlanService interface {
+	// UploadPlan replaces the zone's plan image. Placements and areas keep
+	// their coordinates.
+	UploadPlan(ctx context.Context, req *UploadFloorPlanRequest) (*domain.FloorPlan, error)
+	GetPlan(ctx context.Context, zoneID uuid.UUID) (*domain.FloorPlan, error)
+	DeletePlan(ctx context.Context, zoneID uuid.UUID, requestedBy *uuid.UUID) error
+
+	PlaceCamera(ctx context.Context, cameraID uuid.UUID, req *CameraPlacementRequest) (*domain.CameraPlacement, error)
+	RemovePlacement(ctx context.Context, cameraID uuid.UUID) error
+	SetZoneArea(ctx context.Context, zoneID uuid.UUID, req *ZoneAreaRequest) (*domain.ZoneArea, error)
+	RemoveZoneArea(ctx context.Context, zoneID uuid.UUID) error
+
+	Map(ctx context.Context, zoneID uuid.UUID, window time.Duration, scope *domain.CameraScope) (*domain.FloorPlanMap, error)
+}
+
+type UploadFloorPlanRequest struct {
+	ZoneID     uuid.UUID
+	Image      []byte
+	UploadedBy *uuid.UUID
+}
+
+// CameraPlacementRequest places the camera on the plan of PlanZoneID, by
+// default the camera's own zone.
+type CameraPlacementRequest struct {
+	PlanZoneID *uuid.UUID `json:"plan_zone_id"`
+	X          float64    `json:"x"`
+	Y          float64    `json:"y"`
+	Heading    float64    `json:"heading"`
+	FOV        *float64   `json:"fov"`   // Default 90
+	Range      *float64   `json:"range"` // Default a tenth of the plan's larger side
+}
+
+// ZoneAreaRequest draws the zone on the plan of PlanZoneID, by default its
+// parent's.
+type ZoneAreaRequest struct {
+	PlanZoneID *uuid.UUID     `json:"plan_zone_id"`
+	Polygon    []domain.Point `json:"polygon" binding:"required"`
+}
@@ -2,6 +2,8 @@ package ports
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"app/internal/core/domain"
 
@@ -12,18 +14,52 @@ type IdentityRepository interface {
 	CreateIdentity(ctx context.Context, identity *domain.Identity) (*domain.Identity, error)
 	GetIdentity(ctx context.Context, id uuid.UUID) (*domain.Identity, error)
 	GetIdentityByCode(ctx context.Context, code string) (*domain.Identity, error)
+	GetIdentityByCardNumber(ctx context.Context, cardNumber string) (*domain.Identity, error)
+	ImportIdentities(ctx context.Context, identities []*domain.Identity) error
 	ListIdentities(ctx context.Context, page, limit int, search string) ([]*domain.Identity, int64, error)
 	CountIdentities(ctx context.Context) (int64, error)
 	UpdateIdentity(ctx context.Context, identity *domain.Identity) (*domain.Identity, error)
-	UpdateIdentityStatus(ctx context.Context, id uuid.UUID, status domain.IdentityStatus) (*domain.Identity, error)
+	ListIdentitiesByStatus(ctx context.Context, status domain.IdentityStatus, page, limit int) ([]*domain.Identity, int64, error)
+	ReviewIdentity(ctx context.Context, id uuid.UUID, status domain.IdentityStatus, reviewerID uuid.UUID, reason string) (*domain.Identity, error)
 	DeleteIdentity(ctx context.Context, id uuid.UUID) error
 }
 
 type IdentityFaceRepository interface {
 	CreateFace(ctx context.Context, face *domain.IdentityFace) (*domain.IdentityFace, error)
+	GetFace(ctx context.Context, id uuid.UUID) (*domain.IdentityFace, error)
 	ListFaces(ctx context.Context, identityID uuid.UUID) ([]*domain.IdentityFace, error)
 	DeleteFace(ctx context.Context, id uuid.UUID) error
 	SetPrimary(ctx context.Context, identityID, faceID uuid.UUID) error
+	ListEmbeddings(ctx context.Context) ([]*domain.IdentityFace, error)
+}
+
+// IdentityVersionRepository stores the append-only history of identities and
+// their faces.
+type IdentityVersionRepository interface {
+	// RecordVersion appends v with the next version number. When the identity
+	// has no history yet and baseline is set, baseline is stored first so the
+	// state before the change can still be looked up.
+	RecordVersion(ctx context.Context, v *domain.IdentityVersion, baseline *domain.Identity) error
+	RecordVersions(ctx context.Context, versions []*domain.IdentityVersion) error
+	ListVersions(ctx context.Context, identityID uuid.UUID, limit, offset int32) ([]*domain.IdentityVersion, int64, error)
+	GetVersion(ctx context.Context, identityID uuid.UUID, version int) (*domain.IdentityVersion, error)
+	// IdentityAsOf returns the latest identity entry at or before t, or nil.
+	IdentityAsOf(ctx context.Context, identityID uuid.UUID, t time.Time) (*domain.IdentityVersion, error)
+	// FacesAsOf returns the latest entry for each face at or before t.
+	FacesAsOf(ctx context.Context, identityID uuid.UUID, t time.Time) ([]*domain.IdentityVersion, error)
+}
+
+// FaceIndex answers cosine-similarity queries over enrolled face embeddings.
+type FaceIndex interface {
+	Upsert(ctx context.Context, face *domain.IdentityFace) error
+	Remove(ctx context.Context, faceID uuid.UUID) error
+	RemoveIdentity(ctx context.Context, identityID uuid.UUID) error
+	Search(ctx context.Context, embedding []float32, topK int, minScore float64) ([]*domain.FaceMatch, error)
+}
+
+// EmbeddingProvider turns a face image into an embedding vector.
+type EmbeddingProvider interface {
+	Embed(ctx context.Context, image io.Reader) ([]float32, error)
 }
 
 type IdentityService interface {
@@ -31,13 +67,22 @@ type IdentityService interface {
 	GetIdentity(ctx context.Context, id uuid.UUID) (*domain.Identity, error)
 	ListIdentities(ctx context.Context, page, limit int, search string) ([]*domain.Identity, int64, error)
 	UpdateIdentity(ctx context.Context, id uuid.UUID, req *UpdateIdentityRequest) (*domain.Identity, error)
-	UpdateStatus(ctx context.Context, id uuid.UUID, status domain.IdentityStatus) (*domain.Identity, error)
-	DeleteIdentity(ctx context.Context, id uuid.UUID) error
+	ListPending(ctx context.Context, page, limit int) ([]*domain.Identity, int64, error)
+	ReviewIdentity(ctx context.Context, id uuid.UUID, req *ReviewIdentityRequest) (*domain.Identity, error)
+	DeleteIdentity(ctx context.Context, id uuid.UUID, deletedBy *uuid.UUID) error
+
+	ListHistory(ctx context.Context, id uuid.UUID, limit, offset int32) ([]*domain.IdentityVersion, int64, error)
+	GetIdentityAsOf(ctx context.Context, id uuid.UUID, asOf time.Time) (*domain.Identity, error)
+	RevertIdentity(ctx context.Context, id uuid.UUID, req *RevertIdentityRequest) (*domain.Identity, error)
 
-	EnrollFace(ctx context.Context, identityID uuid.UUID, imageURL string, isPrimary bool) (*domain.IdentityFace, error)
-	DeleteFace(ctx context.Context, faceID uuid.UUID) error
+	EnrollFace(ctx context.Context, req *EnrollFaceRequest) (*domain.IdentityFace, error)
+	DeleteFace(ctx context.Context, faceID uuid.UUID, deletedBy *uuid.UUID) error
+	SearchFaces(ctx context.Context, req *FaceSearchRequest) ([]*domain.FaceMatch, error)
 }
 
+// TopicIdentitySync carries domain.EdgeIdentitySync messages to edge devices.
+const TopicIdentitySync = "identities.sync"
+
 // DTOs
 type CreateIdentityRequest struct {
 	Code               string         `json:"code" binding:"required"`
@@ -51,6 +96,30 @@ type CreateIdentityRequest struct {
 	CreatedBy          *uuid.UUID
 }
 
+// ReviewIdentityRequest approves (Status active) or rejects a pending identity.
+type ReviewIdentityRequest struct {
+	Status     domain.IdentityStatus `json:"status"`
+	Reason     string                `json:"reason" binding:"required"`
+	ReviewerID uuid.UUID             `json:"-"`
+}
+
+type EnrollFaceRequest struct {
+	IdentityID uuid.UUID  `json:"identity_id" binding:"required"`
+	ImageURL   string     `json:"image_url" binding:"required"`
+	IsPrimary  bool       `json:"is_primary"`
+	Embedding  []float32  `json:"embedding"` // Optional, computed by the embedding provider when omitted
+	EnrolledBy *uuid.UUID `json:"-"`
+}
+
+// FaceSearchRequest searches by Embedding, or by Image when an embedding
+// provider is configured.
+type FaceSearchRequest struct {
+	Embedding []float32 `json:"embedding"`
+	Image     io.Reader `json:"-"`
+	TopK      int       `json:"top_k"`
+	MinScore  *float64  `json:"min_score"`
+}
+
 type UpdateIdentityRequest struct {
 	FullName           string         `json:"full_name"`
 	Type               string         `json:"type"`
@@ -59,4 +128,36 @@ type UpdateIdentityRequest struct {
 	Department         string         `json:"department"`
 	Metadata           map[string]any `json:"metadata"`
 	Note               string         `json:"note"`
+	UpdatedBy          *uuid.UUID     `json:"-"`
+}
+
+// RevertIdentityRequest restores the editable fields of an identity to a
+// previous version. Status is left alone so reverts cannot bypass review.
+type RevertIdentityRequest struct {
+	Version    int        `json:"version" binding:"required"`
+	Reason     string     `json:"reason" binding:"required"`
+	RevertedBy *uuid.UUID `json:"-"`
+}
+
+type IdentityImportMode string
+
+const (
+	ImportModeAllOrNothing IdentityImportMode = "all_or_nothing"
+	ImportModeSkipInvalid  IdentityImportMode = "skip_invalid"
+)
+
+// IdentityImportRequest carries an uploaded sheet and an optional ZIP of face
+// photos named by identity code (CODE.jpg, CODE_2.jpg, ...). PhotosPath is a
+// temporary file owned by the import job, which removes it when done.
+type IdentityImportRequest struct {
+	SheetName  string
+	Sheet      []byte
+	PhotosPath string
+	Mode       IdentityImportMode
+	CreatedBy  *uuid.UUID
+}
+
+type IdentityImportService interface {
+	StartImport(ctx context.Context, req *IdentityImportRequest) (*domain.Job, error)
+	ImportReport(ctx context.Context, jobID uuid.UUID) ([]domain.ImportRowError, error)
 }
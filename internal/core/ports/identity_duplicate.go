@@ -0,0 +1,54 @@
+package ports
+
+import (
+	"context"
+
+	"app/internal/core/domain"
+
+	"github.com/google/uuid"
+)
+
+// IdentityMatchKey holds what the duplicate scan compares for one live
+// identity. PhoneKey and CardKey are normalized lookup keys (blind indexes
+// when encryption is on), never the raw values.
+type IdentityMatchKey struct {
+	ID       uuid.UUID
+	Code     string
+	FullName string
+	PhoneKey string
+	CardKey  string
+}
+
+type IdentityDuplicateRepository interface {
+	ListMatchKeys(ctx context.Context) ([]*IdentityMatchKey, error)
+	// ReplaceOpenCandidates swaps the open candidates for a fresh scan result.
+	// Dismissed and merged pairs are kept and not reopened.
+	ReplaceOpenCandidates(ctx context.Context, candidates []*domain.DuplicateCandidate) error
+	ListCandidates(ctx context.Context, filter *DuplicateFilter) ([]*domain.DuplicateCandidate, int64, error)
+	GetCandidate(ctx context.Context, id uuid.UUID) (*domain.DuplicateCandidate, error)
+	ResolveCandidate(ctx context.Context, id uuid.UUID, status domain.DuplicateStatus, resolvedBy *uuid.UUID) error
+	// MergeIdentities moves faces, recognition logs and attendance from
+	// mergedID to survivorID and soft deletes mergedID, in one transaction.
+	MergeIdentities(ctx context.Context, survivorID, mergedID uuid.UUID, mergedBy *uuid.UUID) (*domain.IdentityMerge, error)
+}
+
+type IdentityDuplicateService interface {
+	StartScan(ctx context.Context, requestedBy *uuid.UUID) (*domain.Job, error)
+	ListCandidates(ctx context.Context, filter *DuplicateFilter) ([]*domain.DuplicateCandidate, int64, error)
+	DismissCandidate(ctx context.Context, id uuid.UUID, dismissedBy *uuid.UUID) error
+	MergeIdentities(ctx context.Context, survivorID uuid.UUID, req *MergeIdentitiesRequest) (*domain.IdentityMerge, error)
+}
+
+type DuplicateFilter struct {
+	Status     *domain.DuplicateStatus
+	IdentityID *uuid.UUID
+	Limit      int32
+	Offset     int32
+}
+
+// MergeIdentitiesRequest folds SourceID into the identity in the URL.
+type MergeIdentitiesRequest struct {
+	SourceID uuid.UUID  `json:"source_id" binding:"required"`
+	Reason   string     `json:"reason" binding:"required"`
+	MergedBy *uuid.UUID `json:"-"`
+}
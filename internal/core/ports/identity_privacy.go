@@ -0,0 +1,32 @@
+package ports
+
+import (
+	"context"
+	"io"
+
+	"app/internal/core/domain"
+
+	"github.com/google/uuid"
+)
+
+// IdentityPrivacyRepository reads and erases everything held about a person.
+// Unlike IdentityRepository it also sees soft-deleted identities.
+type IdentityPrivacyRepository interface {
+	GetIdentity(ctx context.Context, id uuid.UUID) (*domain.Identity, error)
+	ListRecognitionLogs(ctx context.Context, identityID uuid.UUID) ([]*domain.RecognitionLog, error)
+	ListAttendanceRecords(ctx context.Context, identityID uuid.UUID) ([]*domain.AttendanceRecord, error)
+	ListAuditLogs(ctx context.Context, identityID uuid.UUID, faceIDs []uuid.UUID) ([]*domain.AuditLog, error)
+	EraseIdentity(ctx context.Context, id uuid.UUID) (*domain.IdentityErasure, error)
+}
+
+// IdentityPrivacyService serves data subject access and erasure requests
+// (Decree 13/2023 on personal data protection).
+type IdentityPrivacyService interface {
+	ExportIdentity(ctx context.Context, id uuid.UUID, requestedBy *uuid.UUID, w io.Writer) error
+	EraseIdentity(ctx context.Context, id uuid.UUID, req *EraseIdentityRequest) (*domain.IdentityErasure, error)
+}
+
+type EraseIdentityRequest struct {
+	Reason      string `json:"reason"`
+	RequestedBy *uuid.UUID
+}
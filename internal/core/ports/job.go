@@ -0,0 +1,42 @@
+package ports
+
+import (
+	"context"
+
+	"app/internal/core/domain"
+
+	"github.com/google/uuid"
+)
+
+type JobRepository interface {
+	Create(ctx context.Context, job *domain.Job) error
+	Get(ctx context.Context, id uuid.UUID) (*domain.Job, error)
+	Update(ctx context.Context, job *domain.Job) error
+	List(ctx context.Context, jobType *string, createdBy *uuid.UUID, limit, offset int32) ([]*domain.Job, error)
+	FailRunning(ctx context.Context, reason string) (int64, error)
+}
+
+// JobTracker lets a running job report its progress.
+type JobTracker interface {
+	JobID() uuid.UUID
+	SetTotal(total int)
+	Step(ok bool)
+	SetResultURL(url string)
+}
+
+// JobFunc is the body of a background job. The returned map is stored as
+// the job result, even when an error is returned.
+type JobFunc func(ctx context.Context, tracker JobTracker) (map[string]any, error)
+
+type JobService interface {
+	Start(ctx context.Context, jobType string, createdBy *uuid.UUID, params map[string]any, run JobFunc) (*domain.Job, error)
+	GetJob(ctx context.Context, id uuid.UUID) (*domain.Job, error)
+	ListJobs(ctx context.Context, filter *JobFilter) ([]*domain.Job, error)
+}
+
+type JobFilter struct {
+	Type      *string
+	CreatedBy *uuid.UUID
+	Limit     int32
+	Offset    int32
+}
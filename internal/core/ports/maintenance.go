@@ -0,0 +1,59 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"app/internal/core/domain"
+
+	"github.com/google/uuid"
+)
+
+type MaintenanceRepository interface {
+	Create(ctx context.Context, w *domain.MaintenanceWindow) error
+	Get(ctx context.Context, id uuid.UUID) (*domain.MaintenanceWindow, error)
+	List(ctx context.Context, filter *MaintenanceWindowFilter) ([]*domain.MaintenanceWindow, int64, error)
+	Update(ctx context.Context, w *domain.MaintenanceWindow) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	// ListCandidates returns windows that may cover at, with CameraIDs
+	// filled in, optionally only those covering cameraID. Callers still check
+	// OccurrenceAt for recurring windows.
+	ListCandidates(ctx context.Context, at time.Time, cameraID *uuid.UUID) ([]*domain.MaintenanceWindow, error)
+	ListCameraMaintenance(ctx context.Context) ([]*domain.CameraMaintenance, error)
+}
+
+type MaintenanceService interface {
+	CreateWindow(ctx context.Context, req *MaintenanceWindowRequest) (*domain.MaintenanceWindow, error)
+	GetWindow(ctx context.Context, id uuid.UUID) (*domain.MaintenanceWindow, error)
+	ListWindows(ctx context.Context, filter *MaintenanceWindowFilter) ([]*domain.MaintenanceWindow, int64, error)
+	UpdateWindow(ctx context.Context, id uuid.UUID, req *MaintenanceWindowRequest) (*domain.MaintenanceWindow, error)
+	DeleteWindow(ctx context.Context, id uuid.UUID, deletedBy *uuid.UUID) error
+
+	// Apply moves cameras into maintenance when a window starts and back
+	// online when it ends.
+	Apply(ctx context.Context, now time.Time) error
+	// Run calls Apply on the configured interval until ctx is done.
+	Run(ctx context.Context)
+}
+
+type MaintenanceWindowRequest struct {
+	Name        string                        `json:"name" binding:"required"`
+	CameraID    *uuid.UUID                    `json:"camera_id"`
+	ZoneID      *uuid.UUID                    `json:"zone_id"`
+	StartsAt    time.Time                     `json:"starts_at" binding:"required"`
+	EndsAt      time.Time                     `json:"ends_at" binding:"required"`
+	Recurrence  domain.MaintenanceRecurrence  `json:"recurrence"`   // Default none
+	RepeatUntil *time.Time                    `json:"repeat_until"` // Recurring windows only
+	Timezone    string                        `json:"timezone"`     // Default UTC
+	EventPolicy domain.MaintenanceEventPolicy `json:"event_policy"` // Default tag
+	Note        string                        `json:"note"`
+	RequestedBy *uuid.UUID                    `json:"-"`
+}
+
+type MaintenanceWindowFilter struct {
+	CameraID *uuid.UUID // Windows for the camera, its zone or any zone above it
+	ZoneID   *uuid.UUID
+	ActiveAt *time.Time // Only windows with an occurrence covering this time
+	Limit    int32
+	Offset   int32
+}
@@ -0,0 +1,35 @@
+package ports
+
+import (
+	"context"
+
+	"app/internal/core/domain"
+
+	"github.com/google/uuid"
+)
+
+type ManifestService interface {
+	Export(ctx context.Context, req *ManifestExportRequest) (*domain.SiteManifest, error)
+	// Plan compares the manifest with the current site without changing it.
+	Plan(ctx context.Context, manifest *domain.SiteManifest, prune bool) (*domain.ManifestPlan, error)
+	// Apply runs the plan in order: zones, cameras and their AI configs, then
+	// with prune the deletions. It stops at the first failure and reports it
+	// on the failing change.
+	Apply(ctx context.Context, req *ManifestApplyRequest) (*domain.ManifestPlan, error)
+
+	Decode(format string, data []byte) (*domain.SiteManifest, error)
+	Encode(format string, manifest *domain.SiteManifest) ([]byte, error)
+}
+
+// ManifestExportRequest includes camera passwords only when asked; the
+// caller checks the permission and the export is audited.
+type ManifestExportRequest struct {
+	IncludeCredentials bool
+	RequestedBy        *uuid.UUID
+}
+
+type ManifestApplyRequest struct {
+	Manifest    *domain.SiteManifest
+	Prune       bool
+	RequestedBy *uuid.UUID
+}
@@ -7,6 +7,7 @@ import (
 
 type FileStorage interface {
 	SaveFile(ctx context.Context, filename string, reader io.Reader) (string, error)
+	OpenFile(ctx context.Context, fileURL string) (io.ReadCloser, error)
 	DeleteFile(ctx context.Context, fileURL string) error
 }
 
@@ -0,0 +1,26 @@
+package ports
+
+import (
+	"context"
+
+	"app/internal/core/domain"
+
+	"github.com/google/uuid"
+)
+
+type NotificationRepository interface {
+	Create(ctx context.Context, n *domain.Notification) error
+	ListByUser(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit, offset int32) ([]*domain.Notification, error)
+	MarkRead(ctx context.Context, id, userID uuid.UUID) error
+}
+
+type NotificationService interface {
+	Notify(ctx context.Context, n *domain.Notification) error
+	ListNotifications(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit, offset int32) ([]*domain.Notification, error)
+	MarkRead(ctx context.Context, id, userID uuid.UUID) error
+}
+
+// EventPublisher publishes messages to the broker (Kafka).
+type EventPublisher interface {
+	Publish(ctx context.Context, topic string, key, value []byte) error
+}
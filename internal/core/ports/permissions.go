@@ -3,6 +3,8 @@ package ports
 import (
 	"context"
 
+	"app/internal/core/domain"
+
 	"github.com/google/uuid"
 )
 
@@ -25,4 +27,7 @@ type PermissionService interface {
 	UpdateUserCameraPermissions(ctx context.Context, userID uuid.UUID, cameraIDs []uuid.UUID) error
 	UpdateUserZonePermissions(ctx context.Context, userID uuid.UUID, zoneIDs []uuid.UUID) error
 	GetUserPermissions(ctx context.Context, userID uuid.UUID) (*UserPermissions, error)
+	// CameraScope returns the user's grants as a scope, or nil when the user
+	// has none and is therefore unrestricted.
+	CameraScope(ctx context.Context, userID uuid.UUID) (*domain.CameraScope, error)
 }
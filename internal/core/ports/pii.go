@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"context"
+
+	"app/internal/core/domain"
+
+	"github.com/google/uuid"
+)
+
+// PIIService decides who may see identity PII in API responses.
+type PIIService interface {
+	CanReadPII(ctx context.Context, userID uuid.UUID) bool
+	// HasPermission reports whether the user's role grants permission.
+	HasPermission(ctx context.Context, userID uuid.UUID, permission string) bool
+	MaskIdentity(identity *domain.Identity)
+	// MaskVersion masks a history entry's snapshot and changed values.
+	MaskVersion(version *domain.IdentityVersion)
+}
@@ -0,0 +1,55 @@
+package ports
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"app/internal/core/domain"
+
+	"github.com/google/uuid"
+)
+
+type RecognitionFeedbackRepository interface {
+	GetLog(ctx context.Context, id uuid.UUID) (*domain.RecognitionLog, error)
+	// ApplyFeedback stores fb and moves the log to fb.CorrectedIdentityID in
+	// one transaction.
+	ApplyFeedback(ctx context.Context, fb *domain.RecognitionFeedback) error
+	ListFeedback(ctx context.Context, filter *FeedbackFilter) ([]*domain.RecognitionFeedback, int64, error)
+	// EachLatestFeedback streams the latest verdict per log, oldest first.
+	EachLatestFeedback(ctx context.Context, filter *FeedbackFilter, fn func(*domain.RecognitionFeedback) error) error
+
+	// ListRecognitionTimes returns when the identity was recognized in [from, to).
+	ListRecognitionTimes(ctx context.Context, identityID uuid.UUID, from, to time.Time) ([]time.Time, error)
+	// SaveAttendance upserts the record for its identity and date.
+	SaveAttendance(ctx context.Context, record *domain.AttendanceRecord) error
+	// ClearAttendance marks an existing record absent; it creates nothing.
+	ClearAttendance(ctx context.Context, identityID uuid.UUID, date time.Time) (*domain.AttendanceRecord, error)
+}
+
+type RecognitionFeedbackService interface {
+	SubmitFeedback(ctx context.Context, logID uuid.UUID, req *RecognitionFeedbackRequest) (*domain.RecognitionReview, error)
+	ListFeedback(ctx context.Context, filter *FeedbackFilter) ([]*domain.RecognitionFeedback, int64, error)
+	// ExportDataset writes the latest verdict per log as JSON lines and
+	// audits the export.
+	ExportDataset(ctx context.Context, filter *FeedbackFilter, requestedBy *uuid.UUID, w io.Writer) (int, error)
+}
+
+// RecognitionFeedbackRequest confirms, rejects or reassigns a recognition.
+// IdentityID is the correct identity for reassign.
+type RecognitionFeedbackRequest struct {
+	Action     domain.FeedbackAction `json:"action" binding:"required"`
+	IdentityID *uuid.UUID            `json:"identity_id"`
+	Reason     string                `json:"reason"`
+	ReviewedBy *uuid.UUID            `json:"-"`
+}
+
+type FeedbackFilter struct {
+	Action     *domain.FeedbackAction
+	IdentityID *uuid.UUID // Original or corrected
+	CameraID   *uuid.UUID
+	FromDate   *time.Time
+	ToDate     *time.Time
+	Limit      int32
+	Offset     int32
+}
@@ -12,6 +12,7 @@ type RoleRepository interface {
 	List(ctx context.Context, search string) ([]*domain.Role, error)
 	Update(ctx context.Context, role *domain.Role) error
 	Delete(ctx context.Context, id string) error
+	GetUserPermissions(ctx context.Context, userID string) ([]string, error)
 }
 
 type RoleService interface {
@@ -0,0 +1,52 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"app/internal/core/domain"
+
+	"github.com/google/uuid"
+)
+
+type StrangerRepository interface {
+	// ListUnclustered pages through stranger logs with a face crop that no
+	// cluster holds yet, oldest first, starting after the (after, afterID) cursor.
+	ListUnclustered(ctx context.Context, after time.Time, afterID uuid.UUID, limit int) ([]*domain.RecognitionLog, error)
+	SetLogEmbedding(ctx context.Context, log *domain.RecognitionLog) error
+	ListOpenCentroids(ctx context.Context) ([]*domain.StrangerCluster, error)
+	// SaveCluster creates the cluster when its ID is nil, stores the centroid,
+	// assigns logIDs to it and refreshes its counts, cameras and seen times.
+	SaveCluster(ctx context.Context, cluster *domain.StrangerCluster, logIDs []uuid.UUID) error
+	ListClusters(ctx context.Context, filter *StrangerClusterFilter) ([]*domain.StrangerCluster, int64, error)
+	GetCluster(ctx context.Context, id uuid.UUID) (*domain.StrangerCluster, error)
+	ListClusterLogs(ctx context.Context, id uuid.UUID, limit, offset int32) ([]*domain.RecognitionLog, int64, error)
+	// PromoteCluster links the cluster and its logs to identityID.
+	PromoteCluster(ctx context.Context, id, identityID uuid.UUID, promotedBy *uuid.UUID) (int64, error)
+}
+
+type StrangerService interface {
+	StartClustering(ctx context.Context, requestedBy *uuid.UUID) (*domain.Job, error)
+	ListClusters(ctx context.Context, filter *StrangerClusterFilter) ([]*domain.StrangerCluster, int64, error)
+	GetCluster(ctx context.Context, id uuid.UUID) (*domain.StrangerCluster, error)
+	ListClusterLogs(ctx context.Context, id uuid.UUID, limit, offset int32) ([]*domain.RecognitionLog, int64, error)
+	PromoteCluster(ctx context.Context, id uuid.UUID, req *PromoteStrangerRequest) (*domain.StrangerPromotion, error)
+}
+
+type StrangerClusterFilter struct {
+	Status   *domain.StrangerClusterStatus
+	CameraID *uuid.UUID
+	Limit    int32
+	Offset   int32
+}
+
+// PromoteStrangerRequest turns a cluster into a pending identity. Code and
+// FullName default to the cluster label when empty.
+type PromoteStrangerRequest struct {
+	Code       string     `json:"code"`
+	FullName   string     `json:"full_name"`
+	Type       string     `json:"type"`
+	Department string     `json:"department"`
+	Note       string     `json:"note"`
+	PromotedBy *uuid.UUID `json:"-"`
+}
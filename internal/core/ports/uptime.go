@@ -0,0 +1,29 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"app/internal/core/domain"
+
+	"github.com/google/uuid"
+)
+
+type UptimeRepository interface {
+	ListCameras(ctx context.Context, zoneID *uuid.UUID) ([]*domain.UptimeCamera, error)
+	// ListStatusChanges returns the transitions in [from, to) plus the last
+	// one before from for each camera, ordered by camera, then time.
+	ListStatusChanges(ctx context.Context, from, to time.Time, zoneID *uuid.UUID) ([]*domain.CameraStatusChange, error)
+}
+
+type UptimeService interface {
+	GetUptimeReport(ctx context.Context, filter *UptimeFilter) (*domain.UptimeReport, error)
+	// WorstCameras ranks cameras by lowest uptime over the last days.
+	WorstCameras(ctx context.Context, days, limit int) ([]domain.CameraUptime, error)
+}
+
+type UptimeFilter struct {
+	FromDate time.Time
+	ToDate   time.Time
+	ZoneID   *uuid.UUID // Includes the zones below it
+}
@@ -11,7 +11,12 @@ type ZoneRepository interface {
 	GetByID(ctx context.Context, id string) (*domain.Zone, error)
 	List(ctx context.Context, search string) ([]*domain.Zone, error)
 	Update(ctx context.Context, zone *domain.Zone) error
+	// Move reparents the zone and rewrites the paths of its subtree; a nil
+	// parentID makes it a top-level zone.
+	Move(ctx context.Context, id string, parentID *string) error
 	Delete(ctx context.Context, id string) error
+	// CameraCounts counts the cameras in scope placed directly in each zone.
+	CameraCounts(ctx context.Context, scope *domain.CameraScope) (map[string]int, error)
 }
 
 type ZoneService interface {
@@ -20,4 +25,5 @@ type ZoneService interface {
 	ListZones(ctx context.Context, search string) ([]*domain.Zone, error)
 	UpdateZone(ctx context.Context, id string, req *domain.UpdateZoneRequest) (*domain.Zone, error)
 	DeleteZone(ctx context.Context, id string) error
+	ZoneTree(ctx context.Context, scope *domain.CameraScope) ([]*domain.ZoneNode, error)
 }
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+	"app/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	accessQueueSize     = 4096
+	accessBatchSize     = 200
+	accessFlushInterval = 2 * time.Second
+	accessWriteTimeout  = 10 * time.Second
+)
+
+// AccessAuditService batches entries through queue. mu guards closing it:
+// Record holds the read lock while sending, so Close never closes the
+// queue under a sender.
+type AccessAuditService struct {
+	repo   ports.AccessLogRepository
+	queue  chan *domain.AccessLog
+	done   chan struct{}
+	mu     sync.RWMutex
+	closed bool
+}
+
+func NewAccessAuditService(repo ports.AccessLogRepository) ports.AccessAuditService {
+	s := &AccessAuditService{
+		repo:  repo,
+		queue: make(chan *domain.AccessLog, accessQueueSize),
+		done:  make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// Record queues an entry. When the queue is full the entry is dropped and
+// logged rather than slowing down the request. Requests still running after
+// Close write their entry directly.
+func (s *AccessAuditService) Record(log *domain.AccessLog) {
+	if log.CreatedAt.IsZero() {
+		log.CreatedAt = time.Now()
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		s.flush([]*domain.AccessLog{log})
+		return
+	}
+	select {
+	case s.queue <- log:
+	default:
+		logger.Error("Access audit queue full, dropping entry",
+			zap.String("resource_type", log.ResourceType), zap.String("route", log.Route))
+	}
+}
+
+func (s *AccessAuditService) Close() {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.queue)
+	}
+	s.mu.Unlock()
+	<-s.done
+}
+
+func (s *AccessAuditService) run() {
+	defer close(s.done)
+	ticker := time.NewTicker(accessFlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*domain.AccessLog, 0, accessBatchSize)
+	for {
+		select {
+		case log, ok := <-s.queue:
+			if !ok {
+				s.flush(batch)
+				return
+			}
+			batch = append(batch, log)
+			if len(batch) >= accessBatchSize {
+				s.flush(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			s.flush(batch)
+			batch = batch[:0]
+		}
+	}
+}
+
+func (s *AccessAuditService) flush(batch []*domain.AccessLog) {
+	if len(batch) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), accessWriteTimeout)
+	defer cancel()
+	if err := s.repo.CreateAccessLogs(ctx, batch); err != nil {
+		logger.Error("Failed to write access logs", zap.Int("entries", len(batch)), zap.Error(err))
+	}
+}
+
+func (s *AccessAuditService) IdentityAccessReport(ctx context.Context, identityID uuid.UUID, filter *ports.AccessLogFilter) (*domain.IdentityAccessReport, error) {
+	filter.IdentityID = &identityID
+	accessors, err := s.repo.SummarizeIdentityAccess(ctx, identityID, filter.FromDate, filter.ToDate)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.repo.ListAccessLogs(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.IdentityAccessReport{
+		IdentityID: identityID,
+		From:       filter.FromDate,
+		To:         filter.ToDate,
+		Accessors:  accessors,
+		Entries:    entries,
+	}, nil
+}
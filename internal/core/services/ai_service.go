@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"time"
 
 	"app/internal/core/domain"
 	"app/internal/core/ports"
@@ -10,25 +11,117 @@ import (
 )
 
 type AIService struct {
-	repo ports.AIRepository
+	repo        ports.AIRepository
+	maintenance ports.MaintenanceRepository
 }
 
-func NewAIService(repo ports.AIRepository) ports.AIService {
-	return &AIService{repo: repo}
+func NewAIService(repo ports.AIRepository, maintenance ports.MaintenanceRepository) ports.AIService {
+	return &AIService{repo: repo, maintenance: maintenance}
 }
 
 func (s *AIService) GetConfig(ctx context.Context, cameraID uuid.UUID) (*domain.AIConfig, error) {
 	return s.repo.GetConfigByCamera(ctx, cameraID)
 }
 
+// UpdateConfig rejects a config the edge could not run with an
+// *domain.AIConfigError naming each bad field. A template link is kept, so
+// a hand edit shows as drift from the template.
 func (s *AIService) UpdateConfig(ctx context.Context, req *domain.AIConfig) error {
+	req.Normalize()
+	if err := req.Validate(); err != nil {
+		return err
+	}
+	current, err := s.repo.GetConfigByCamera(ctx, req.CameraID)
+	if err != nil {
+		return err
+	}
+	req.TemplateID, req.Overrides = nil, nil
+	if current != nil {
+		req.TemplateID, req.Overrides = current.TemplateID, current.Overrides
+	}
+	req.Source = domain.AIConfigSourceManual
 	return s.repo.SaveConfig(ctx, req)
 }
 
+func (s *AIService) ListConfigVersions(ctx context.Context, cameraID uuid.UUID) ([]*domain.AIConfigVersion, error) {
+	return s.repo.ListConfigVersions(ctx, cameraID)
+}
+
+func (s *AIService) GetConfigVersion(ctx context.Context, cameraID uuid.UUID, version int) (*domain.AIConfigVersion, error) {
+	v, err := s.repo.GetConfigVersion(ctx, cameraID, version)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, domain.ErrAIConfigVersionNotFound
+	}
+	return v, nil
+}
+
+func (s *AIService) RollbackConfig(ctx context.Context, cameraID uuid.UUID, version int, requestedBy *uuid.UUID) (*domain.AIConfig, error) {
+	v, err := s.GetConfigVersion(ctx, cameraID, version)
+	if err != nil {
+		return nil, err
+	}
+	config := &domain.AIConfig{
+		CameraID:   cameraID,
+		AISettings: v.AISettings,
+		TemplateID: v.TemplateID,
+		Overrides:  v.Overrides,
+		Source:     domain.AIConfigSourceRollback,
+		UpdatedBy:  requestedBy,
+	}
+	config.Normalize()
+	if err := s.repo.SaveConfig(ctx, config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func (s *AIService) DetachTemplate(ctx context.Context, cameraID uuid.UUID, requestedBy *uuid.UUID) (*domain.AIConfig, error) {
+	config, err := s.repo.GetConfigByCamera(ctx, cameraID)
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		return nil, domain.ErrAIConfigNotFound
+	}
+	if config.TemplateID == nil {
+		return config, nil
+	}
+	config.TemplateID, config.Overrides = nil, nil
+	config.Source = domain.AIConfigSourceManual
+	config.UpdatedBy = requestedBy
+	if err := s.repo.SaveConfig(ctx, config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// CreateEvent drops or tags events from a camera in a maintenance window,
+// as the window's event policy says.
 func (s *AIService) CreateEvent(ctx context.Context, event *domain.AIEvent) (*domain.AIEvent, error) {
 	if event.Status == "" {
 		event.Status = domain.EventStatusNew
 	}
+	at := event.CreatedAt
+	if at.IsZero() {
+		at = time.Now()
+	}
+	window, err := activeWindowFor(ctx, s.maintenance, event.CameraID, at)
+	if err != nil {
+		return nil, err
+	}
+	if window != nil {
+		if window.EventPolicy == domain.MaintenanceEventsDrop {
+			return nil, domain.ErrEventSuppressed
+		}
+		if event.Metadata == nil {
+			event.Metadata = map[string]any{}
+		}
+		event.Metadata["maintenance_window_id"] = window.ID
+		event.Metadata["maintenance_window"] = window.Name
+	}
 	return s.repo.CreateEvent(ctx, event)
 }
 
@@ -36,20 +129,26 @@ func (s *AIService) ListEvents(ctx context.Context, filter *ports.EventFilter) (
 	return s.repo.ListEvents(ctx, filter.CameraID, filter.EventType, filter.Status, filter.FromDate, filter.ToDate, filter.Limit, filter.Offset)
 }
 
-func (s *AIService) UpdateEventStatus(ctx context.Context, id uuid.UUID, status domain.EventStatus, resolvedBy uuid.UUID) (*domain.AIEvent, error) {
-	return s.repo.UpdateEventStatus(ctx, id, status, &resolvedBy)
+func (s *AIService) UpdateEventStatus(ctx context.Context, id uuid.UUID, req *ports.UpdateEventRequest) (*domain.AIEvent, error) {
+	if req.Status == nil && req.Verdict == nil {
+		return nil, domain.ErrEventUpdateEmpty
+	}
+	if req.Verdict != nil && *req.Verdict != domain.EventVerdictTruePositive && *req.Verdict != domain.EventVerdictFalsePositive {
+		return nil, domain.ErrInvalidEventVerdict
+	}
+	return s.repo.UpdateEventStatus(ctx, id, req.Status, req.Verdict, req.ResolvedBy)
 }
 
-func (s *AIService) GetDashboardStats(ctx context.Context) (*domain.DashboardStats, error) {
-	total, online, offline, maintenance, err := s.repo.GetDashboardStats(ctx)
+func (s *AIService) GetDashboardStats(ctx context.Context, filter *ports.DashboardFilter) (*domain.DashboardStats, error) {
+	total, online, offline, maintenance, err := s.repo.GetDashboardStats(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
 
-	todayEvents, _ := s.repo.GetTodayEventsCount(ctx)
+	todayEvents, _ := s.repo.GetTodayEventsCount(ctx, filter)
 
 	// Fetch recent events (optional, can be separate call or limit filter)
-	recent, _ := s.repo.ListEvents(ctx, nil, nil, nil, nil, nil, 5, 0)
+	recent, _ := s.repo.ListRecentEvents(ctx, filter, 5)
 
 	return &domain.DashboardStats{
 		TotalCameras:       int(total),
@@ -0,0 +1,309 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+	"app/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type AITemplateService struct {
+	repo    ports.AITemplateRepository
+	ai      ports.AIRepository
+	cameras ports.CameraRepository
+	audit   ports.AuditService
+}
+
+func NewAITemplateService(repo ports.AITemplateRepository, ai ports.AIRepository, cameras ports.CameraRepository, audit ports.AuditService) ports.AITemplateService {
+	return &AITemplateService{
+		repo:    repo,
+		ai:      ai,
+		cameras: cameras,
+		audit:   audit,
+	}
+}
+
+func (s *AITemplateService) CreateTemplate(ctx context.Context, req *ports.AITemplateRequest) (*domain.AIConfigTemplate, error) {
+	template := &domain.AIConfigTemplate{
+		Name:        strings.TrimSpace(req.Name),
+		Description: req.Description,
+		AISettings:  req.AISettings,
+		CreatedBy:   req.RequestedBy,
+	}
+	if err := s.check(ctx, template); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Save(ctx, template); err != nil {
+		logger.Error("Failed to create AI config template", zap.Error(err))
+		return nil, err
+	}
+	s.logAction(ctx, req.RequestedBy, "CREATE_AI_TEMPLATE", template.ID, nil, templateAuditValue(template))
+	return template, nil
+}
+
+func (s *AITemplateService) GetTemplate(ctx context.Context, id uuid.UUID) (*domain.AIConfigTemplate, error) {
+	template, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if template == nil {
+		return nil, domain.ErrAITemplateNotFound
+	}
+	return template, nil
+}
+
+func (s *AITemplateService) ListTemplates(ctx context.Context) ([]*domain.AIConfigTemplate, error) {
+	return s.repo.List(ctx)
+}
+
+// UpdateTemplate leaves linked cameras alone; they drift until the
+// template is applied to them again.
+func (s *AITemplateService) UpdateTemplate(ctx context.Context, id uuid.UUID, req *ports.AITemplateRequest) (*domain.AIConfigTemplate, error) {
+	template, err := s.GetTemplate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	old := templateAuditValue(template)
+	template.Name = strings.TrimSpace(req.Name)
+	template.Description = req.Description
+	template.AISettings = req.AISettings
+	if err := s.check(ctx, template); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, template); err != nil {
+		return nil, err
+	}
+	s.logAction(ctx, req.RequestedBy, "UPDATE_AI_TEMPLATE", id, old, templateAuditValue(template))
+	return template, nil
+}
+
+func (s *AITemplateService) DeleteTemplate(ctx context.Context, id uuid.UUID, requestedBy *uuid.UUID) error {
+	template, err := s.GetTemplate(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.logAction(ctx, requestedBy, "DELETE_AI_TEMPLATE", id, templateAuditValue(template), nil)
+	return nil
+}
+
+// check normalizes and validates the template, and makes sure its name is
+// free.
+func (s *AITemplateService) check(ctx context.Context, template *domain.AIConfigTemplate) error {
+	template.Normalize()
+	if err := template.Validate(); err != nil {
+		return err
+	}
+	existing, err := s.repo.GetByName(ctx, template.Name)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != template.ID {
+		return domain.ErrAITemplateNameTaken
+	}
+	return nil
+}
+
+// ApplyTemplate saves the template's settings, with each camera's
+// overrides, as a new config version of every targeted camera. A camera
+// whose result is invalid is reported and skipped; the rest are applied.
+func (s *AITemplateService) ApplyTemplate(ctx context.Context, id uuid.UUID, req *ports.ApplyAITemplateRequest) (*domain.AITemplateApplyResult, error) {
+	template, err := s.GetTemplate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.CameraIDs) == 0 && req.ZoneID == nil {
+		return nil, domain.ErrAITemplateTargets
+	}
+
+	result := &domain.AITemplateApplyResult{
+		TemplateID: id,
+		Applied:    []uuid.UUID{},
+		Unchanged:  []uuid.UUID{},
+		Failed:     []domain.AITemplateApplyFailure{},
+	}
+	targets := []uuid.UUID{}
+	seen := map[uuid.UUID]bool{}
+	for _, cameraID := range req.CameraIDs {
+		if seen[cameraID] {
+			continue
+		}
+		seen[cameraID] = true
+		camera, err := s.cameras.GetByID(ctx, cameraID.String())
+		if err != nil {
+			return nil, err
+		}
+		if camera == nil {
+			result.Failed = append(result.Failed, domain.AITemplateApplyFailure{CameraID: cameraID, Error: domain.ErrCameraNotFound.Error()})
+			continue
+		}
+		targets = append(targets, cameraID)
+	}
+	if req.ZoneID != nil {
+		cameras, err := s.cameras.Find(ctx, &ports.CameraFilter{ZoneID: req.ZoneID.String()})
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range cameras {
+			cameraID, err := uuid.Parse(c.ID)
+			if err != nil || seen[cameraID] {
+				continue
+			}
+			seen[cameraID] = true
+			targets = append(targets, cameraID)
+		}
+	}
+
+	overrides := map[uuid.UUID]*domain.AIConfigOverrides{}
+	for key, o := range req.Overrides {
+		cameraID, err := uuid.Parse(key)
+		if err != nil || !slices.Contains(targets, cameraID) {
+			return nil, domain.ErrAITemplateOverride
+		}
+		// An empty entry clears the camera's overrides
+		overrides[cameraID] = nil
+		if !o.IsEmpty() {
+			overrides[cameraID] = &o
+		}
+	}
+
+	for _, cameraID := range targets {
+		current, err := s.ai.GetConfigByCamera(ctx, cameraID)
+		if err != nil {
+			return nil, err
+		}
+		o, given := overrides[cameraID]
+		if !given && current != nil && current.TemplateID != nil && *current.TemplateID == id {
+			o = current.Overrides
+		}
+		config := &domain.AIConfig{
+			CameraID:   cameraID,
+			AISettings: o.Apply(template.AISettings),
+			TemplateID: &id,
+			Overrides:  o,
+			Source:     domain.AIConfigSourceTemplate,
+			UpdatedBy:  req.RequestedBy,
+		}
+		config.Normalize()
+		if err := config.Validate(); err != nil {
+			failure := domain.AITemplateApplyFailure{CameraID: cameraID, Error: err.Error()}
+			var invalid *domain.AIConfigError
+			if errors.As(err, &invalid) {
+				failure.Fields = invalid.Fields
+			}
+			result.Failed = append(result.Failed, failure)
+			continue
+		}
+		if current != nil && current.TemplateID != nil && *current.TemplateID == id &&
+			sameOverrides(current.Overrides, o) && len(current.Diff(&config.AISettings)) == 0 {
+			result.Unchanged = append(result.Unchanged, cameraID)
+			continue
+		}
+		if err := s.ai.SaveConfig(ctx, config); err != nil {
+			logger.Error("Failed to apply AI config template", zap.String("camera_id", cameraID.String()), zap.Error(err))
+			result.Failed = append(result.Failed, domain.AITemplateApplyFailure{CameraID: cameraID, Error: err.Error()})
+			continue
+		}
+		result.Applied = append(result.Applied, cameraID)
+	}
+
+	s.logAction(ctx, req.RequestedBy, "APPLY_AI_TEMPLATE", id, nil, map[string]any{
+		"name":      template.Name,
+		"applied":   len(result.Applied),
+		"unchanged": len(result.Unchanged),
+		"failed":    len(result.Failed),
+	})
+	return result, nil
+}
+
+// TemplateDrift compares every linked camera with what the template and
+// its overrides give now, drifted cameras first.
+func (s *AITemplateService) TemplateDrift(ctx context.Context, id uuid.UUID) ([]*domain.AIConfigDrift, error) {
+	template, err := s.GetTemplate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	configs, err := s.ai.ListConfigsByTemplate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	cameras, err := s.cameras.List(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	names := map[string]string{}
+	for _, c := range cameras {
+		names[c.ID] = c.Name
+	}
+
+	drift := []*domain.AIConfigDrift{}
+	for _, c := range configs {
+		expected := c.Overrides.Apply(template.AISettings)
+		expected.Normalize()
+		fields := expected.Diff(&c.AISettings)
+		if fields == nil {
+			fields = []string{}
+		}
+		drift = append(drift, &domain.AIConfigDrift{
+			CameraID:   c.CameraID,
+			CameraName: names[c.CameraID.String()],
+			Version:    c.Version,
+			Drifted:    len(fields) > 0,
+			Fields:     fields,
+		})
+	}
+	slices.SortStableFunc(drift, func(a, b *domain.AIConfigDrift) int {
+		if a.Drifted != b.Drifted {
+			if a.Drifted {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.CameraName, b.CameraName)
+	})
+	return drift, nil
+}
+
+func (s *AITemplateService) logAction(ctx context.Context, userID *uuid.UUID, action string, id uuid.UUID, oldValue, newValue map[string]any) {
+	if err := s.audit.LogAction(ctx, &domain.AuditLog{
+		UserID:    userID,
+		Action:    action,
+		TableName: "ai_config_templates",
+		RecordID:  id.String(),
+		OldValue:  oldValue,
+		NewValue:  newValue,
+	}); err != nil {
+		logger.Error("Failed to audit AI config template", zap.String("template_id", id.String()), zap.Error(err))
+	}
+}
+
+func sameOverrides(a, b *domain.AIConfigOverrides) bool {
+	if a.IsEmpty() || b.IsEmpty() {
+		return a.IsEmpty() == b.IsEmpty()
+	}
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(ja) == string(jb)
+}
+
+func templateAuditValue(t *domain.AIConfigTemplate) map[string]any {
+	return map[string]any{
+		"name":           t.Name,
+		"ai_enabled":     t.AIEnabled,
+		"ai_types":       t.AITypes,
+		"roi_zones":      len(t.ROIZones),
+		"active_hours":   t.ActiveHours != nil,
+		"sensitivity":    t.Sensitivity,
+		"min_confidence": t.MinConfidence,
+	}
+}
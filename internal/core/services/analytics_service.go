@@ -6,18 +6,28 @@ import (
 
 	"app/internal/core/domain"
 	"app/internal/core/ports"
+
+	"github.com/google/uuid"
+)
+
+const (
+	timelineDefaultRange = 7 * 24 * time.Hour
+	timelineDefaultGap   = 10 * time.Minute
+	timelineMaxSightings = 10000
+	timelineMaxSnapshots = 5 // Per visit, spread from first to last sighting
 )
 
 type AnalyticsService struct {
-	repo ports.AnalyticsRepository
+	repo       ports.AnalyticsRepository
+	identities ports.IdentityRepository
 }
 
-func NewAnalyticsService(repo ports.AnalyticsRepository) ports.AnalyticsService {
-	return &AnalyticsService{repo: repo}
+func NewAnalyticsService(repo ports.AnalyticsRepository, identities ports.IdentityRepository) ports.AnalyticsService {
+	return &AnalyticsService{repo: repo, identities: identities}
 }
 
 func (s *AnalyticsService) ListRecognitionLogs(ctx context.Context, filter *ports.RecognitionFilter) ([]*domain.RecognitionLog, error) {
-	return s.repo.ListRecognitionLogs(ctx, filter.IdentityID, filter.CameraID, filter.FromDate, filter.ToDate, filter.Limit, filter.Offset)
+	return s.repo.ListRecognitionLogs(ctx, filter)
 }
 
 func (s *AnalyticsService) ListAttendance(ctx context.Context, filter *ports.AttendanceFilter) ([]*domain.AttendanceRecord, error) {
@@ -27,3 +37,145 @@ func (s *AnalyticsService) ListAttendance(ctx context.Context, filter *ports.Att
 func (s *AnalyticsService) GetDailyAttendanceSummary(ctx context.Context, date time.Time) (any, error) {
 	return s.repo.GetAttendanceStats(ctx, date)
 }
+
+// GetTimeline merges the identity's recognitions across cameras into visits,
+// zone transitions and per-zone totals. The range defaults to the last week.
+func (s *AnalyticsService) GetTimeline(ctx context.Context, identityID uuid.UUID, filter *ports.TimelineFilter) (*domain.PersonTimeline, error) {
+	identity, err := s.identities.GetIdentity(ctx, identityID)
+	if err != nil || identity == nil {
+		return nil, domain.ErrIdentityNotFound
+	}
+
+	to := time.Now()
+	if filter.ToDate != nil {
+		to = *filter.ToDate
+	}
+	from := to.Add(-timelineDefaultRange)
+	if filter.FromDate != nil {
+		from = *filter.FromDate
+	}
+	gap := filter.MaxGap
+	if gap <= 0 {
+		gap = timelineDefaultGap
+	}
+
+	sightings, err := s.repo.ListSightings(ctx, identityID, from, to, timelineMaxSightings+1)
+	if err != nil {
+		return nil, err
+	}
+	timeline := &domain.PersonTimeline{IdentityID: identityID, From: from, To: to}
+	if len(sightings) > timelineMaxSightings {
+		sightings = sightings[:timelineMaxSightings]
+		timeline.Truncated = true
+	}
+	buildTimeline(timeline, sightings, gap)
+	return timeline, nil
+}
+
+func buildTimeline(timeline *domain.PersonTimeline, sightings []*domain.Sighting, gap time.Duration) {
+	timeline.Sightings = len(sightings)
+	timeline.Visits = []domain.TimelineVisit{}
+	timeline.Transitions = []domain.ZoneTransition{}
+	timeline.Zones = []domain.ZoneSummary{}
+
+	var visitSightings [][]*domain.Sighting
+	for i, s := range sightings {
+		if i > 0 {
+			prev := sightings[i-1]
+			if prev.CameraID == s.CameraID && s.OccurredAt.Sub(prev.OccurredAt) <= gap {
+				visitSightings[len(visitSightings)-1] = append(visitSightings[len(visitSightings)-1], s)
+				continue
+			}
+		}
+		visitSightings = append(visitSightings, []*domain.Sighting{s})
+	}
+
+	zoneIndex := map[uuid.UUID]int{}
+	unzoned := -1
+	for _, group := range visitSightings {
+		first, last := group[0], group[len(group)-1]
+		visit := domain.TimelineVisit{
+			CameraID:     first.CameraID,
+			CameraName:   first.CameraName,
+			ZoneID:       first.ZoneID,
+			ZoneName:     first.ZoneName,
+			StartAt:      first.OccurredAt,
+			EndAt:        last.OccurredAt,
+			DwellSeconds: last.OccurredAt.Sub(first.OccurredAt).Seconds(),
+			Sightings:    len(group),
+			Snapshots:    visitSnapshots(group),
+		}
+		for _, s := range group {
+			if s.Confidence > visit.MaxConfidence {
+				visit.MaxConfidence = s.Confidence
+			}
+		}
+
+		if n := len(timeline.Visits); n > 0 {
+			prev := timeline.Visits[n-1]
+			if !sameZone(prev.ZoneID, visit.ZoneID) {
+				timeline.Transitions = append(timeline.Transitions, domain.ZoneTransition{
+					FromZoneID:   prev.ZoneID,
+					FromZoneName: prev.ZoneName,
+					ToZoneID:     visit.ZoneID,
+					ToZoneName:   visit.ZoneName,
+					LeftAt:       prev.EndAt,
+					EnteredAt:    visit.StartAt,
+				})
+			}
+		}
+		timeline.Visits = append(timeline.Visits, visit)
+
+		idx, ok := unzoned, unzoned >= 0
+		if visit.ZoneID != nil {
+			idx, ok = zoneIndex[*visit.ZoneID]
+		}
+		if !ok {
+			timeline.Zones = append(timeline.Zones, domain.ZoneSummary{
+				ZoneID:      visit.ZoneID,
+				ZoneName:    visit.ZoneName,
+				FirstSeenAt: visit.StartAt,
+			})
+			idx = len(timeline.Zones) - 1
+			if visit.ZoneID != nil {
+				zoneIndex[*visit.ZoneID] = idx
+			} else {
+				unzoned = idx
+			}
+		}
+		zone := &timeline.Zones[idx]
+		zone.Visits++
+		zone.Sightings += visit.Sightings
+		zone.DwellSeconds += visit.DwellSeconds
+		zone.LastSeenAt = visit.EndAt
+	}
+}
+
+// visitSnapshots picks up to timelineMaxSnapshots snapshots spread evenly
+// over the visit, always including the first and last.
+func visitSnapshots(group []*domain.Sighting) []string {
+	var urls []string
+	for _, s := range group {
+		if s.SnapshotURL != "" {
+			urls = append(urls, s.SnapshotURL)
+		}
+	}
+	if len(urls) <= timelineMaxSnapshots {
+		if urls == nil {
+			return []string{}
+		}
+		return urls
+	}
+	out := make([]string, timelineMaxSnapshots)
+	for i := range out {
+		out[i] = urls[i*(len(urls)-1)/(timelineMaxSnapshots-1)]
+	}
+	return out
+}
+
+func sameZone(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"time"
+
+	"app/config"
+	"app/internal/core/domain"
+	"app/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// attendanceRules rebuilds a day's attendance record from the times an
+// identity was recognized.
+type attendanceRules struct {
+	loc   *time.Location
+	start time.Duration // Offset of the work day start from midnight
+	end   time.Duration
+	grace time.Duration
+}
+
+func newAttendanceRules(cfg config.AttendanceConfig) attendanceRules {
+	rules := attendanceRules{
+		loc:   time.UTC,
+		start: 8 * time.Hour,
+		end:   17 * time.Hour,
+		grace: time.Duration(cfg.LateGraceMinutes) * time.Minute,
+	}
+	if cfg.Timezone != "" {
+		if loc, err := time.LoadLocation(cfg.Timezone); err == nil {
+			rules.loc = loc
+		} else {
+			logger.Error("Invalid attendance timezone, using UTC", zap.String("timezone", cfg.Timezone), zap.Error(err))
+		}
+	}
+	if d, ok := clockOffset(cfg.WorkStart); ok {
+		rules.start = d
+	}
+	if d, ok := clockOffset(cfg.WorkEnd); ok {
+		rules.end = d
+	}
+	return rules
+}
+
+func clockOffset(clock string) (time.Duration, bool) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, false
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, true
+}
+
+// dateOf returns the local calendar date of t as midnight UTC, the form the
+// attendance_records.date column is written with.
+func (r attendanceRules) dateOf(t time.Time) time.Time {
+	y, m, d := t.In(r.loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// bounds returns the local day [from, to) for a date from dateOf.
+func (r attendanceRules) bounds(date time.Time) (time.Time, time.Time) {
+	from := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, r.loc)
+	return from, from.AddDate(0, 0, 1)
+}
+
+// build derives the record from sorted recognition times: first sighting is
+// the check-in, last is the check-out. Early leave is only decided once the
+// day is over.
+func (r attendanceRules) build(identityID uuid.UUID, date time.Time, times []time.Time, now time.Time) *domain.AttendanceRecord {
+	record := &domain.AttendanceRecord{IdentityID: identityID, Date: date, Status: domain.AttendanceAbsent}
+	if len(times) == 0 {
+		return record
+	}
+
+	checkIn := times[0]
+	record.CheckIn = &checkIn
+	if last := times[len(times)-1]; last.After(checkIn) {
+		record.CheckOut = &last
+		record.WorkHours = last.Sub(checkIn).Hours()
+	}
+
+	dayStart, dayEnd := r.bounds(date)
+	switch {
+	case checkIn.After(dayStart.Add(r.start + r.grace)):
+		record.Status = domain.AttendanceLate
+	case !now.Before(dayEnd) && (record.CheckOut == nil || record.CheckOut.Before(dayStart.Add(r.end))):
+		record.Status = domain.AttendanceEarlyLeave
+	default:
+		record.Status = domain.AttendanceOnTime
+	}
+	return record
+}
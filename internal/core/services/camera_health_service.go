@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"app/config"
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+	"app/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+type CameraHealthService struct {
+	repo        ports.CameraHealthRepository
+	cameras     ports.CameraRepository
+	maintenance ports.MaintenanceRepository
+	prober      ports.CameraProber
+	events      ports.AIRepository
+	cfg         config.CameraHealthConfig
+}
+
+func NewCameraHealthService(
+	repo ports.CameraHealthRepository,
+	cameras ports.CameraRepository,
+	maintenance ports.MaintenanceRepository,
+	prober ports.CameraProber,
+	events ports.AIRepository,
+	cfg config.CameraHealthConfig,
+) ports.CameraHealthService {
+	if cfg.IntervalSeconds <= 0 {
+		cfg.IntervalSeconds = 30
+	}
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = 3
+	}
+	if cfg.RecoveryThreshold <= 0 {
+		cfg.RecoveryThreshold = 2
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 8
+	}
+	return &CameraHealthService{repo: repo, cameras: cameras, maintenance: maintenance, prober: prober, events: events, cfg: cfg}
+}
+
+func (s *CameraHealthService) Run(ctx context.Context) {
+	interval := time.Duration(s.cfg.IntervalSeconds) * time.Second
+	logger.Info("Camera health monitor started", zap.Duration("interval", interval))
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := s.CheckAll(ctx); err != nil {
+			logger.Error("Camera health check failed", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *CameraHealthService) CheckAll(ctx context.Context) error {
+	cameras, err := s.repo.ListMonitored(ctx)
+	if err != nil {
+		return err
+	}
+
+	sem := make(chan struct{}, s.cfg.Concurrency)
+	var wg sync.WaitGroup
+	for _, camera := range cameras {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(camera *domain.CameraHealth) {
+			defer func() { <-sem; wg.Done() }()
+			s.check(ctx, camera)
+		}(camera)
+	}
+	wg.Wait()
+	return nil
+}
+
+// check probes one camera and moves it online or offline once enough probes
+// in a row agree.
+func (s *CameraHealthService) check(ctx context.Context, camera *domain.CameraHealth) {
+	probe := s.prober.Probe(ctx, camera.StreamURL)
+	if ctx.Err() != nil {
+		return
+	}
+	if probe.Healthy {
+		camera.Successes++
+		camera.Failures = 0
+	} else {
+		camera.Failures++
+		camera.Successes = 0
+	}
+	if err := s.repo.SaveProbe(ctx, camera, probe); err != nil {
+		logger.Error("Failed to save camera probe", zap.String("camera_id", camera.CameraID.String()), zap.Error(err))
+	}
+
+	next := nextCameraStatus(camera, s.cfg)
+	if next == camera.Status {
+		return
+	}
+	// A window may have started since the last scheduler pass; its camera
+	// going dark is expected, not an outage
+	if next == domain.CameraStatusOffline {
+		window, err := activeWindowFor(ctx, s.maintenance, camera.CameraID, probe.ProbedAt)
+		if err != nil {
+			logger.Error("Failed to check maintenance windows", zap.String("camera_id", camera.CameraID.String()), zap.Error(err))
+		}
+		if window != nil {
+			return
+		}
+	}
+	latency := int(probe.Latency.Milliseconds())
+	change := &domain.CameraStatusChange{
+		CameraID:   camera.CameraID,
+		FromStatus: camera.Status,
+		ToStatus:   next,
+		Source:     domain.CameraStatusSourceMonitor,
+		Reason:     probe.Error,
+		LatencyMs:  &latency,
+	}
+	if err := s.repo.ChangeStatus(ctx, change); err != nil {
+		if errors.Is(err, domain.ErrCameraStatusChanged) {
+			// Changed by hand or by a maintenance window since it was listed;
+			// the next round starts from the new status
+			return
+		}
+		logger.Error("Failed to change camera status", zap.String("camera_id", camera.CameraID.String()), zap.Error(err))
+		return
+	}
+	logger.Info("Camera status changed", zap.String("camera_id", camera.CameraID.String()),
+		zap.String("from", string(change.FromStatus)), zap.String("to", string(next)), zap.String("reason", probe.Error))
+
+	if next == domain.CameraStatusOffline {
+		event := &domain.AIEvent{
+			CameraID:   camera.CameraID,
+			EventType:  domain.EventTypeCameraOffline,
+			Confidence: 1,
+			Status:     domain.EventStatusNew,
+			Metadata: map[string]any{
+				"camera_name":   camera.Name,
+				"reason":        probe.Error,
+				"failed_probes": camera.Failures,
+				"last_seen_at":  camera.LastSeenAt,
+			},
+		}
+		if _, err := s.events.CreateEvent(ctx, event); err != nil {
+			logger.Error("Failed to raise camera offline event", zap.String("camera_id", camera.CameraID.String()), zap.Error(err))
+		}
+	}
+}
+
+// nextCameraStatus applies hysteresis. Any status other than offline is
+// treated as online, so a camera saved with an unknown status is still
+// watched.
+func nextCameraStatus(camera *domain.CameraHealth, cfg config.CameraHealthConfig) domain.CameraStatus {
+	if camera.Status == domain.CameraStatusOffline {
+		if camera.Successes >= cfg.RecoveryThreshold {
+			return domain.CameraStatusOnline
+		}
+		return camera.Status
+	}
+	if camera.Failures >= cfg.FailureThreshold {
+		return domain.CameraStatusOffline
+	}
+	return camera.Status
+}
+
+func (s *CameraHealthService) ListStatusHistory(ctx context.Context, filter *ports.CameraStatusHistoryFilter) ([]*domain.CameraStatusChange, int64, error) {
+	camera, err := s.cameras.GetByID(ctx, filter.CameraID.String())
+	if err != nil {
+		return nil, 0, err
+	}
+	if camera == nil {
+		return nil, 0, domain.ErrCameraNotFound
+	}
+	if filter.Limit < 1 {
+		filter.Limit = 20
+	}
+	return s.repo.ListStatusHistory(ctx, filter)
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"app/config"
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+	"app/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type fakeHealthRepo struct {
+	ports.CameraHealthRepository
+	changes   []*domain.CameraStatusChange
+	changeErr error
+}
+
+func (r *fakeHealthRepo) SaveProbe(context.Context, *domain.CameraHealth, *domain.CameraProbe) error {
+	return nil
+}
+
+func (r *fakeHealthRepo) ChangeStatus(_ context.Context, change *domain.CameraStatusChange) error {
+	if r.changeErr != nil {
+		return r.changeErr
+	}
+	r.changes = append(r.changes, change)
+	return nil
+}
+
+type fakeMaintenanceRepo struct {
+	ports.MaintenanceRepository
+}
+
+func (fakeMaintenanceRepo) ListCandidates(context.Context, time.Time, *uuid.UUID) ([]*domain.MaintenanceWindow, error) {
+	return nil, nil
+}
+
+type fakeEventRepo struct {
+	ports.AIRepository
+	events []*domain.AIEvent
+}
+
+func (r *fakeEventRepo) CreateEvent(_ context.Context, event *domain.AIEvent) (*domain.AIEvent, error) {
+	r.events = append(r.events, event)
+	return event, nil
+}
+
+// scriptedProber answers each probe with the next result in healthy.
+type scriptedProber struct {
+	healthy []bool
+}
+
+func (p *scriptedProber) Probe(context.Context, string) *domain.CameraProbe {
+	ok := p.healthy[0]
+	p.healthy = p.healthy[1:]
+	probe := &domain.CameraProbe{ProbedAt: time.Now(), Healthy: ok, Reachable: ok}
+	if !ok {
+		probe.Error = "connect: connection refused"
+	}
+	return probe
+}
+
+func TestNextCameraStatus(t *testing.T) {
+	cfg := config.CameraHealthConfig{FailureThreshold: 3, RecoveryThreshold: 2}
+	tests := []struct {
+		name      string
+		status    domain.CameraStatus
+		failures  int
+		successes int
+		want      domain.CameraStatus
+	}{
+		{"online below failure threshold", domain.CameraStatusOnline, 2, 0, domain.CameraStatusOnline},
+		{"online at failure threshold", domain.CameraStatusOnline, 3, 0, domain.CameraStatusOffline},
+		{"unknown status is watched as online", "", 3, 0, domain.CameraStatusOffline},
+		{"offline below recovery threshold", domain.CameraStatusOffline, 0, 1, domain.CameraStatusOffline},
+		{"offline at recovery threshold", domain.CameraStatusOffline, 0, 2, domain.CameraStatusOnline},
+		{"offline keeps failing", domain.CameraStatusOffline, 10, 0, domain.CameraStatusOffline},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			camera := &domain.CameraHealth{Status: tt.status, Failures: tt.failures, Successes: tt.successes}
+			if got := nextCameraStatus(camera, cfg); got != tt.want {
+				t.Fatalf("nextCameraStatus = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCameraHealthHysteresis(t *testing.T) {
+	// A dropped probe is ignored, three in a row take the camera offline and
+	// a single success while offline does not bring it back
+	logger.Log = zap.NewNop()
+	prober := &scriptedProber{healthy: []bool{false, true, false, false, false, false, true, false, true, true, true}}
+	repo := &fakeHealthRepo{}
+	events := &fakeEventRepo{}
+	service := NewCameraHealthService(repo, nil, fakeMaintenanceRepo{}, prober, events,
+		config.CameraHealthConfig{FailureThreshold: 3, RecoveryThreshold: 2}).(*CameraHealthService)
+
+	camera := &domain.CameraHealth{CameraID: uuid.New(), Name: "Lobby", Status: domain.CameraStatusOnline}
+	var statuses []domain.CameraStatus
+	for len(prober.healthy) > 0 {
+		service.check(context.Background(), camera)
+		if n := len(repo.changes); n > 0 && repo.changes[n-1].ToStatus != camera.Status {
+			camera.Status = repo.changes[n-1].ToStatus
+			camera.Failures, camera.Successes = 0, 0
+		}
+		statuses = append(statuses, camera.Status)
+	}
+
+	on, off := domain.CameraStatusOnline, domain.CameraStatusOffline
+	want := []domain.CameraStatus{on, on, on, on, off, off, off, off, off, on, on}
+	for i := range want {
+		if statuses[i] != want[i] {
+			t.Fatalf("status after probe %d = %q, want %q (all: %v)", i+1, statuses[i], want[i], statuses)
+		}
+	}
+	if len(repo.changes) != 2 {
+		t.Fatalf("got %d status changes, want offline then online", len(repo.changes))
+	}
+	if repo.changes[0].Source != domain.CameraStatusSourceMonitor || repo.changes[0].Reason == "" {
+		t.Fatalf("offline change = %+v, want a monitor change with the probe error", repo.changes[0])
+	}
+	if len(events.events) != 1 || events.events[0].EventType != domain.EventTypeCameraOffline {
+		t.Fatalf("events = %v, want one camera offline event", events.events)
+	}
+}
+
+func TestCameraHealthLostRace(t *testing.T) {
+	// The camera was put in maintenance after it was listed, so the
+	// monitor's offline change no longer applies
+	logger.Log = zap.NewNop()
+	repo := &fakeHealthRepo{changeErr: domain.ErrCameraStatusChanged}
+	events := &fakeEventRepo{}
+	service := NewCameraHealthService(repo, nil, fakeMaintenanceRepo{}, &scriptedProber{healthy: []bool{false}}, events,
+		config.CameraHealthConfig{FailureThreshold: 1, RecoveryThreshold: 1}).(*CameraHealthService)
+
+	service.check(context.Background(), &domain.CameraHealth{CameraID: uuid.New(), Status: domain.CameraStatusOnline})
+	if len(events.events) != 0 {
+		t.Fatalf("events = %v, want none after losing the race", events.events)
+	}
+}
@@ -7,30 +7,46 @@ import (
 	"app/internal/core/ports"
 	"app/pkg/logger"
 
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
 type CameraService struct {
-	repo ports.CameraRepository
+	repo   ports.CameraRepository
+	zones  ports.ZoneRepository
+	health ports.CameraHealthRepository
+	audit  ports.AuditService
 }
 
-func NewCameraService(repo ports.CameraRepository) ports.CameraService {
+func NewCameraService(repo ports.CameraRepository, zones ports.ZoneRepository, health ports.CameraHealthRepository, audit ports.AuditService) ports.CameraService {
 	return &CameraService{
-		repo: repo,
+		repo:   repo,
+		zones:  zones,
+		health: health,
+		audit:  audit,
 	}
 }
 
 func (s *CameraService) CreateCamera(ctx context.Context, req *domain.CreateCameraRequest) (*domain.Camera, error) {
+	rtspURL, username, password, err := domain.SplitStreamURL(req.RTSPURL)
+	if err != nil {
+		return nil, err
+	}
+	if req.Username != "" {
+		username, password = req.Username, req.Password
+	}
 	camera := &domain.Camera{
 		ZoneID:    req.ZoneID,
 		Name:      req.Name,
 		IPAddress: req.IPAddress,
-		RTSPURL:   req.RTSPURL,
+		RTSPURL:   rtspURL,
+		Username:  username,
+		Password:  password,
 		Status:    domain.CameraStatusOnline,
 		AIEnabled: req.AIEnabled,
 	}
 
-	err := s.repo.Save(ctx, camera)
+	err = s.repo.Save(ctx, camera)
 	if err != nil {
 		logger.Error("Failed to create camera", zap.Error(err))
 		return nil, err
@@ -40,15 +56,25 @@ func (s *CameraService) CreateCamera(ctx context.Context, req *domain.CreateCame
 	return camera, nil
 }
 
-func (s *CameraService) GetCamera(ctx context.Context, id string) (*domain.Camera, error) {
-	return s.repo.GetByID(ctx, id)
+func (s *CameraService) GetCamera(ctx context.Context, id string, scope *domain.CameraScope) (*domain.Camera, error) {
+	camera, err := s.repo.GetByID(ctx, id)
+	if err != nil || camera == nil || scope == nil {
+		return camera, err
+	}
+	var zone *domain.Zone
+	if camera.ZoneID != nil {
+		if zone, err = s.zones.GetByID(ctx, *camera.ZoneID); err != nil {
+			return nil, err
+		}
+	}
+	if !scope.Allows(camera.ID, zone) {
+		return nil, nil
+	}
+	return camera, nil
 }
 
-func (s *CameraService) ListCameras(ctx context.Context, zoneID string, search string) ([]*domain.Camera, error) {
-	if zoneID != "" {
-		return s.repo.ListByZone(ctx, zoneID, search)
-	}
-	return s.repo.List(ctx, search)
+func (s *CameraService) ListCameras(ctx context.Context, filter *ports.CameraFilter) ([]*domain.Camera, error) {
+	return s.repo.Find(ctx, filter)
 }
 
 func (s *CameraService) UpdateCamera(ctx context.Context, id string, req *domain.UpdateCameraRequest) (*domain.Camera, error) {
@@ -59,6 +85,8 @@ func (s *CameraService) UpdateCamera(ctx context.Context, id string, req *domain
 	if camera == nil {
 		return nil, nil // Or NotFound error
 	}
+	previous := camera.Status
+	previousUser, previousPassword := camera.Username, camera.Password
 
 	if req.ZoneID != nil {
 		camera.ZoneID = req.ZoneID
@@ -70,7 +98,20 @@ func (s *CameraService) UpdateCamera(ctx context.Context, id string, req *domain
 		camera.IPAddress = req.IPAddress
 	}
 	if req.RTSPURL != "" {
-		camera.RTSPURL = req.RTSPURL
+		rtspURL, username, password, err := domain.SplitStreamURL(req.RTSPURL)
+		if err != nil {
+			return nil, err
+		}
+		camera.RTSPURL = rtspURL
+		if username != "" {
+			camera.Username, camera.Password = username, password
+		}
+	}
+	if req.Username != nil {
+		camera.Username = *req.Username
+	}
+	if req.Password != nil {
+		camera.Password = *req.Password
 	}
 	if req.Status != "" {
 		camera.Status = req.Status
@@ -82,9 +123,70 @@ func (s *CameraService) UpdateCamera(ctx context.Context, id string, req *domain
 	if err := s.repo.Update(ctx, camera); err != nil {
 		return nil, err
 	}
+	if camera.Status != previous {
+		s.recordManualStatus(ctx, camera, previous)
+	}
+	if camera.Username != previousUser || camera.Password != previousPassword {
+		s.logAction(ctx, nil, "UPDATE_CAMERA_CREDENTIALS", camera.ID, map[string]any{
+			"username":         camera.Username,
+			"password_changed": camera.Password != previousPassword,
+		})
+	}
 	return camera, nil
 }
 
+// RevealCredentials returns the stored username and password. Every reveal
+// is audited; callers must check the permission first.
+func (s *CameraService) RevealCredentials(ctx context.Context, id string, requestedBy *uuid.UUID) (*domain.CameraCredentials, error) {
+	camera, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if camera == nil {
+		return nil, domain.ErrCameraNotFound
+	}
+	if camera.CredentialsUnreadable {
+		return nil, domain.ErrCameraCredentialsUnreadable
+	}
+	s.logAction(ctx, requestedBy, "REVEAL_CAMERA_CREDENTIALS", camera.ID, nil)
+	return &domain.CameraCredentials{
+		CameraID:  camera.ID,
+		Username:  camera.Username,
+		Password:  camera.Password,
+		StreamURL: camera.StreamURL(),
+	}, nil
+}
+
+func (s *CameraService) logAction(ctx context.Context, userID *uuid.UUID, action, id string, newValue map[string]any) {
+	if err := s.audit.LogAction(ctx, &domain.AuditLog{
+		UserID:    userID,
+		Action:    action,
+		TableName: "cameras",
+		RecordID:  id,
+		NewValue:  newValue,
+	}); err != nil {
+		logger.Error("Failed to audit camera action", zap.String("camera_id", id), zap.String("action", action), zap.Error(err))
+	}
+}
+
+// recordManualStatus keeps hand-made changes, such as entering maintenance,
+// in the same history as the monitor's.
+func (s *CameraService) recordManualStatus(ctx context.Context, camera *domain.Camera, previous domain.CameraStatus) {
+	cameraID, err := uuid.Parse(camera.ID)
+	if err != nil {
+		return
+	}
+	change := &domain.CameraStatusChange{
+		CameraID:   cameraID,
+		FromStatus: previous,
+		ToStatus:   camera.Status,
+		Source:     domain.CameraStatusSourceManual,
+	}
+	if err := s.health.RecordStatusChange(ctx, change); err != nil {
+		logger.Error("Failed to record camera status change", zap.String("camera_id", camera.ID), zap.Error(err))
+	}
+}
+
 func (s *CameraService) DeleteCamera(ctx context.Context, id string) error {
 	return s.repo.Delete(ctx, id)
 }
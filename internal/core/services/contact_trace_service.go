@@ -0,0 +1,242 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"app/config"
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+	"app/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	contactDefaultWindow       = 15 * time.Minute
+	contactDefaultLookbackDays = 14
+	contactDefaultMaxSightings = 50000
+)
+
+type ContactTraceService struct {
+	repo       ports.AnalyticsRepository
+	identities ports.IdentityRepository
+	audit      ports.AuditService
+	cfg        config.ContactsConfig
+}
+
+func NewContactTraceService(
+	repo ports.AnalyticsRepository,
+	identities ports.IdentityRepository,
+	audit ports.AuditService,
+	cfg config.ContactsConfig,
+) ports.ContactTraceService {
+	if cfg.WindowMinutes <= 0 {
+		cfg.WindowMinutes = int(contactDefaultWindow / time.Minute)
+	}
+	if cfg.LookbackDays <= 0 {
+		cfg.LookbackDays = contactDefaultLookbackDays
+	}
+	if cfg.MaxSightings <= 0 {
+		cfg.MaxSightings = contactDefaultMaxSightings
+	}
+	return &ContactTraceService{repo: repo, identities: identities, audit: audit, cfg: cfg}
+}
+
+// presence is a stretch of time someone was in one zone. Each sighting
+// counts as window wide, centered on it, so two sightings overlap exactly
+// when they are at most window apart.
+type presence struct {
+	key        uuid.UUID // Zone, or camera when it has no zone
+	zoneID     *uuid.UUID
+	zoneName   string
+	cameraName string
+	start      time.Time
+	end        time.Time
+}
+
+func (s *ContactTraceService) TraceContacts(ctx context.Context, identityID uuid.UUID, filter *ports.ContactTraceFilter) (*domain.ContactTrace, error) {
+	identity, err := s.identities.GetIdentity(ctx, identityID)
+	if err != nil || identity == nil {
+		return nil, domain.ErrIdentityNotFound
+	}
+
+	to := time.Now()
+	if filter.ToDate != nil {
+		to = *filter.ToDate
+	}
+	from := to.AddDate(0, 0, -s.cfg.LookbackDays)
+	if filter.FromDate != nil {
+		from = *filter.FromDate
+	}
+	window := filter.Window
+	if window <= 0 {
+		window = time.Duration(s.cfg.WindowMinutes) * time.Minute
+	}
+
+	trace := &domain.ContactTrace{IdentityID: identityID, From: from, To: to, WindowSeconds: window.Seconds(), Contacts: []domain.Contact{}}
+	sightings, err := s.repo.ListSightings(ctx, identityID, from, to, s.cfg.MaxSightings+1)
+	if err != nil {
+		return nil, err
+	}
+	if len(sightings) > s.cfg.MaxSightings {
+		sightings = sightings[:s.cfg.MaxSightings]
+		trace.Truncated = true
+	}
+	trace.Sightings = len(sightings)
+
+	if len(sightings) > 0 {
+		others, err := s.repo.ListContactSightings(ctx, identityID, from, to, window, s.cfg.MaxSightings+1)
+		if err != nil {
+			return nil, err
+		}
+		if len(others) > s.cfg.MaxSightings {
+			others = others[:s.cfg.MaxSightings]
+			trace.Truncated = true
+		}
+		trace.Contacts = rankContacts(presences(sightings, window), others, window, filter.MinEncounters)
+		if filter.Limit > 0 && len(trace.Contacts) > filter.Limit {
+			trace.Contacts = trace.Contacts[:filter.Limit]
+		}
+	}
+
+	if err := s.audit.LogAction(ctx, &domain.AuditLog{
+		UserID:    filter.RequestedBy,
+		Action:    "CONTACT_TRACE",
+		TableName: "identities",
+		RecordID:  identityID.String(),
+		NewValue: map[string]any{
+			"from":           from,
+			"to":             to,
+			"window_minutes": window.Minutes(),
+			"contacts":       len(trace.Contacts),
+		},
+	}); err != nil {
+		logger.Error("Failed to audit contact trace", zap.String("identity_id", identityID.String()), zap.Error(err))
+	}
+	return trace, nil
+}
+
+// presences merges time-ordered sightings into per-zone stretches.
+func presences(sightings []*domain.Sighting, window time.Duration) []presence {
+	half := window / 2
+	open := map[uuid.UUID]int{}
+	var out []presence
+	for _, s := range sightings {
+		key := s.CameraID
+		if s.ZoneID != nil {
+			key = *s.ZoneID
+		}
+		start, end := s.OccurredAt.Add(-half), s.OccurredAt.Add(half)
+		if i, ok := open[key]; ok && !start.After(out[i].end) {
+			out[i].end = end
+			continue
+		}
+		open[key] = len(out)
+		out = append(out, presence{
+			key:        key,
+			zoneID:     s.ZoneID,
+			zoneName:   s.ZoneName,
+			cameraName: s.CameraName,
+			start:      start,
+			end:        end,
+		})
+	}
+	return out
+}
+
+// rankContacts intersects the target's presence with every other identity's
+// and orders contacts by total overlap, then encounter count.
+func rankContacts(target []presence, others []*domain.ContactSighting, window time.Duration, minEncounters int) []domain.Contact {
+	byZone := map[uuid.UUID][]presence{}
+	for _, p := range target {
+		byZone[p.key] = append(byZone[p.key], p)
+	}
+
+	contacts := []domain.Contact{}
+	for start := 0; start < len(others); {
+		end := start
+		for end < len(others) && others[end].IdentityID == others[start].IdentityID {
+			end++
+		}
+		group := make([]*domain.Sighting, end-start)
+		for i, o := range others[start:end] {
+			group[i] = &o.Sighting
+		}
+		contact := domain.Contact{
+			IdentityID: others[start].IdentityID,
+			Code:       others[start].IdentityCode,
+			FullName:   others[start].IdentityName,
+			Zones:      []string{},
+			Details:    []domain.ContactEncounter{},
+		}
+		start = end
+
+		zones := map[uuid.UUID]bool{}
+		for _, p := range presences(group, window) {
+			for _, t := range byZone[p.key] {
+				from, to := maxTime(p.start, t.start), minTime(p.end, t.end)
+				if from.After(to) {
+					continue
+				}
+				contact.Details = append(contact.Details, domain.ContactEncounter{
+					ZoneID:         p.zoneID,
+					ZoneName:       p.zoneName,
+					CameraName:     p.cameraName,
+					StartAt:        from,
+					EndAt:          to,
+					OverlapSeconds: to.Sub(from).Seconds(),
+				})
+				if !zones[p.key] {
+					zones[p.key] = true
+					name := p.zoneName
+					if name == "" {
+						name = p.cameraName
+					}
+					contact.Zones = append(contact.Zones, name)
+				}
+			}
+		}
+		if len(contact.Details) == 0 || len(contact.Details) < minEncounters {
+			continue
+		}
+
+		sort.Slice(contact.Details, func(i, j int) bool { return contact.Details[i].StartAt.Before(contact.Details[j].StartAt) })
+		contact.Encounters = len(contact.Details)
+		contact.FirstContactAt = contact.Details[0].StartAt
+		for _, e := range contact.Details {
+			contact.OverlapSeconds += e.OverlapSeconds
+			if e.EndAt.After(contact.LastContactAt) {
+				contact.LastContactAt = e.EndAt
+			}
+		}
+		contacts = append(contacts, contact)
+	}
+
+	sort.SliceStable(contacts, func(i, j int) bool {
+		if contacts[i].OverlapSeconds != contacts[j].OverlapSeconds {
+			return contacts[i].OverlapSeconds > contacts[j].OverlapSeconds
+		}
+		if contacts[i].Encounters != contacts[j].Encounters {
+			return contacts[i].Encounters > contacts[j].Encounters
+		}
+		return contacts[i].FullName < contacts[j].FullName
+	})
+	return contacts
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
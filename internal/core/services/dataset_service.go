@@ -0,0 +1,419 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"image"
+	"io"
+	"math"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+	"app/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const datasetDefaultValRatio = 0.2
+
+type DatasetService struct {
+	repo    ports.DatasetRepository
+	storage ports.FileStorage
+	jobs    ports.JobService
+	audit   ports.AuditService
+}
+
+func NewDatasetService(repo ports.DatasetRepository, storage ports.FileStorage, jobs ports.JobService, audit ports.AuditService) ports.DatasetService {
+	return &DatasetService{repo: repo, storage: storage, jobs: jobs, audit: audit}
+}
+
+// datasetBox is a bounding box in pixels, or in 0..1 when normalized.
+type datasetBox struct {
+	label      string
+	x, y, w, h float64
+	normalized bool
+}
+
+type datasetItem struct {
+	sample *domain.DatasetSample
+	boxes  []datasetBox
+}
+
+type cocoImage struct {
+	ID       int    `json:"id"`
+	FileName string `json:"file_name"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+}
+
+type cocoAnnotation struct {
+	ID         int        `json:"id"`
+	ImageID    int        `json:"image_id"`
+	CategoryID int        `json:"category_id"`
+	BBox       [4]float64 `json:"bbox"`
+	Area       float64    `json:"area"`
+	IsCrowd    int        `json:"iscrowd"`
+}
+
+type cocoCategory struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type cocoDataset struct {
+	Info        map[string]any   `json:"info"`
+	Images      []cocoImage      `json:"images"`
+	Annotations []cocoAnnotation `json:"annotations"`
+	Categories  []cocoCategory   `json:"categories"`
+}
+
+// StartExport builds a ZIP laid out for both tools: images/{train,val},
+// labels/{train,val} for YOLO and annotations/instances_{train,val}.json for
+// COCO. Samples are split by a hash of their ID, so re-exports are stable.
+func (s *DatasetService) StartExport(ctx context.Context, req *ports.DatasetExportRequest) (*domain.Job, error) {
+	if req.Format == "" {
+		req.Format = domain.DatasetFormatBoth
+	}
+	if req.Format != domain.DatasetFormatCOCO && req.Format != domain.DatasetFormatYOLO && req.Format != domain.DatasetFormatBoth {
+		return nil, domain.ErrInvalidDatasetFormat
+	}
+	valRatio := datasetDefaultValRatio
+	if req.ValRatio != nil {
+		valRatio = *req.ValRatio
+	}
+	if valRatio < 0 || valRatio >= 1 {
+		return nil, domain.ErrInvalidValRatio
+	}
+
+	params := map[string]any{
+		"format":         req.Format,
+		"camera_id":      req.CameraID,
+		"event_types":    req.EventTypes,
+		"from":           req.FromDate,
+		"to":             req.ToDate,
+		"val_ratio":      valRatio,
+		"skip_negatives": req.SkipNegatives,
+	}
+	job, err := s.jobs.Start(ctx, domain.JobTypeDatasetExport, req.RequestedBy, params, func(ctx context.Context, tracker ports.JobTracker) (map[string]any, error) {
+		return s.export(ctx, tracker, req, valRatio)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.audit.LogAction(ctx, &domain.AuditLog{
+		UserID:    req.RequestedBy,
+		Action:    "DATASET_EXPORT",
+		TableName: "jobs",
+		RecordID:  job.ID.String(),
+		NewValue:  params,
+	}); err != nil {
+		logger.Error("Failed to audit dataset export", zap.Error(err))
+	}
+	return job, nil
+}
+
+func (s *DatasetService) export(ctx context.Context, tracker ports.JobTracker, req *ports.DatasetExportRequest, valRatio float64) (map[string]any, error) {
+	items := []*datasetItem{}
+	labels := map[string]bool{}
+	skipped := 0
+	err := s.repo.EachSample(ctx, req, func(sample *domain.DatasetSample) error {
+		item := &datasetItem{sample: sample}
+		if sample.Positive {
+			item.boxes = parseDatasetBoxes(sample.Metadata, sample.Category)
+			if len(item.boxes) == 0 {
+				// A true positive without a box teaches the detector nothing
+				skipped++
+				return nil
+			}
+			for _, b := range item.boxes {
+				labels[b.label] = true
+			}
+		}
+		items = append(items, item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	tracker.SetTotal(len(items))
+
+	categories := make([]string, 0, len(labels))
+	for l := range labels {
+		categories = append(categories, l)
+	}
+	sort.Strings(categories)
+	categoryIndex := make(map[string]int, len(categories))
+	for i, c := range categories {
+		categoryIndex[c] = i
+	}
+
+	tmp, err := os.CreateTemp("", "dataset-*.zip")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	withCOCO := req.Format != domain.DatasetFormatYOLO
+	withYOLO := req.Format != domain.DatasetFormatCOCO
+	zw := zip.NewWriter(tmp)
+	splits := map[string]*cocoDataset{}
+	for _, split := range []string{"train", "val"} {
+		splits[split] = &cocoDataset{
+			Info:        map[string]any{"description": "Reviewed detections", "date_created": time.Now().Format(time.RFC3339), "split": split},
+			Images:      []cocoImage{},
+			Annotations: []cocoAnnotation{},
+			Categories:  make([]cocoCategory, len(categories)),
+		}
+		for i, c := range categories {
+			splits[split].Categories[i] = cocoCategory{ID: i + 1, Name: c}
+		}
+	}
+
+	imageID, annotationID, missing := 0, 0, 0
+	counts := map[string]int{}
+	for _, item := range items {
+		data, width, height, err := s.readSnapshot(ctx, item.sample.SnapshotURL)
+		if err != nil {
+			missing++
+			tracker.Step(false)
+			continue
+		}
+
+		split := datasetSplit(item.sample.ID, valRatio)
+		ext := strings.ToLower(path.Ext(item.sample.SnapshotURL))
+		if ext == "" {
+			ext = ".jpg"
+		}
+		base := fmt.Sprintf("%s_%s", item.sample.Source, item.sample.ID)
+		imageName := fmt.Sprintf("images/%s/%s%s", split, base, ext)
+		dst, err := zw.Create(imageName)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := dst.Write(data); err != nil {
+			return nil, err
+		}
+
+		imageID++
+		coco := splits[split]
+		coco.Images = append(coco.Images, cocoImage{ID: imageID, FileName: path.Base(imageName), Width: width, Height: height})
+		var yolo strings.Builder
+		for _, b := range item.boxes {
+			x, y, w, h, ok := b.pixels(width, height)
+			if !ok {
+				continue
+			}
+			annotationID++
+			coco.Annotations = append(coco.Annotations, cocoAnnotation{
+				ID:         annotationID,
+				ImageID:    imageID,
+				CategoryID: categoryIndex[b.label] + 1,
+				BBox:       [4]float64{x, y, w, h},
+				Area:       w * h,
+			})
+			fmt.Fprintf(&yolo, "%d %.6f %.6f %.6f %.6f\n", categoryIndex[b.label],
+				(x+w/2)/float64(width), (y+h/2)/float64(height), w/float64(width), h/float64(height))
+		}
+		if withYOLO {
+			// Negatives get an empty label file, which YOLO reads as background
+			dst, err := zw.Create(fmt.Sprintf("labels/%s/%s.txt", split, base))
+			if err != nil {
+				return nil, err
+			}
+			if _, err := io.WriteString(dst, yolo.String()); err != nil {
+				return nil, err
+			}
+		}
+		counts[split]++
+		tracker.Step(true)
+	}
+
+	if withCOCO {
+		for split, coco := range splits {
+			if err := writeZipJSON(zw, fmt.Sprintf("annotations/instances_%s.json", split), coco); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if withYOLO {
+		dst, err := zw.Create("data.yaml")
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, len(categories))
+		for i, c := range categories {
+			names[i] = strconv.Quote(c)
+		}
+		fmt.Fprintf(dst, "path: .\ntrain: images/train\nval: images/val\nnc: %d\nnames: [%s]\n", len(categories), strings.Join(names, ", "))
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	filename := fmt.Sprintf("exports/dataset-%s-%s.zip", time.Now().Format("20060102-150405"), uuid.New().String()[:8])
+	url, err := s.storage.SaveFile(ctx, filename, tmp)
+	if err != nil {
+		return nil, err
+	}
+	tracker.SetResultURL(url)
+
+	return map[string]any{
+		"images":           counts["train"] + counts["val"],
+		"train":            counts["train"],
+		"val":              counts["val"],
+		"annotations":      annotationID,
+		"categories":       categories,
+		"skipped_no_bbox":  skipped,
+		"missing_snapshot": missing,
+	}, nil
+}
+
+func (s *DatasetService) readSnapshot(ctx context.Context, url string) ([]byte, int, int, error) {
+	file, err := s.storage.OpenFile(ctx, url)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	return data, cfg.Width, cfg.Height, nil
+}
+
+func datasetSplit(id uuid.UUID, valRatio float64) string {
+	h := fnv.New32a()
+	h.Write(id[:])
+	if float64(h.Sum32()%10000) < valRatio*10000 {
+		return "val"
+	}
+	return "train"
+}
+
+// parseDatasetBoxes reads "bbox" or a "boxes"/"detections" list from event
+// metadata. A box is [x, y, w, h] or an object with x, y, w|width, h|height
+// or x1, y1, x2, y2; values all within 0..1 are taken as normalized.
+func parseDatasetBoxes(metadata map[string]any, category string) []datasetBox {
+	var boxes []datasetBox
+	for _, key := range []string{"boxes", "detections"} {
+		list, ok := metadata[key].([]any)
+		if !ok {
+			continue
+		}
+		for _, entry := range list {
+			obj, ok := entry.(map[string]any)
+			if !ok {
+				continue
+			}
+			label := category
+			for _, k := range []string{"label", "class", "type"} {
+				if v, ok := obj[k].(string); ok && v != "" {
+					label = v
+					break
+				}
+			}
+			raw := any(obj)
+			if v, ok := obj["bbox"]; ok {
+				raw = v
+			}
+			if b, ok := parseDatasetBox(raw); ok {
+				b.label = label
+				boxes = append(boxes, b)
+			}
+		}
+	}
+	if len(boxes) == 0 {
+		if b, ok := parseDatasetBox(metadata["bbox"]); ok {
+			b.label = category
+			boxes = append(boxes, b)
+		}
+	}
+	return boxes
+}
+
+func parseDatasetBox(v any) (datasetBox, bool) {
+	var b datasetBox
+	switch raw := v.(type) {
+	case []any:
+		if len(raw) != 4 {
+			return b, false
+		}
+		vals := make([]float64, 4)
+		for i, n := range raw {
+			f, ok := n.(float64)
+			if !ok {
+				return b, false
+			}
+			vals[i] = f
+		}
+		b.x, b.y, b.w, b.h = vals[0], vals[1], vals[2], vals[3]
+	case map[string]any:
+		num := func(keys ...string) (float64, bool) {
+			for _, k := range keys {
+				if f, ok := raw[k].(float64); ok {
+					return f, true
+				}
+			}
+			return 0, false
+		}
+		if x1, ok := num("x1"); ok {
+			y1, ok1 := num("y1")
+			x2, ok2 := num("x2")
+			y2, ok3 := num("y2")
+			if !ok1 || !ok2 || !ok3 {
+				return b, false
+			}
+			b.x, b.y, b.w, b.h = x1, y1, x2-x1, y2-y1
+			break
+		}
+		x, ok1 := num("x")
+		y, ok2 := num("y")
+		w, ok3 := num("w", "width")
+		h, ok4 := num("h", "height")
+		if !ok1 || !ok2 || !ok3 || !ok4 {
+			return b, false
+		}
+		b.x, b.y, b.w, b.h = x, y, w, h
+	default:
+		return b, false
+	}
+	if b.w <= 0 || b.h <= 0 {
+		return b, false
+	}
+	b.normalized = b.x <= 1 && b.y <= 1 && b.w <= 1 && b.h <= 1
+	return b, true
+}
+
+// pixels scales the box to the image and clips it to the frame.
+func (b datasetBox) pixels(width, height int) (x, y, w, h float64, ok bool) {
+	x, y, w, h = b.x, b.y, b.w, b.h
+	if b.normalized {
+		x, w = x*float64(width), w*float64(width)
+		y, h = y*float64(height), h*float64(height)
+	}
+	x2 := math.Min(x+w, float64(width))
+	y2 := math.Min(y+h, float64(height))
+	x, y = math.Max(x, 0), math.Max(y, 0)
+	w, h = x2-x, y2-y
+	return x, y, w, h, w >= 1 && h >= 1
+}
@@ -0,0 +1,229 @@
+package services
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+	"app/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// discoveryConcurrency caps how many devices are queried at once.
+const discoveryConcurrency = 8
+
+type DiscoveryService struct {
+	repo       ports.DiscoveryRepository
+	discoverer ports.DeviceDiscoverer
+	cameras    ports.CameraService
+	zones      ports.ZoneRepository
+	jobs       ports.JobService
+	audit      ports.AuditService
+}
+
+func NewDiscoveryService(
+	repo ports.DiscoveryRepository,
+	discoverer ports.DeviceDiscoverer,
+	cameras ports.CameraService,
+	zones ports.ZoneRepository,
+	jobs ports.JobService,
+	audit ports.AuditService,
+) ports.DiscoveryService {
+	return &DiscoveryService{repo: repo, discoverer: discoverer, cameras: cameras, zones: zones, jobs: jobs, audit: audit}
+}
+
+// StartDiscovery probes the network in the background. Every device that
+// answers is stored with the job, including ones that could not be queried,
+// so the operator can see them and retry with other credentials.
+func (s *DiscoveryService) StartDiscovery(ctx context.Context, req *ports.DiscoveryRequest) (*domain.Job, error) {
+	params := map[string]any{"username": req.Username}
+	creds := *req
+	return s.jobs.Start(ctx, domain.JobTypeCameraDiscovery, req.RequestedBy, params, func(ctx context.Context, tracker ports.JobTracker) (map[string]any, error) {
+		return s.discover(ctx, tracker, &creds)
+	})
+}
+
+func (s *DiscoveryService) discover(ctx context.Context, tracker ports.JobTracker, req *ports.DiscoveryRequest) (map[string]any, error) {
+	endpoints, err := s.discoverer.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tracker.SetTotal(len(endpoints))
+
+	sem := make(chan struct{}, discoveryConcurrency)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	saved := 0
+	for _, endpoint := range endpoints {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(endpoint domain.ONVIFEndpoint) {
+			defer func() { <-sem; wg.Done() }()
+			device := s.describe(ctx, endpoint, req)
+			device.JobID = tracker.JobID()
+			err := s.repo.SaveDevice(ctx, device)
+			if err != nil {
+				logger.Error("Failed to save discovered device", zap.String("xaddr", device.XAddr), zap.Error(err))
+			} else {
+				mu.Lock()
+				saved++
+				mu.Unlock()
+			}
+			tracker.Step(err == nil && device.Error == "")
+		}(endpoint)
+	}
+	wg.Wait()
+	return map[string]any{"devices": saved}, nil
+}
+
+// describe tries each address the device advertised until one answers.
+func (s *DiscoveryService) describe(ctx context.Context, endpoint domain.ONVIFEndpoint, req *ports.DiscoveryRequest) *domain.DiscoveredDevice {
+	var device *domain.DiscoveredDevice
+	var err error
+	for _, xaddr := range endpoint.XAddrs {
+		device, err = s.discoverer.Describe(ctx, xaddr, req.Username, req.Password)
+		if err == nil {
+			break
+		}
+	}
+	if device == nil {
+		device = &domain.DiscoveredDevice{XAddr: endpoint.XAddrs[0]}
+	}
+	if err != nil {
+		device.Error = err.Error()
+	}
+	device.EndpointRef = endpoint.EndpointRef
+	device.Scopes = endpoint.Scopes
+	device.Name = onvifScope(endpoint.Scopes, "name")
+	if device.Model == "" {
+		device.Model = onvifScope(endpoint.Scopes, "hardware")
+	}
+	return device
+}
+
+// onvifScope reads a value such as onvif://www.onvif.org/name/Gate%20Cam.
+func onvifScope(scopes []string, key string) string {
+	prefix := "onvif://www.onvif.org/" + key + "/"
+	for _, scope := range scopes {
+		if rest, ok := strings.CutPrefix(scope, prefix); ok {
+			if value, err := url.PathUnescape(rest); err == nil {
+				return value
+			}
+			return rest
+		}
+	}
+	return ""
+}
+
+func (s *DiscoveryService) ListDevices(ctx context.Context, jobID uuid.UUID) ([]*domain.DiscoveredDevice, error) {
+	job, err := s.jobs.GetJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job.Type != domain.JobTypeCameraDiscovery {
+		return nil, domain.ErrJobNotFound
+	}
+	return s.repo.ListDevices(ctx, jobID)
+}
+
+// AdoptDevices creates a camera for each device. A device that fails does
+// not stop the rest; its error is reported in the result.
+func (s *DiscoveryService) AdoptDevices(ctx context.Context, req *ports.AdoptDevicesRequest) (*ports.AdoptDevicesResult, error) {
+	if len(req.Devices) == 0 {
+		return nil, domain.ErrNoDevicesToAdopt
+	}
+	if req.ZoneID != nil {
+		zone, err := s.zones.GetByID(ctx, *req.ZoneID)
+		if err != nil {
+			return nil, err
+		}
+		if zone == nil {
+			return nil, domain.ErrZoneNotFound
+		}
+	}
+
+	result := &ports.AdoptDevicesResult{Cameras: []*domain.Camera{}, Errors: []ports.AdoptDeviceError{}}
+	adopted := []map[string]any{}
+	for _, item := range req.Devices {
+		camera, err := s.adopt(ctx, req, item)
+		if err != nil {
+			result.Errors = append(result.Errors, ports.AdoptDeviceError{DeviceID: item.DeviceID, Error: err.Error()})
+			continue
+		}
+		result.Cameras = append(result.Cameras, camera)
+		adopted = append(adopted, map[string]any{"device_id": item.DeviceID, "camera_id": camera.ID, "name": camera.Name})
+	}
+
+	if len(adopted) > 0 {
+		if err := s.audit.LogAction(ctx, &domain.AuditLog{
+			UserID:    req.RequestedBy,
+			Action:    "ADOPT_CAMERAS",
+			TableName: "cameras",
+			NewValue:  map[string]any{"zone_id": req.ZoneID, "cameras": adopted},
+		}); err != nil {
+			logger.Error("Failed to audit camera adoption", zap.Error(err))
+		}
+	}
+	return result, nil
+}
+
+func (s *DiscoveryService) adopt(ctx context.Context, req *ports.AdoptDevicesRequest, item ports.AdoptDevice) (*domain.Camera, error) {
+	device, err := s.repo.GetDevice(ctx, item.DeviceID)
+	if err != nil {
+		return nil, err
+	}
+	if device == nil {
+		return nil, domain.ErrDiscoveredDeviceNotFound
+	}
+	if device.AdoptedAt != nil {
+		return nil, domain.ErrDeviceAlreadyAdopted
+	}
+	profile, ok := device.Profile(item.ProfileToken)
+	if !ok {
+		return nil, domain.ErrDeviceProfileNotFound
+	}
+	if profile.RTSPURL == "" {
+		return nil, domain.ErrDeviceNoStreamURI
+	}
+
+	name := item.Name
+	if name == "" {
+		name = device.Name
+	}
+	if name == "" {
+		name = strings.TrimSpace(device.Manufacturer + " " + device.Model)
+	}
+	if name == "" {
+		name = device.IPAddress
+	}
+
+	camera, err := s.cameras.CreateCamera(ctx, &domain.CreateCameraRequest{
+		ZoneID:    req.ZoneID,
+		Name:      name,
+		IPAddress: device.IPAddress,
+		RTSPURL:   profile.RTSPURL,
+		Username:  req.Username,
+		Password:  req.Password,
+		AIEnabled: req.AIEnabled,
+	})
+	if err != nil {
+		return nil, err
+	}
+	cameraID, err := uuid.Parse(camera.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.MarkAdopted(ctx, device.ID, cameraID); err != nil {
+		// Another request adopted the device meanwhile; keep one camera
+		if delErr := s.cameras.DeleteCamera(ctx, camera.ID); delErr != nil {
+			logger.Error("Failed to remove duplicate adopted camera", zap.String("camera_id", camera.ID), zap.Error(delErr))
+		}
+		return nil, err
+	}
+	return camera, nil
+}
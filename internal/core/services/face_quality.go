@@ -0,0 +1,151 @@
+package services
+
+import (
+	"fmt"
+	"image"
+	_ "image/jpeg" // Register decoders for uploaded face images
+	_ "image/png"
+	"io"
+	"math"
+	"strings"
+
+	"app/config"
+	"app/internal/core/domain"
+)
+
+// Images larger than this (on the long side) are downsampled before analysis.
+const faceQualityMaxSide = 1024
+
+// AssessFaceQuality decodes an image and measures sharpness, brightness,
+// contrast, resolution and aspect ratio against the configured thresholds.
+func AssessFaceQuality(reader io.Reader, cfg config.FaceQualityConfig) (*domain.FaceQuality, error) {
+	img, _, err := image.Decode(reader)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrFaceImageUnreadable, err)
+	}
+
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width == 0 || height == 0 {
+		return nil, fmt.Errorf("%w: empty image", domain.ErrFaceImageUnreadable)
+	}
+
+	gray, gw, gh := luminance(img, faceQualityMaxSide)
+	mean, stddev := meanStdDev(gray)
+
+	q := &domain.FaceQuality{
+		Sharpness:   laplacianVariance(gray, gw, gh),
+		Brightness:  mean,
+		Contrast:    stddev,
+		Width:       width,
+		Height:      height,
+		AspectRatio: float64(width) / float64(height),
+	}
+
+	if q.Sharpness < cfg.MinSharpness {
+		q.Issues = append(q.Issues, fmt.Sprintf("image is blurry (sharpness %.1f < %.1f)", q.Sharpness, cfg.MinSharpness))
+	}
+	if q.Brightness < cfg.MinBrightness {
+		q.Issues = append(q.Issues, fmt.Sprintf("image is too dark (brightness %.1f < %.1f)", q.Brightness, cfg.MinBrightness))
+	}
+	if cfg.MaxBrightness > 0 && q.Brightness > cfg.MaxBrightness {
+		q.Issues = append(q.Issues, fmt.Sprintf("image is overexposed (brightness %.1f > %.1f)", q.Brightness, cfg.MaxBrightness))
+	}
+	if q.Contrast < cfg.MinContrast {
+		q.Issues = append(q.Issues, fmt.Sprintf("image contrast is too low (%.1f < %.1f)", q.Contrast, cfg.MinContrast))
+	}
+	if width < cfg.MinWidth || height < cfg.MinHeight {
+		q.Issues = append(q.Issues, fmt.Sprintf("resolution %dx%d is below %dx%d", width, height, cfg.MinWidth, cfg.MinHeight))
+	}
+	if cfg.MinAspectRatio > 0 && q.AspectRatio < cfg.MinAspectRatio ||
+		cfg.MaxAspectRatio > 0 && q.AspectRatio > cfg.MaxAspectRatio {
+		q.Issues = append(q.Issues, fmt.Sprintf("aspect ratio %.2f is outside %.2f-%.2f", q.AspectRatio, cfg.MinAspectRatio, cfg.MaxAspectRatio))
+	}
+
+	return q, nil
+}
+
+// FaceQualityScore folds the individual measurements into a 0-1 score.
+func FaceQualityScore(q *domain.FaceQuality, cfg config.FaceQualityConfig) float64 {
+	sharp := ratio(q.Sharpness, cfg.MinSharpness*2)
+	contrast := ratio(q.Contrast, cfg.MinContrast*2)
+
+	// Brightness scores highest in the middle of the accepted range
+	brightness := 1.0
+	if cfg.MaxBrightness > cfg.MinBrightness {
+		mid := (cfg.MinBrightness + cfg.MaxBrightness) / 2
+		brightness = math.Max(0, 1-math.Abs(q.Brightness-mid)/(mid-cfg.MinBrightness+1e-9)/2)
+	}
+
+	resolution := math.Min(ratio(float64(q.Width), float64(cfg.MinWidth)), ratio(float64(q.Height), float64(cfg.MinHeight)))
+
+	return math.Round((sharp+contrast+brightness+resolution)/4*1000) / 1000
+}
+
+// FaceQualityReason joins the issues into a single human readable reason.
+func FaceQualityReason(q *domain.FaceQuality) string {
+	return strings.Join(q.Issues, "; ")
+}
+
+func ratio(value, target float64) float64 {
+	if target <= 0 {
+		return 1
+	}
+	return math.Min(1, value/target)
+}
+
+// luminance converts img to 8-bit grayscale, downsampling with nearest
+// neighbour so the long side is at most maxSide pixels.
+func luminance(img image.Image, maxSide int) ([]float64, int, int) {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	step := 1.0
+	if long := max(w, h); long > maxSide {
+		step = float64(long) / float64(maxSide)
+	}
+	gw, gh := int(float64(w)/step), int(float64(h)/step)
+
+	gray := make([]float64, gw*gh)
+	for y := 0; y < gh; y++ {
+		for x := 0; x < gw; x++ {
+			r, g, bl, _ := img.At(b.Min.X+int(float64(x)*step), b.Min.Y+int(float64(y)*step)).RGBA()
+			// ITU-R BT.601 luma on 16-bit channels, scaled to 0-255
+			gray[y*gw+x] = (0.299*float64(r) + 0.587*float64(g) + 0.114*float64(bl)) / 257
+		}
+	}
+	return gray, gw, gh
+}
+
+func meanStdDev(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+
+	var sq float64
+	for _, v := range values {
+		sq += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(sq / float64(len(values)))
+}
+
+// laplacianVariance is the variance of the 4-neighbour Laplacian response,
+// a standard focus measure: blurry images have few edges and a low variance.
+func laplacianVariance(gray []float64, w, h int) float64 {
+	if w < 3 || h < 3 {
+		return 0
+	}
+	resp := make([]float64, 0, (w-2)*(h-2))
+	for y := 1; y < h-1; y++ {
+		for x := 1; x < w-1; x++ {
+			i := y*w + x
+			resp = append(resp, gray[i-w]+gray[i+w]+gray[i-1]+gray[i+1]-4*gray[i])
+		}
+	}
+	_, stddev := meanStdDev(resp)
+	return stddev * stddev
+}
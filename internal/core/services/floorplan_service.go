@@ -0,0 +1,356 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"math"
+	"slices"
+	"time"
+
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+	"app/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultPlacementFOV = 90
+	defaultMapWindow    = time.Hour
+)
+
+// floorPlanExtensions maps the decoded image format to the stored file's
+// extension. The client's filename is never used, so nothing uploaded is
+// served from /uploads as anything but an image.
+var floorPlanExtensions = map[string]string{
+	"png":  ".png",
+	"jpeg": ".jpeg",
+	"gif":  ".gif",
+}
+
+type FloorPlanService struct {
+	repo    ports.FloorPlanRepository
+	zones   ports.ZoneRepository
+	cameras ports.CameraRepository
+	storage ports.FileStorage
+	audit   ports.AuditService
+}
+
+func NewFloorPlanService(repo ports.FloorPlanRepository, zones ports.ZoneRepository, cameras ports.CameraRepository, storage ports.FileStorage, audit ports.AuditService) ports.FloorPlanService {
+	return &FloorPlanService{
+		repo:    repo,
+		zones:   zones,
+		cameras: cameras,
+		storage: storage,
+		audit:   audit,
+	}
+}
+
+func (s *FloorPlanService) UploadPlan(ctx context.Context, req *ports.UploadFloorPlanRequest) (*domain.FloorPlan, error) {
+	if _, err := s.zone(ctx, req.ZoneID); err != nil {
+		return nil, err
+	}
+	config, format, err := image.DecodeConfig(bytes.NewReader(req.Image))
+	ext, known := floorPlanExtensions[format]
+	if err != nil || !known || config.Width == 0 || config.Height == 0 {
+		return nil, domain.ErrFloorPlanImage
+	}
+	old, err := s.repo.GetPlan(ctx, req.ZoneID)
+	if err != nil {
+		return nil, err
+	}
+
+	filename := fmt.Sprintf("floor-plans/%s_%s%s", req.ZoneID, uuid.New().String()[:8], ext)
+	url, err := s.storage.SaveFile(ctx, filename, bytes.NewReader(req.Image))
+	if err != nil {
+		return nil, err
+	}
+	plan := &domain.FloorPlan{
+		ZoneID:   req.ZoneID,
+		ImageURL: url,
+		Width:    config.Width,
+		Height:   config.Height,
+	}
+	if err := s.repo.SavePlan(ctx, plan); err != nil {
+		if delErr := s.storage.DeleteFile(ctx, url); delErr != nil {
+			logger.Error("Failed to remove rejected floor plan", zap.String("url", url), zap.Error(delErr))
+		}
+		return nil, err
+	}
+	if old != nil {
+		s.removeImage(ctx, old.ImageURL)
+	}
+
+	s.auditPlan(ctx, req.UploadedBy, "UPLOAD_FLOOR_PLAN", req.ZoneID, old, plan)
+	return plan, nil
+}
+
+func (s *FloorPlanService) GetPlan(ctx context.Context, zoneID uuid.UUID) (*domain.FloorPlan, error) {
+	plan, err := s.repo.GetPlan(ctx, zoneID)
+	if err != nil {
+		return nil, err
+	}
+	if plan == nil {
+		return nil, domain.ErrFloorPlanNotFound
+	}
+	return plan, nil
+}
+
+func (s *FloorPlanService) DeletePlan(ctx context.Context, zoneID uuid.UUID, requestedBy *uuid.UUID) error {
+	plan, err := s.GetPlan(ctx, zoneID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeletePlan(ctx, zoneID); err != nil {
+		return err
+	}
+	s.removeImage(ctx, plan.ImageURL)
+
+	s.auditPlan(ctx, requestedBy, "DELETE_FLOOR_PLAN", zoneID, plan, nil)
+	return nil
+}
+
+// PlaceCamera puts the camera on its own zone's plan or that of a zone
+// above it, so a floor plan can show the cameras of every room on it.
+func (s *FloorPlanService) PlaceCamera(ctx context.Context, cameraID uuid.UUID, req *ports.CameraPlacementRequest) (*domain.CameraPlacement, error) {
+	camera, err := s.cameras.GetByID(ctx, cameraID.String())
+	if err != nil {
+		return nil, err
+	}
+	if camera == nil {
+		return nil, domain.ErrCameraNotFound
+	}
+	if camera.ZoneID == nil {
+		return nil, domain.ErrPlacementPlan
+	}
+	zone, err := s.zones.GetByID(ctx, *camera.ZoneID)
+	if err != nil {
+		return nil, err
+	}
+	if zone == nil {
+		return nil, domain.ErrZoneNotFound
+	}
+
+	planZoneID := uuid.MustParse(zone.ID)
+	if req.PlanZoneID != nil {
+		planZoneID = *req.PlanZoneID
+	}
+	if !slices.Contains(zone.AncestorIDs(), planZoneID.String()) {
+		return nil, domain.ErrPlacementPlan
+	}
+	plan, err := s.GetPlan(ctx, planZoneID)
+	if err != nil {
+		return nil, err
+	}
+
+	placement := &domain.CameraPlacement{
+		CameraID:   cameraID,
+		PlanZoneID: planZoneID,
+		X:          req.X,
+		Y:          req.Y,
+		Heading:    req.Heading,
+		FOV:        defaultPlacementFOV,
+		Range:      float64(max(plan.Width, plan.Height)) / 10,
+	}
+	if req.FOV != nil {
+		placement.FOV = *req.FOV
+	}
+	if req.Range != nil {
+		placement.Range = *req.Range
+	}
+	if placement.Heading < 0 || placement.Heading >= 360 || placement.FOV <= 0 || placement.FOV > 360 || placement.Range <= 0 {
+		return nil, domain.ErrInvalidPlacement
+	}
+	if !plan.Contains(domain.Point{placement.X, placement.Y}) {
+		return nil, domain.ErrOutsideFloorPlan
+	}
+
+	if err := s.repo.SavePlacement(ctx, placement); err != nil {
+		return nil, err
+	}
+	return placement, nil
+}
+
+func (s *FloorPlanService) RemovePlacement(ctx context.Context, cameraID uuid.UUID) error {
+	return s.repo.DeletePlacement(ctx, cameraID)
+}
+
+// SetZoneArea draws the zone on the plan of a zone strictly above it; a
+// zone's own plan is the whole image and needs no outline.
+func (s *FloorPlanService) SetZoneArea(ctx context.Context, zoneID uuid.UUID, req *ports.ZoneAreaRequest) (*domain.ZoneArea, error) {
+	zone, err := s.zone(ctx, zoneID)
+	if err != nil {
+		return nil, err
+	}
+	var planZoneID uuid.UUID
+	switch {
+	case req.PlanZoneID != nil:
+		planZoneID = *req.PlanZoneID
+	case zone.ParentID != nil:
+		planZoneID = uuid.MustParse(*zone.ParentID)
+	default:
+		return nil, domain.ErrZoneAreaPlan
+	}
+	ancestors := zone.AncestorIDs()
+	if !slices.Contains(ancestors[:len(ancestors)-1], planZoneID.String()) {
+		return nil, domain.ErrZoneAreaPlan
+	}
+	plan, err := s.GetPlan(ctx, planZoneID)
+	if err != nil {
+		return nil, err
+	}
+
+	polygon := req.Polygon
+	if len(polygon) > 1 && polygon[0] == polygon[len(polygon)-1] {
+		polygon = polygon[:len(polygon)-1]
+	}
+	if len(polygon) < 3 {
+		return nil, domain.ErrInvalidZoneArea
+	}
+	for _, pt := range polygon {
+		if math.IsNaN(pt[0]) || math.IsNaN(pt[1]) {
+			return nil, domain.ErrInvalidZoneArea
+		}
+		if !plan.Contains(pt) {
+			return nil, domain.ErrOutsideFloorPlan
+		}
+	}
+
+	area := &domain.ZoneArea{
+		ZoneID:     zoneID,
+		PlanZoneID: planZoneID,
+		Polygon:    polygon,
+	}
+	if err := s.repo.SaveArea(ctx, area); err != nil {
+		return nil, err
+	}
+	return area, nil
+}
+
+func (s *FloorPlanService) RemoveZoneArea(ctx context.Context, zoneID uuid.UUID) error {
+	return s.repo.DeleteArea(ctx, zoneID)
+}
+
+// Map lays out the plan's zone areas first and cameras last, so a client
+// drawing features in order puts cameras on top.
+func (s *FloorPlanService) Map(ctx context.Context, zoneID uuid.UUID, window time.Duration, scope *domain.CameraScope) (*domain.FloorPlanMap, error) {
+	plan, err := s.GetPlan(ctx, zoneID)
+	if err != nil {
+		return nil, err
+	}
+	if window <= 0 {
+		window = defaultMapWindow
+	}
+	since := time.Now().Add(-window)
+
+	areas, err := s.repo.ListAreaStats(ctx, zoneID, since, scope)
+	if err != nil {
+		return nil, err
+	}
+	cameras, err := s.repo.ListPlacedCameras(ctx, zoneID, since, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	features := []domain.MapFeature{}
+	for _, a := range areas {
+		ring := append(slices.Clone(a.Polygon), a.Polygon[0])
+		features = append(features, domain.MapFeature{
+			Type:     "Feature",
+			ID:       "zone:" + a.ZoneID.String(),
+			Geometry: domain.MapGeometry{Type: "Polygon", Coordinates: [][]domain.Point{ring}},
+			Properties: map[string]any{
+				"layer":       "zone",
+				"zone_id":     a.ZoneID,
+				"name":        a.Name,
+				"kind":        a.Kind,
+				"cameras":     a.Cameras,
+				"event_count": a.EventCount,
+			},
+		})
+	}
+	for _, c := range cameras {
+		features = append(features, domain.MapFeature{
+			Type:     "Feature",
+			ID:       "camera_fov:" + c.CameraID.String(),
+			Geometry: domain.MapGeometry{Type: "Polygon", Coordinates: [][]domain.Point{c.FOVCone()}},
+			Properties: map[string]any{
+				"layer":     "camera_fov",
+				"camera_id": c.CameraID,
+			},
+		})
+	}
+	for _, c := range cameras {
+		features = append(features, domain.MapFeature{
+			Type:     "Feature",
+			ID:       "camera:" + c.CameraID.String(),
+			Geometry: domain.MapGeometry{Type: "Point", Coordinates: domain.Point{c.X, c.Y}},
+			Properties: map[string]any{
+				"layer":       "camera",
+				"camera_id":   c.CameraID,
+				"name":        c.Name,
+				"status":      c.Status,
+				"heading":     c.Heading,
+				"fov":         c.FOV,
+				"range":       c.Range,
+				"event_count": c.EventCount,
+			},
+		})
+	}
+
+	return &domain.FloorPlanMap{
+		Type:          "FeatureCollection",
+		FloorPlan:     plan,
+		WindowMinutes: int(window / time.Minute),
+		Features:      features,
+	}, nil
+}
+
+func (s *FloorPlanService) zone(ctx context.Context, id uuid.UUID) (*domain.Zone, error) {
+	zone, err := s.zones.GetByID(ctx, id.String())
+	if err != nil {
+		return nil, err
+	}
+	if zone == nil {
+		return nil, domain.ErrZoneNotFound
+	}
+	return zone, nil
+}
+
+func (s *FloorPlanService) removeImage(ctx context.Context, url string) {
+	if err := s.storage.DeleteFile(ctx, url); err != nil {
+		logger.Error("Failed to remove floor plan image", zap.String("url", url), zap.Error(err))
+	}
+}
+
+func (s *FloorPlanService) auditPlan(ctx context.Context, userID *uuid.UUID, action string, zoneID uuid.UUID, oldValue, newValue *domain.FloorPlan) {
+	log := &domain.AuditLog{
+		UserID:    userID,
+		Action:    action,
+		TableName: "floor_plans",
+		RecordID:  zoneID.String(),
+	}
+	if oldValue != nil {
+		log.OldValue = floorPlanAuditValue(oldValue)
+	}
+	if newValue != nil {
+		log.NewValue = floorPlanAuditValue(newValue)
+	}
+	if err := s.audit.LogAction(ctx, log); err != nil {
+		logger.Error("Failed to audit floor plan", zap.String("zone_id", zoneID.String()), zap.Error(err))
+	}
+}
+
+func floorPlanAuditValue(p *domain.FloorPlan) map[string]any {
+	return map[string]any{
+		"image_url": p.ImageURL,
+		"width":     p.Width,
+		"height":    p.Height,
+	}
+}
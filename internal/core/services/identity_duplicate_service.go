@@ -0,0 +1,327 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"unicode"
+
+	"app/config"
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+	"app/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Signal weights, combined as 1 - product(1 - w). The face signal weighs in
+// with its similarity score.
+const (
+	duplicateWeightCard  = 0.95
+	duplicateWeightPhone = 0.6
+	duplicateWeightName  = 0.35
+
+	duplicateFaceTopK = 5
+	// Larger groups sharing a phone or CCCD are placeholder values, not people
+	duplicateMaxGroup = 20
+)
+
+type IdentityDuplicateService struct {
+	repo       ports.IdentityDuplicateRepository
+	identities ports.IdentityRepository
+	faceRepo   ports.IdentityFaceRepository
+	versions   ports.IdentityVersionRepository
+	faceIndex  ports.FaceIndex
+	jobs       ports.JobService
+	audit      ports.AuditService
+	publisher  ports.EventPublisher
+	cfg        config.DuplicatesConfig
+}
+
+func NewIdentityDuplicateService(
+	repo ports.IdentityDuplicateRepository,
+	identities ports.IdentityRepository,
+	faceRepo ports.IdentityFaceRepository,
+	versions ports.IdentityVersionRepository,
+	faceIndex ports.FaceIndex,
+	jobs ports.JobService,
+	audit ports.AuditService,
+	publisher ports.EventPublisher,
+	cfg config.DuplicatesConfig,
+) ports.IdentityDuplicateService {
+	return &IdentityDuplicateService{
+		repo:       repo,
+		identities: identities,
+		faceRepo:   faceRepo,
+		versions:   versions,
+		faceIndex:  faceIndex,
+		jobs:       jobs,
+		audit:      audit,
+		publisher:  publisher,
+		cfg:        cfg,
+	}
+}
+
+// duplicateSignals collects the evidence found for one pair.
+type duplicateSignals struct {
+	card, phone, name bool
+	face              float64
+}
+
+type identityPair [2]uuid.UUID
+
+func newIdentityPair(a, b uuid.UUID) identityPair {
+	if bytes.Compare(a[:], b[:]) > 0 {
+		a, b = b, a
+	}
+	return identityPair{a, b}
+}
+
+// StartScan compares every live identity by CCCD, phone and face embedding,
+// then uses the normalized name to strengthen pairs found that way. Names
+// alone are too common to report on.
+func (s *IdentityDuplicateService) StartScan(ctx context.Context, requestedBy *uuid.UUID) (*domain.Job, error) {
+	params := map[string]any{"min_score": s.cfg.MinScore, "face_min_score": s.cfg.FaceMinScore}
+	return s.jobs.Start(ctx, domain.JobTypeDuplicateScan, requestedBy, params, func(ctx context.Context, tracker ports.JobTracker) (map[string]any, error) {
+		keys, err := s.repo.ListMatchKeys(ctx)
+		if err != nil {
+			return nil, err
+		}
+		faces, err := s.faceRepo.ListEmbeddings(ctx)
+		if err != nil {
+			return nil, err
+		}
+		tracker.SetTotal(len(faces))
+
+		live := make(map[uuid.UUID]*ports.IdentityMatchKey, len(keys))
+		byCard := make(map[string][]uuid.UUID)
+		byPhone := make(map[string][]uuid.UUID)
+		for _, k := range keys {
+			live[k.ID] = k
+			if k.CardKey != "" {
+				byCard[k.CardKey] = append(byCard[k.CardKey], k.ID)
+			}
+			if k.PhoneKey != "" {
+				byPhone[k.PhoneKey] = append(byPhone[k.PhoneKey], k.ID)
+			}
+		}
+
+		pairs := make(map[identityPair]*duplicateSignals)
+		signals := func(a, b uuid.UUID) *duplicateSignals {
+			p := newIdentityPair(a, b)
+			if pairs[p] == nil {
+				pairs[p] = &duplicateSignals{}
+			}
+			return pairs[p]
+		}
+		eachPair := func(groups map[string][]uuid.UUID, mark func(*duplicateSignals)) {
+			for _, ids := range groups {
+				if len(ids) < 2 || len(ids) > duplicateMaxGroup {
+					continue
+				}
+				for i := range ids {
+					for j := i + 1; j < len(ids); j++ {
+						mark(signals(ids[i], ids[j]))
+					}
+				}
+			}
+		}
+		eachPair(byCard, func(d *duplicateSignals) { d.card = true })
+		eachPair(byPhone, func(d *duplicateSignals) { d.phone = true })
+
+		for _, f := range faces {
+			if live[f.IdentityID] == nil {
+				tracker.Step(true)
+				continue
+			}
+			matches, err := s.faceIndex.Search(ctx, f.Embedding, duplicateFaceTopK, s.cfg.FaceMinScore)
+			if err != nil {
+				tracker.Step(false)
+				continue
+			}
+			for _, m := range matches {
+				if m.IdentityID == f.IdentityID || live[m.IdentityID] == nil {
+					continue
+				}
+				if d := signals(f.IdentityID, m.IdentityID); m.Score > d.face {
+					d.face = m.Score
+				}
+			}
+			tracker.Step(true)
+		}
+
+		candidates := []*domain.DuplicateCandidate{}
+		for p, d := range pairs {
+			d.name = normalizeName(live[p[0]].FullName) != "" &&
+				normalizeName(live[p[0]].FullName) == normalizeName(live[p[1]].FullName)
+			c := scoreDuplicate(p, d)
+			if c.Score >= s.cfg.MinScore {
+				candidates = append(candidates, c)
+			}
+		}
+		if err := s.repo.ReplaceOpenCandidates(ctx, candidates); err != nil {
+			return nil, err
+		}
+		return map[string]any{"identities": len(keys), "faces": len(faces), "candidates": len(candidates)}, nil
+	})
+}
+
+func scoreDuplicate(p identityPair, d *duplicateSignals) *domain.DuplicateCandidate {
+	c := &domain.DuplicateCandidate{IdentityA: p[0], IdentityB: p[1], Reasons: []string{}}
+	miss := 1.0
+	add := func(reason string, weight float64) {
+		c.Reasons = append(c.Reasons, reason)
+		miss *= 1 - weight
+	}
+	if d.card {
+		add(domain.DuplicateReasonCardNumber, duplicateWeightCard)
+	}
+	if d.phone {
+		add(domain.DuplicateReasonPhone, duplicateWeightPhone)
+	}
+	if d.face > 0 {
+		face := d.face
+		c.FaceScore = &face
+		add(domain.DuplicateReasonFace, face)
+	}
+	if d.name {
+		add(domain.DuplicateReasonName, duplicateWeightName)
+	}
+	c.Score = 1 - miss
+	return c
+}
+
+// normalizeName folds case, Vietnamese diacritics and spacing, so
+// "Nguyễn  Văn Đức" and "nguyen van duc" compare equal.
+func normalizeName(name string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(strings.ToLower(name)) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+		case r == 'đ':
+			b.WriteRune('d')
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		default:
+			b.WriteRune(' ')
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+func (s *IdentityDuplicateService) ListCandidates(ctx context.Context, filter *ports.DuplicateFilter) ([]*domain.DuplicateCandidate, int64, error) {
+	if filter.Limit < 1 {
+		filter.Limit = 20
+	}
+	return s.repo.ListCandidates(ctx, filter)
+}
+
+func (s *IdentityDuplicateService) DismissCandidate(ctx context.Context, id uuid.UUID, dismissedBy *uuid.UUID) error {
+	candidate, err := s.repo.GetCandidate(ctx, id)
+	if err != nil {
+		return err
+	}
+	if candidate == nil {
+		return domain.ErrDuplicateCandidateNotFound
+	}
+	if err := s.repo.ResolveCandidate(ctx, id, domain.DuplicateStatusDismissed, dismissedBy); err != nil {
+		return err
+	}
+
+	if err := s.audit.LogAction(ctx, &domain.AuditLog{
+		UserID:    dismissedBy,
+		Action:    "IDENTITY_DUPLICATE_DISMISS",
+		TableName: "identity_duplicate_candidates",
+		RecordID:  id.String(),
+		NewValue:  map[string]any{"identity_a": candidate.IdentityA, "identity_b": candidate.IdentityB, "score": candidate.Score},
+	}); err != nil {
+		logger.Error("Failed to audit duplicate dismissal", zap.Error(err))
+	}
+	return nil
+}
+
+// MergeIdentities folds req.SourceID into survivorID. The source is soft
+// deleted with merged_into set, so its history and audit trail stay readable.
+func (s *IdentityDuplicateService) MergeIdentities(ctx context.Context, survivorID uuid.UUID, req *ports.MergeIdentitiesRequest) (*domain.IdentityMerge, error) {
+	if req.Reason == "" {
+		return nil, domain.ErrMergeReasonRequired
+	}
+	if survivorID == req.SourceID {
+		return nil, domain.ErrMergeSameIdentity
+	}
+	survivor, err := s.identities.GetIdentity(ctx, survivorID)
+	if err != nil {
+		return nil, err
+	}
+	merged, err := s.identities.GetIdentity(ctx, req.SourceID)
+	if err != nil {
+		return nil, err
+	}
+	if survivor == nil || merged == nil {
+		return nil, domain.ErrIdentityNotFound
+	}
+
+	result, err := s.repo.MergeIdentities(ctx, survivorID, req.SourceID, req.MergedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	// Re-key the moved faces in the search index and the history
+	if err := s.faceIndex.RemoveIdentity(ctx, req.SourceID); err != nil {
+		logger.Error("Failed to remove merged identity from face index", zap.Error(err))
+	}
+	moved := make(map[uuid.UUID]bool, len(result.FaceIDs))
+	for _, id := range result.FaceIDs {
+		moved[id] = true
+	}
+	faces, err := s.faceRepo.ListFaces(ctx, survivorID)
+	if err != nil {
+		logger.Error("Failed to load merged faces", zap.Error(err))
+	}
+	for _, f := range faces {
+		if !moved[f.ID] {
+			continue
+		}
+		if err := s.faceIndex.Upsert(ctx, f); err != nil {
+			logger.Error("Failed to index merged face", zap.String("face_id", f.ID.String()), zap.Error(err))
+		}
+		old := *f
+		old.IdentityID = req.SourceID
+		recordFaceVersion(ctx, s.versions, domain.VersionOpFaceDelete, &old, req.MergedBy, req.Reason)
+		recordFaceVersion(ctx, s.versions, domain.VersionOpMerge, f, req.MergedBy, req.Reason)
+	}
+
+	after := *merged
+	after.FaceImageURL = ""
+	after.DeletedAt = &result.MergedAt
+	after.MergedInto = &survivorID
+	after.UpdatedAt = result.MergedAt
+	recordIdentityVersion(ctx, s.versions, domain.VersionOpMerge, merged, &after, req.MergedBy, req.Reason, nil)
+
+	if err := s.audit.LogAction(ctx, &domain.AuditLog{
+		UserID:    req.MergedBy,
+		Action:    "IDENTITY_MERGE",
+		TableName: "identities",
+		RecordID:  survivorID.String(),
+		OldValue:  map[string]any{"merged_id": req.SourceID, "merged_code": merged.Code},
+		NewValue: map[string]any{
+			"reason":                     req.Reason,
+			"faces_moved":                result.FacesMoved,
+			"recognition_logs_moved":     result.RecognitionLogsMoved,
+			"attendance_records_moved":   result.AttendanceRecordsMoved,
+			"attendance_days_reconciled": result.AttendanceDaysReconciled,
+		},
+	}); err != nil {
+		logger.Error("Failed to audit identity merge", zap.Error(err))
+	}
+
+	if merged.Status == domain.IdentityStatusActive {
+		publishIdentitySync(ctx, s.publisher, s.faceRepo, &domain.Identity{ID: req.SourceID})
+	}
+	if survivor.Status == domain.IdentityStatusActive {
+		publishIdentitySync(ctx, s.publisher, s.faceRepo, survivor)
+	}
+	return result, nil
+}
@@ -0,0 +1,222 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"time"
+
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+	"app/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// recordIdentityVersion appends an identity entry to the history. before is
+// nil for new identities; otherwise it also serves as the baseline for
+// identities created before history was kept. Failures are logged, not
+// returned, like audit writes.
+func recordIdentityVersion(ctx context.Context, versions ports.IdentityVersionRepository, op string, before, after *domain.Identity, by *uuid.UUID, reason string, revertOf *int) {
+	v := &domain.IdentityVersion{
+		IdentityID: after.ID,
+		EntityType: domain.VersionEntityIdentity,
+		Operation:  op,
+		Identity:   after,
+		Changes:    diffIdentity(before, after),
+		RevertOf:   revertOf,
+		Reason:     reason,
+		ChangedBy:  by,
+	}
+	if err := versions.RecordVersion(ctx, v, before); err != nil {
+		logger.Error("Failed to record identity version", zap.String("identity_id", after.ID.String()), zap.Error(err))
+	}
+}
+
+func recordFaceVersion(ctx context.Context, versions ports.IdentityVersionRepository, op string, face *domain.IdentityFace, by *uuid.UUID, reason string) {
+	faceID := face.ID
+	v := &domain.IdentityVersion{
+		IdentityID: face.IdentityID,
+		EntityType: domain.VersionEntityFace,
+		FaceID:     &faceID,
+		Operation:  op,
+		Face:       face,
+		Reason:     reason,
+		ChangedBy:  by,
+	}
+	if err := versions.RecordVersion(ctx, v, nil); err != nil {
+		logger.Error("Failed to record face version", zap.String("face_id", faceID.String()), zap.Error(err))
+	}
+}
+
+// diffIdentity lists the fields that differ between two identity states.
+func diffIdentity(before, after *domain.Identity) []domain.FieldChange {
+	if before == nil {
+		before = &domain.Identity{}
+	}
+	var changes []domain.FieldChange
+	add := func(field string, old, new any) {
+		if !reflect.DeepEqual(old, new) {
+			changes = append(changes, domain.FieldChange{Field: field, Old: old, New: new})
+		}
+	}
+	add("code", before.Code, after.Code)
+	add("full_name", before.FullName, after.FullName)
+	add("type", before.Type, after.Type)
+	add("phone_number", before.PhoneNumber, after.PhoneNumber)
+	add("identity_card_number", before.IdentityCardNumber, after.IdentityCardNumber)
+	add("face_image_url", before.FaceImageURL, after.FaceImageURL)
+	add("department", before.Department, after.Department)
+	add("note", before.Note, after.Note)
+	add("status", before.Status, after.Status)
+	add("review_reason", before.ReviewReason, after.ReviewReason)
+	add("deleted_at", before.DeletedAt, after.DeletedAt)
+
+	keys := make(map[string]bool)
+	for k := range before.Metadata {
+		keys[k] = true
+	}
+	for k := range after.Metadata {
+		keys[k] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+	for _, k := range sorted {
+		add("metadata."+k, before.Metadata[k], after.Metadata[k])
+	}
+	return changes
+}
+
+func (s *IdentityService) ListHistory(ctx context.Context, id uuid.UUID, limit, offset int32) ([]*domain.IdentityVersion, int64, error) {
+	if limit < 1 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return s.versions.ListVersions(ctx, id, limit, offset)
+}
+
+// GetIdentityAsOf rebuilds an identity and its faces as they were at asOf.
+// Identities and faces that predate the history fall back to their current
+// state when they already existed at asOf.
+func (s *IdentityService) GetIdentityAsOf(ctx context.Context, id uuid.UUID, asOf time.Time) (*domain.Identity, error) {
+	var identity *domain.Identity
+	v, err := s.versions.IdentityAsOf(ctx, id, asOf)
+	if err != nil {
+		return nil, err
+	}
+	if v != nil {
+		identity = v.Identity
+	} else {
+		first, err := s.versions.GetVersion(ctx, id, 1)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case first != nil && first.Operation == domain.VersionOpBaseline:
+			identity = first.Identity
+		case first == nil:
+			if identity, err = s.repo.GetIdentity(ctx, id); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if identity == nil || identity.CreatedAt.After(asOf) {
+		return nil, domain.ErrIdentityNotFound
+	}
+
+	faceVersions, err := s.versions.FacesAsOf(ctx, id, asOf)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[uuid.UUID]bool, len(faceVersions))
+	identity.Faces = []domain.IdentityFace{}
+	for _, fv := range faceVersions {
+		seen[*fv.FaceID] = true
+		if fv.Operation != domain.VersionOpFaceDelete {
+			identity.Faces = append(identity.Faces, *fv.Face)
+		}
+	}
+	// Faces enrolled before history was kept have no entries at all
+	recorded, err := s.versions.FacesAsOf(ctx, id, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	for _, fv := range recorded {
+		seen[*fv.FaceID] = true
+	}
+	current, err := s.faceRepo.ListFaces(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range current {
+		if !seen[f.ID] && !f.CreatedAt.After(asOf) {
+			identity.Faces = append(identity.Faces, *f)
+		}
+	}
+	return identity, nil
+}
+
+// RevertIdentity restores the editable fields of an identity from a previous
+// version, recording the revert as a new version and in the audit log.
+func (s *IdentityService) RevertIdentity(ctx context.Context, id uuid.UUID, req *ports.RevertIdentityRequest) (*domain.Identity, error) {
+	if req.Reason == "" {
+		return nil, domain.ErrRevertReasonRequired
+	}
+	target, err := s.versions.GetVersion(ctx, id, req.Version)
+	if err != nil {
+		return nil, err
+	}
+	if target == nil {
+		return nil, domain.ErrIdentityVersionNotFound
+	}
+	if target.EntityType != domain.VersionEntityIdentity {
+		return nil, domain.ErrVersionNotRevertible
+	}
+
+	current, err := s.repo.GetIdentity(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, domain.ErrIdentityNotFound
+	}
+	before := *current
+
+	old := target.Identity
+	current.FullName = old.FullName
+	current.Type = old.Type
+	current.PhoneNumber = old.PhoneNumber
+	current.IdentityCardNumber = old.IdentityCardNumber
+	current.FaceImageURL = old.FaceImageURL
+	current.Department = old.Department
+	current.Metadata = old.Metadata
+	current.Note = old.Note
+
+	identity, err := s.repo.UpdateIdentity(ctx, current)
+	if err != nil {
+		return nil, err
+	}
+
+	revertOf := target.Version
+	recordIdentityVersion(ctx, s.versions, domain.VersionOpRevert, &before, identity, req.RevertedBy, req.Reason, &revertOf)
+	if err := s.audit.LogAction(ctx, &domain.AuditLog{
+		UserID:    req.RevertedBy,
+		Action:    "IDENTITY_REVERT",
+		TableName: "identities",
+		RecordID:  id.String(),
+		NewValue:  map[string]any{"version": target.Version, "reason": req.Reason},
+	}); err != nil {
+		logger.Error("Failed to audit identity revert", zap.Error(err))
+	}
+
+	if identity.Status == domain.IdentityStatusActive {
+		s.publishIdentity(ctx, identity)
+	}
+	return identity, nil
+}
@@ -0,0 +1,536 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"app/config"
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+	"app/pkg/logger"
+	"app/pkg/sheet"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	importMaxRows       = 10000
+	importMaxPhotoBytes = 10 << 20
+)
+
+// importColumns maps accepted header spellings to identity fields. Any
+// other column is kept in the identity metadata.
+var importColumns = map[string]string{
+	"code":                 "code",
+	"ma":                   "code",
+	"full_name":            "full_name",
+	"name":                 "full_name",
+	"ho_ten":               "full_name",
+	"type":                 "type",
+	"loai":                 "type",
+	"phone_number":         "phone_number",
+	"phone":                "phone_number",
+	"sdt":                  "phone_number",
+	"identity_card_number": "identity_card_number",
+	"cccd":                 "identity_card_number",
+	"department":           "department",
+	"phong_ban":            "department",
+	"note":                 "note",
+	"ghi_chu":              "note",
+}
+
+var (
+	importPhonePattern = regexp.MustCompile(`^\+?[0-9 .\-]{8,20}$`)
+	importCardPattern  = regexp.MustCompile(`^[0-9]{9}$|^[0-9]{12}$`)
+	importPhotoSuffix  = regexp.MustCompile(`_[0-9]+$`)
+	importPhotoExts    = map[string]bool{".jpg": true, ".jpeg": true, ".png": true}
+)
+
+type IdentityImportService struct {
+	repo       ports.IdentityRepository
+	versions   ports.IdentityVersionRepository
+	storage    ports.FileStorage
+	faceIndex  ports.FaceIndex
+	embedder   ports.EmbeddingProvider // Optional
+	jobs       ports.JobService
+	audit      ports.AuditService
+	qualityCfg config.FaceQualityConfig
+}
+
+func NewIdentityImportService(
+	repo ports.IdentityRepository,
+	versions ports.IdentityVersionRepository,
+	storage ports.FileStorage,
+	faceIndex ports.FaceIndex,
+	embedder ports.EmbeddingProvider,
+	jobs ports.JobService,
+	audit ports.AuditService,
+	qualityCfg config.FaceQualityConfig,
+) ports.IdentityImportService {
+	return &IdentityImportService{
+		repo:       repo,
+		versions:   versions,
+		storage:    storage,
+		faceIndex:  faceIndex,
+		embedder:   embedder,
+		jobs:       jobs,
+		audit:      audit,
+		qualityCfg: qualityCfg,
+	}
+}
+
+// importRow is a parsed sheet row together with the photos found for it.
+type importRow struct {
+	line     int
+	identity *domain.Identity
+	photos   []*zip.File
+	faces    []domain.IdentityFace
+	// sources holds the archive entry of each face; the photo is read again
+	// from the archive when stored rather than kept in memory
+	sources []*zip.File
+	errors  []string
+}
+
+// StartImport parses the sheet up front so that malformed uploads are
+// rejected immediately; row validation and writes happen in the job.
+func (s *IdentityImportService) StartImport(ctx context.Context, req *ports.IdentityImportRequest) (*domain.Job, error) {
+	cleanup := func() {
+		if req.PhotosPath != "" {
+			_ = os.Remove(req.PhotosPath)
+		}
+	}
+
+	if req.Mode == "" {
+		req.Mode = ports.ImportModeAllOrNothing
+	}
+	if req.Mode != ports.ImportModeAllOrNothing && req.Mode != ports.ImportModeSkipInvalid {
+		cleanup()
+		return nil, fmt.Errorf("%w: mode must be %s or %s", domain.ErrInvalidJobInput, ports.ImportModeAllOrNothing, ports.ImportModeSkipInvalid)
+	}
+
+	records, err := sheet.Read(req.SheetName, req.Sheet)
+	if err != nil {
+		cleanup()
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidJobInput, err)
+	}
+	rows, err := parseImportRows(records)
+	if err != nil {
+		cleanup()
+		return nil, err
+	}
+
+	params := map[string]any{
+		"file":       req.SheetName,
+		"mode":       req.Mode,
+		"rows":       len(rows),
+		"has_photos": req.PhotosPath != "",
+	}
+	job, err := s.jobs.Start(ctx, domain.JobTypeIdentityImport, req.CreatedBy, params, func(ctx context.Context, tracker ports.JobTracker) (map[string]any, error) {
+		defer cleanup()
+		return s.runImport(ctx, tracker, rows, req)
+	})
+	if err != nil {
+		cleanup()
+		return nil, err
+	}
+	return job, nil
+}
+
+func parseImportRows(records [][]string) ([]*importRow, error) {
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%w: file is empty", domain.ErrInvalidJobInput)
+	}
+
+	header := make([]string, len(records[0]))
+	seen := make(map[string]bool)
+	for i, h := range records[0] {
+		key := strings.ToLower(strings.Join(strings.Fields(h), "_"))
+		if field, ok := importColumns[key]; ok {
+			key = field
+		}
+		if key != "" && seen[key] {
+			return nil, fmt.Errorf("%w: duplicate column %q", domain.ErrInvalidJobInput, h)
+		}
+		seen[key] = true
+		header[i] = key
+	}
+	if !seen["code"] || !seen["full_name"] {
+		return nil, fmt.Errorf("%w: header must contain code and full_name columns", domain.ErrInvalidJobInput)
+	}
+
+	rows := make([]*importRow, 0, len(records)-1)
+	for i, record := range records[1:] {
+		identity := &domain.Identity{Metadata: map[string]any{}}
+		empty := true
+		for col, value := range record {
+			value = strings.TrimSpace(value)
+			if col >= len(header) || header[col] == "" || value == "" {
+				continue
+			}
+			empty = false
+			switch header[col] {
+			case "code":
+				identity.Code = value
+			case "full_name":
+				identity.FullName = value
+			case "type":
+				identity.Type = strings.ToUpper(value)
+			case "phone_number":
+				identity.PhoneNumber = value
+			case "identity_card_number":
+				identity.IdentityCardNumber = value
+			case "department":
+				identity.Department = value
+			case "note":
+				identity.Note = value
+			default:
+				identity.Metadata[header[col]] = value
+			}
+		}
+		if empty {
+			continue
+		}
+		rows = append(rows, &importRow{line: i + 2, identity: identity})
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: file has no data rows", domain.ErrInvalidJobInput)
+	}
+	if len(rows) > importMaxRows {
+		return nil, fmt.Errorf("%w: file has %d rows, the limit is %d", domain.ErrInvalidJobInput, len(rows), importMaxRows)
+	}
+	return rows, nil
+}
+
+func (s *IdentityImportService) runImport(ctx context.Context, tracker ports.JobTracker, rows []*importRow, req *ports.IdentityImportRequest) (map[string]any, error) {
+	tracker.SetTotal(len(rows))
+
+	var photos map[string][]*zip.File
+	if req.PhotosPath != "" {
+		archive, err := zip.OpenReader(req.PhotosPath)
+		if err != nil {
+			return nil, fmt.Errorf("invalid photo archive: %w", err)
+		}
+		defer archive.Close()
+		codes := make(map[string]bool, len(rows))
+		for _, row := range rows {
+			codes[row.identity.Code] = true
+		}
+		photos = indexImportPhotos(archive.File, codes)
+	}
+
+	s.validateRows(ctx, rows, photos)
+
+	var valid []*importRow
+	var rowErrors []domain.ImportRowError
+	for _, row := range rows {
+		if len(row.errors) > 0 {
+			rowErrors = append(rowErrors, domain.ImportRowError{Row: row.line, Code: row.identity.Code, Errors: row.errors})
+			tracker.Step(false)
+			continue
+		}
+		valid = append(valid, row)
+	}
+
+	result := map[string]any{
+		"mode":     req.Mode,
+		"imported": 0,
+		"invalid":  len(rowErrors),
+		"errors":   rowErrors,
+	}
+	if len(rowErrors) > 0 && req.Mode == ports.ImportModeAllOrNothing {
+		return result, fmt.Errorf("%d of %d rows are invalid, nothing was imported", len(rowErrors), len(rows))
+	}
+	if len(valid) == 0 {
+		return result, nil
+	}
+
+	var saved []string
+	rollback := func() {
+		for _, url := range saved {
+			if err := s.storage.DeleteFile(context.Background(), url); err != nil {
+				logger.Error("Failed to remove imported photo", zap.String("url", url), zap.Error(err))
+			}
+		}
+	}
+
+	identities := make([]*domain.Identity, 0, len(valid))
+	for _, row := range valid {
+		for i := range row.faces {
+			url, err := s.storePhoto(ctx, row.identity.Code, row.sources[i])
+			if err != nil {
+				rollback()
+				return result, fmt.Errorf("failed to store photo for %s: %w", row.identity.Code, err)
+			}
+			saved = append(saved, url)
+			row.faces[i].ImageURL = url
+		}
+		if len(row.faces) > 0 {
+			row.identity.FaceImageURL = row.faces[0].ImageURL
+		}
+		row.identity.Faces = row.faces
+		row.identity.Status = domain.IdentityStatusPending
+		row.identity.CreatedBy = req.CreatedBy
+		identities = append(identities, row.identity)
+	}
+
+	if err := s.repo.ImportIdentities(ctx, identities); err != nil {
+		rollback()
+		return result, err
+	}
+
+	var versions []*domain.IdentityVersion
+	for _, identity := range identities {
+		for i := range identity.Faces {
+			face := &identity.Faces[i]
+			if err := s.faceIndex.Upsert(ctx, face); err != nil {
+				logger.Error("Failed to index imported face", zap.String("code", identity.Code), zap.Error(err))
+			}
+			faceID := face.ID
+			versions = append(versions, &domain.IdentityVersion{
+				IdentityID: identity.ID,
+				EntityType: domain.VersionEntityFace,
+				FaceID:     &faceID,
+				Operation:  domain.VersionOpFaceEnroll,
+				Face:       face,
+				ChangedBy:  req.CreatedBy,
+				ChangedAt:  face.CreatedAt,
+			})
+		}
+		versions = append(versions, &domain.IdentityVersion{
+			IdentityID: identity.ID,
+			EntityType: domain.VersionEntityIdentity,
+			Operation:  domain.VersionOpImport,
+			Identity:   identity,
+			Changes:    diffIdentity(nil, identity),
+			ChangedBy:  req.CreatedBy,
+			ChangedAt:  identity.CreatedAt,
+		})
+		tracker.Step(true)
+	}
+	result["imported"] = len(identities)
+	if err := s.versions.RecordVersions(ctx, versions); err != nil {
+		logger.Error("Failed to record imported identity versions", zap.Error(err))
+	}
+
+	if err := s.audit.LogAction(ctx, &domain.AuditLog{
+		UserID:    req.CreatedBy,
+		Action:    "IDENTITY_IMPORT",
+		TableName: "identities",
+		RecordID:  req.SheetName,
+		NewValue:  map[string]any{"mode": req.Mode, "imported": len(identities), "invalid": len(rowErrors)},
+	}); err != nil {
+		logger.Error("Failed to audit identity import", zap.Error(err))
+	}
+	return result, nil
+}
+
+// storePhoto copies an archive entry already checked by loadPhoto to storage.
+func (s *IdentityImportService) storePhoto(ctx context.Context, code string, f *zip.File) (string, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+	filename := fmt.Sprintf("identities/%s_%s%s", code, uuid.New().String()[:8], path.Ext(f.Name))
+	return s.storage.SaveFile(ctx, filename, io.LimitReader(rc, importMaxPhotoBytes))
+}
+
+// indexImportPhotos groups archive entries by identity code. CODE.jpg and
+// CODE_2.jpg both belong to CODE unless the sheet has a row coded CODE_2,
+// which then owns that photo; entries are sorted so CODE.jpg comes first and
+// becomes the primary face.
+func indexImportPhotos(files []*zip.File, codes map[string]bool) map[string][]*zip.File {
+	index := make(map[string][]*zip.File)
+	for _, f := range files {
+		if f.FileInfo().IsDir() || strings.HasPrefix(path.Base(f.Name), ".") {
+			continue
+		}
+		ext := strings.ToLower(path.Ext(f.Name))
+		if !importPhotoExts[ext] {
+			continue
+		}
+		code := strings.TrimSuffix(path.Base(f.Name), path.Ext(f.Name))
+		// Codes may themselves end in _N, so the suffix only marks an extra
+		// photo when no row has the full name as its code
+		if !codes[code] {
+			code = importPhotoSuffix.ReplaceAllString(code, "")
+		}
+		index[code] = append(index[code], f)
+	}
+	for _, list := range index {
+		sort.Slice(list, func(i, j int) bool {
+			a, b := path.Base(list[i].Name), path.Base(list[j].Name)
+			return len(a) < len(b) || len(a) == len(b) && list[i].Name < list[j].Name
+		})
+	}
+	return index
+}
+
+func (s *IdentityImportService) validateRows(ctx context.Context, rows []*importRow, photos map[string][]*zip.File) {
+	codes := make(map[string]int)
+	cards := make(map[string]int)
+
+	for _, row := range rows {
+		id := row.identity
+		addErr := func(format string, args ...any) {
+			row.errors = append(row.errors, fmt.Sprintf(format, args...))
+		}
+
+		switch {
+		case id.Code == "":
+			addErr("code is required")
+		case utf8.RuneCountInString(id.Code) > 50:
+			addErr("code must be at most 50 characters")
+		case strings.ContainsAny(id.Code, `/\`):
+			addErr("code must not contain slashes")
+		}
+		if id.FullName == "" {
+			addErr("full_name is required")
+		} else if utf8.RuneCountInString(id.FullName) > 100 {
+			addErr("full_name must be at most 100 characters")
+		}
+		if id.Type == "" {
+			id.Type = "STAFF"
+		} else if utf8.RuneCountInString(id.Type) > 50 {
+			addErr("type must be at most 50 characters")
+		}
+		if id.PhoneNumber != "" && !importPhonePattern.MatchString(id.PhoneNumber) {
+			addErr("phone_number is invalid")
+		}
+		if id.IdentityCardNumber != "" {
+			// Spreadsheets drop the leading zero of 12-digit CCCD numbers stored as numbers
+			if len(id.IdentityCardNumber) == 11 && importCardPattern.MatchString("0"+id.IdentityCardNumber) {
+				id.IdentityCardNumber = "0" + id.IdentityCardNumber
+			}
+			if !importCardPattern.MatchString(id.IdentityCardNumber) {
+				addErr("identity_card_number must have 9 or 12 digits")
+			}
+		}
+
+		if id.Code != "" {
+			if line, ok := codes[id.Code]; ok {
+				addErr("code %s duplicates row %d", id.Code, line)
+			} else {
+				codes[id.Code] = row.line
+				if existing, err := s.repo.GetIdentityByCode(ctx, id.Code); err != nil {
+					addErr("failed to check code: %v", err)
+				} else if existing != nil {
+					addErr("code %s already exists", id.Code)
+				}
+			}
+		}
+		if id.IdentityCardNumber != "" {
+			if line, ok := cards[id.IdentityCardNumber]; ok {
+				addErr("identity_card_number duplicates row %d", line)
+			} else {
+				cards[id.IdentityCardNumber] = row.line
+				if existing, err := s.repo.GetIdentityByCardNumber(ctx, id.IdentityCardNumber); err != nil {
+					addErr("failed to check identity_card_number: %v", err)
+				} else if existing != nil {
+					addErr("identity_card_number already belongs to %s", existing.Code)
+				}
+			}
+		}
+
+		if photos != nil && id.Code != "" {
+			row.photos = photos[id.Code]
+			if len(row.photos) == 0 {
+				addErr("no photo found for code %s", id.Code)
+			}
+			for i, f := range row.photos {
+				s.loadPhoto(ctx, row, f, i == 0)
+			}
+		}
+	}
+}
+
+// loadPhoto reads and scores one photo. On success the face and its archive
+// entry are appended to the row; otherwise an error is recorded.
+func (s *IdentityImportService) loadPhoto(ctx context.Context, row *importRow, f *zip.File, primary bool) {
+	name := path.Base(f.Name)
+	if f.UncompressedSize64 > importMaxPhotoBytes {
+		row.errors = append(row.errors, fmt.Sprintf("%s: photo exceeds %d MB", name, importMaxPhotoBytes>>20))
+		return
+	}
+	rc, err := f.Open()
+	if err != nil {
+		row.errors = append(row.errors, fmt.Sprintf("%s: %v", name, err))
+		return
+	}
+	data, err := io.ReadAll(io.LimitReader(rc, importMaxPhotoBytes+1))
+	rc.Close()
+	if err != nil {
+		row.errors = append(row.errors, fmt.Sprintf("%s: %v", name, err))
+		return
+	}
+
+	quality, err := AssessFaceQuality(bytes.NewReader(data), s.qualityCfg)
+	if err != nil {
+		row.errors = append(row.errors, fmt.Sprintf("%s: %v", name, err))
+		return
+	}
+	face := domain.IdentityFace{
+		IsPrimary:    primary,
+		QualityScore: FaceQualityScore(quality, s.qualityCfg),
+		BlurScore:    quality.Sharpness,
+		Quality:      quality,
+	}
+	if len(quality.Issues) > 0 {
+		reason := FaceQualityReason(quality)
+		if s.qualityCfg.Mode != config.FaceQualityModeFlag {
+			row.errors = append(row.errors, fmt.Sprintf("%s: %s", name, reason))
+			return
+		}
+		face.QualityFlagged = true
+		face.QualityReason = reason
+	}
+	if s.embedder != nil {
+		embedding, err := s.embedder.Embed(ctx, bytes.NewReader(data))
+		if err != nil {
+			row.errors = append(row.errors, fmt.Sprintf("%s: %v", name, err))
+			return
+		}
+		face.Embedding = embedding
+	}
+
+	row.faces = append(row.faces, face)
+	row.sources = append(row.sources, f)
+}
+
+// ImportReport returns the rejected rows of an import job.
+func (s *IdentityImportService) ImportReport(ctx context.Context, jobID uuid.UUID) ([]domain.ImportRowError, error) {
+	job, err := s.jobs.GetJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job.Type != domain.JobTypeIdentityImport {
+		return nil, domain.ErrJobNotFound
+	}
+
+	rowErrors := []domain.ImportRowError{}
+	raw, ok := job.Result["errors"]
+	if !ok || raw == nil {
+		return rowErrors, nil
+	}
+	// The result round-trips through JSONB, so decode it back into the typed form
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &rowErrors); err != nil {
+		return nil, err
+	}
+	return rowErrors, nil
+}
@@ -0,0 +1,279 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"strings"
+	"time"
+
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+	"app/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type IdentityPrivacyService struct {
+	repo      ports.IdentityPrivacyRepository
+	faceRepo  ports.IdentityFaceRepository
+	versions  ports.IdentityVersionRepository
+	storage   ports.FileStorage
+	faceIndex ports.FaceIndex
+	audit     ports.AuditService
+	publisher ports.EventPublisher
+}
+
+func NewIdentityPrivacyService(
+	repo ports.IdentityPrivacyRepository,
+	faceRepo ports.IdentityFaceRepository,
+	versions ports.IdentityVersionRepository,
+	storage ports.FileStorage,
+	faceIndex ports.FaceIndex,
+	audit ports.AuditService,
+	publisher ports.EventPublisher,
+) ports.IdentityPrivacyService {
+	return &IdentityPrivacyService{
+		repo:      repo,
+		faceRepo:  faceRepo,
+		versions:  versions,
+		storage:   storage,
+		faceIndex: faceIndex,
+		audit:     audit,
+		publisher: publisher,
+	}
+}
+
+// exportManifest describes the content of a data subject export.
+type exportManifest struct {
+	IdentityID      uuid.UUID  `json:"identity_id"`
+	GeneratedAt     time.Time  `json:"generated_at"`
+	GeneratedBy     *uuid.UUID `json:"generated_by"`
+	Faces           int        `json:"faces"`
+	RecognitionLogs int        `json:"recognition_logs"`
+	Attendance      int        `json:"attendance_records"`
+	AuditLogs       int        `json:"audit_logs"`
+	Versions        int        `json:"history_versions"`
+	Files           []string   `json:"files"`
+	MissingFiles    []string   `json:"missing_files,omitempty"`
+}
+
+type exportEmbedding struct {
+	FaceID    uuid.UUID `json:"face_id"`
+	Embedding []float32 `json:"embedding"`
+}
+
+// ExportIdentity writes a ZIP with everything held about the identity. All
+// records are loaded before anything is written, so a database error can
+// still be reported to the caller as a normal error.
+func (s *IdentityPrivacyService) ExportIdentity(ctx context.Context, id uuid.UUID, requestedBy *uuid.UUID, w io.Writer) error {
+	identity, err := s.repo.GetIdentity(ctx, id)
+	if err != nil {
+		return err
+	}
+	if identity == nil {
+		return domain.ErrIdentityNotFound
+	}
+
+	faces, err := s.faceRepo.ListFaces(ctx, id)
+	if err != nil {
+		return err
+	}
+	logs, err := s.repo.ListRecognitionLogs(ctx, id)
+	if err != nil {
+		return err
+	}
+	attendance, err := s.repo.ListAttendanceRecords(ctx, id)
+	if err != nil {
+		return err
+	}
+	faceIDs := make([]uuid.UUID, len(faces))
+	for i, f := range faces {
+		faceIDs[i] = f.ID
+	}
+	auditLogs, err := s.repo.ListAuditLogs(ctx, id, faceIDs)
+	if err != nil {
+		return err
+	}
+	versions, _, err := s.versions.ListVersions(ctx, id, math.MaxInt32, 0)
+	if err != nil {
+		return err
+	}
+
+	identity.Faces = make([]domain.IdentityFace, len(faces))
+	embeddings := []exportEmbedding{}
+	for i, f := range faces {
+		identity.Faces[i] = *f
+		if len(f.Embedding) > 0 {
+			embeddings = append(embeddings, exportEmbedding{FaceID: f.ID, Embedding: f.Embedding})
+		}
+	}
+
+	manifest := &exportManifest{
+		IdentityID:      id,
+		GeneratedAt:     time.Now(),
+		GeneratedBy:     requestedBy,
+		Faces:           len(faces),
+		RecognitionLogs: len(logs),
+		Attendance:      len(attendance),
+		AuditLogs:       len(auditLogs),
+		Versions:        len(versions),
+	}
+
+	zw := zip.NewWriter(w)
+	for _, entry := range []struct {
+		name  string
+		value any
+	}{
+		{"identity.json", identity},
+		{"face_embeddings.json", embeddings},
+		{"recognition_logs.json", logs},
+		{"attendance_records.json", attendance},
+		{"audit_logs.json", auditLogs},
+		{"identity_history.json", versions},
+	} {
+		if err := writeZipJSON(zw, entry.name, entry.value); err != nil {
+			return err
+		}
+		manifest.Files = append(manifest.Files, entry.name)
+	}
+
+	for _, f := range faces {
+		s.copyToZip(ctx, zw, manifest, f.ImageURL, "faces/"+f.ID.String())
+	}
+	for _, l := range logs {
+		s.copyToZip(ctx, zw, manifest, l.FaceCropURL, "recognition/"+l.ID.String()+"_face")
+		s.copyToZip(ctx, zw, manifest, l.SnapshotURL, "recognition/"+l.ID.String()+"_snapshot")
+	}
+
+	if err := writeZipJSON(zw, "manifest.json", manifest); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	if err := s.audit.LogAction(ctx, &domain.AuditLog{
+		UserID:    requestedBy,
+		Action:    "IDENTITY_EXPORT",
+		TableName: "identities",
+		RecordID:  id.String(),
+		NewValue: map[string]any{
+			"faces":              manifest.Faces,
+			"recognition_logs":   manifest.RecognitionLogs,
+			"attendance_records": manifest.Attendance,
+		},
+	}); err != nil {
+		logger.Error("Failed to audit identity export", zap.Error(err))
+	}
+	return nil
+}
+
+// copyToZip adds a stored file to the archive. Files that can no longer be
+// read are listed in the manifest instead of failing the whole export.
+func (s *IdentityPrivacyService) copyToZip(ctx context.Context, zw *zip.Writer, manifest *exportManifest, fileURL, name string) {
+	if fileURL == "" {
+		return
+	}
+	name += strings.ToLower(path.Ext(fileURL))
+
+	src, err := s.storage.OpenFile(ctx, fileURL)
+	if err != nil {
+		manifest.MissingFiles = append(manifest.MissingFiles, fileURL)
+		return
+	}
+	defer src.Close()
+
+	dst, err := zw.Create(name)
+	if err != nil {
+		manifest.MissingFiles = append(manifest.MissingFiles, fileURL)
+		return
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		manifest.MissingFiles = append(manifest.MissingFiles, fileURL)
+		return
+	}
+	manifest.Files = append(manifest.Files, name)
+}
+
+func writeZipJSON(zw *zip.Writer, name string, v any) error {
+	dst, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(dst)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// EraseIdentity permanently removes faces, media and personal data. Counts
+// that do not identify the person (attendance, recognitions) are kept.
+func (s *IdentityPrivacyService) EraseIdentity(ctx context.Context, id uuid.UUID, req *ports.EraseIdentityRequest) (*domain.IdentityErasure, error) {
+	if strings.TrimSpace(req.Reason) == "" {
+		return nil, domain.ErrErasureReasonMissing
+	}
+
+	identity, err := s.repo.GetIdentity(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if identity == nil {
+		return nil, domain.ErrIdentityNotFound
+	}
+
+	result, err := s.repo.EraseIdentity(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to erase identity: %w", err)
+	}
+
+	if err := s.faceIndex.RemoveIdentity(ctx, id); err != nil {
+		logger.Error("Failed to remove erased identity from face index", zap.Error(err))
+	}
+	for _, url := range result.MediaURLs {
+		if err := s.storage.DeleteFile(ctx, url); err != nil {
+			logger.Error("Failed to delete erased media", zap.String("url", url), zap.Error(err))
+			continue
+		}
+		result.MediaDeleted++
+	}
+	s.publishRemoval(ctx, id)
+
+	// The audit entry must not reintroduce the personal data just removed
+	if err := s.audit.LogAction(ctx, &domain.AuditLog{
+		UserID:    req.RequestedBy,
+		Action:    "IDENTITY_ERASE",
+		TableName: "identities",
+		RecordID:  id.String(),
+		NewValue: map[string]any{
+			"reason":                      req.Reason,
+			"faces_deleted":               result.FacesDeleted,
+			"media_deleted":               result.MediaDeleted,
+			"recognition_logs_anonymized": result.RecognitionLogsAnonymized,
+			"attendance_records_kept":     result.AttendanceRecordsKept,
+			"audit_logs_redacted":         result.AuditLogsRedacted,
+			"versions_deleted":            result.VersionsDeleted,
+			"feedback_deleted":            result.FeedbackDeleted,
+			"stranger_clusters_deleted":   result.StrangerClustersDeleted,
+		},
+	}); err != nil {
+		logger.Error("Failed to audit identity erasure", zap.Error(err))
+	}
+	return result, nil
+}
+
+func (s *IdentityPrivacyService) publishRemoval(ctx context.Context, id uuid.UUID) {
+	payload, err := json.Marshal(domain.EdgeIdentitySync{Action: domain.EdgeSyncRemove, IdentityID: id})
+	if err != nil {
+		return
+	}
+	pubCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), edgePublishTimeout)
+	defer cancel()
+	if err := s.publisher.Publish(pubCtx, ports.TopicIdentitySync, []byte(id.String()), payload); err != nil {
+		logger.Error("Failed to publish identity removal to edge", zap.String("identity_id", id.String()), zap.Error(err))
+	}
+}
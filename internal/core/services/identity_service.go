@@ -1,25 +1,64 @@
 package services
 
 import (
+	"bytes"
 	"context"
-	"errors"
+	"encoding/json"
 	"fmt"
+	"io"
+	"time"
 
+	"app/config"
 	"app/internal/core/domain"
 	"app/internal/core/ports"
+	"app/pkg/logger"
 
 	"github.com/google/uuid"
+	"go.uber.org/zap"
 )
 
+// edgePublishTimeout bounds how long a request waits on the broker.
+const edgePublishTimeout = 5 * time.Second
+
 type IdentityService struct {
-	repo     ports.IdentityRepository
-	faceRepo ports.IdentityFaceRepository
+	repo       ports.IdentityRepository
+	faceRepo   ports.IdentityFaceRepository
+	versions   ports.IdentityVersionRepository
+	storage    ports.FileStorage
+	faceIndex  ports.FaceIndex
+	embedder   ports.EmbeddingProvider // Optional
+	audit      ports.AuditService
+	notifier   ports.NotificationService
+	publisher  ports.EventPublisher
+	qualityCfg config.FaceQualityConfig
+	searchCfg  config.FaceSearchConfig
 }
 
-func NewIdentityService(repo ports.IdentityRepository, faceRepo ports.IdentityFaceRepository) ports.IdentityService {
+func NewIdentityService(
+	repo ports.IdentityRepository,
+	faceRepo ports.IdentityFaceRepository,
+	versions ports.IdentityVersionRepository,
+	storage ports.FileStorage,
+	faceIndex ports.FaceIndex,
+	embedder ports.EmbeddingProvider,
+	audit ports.AuditService,
+	notifier ports.NotificationService,
+	publisher ports.EventPublisher,
+	qualityCfg config.FaceQualityConfig,
+	searchCfg config.FaceSearchConfig,
+) ports.IdentityService {
 	return &IdentityService{
-		repo:     repo,
-		faceRepo: faceRepo,
+		repo:       repo,
+		faceRepo:   faceRepo,
+		versions:   versions,
+		storage:    storage,
+		faceIndex:  faceIndex,
+		embedder:   embedder,
+		audit:      audit,
+		notifier:   notifier,
+		publisher:  publisher,
+		qualityCfg: qualityCfg,
+		searchCfg:  searchCfg,
 	}
 }
 
@@ -45,7 +84,12 @@ func (s *IdentityService) CreateIdentity(ctx context.Context, req *ports.CreateI
 	}
 
 	// 3. Save to repo
-	return s.repo.CreateIdentity(ctx, identity)
+	created, err := s.repo.CreateIdentity(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+	recordIdentityVersion(ctx, s.versions, domain.VersionOpCreate, nil, created, req.CreatedBy, "", nil)
+	return created, nil
 }
 
 func (s *IdentityService) GetIdentity(ctx context.Context, id uuid.UUID) (*domain.Identity, error) {
@@ -83,8 +127,9 @@ func (s *IdentityService) UpdateIdentity(ctx context.Context, id uuid.UUID, req
 		return nil, err
 	}
 	if current == nil {
-		return nil, errors.New("identity not found")
+		return nil, domain.ErrIdentityNotFound
 	}
+	before := *current
 
 	// 2. Update fields
 	if req.FullName != "" {
@@ -110,29 +155,299 @@ func (s *IdentityService) UpdateIdentity(ctx context.Context, id uuid.UUID, req
 	}
 
 	// 3. Save
-	return s.repo.UpdateIdentity(ctx, current)
+	identity, err := s.repo.UpdateIdentity(ctx, current)
+	if err != nil {
+		return nil, err
+	}
+	recordIdentityVersion(ctx, s.versions, domain.VersionOpUpdate, &before, identity, req.UpdatedBy, "", nil)
+	return identity, nil
 }
 
-func (s *IdentityService) UpdateStatus(ctx context.Context, id uuid.UUID, status domain.IdentityStatus) (*domain.Identity, error) {
-	return s.repo.UpdateIdentityStatus(ctx, id, status)
+func (s *IdentityService) ListPending(ctx context.Context, page, limit int) ([]*domain.Identity, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+	return s.repo.ListIdentitiesByStatus(ctx, domain.IdentityStatusPending, page, limit)
 }
 
-func (s *IdentityService) DeleteIdentity(ctx context.Context, id uuid.UUID) error {
-	return s.repo.DeleteIdentity(ctx, id)
+// ReviewIdentity approves or rejects a pending identity. The reviewer is
+// recorded in approved_by, the creator is notified, and approved identities
+// are published to edge devices.
+func (s *IdentityService) ReviewIdentity(ctx context.Context, id uuid.UUID, req *ports.ReviewIdentityRequest) (*domain.Identity, error) {
+	if req.Status != domain.IdentityStatusActive && req.Status != domain.IdentityStatusRejected {
+		return nil, domain.ErrInvalidReviewStatus
+	}
+	if req.Reason == "" {
+		return nil, domain.ErrReviewReasonRequired
+	}
+
+	current, err := s.repo.GetIdentity(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, domain.ErrIdentityNotFound
+	}
+	if current.Status != domain.IdentityStatusPending {
+		return nil, domain.ErrIdentityNotPending
+	}
+	// Separation of duties: nobody approves a record they created
+	if req.Status == domain.IdentityStatusActive && current.CreatedBy != nil && *current.CreatedBy == req.ReviewerID {
+		return nil, domain.ErrSelfApproval
+	}
+
+	identity, err := s.repo.ReviewIdentity(ctx, id, req.Status, req.ReviewerID, req.Reason)
+	if err != nil {
+		return nil, err
+	}
+	reviewer := req.ReviewerID
+	recordIdentityVersion(ctx, s.versions, domain.VersionOpReview, current, identity, &reviewer, req.Reason, nil)
+
+	action := "IDENTITY_APPROVE"
+	if req.Status == domain.IdentityStatusRejected {
+		action = "IDENTITY_REJECT"
+	}
+	if err := s.audit.LogAction(ctx, &domain.AuditLog{
+		UserID:    &reviewer,
+		Action:    action,
+		TableName: "identities",
+		RecordID:  id.String(),
+		OldValue:  map[string]any{"status": current.Status},
+		NewValue:  map[string]any{"status": req.Status, "reason": req.Reason},
+	}); err != nil {
+		logger.Error("Failed to audit identity review", zap.Error(err))
+	}
+
+	s.notifyReview(ctx, identity)
+	s.publishIdentity(ctx, identity)
+	return identity, nil
 }
 
-func (s *IdentityService) EnrollFace(ctx context.Context, identityID uuid.UUID, imageURL string, isPrimary bool) (*domain.IdentityFace, error) {
+func (s *IdentityService) notifyReview(ctx context.Context, identity *domain.Identity) {
+	if identity.CreatedBy == nil {
+		return
+	}
+	n := &domain.Notification{
+		UserID:       *identity.CreatedBy,
+		Type:         domain.NotificationIdentityApproved,
+		Title:        fmt.Sprintf("Identity %s approved", identity.Code),
+		Message:      identity.ReviewReason,
+		ResourceType: "identity",
+		ResourceID:   identity.ID.String(),
+	}
+	if identity.Status == domain.IdentityStatusRejected {
+		n.Type = domain.NotificationIdentityRejected
+		n.Title = fmt.Sprintf("Identity %s rejected", identity.Code)
+	}
+	if err := s.notifier.Notify(ctx, n); err != nil {
+		logger.Error("Failed to notify identity creator", zap.Error(err))
+	}
+}
+
+func (s *IdentityService) publishIdentity(ctx context.Context, identity *domain.Identity) {
+	publishIdentitySync(ctx, s.publisher, s.faceRepo, identity)
+}
+
+// publishIdentitySync pushes an active identity and its faces to edge devices,
+// or tells them to drop it when it is no longer active.
+func publishIdentitySync(ctx context.Context, publisher ports.EventPublisher, faceRepo ports.IdentityFaceRepository, identity *domain.Identity) {
+	msg := domain.EdgeIdentitySync{Action: domain.EdgeSyncRemove, IdentityID: identity.ID}
+	if identity.Status == domain.IdentityStatusActive && identity.DeletedAt == nil {
+		msg.Action = domain.EdgeSyncUpsert
+		msg.Code = identity.Code
+		msg.FullName = identity.FullName
+		msg.Type = identity.Type
+
+		faces, err := faceRepo.ListFaces(ctx, identity.ID)
+		if err != nil {
+			logger.Error("Failed to load faces for edge sync", zap.Error(err))
+			return
+		}
+		for _, f := range faces {
+			msg.Faces = append(msg.Faces, domain.EdgeFace{FaceID: f.ID, ImageURL: f.ImageURL, Embedding: f.Embedding})
+		}
+	}
+
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+	pubCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), edgePublishTimeout)
+	defer cancel()
+	if err := publisher.Publish(pubCtx, ports.TopicIdentitySync, []byte(identity.ID.String()), payload); err != nil {
+		logger.Error("Failed to publish identity to edge", zap.String("identity_id", identity.ID.String()), zap.Error(err))
+	}
+}
+
+func (s *IdentityService) DeleteIdentity(ctx context.Context, id uuid.UUID, deletedBy *uuid.UUID) error {
+	current, err := s.repo.GetIdentity(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteIdentity(ctx, id); err != nil {
+		return err
+	}
+	if current != nil {
+		deleted := *current
+		now := time.Now()
+		deleted.DeletedAt = &now
+		deleted.UpdatedAt = now
+		recordIdentityVersion(ctx, s.versions, domain.VersionOpDelete, current, &deleted, deletedBy, "", nil)
+	}
+	if current != nil && current.Status == domain.IdentityStatusActive {
+		s.publishIdentity(ctx, &domain.Identity{ID: id})
+	}
+	return s.faceIndex.RemoveIdentity(ctx, id)
+}
+
+func (s *IdentityService) EnrollFace(ctx context.Context, req *ports.EnrollFaceRequest) (*domain.IdentityFace, error) {
+	image, err := s.readImage(ctx, req.ImageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	quality, err := AssessFaceQuality(bytes.NewReader(image), s.qualityCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	embedding := req.Embedding
+	if len(embedding) == 0 && s.embedder != nil {
+		if embedding, err = s.embedder.Embed(ctx, bytes.NewReader(image)); err != nil {
+			return nil, err
+		}
+	}
+	if len(embedding) > 0 {
+		if err := s.validateEmbedding(embedding); err != nil {
+			return nil, err
+		}
+	}
+
 	face := &domain.IdentityFace{
-		IdentityID: identityID,
-		ImageURL:   imageURL,
-		IsPrimary:  isPrimary,
+		IdentityID:   req.IdentityID,
+		ImageURL:     req.ImageURL,
+		IsPrimary:    req.IsPrimary,
+		QualityScore: FaceQualityScore(quality, s.qualityCfg),
+		BlurScore:    quality.Sharpness,
+		Quality:      quality,
+		Embedding:    embedding,
+	}
+	if len(quality.Issues) > 0 {
+		reason := FaceQualityReason(quality)
+		if s.qualityCfg.Mode != config.FaceQualityModeFlag {
+			return nil, fmt.Errorf("%w: %s", domain.ErrFaceQualityTooLow, reason)
+		}
+		face.QualityFlagged = true
+		face.QualityReason = reason
+	}
+
+	if req.IsPrimary {
+		_ = s.faceRepo.SetPrimary(ctx, req.IdentityID, uuid.Nil) // Reset others if any (logic inside SetPrimary handled it)
+	}
+	created, err := s.faceRepo.CreateFace(ctx, face)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.faceIndex.Upsert(ctx, created); err != nil {
+		return nil, err
+	}
+	recordFaceVersion(ctx, s.versions, domain.VersionOpFaceEnroll, created, req.EnrolledBy, "")
+	s.republishIfActive(ctx, req.IdentityID)
+	return created, nil
+}
+
+// republishIfActive refreshes the edge copy after an active identity's faces change.
+func (s *IdentityService) republishIfActive(ctx context.Context, identityID uuid.UUID) {
+	identity, err := s.repo.GetIdentity(ctx, identityID)
+	if err == nil && identity != nil && identity.Status == domain.IdentityStatusActive {
+		s.publishIdentity(ctx, identity)
+	}
+}
+
+func (s *IdentityService) readImage(ctx context.Context, imageURL string) ([]byte, error) {
+	file, err := s.storage.OpenFile(ctx, imageURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrFaceImageUnreadable, err)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrFaceImageUnreadable, err)
 	}
-	if isPrimary {
-		_ = s.faceRepo.SetPrimary(ctx, identityID, uuid.Nil) // Reset others if any (logic inside SetPrimary handled it)
+	return data, nil
+}
+
+func (s *IdentityService) validateEmbedding(embedding []float32) error {
+	if s.searchCfg.Dimension > 0 && len(embedding) != s.searchCfg.Dimension {
+		return fmt.Errorf("%w: expected %d dimensions, got %d", domain.ErrInvalidEmbedding, s.searchCfg.Dimension, len(embedding))
 	}
-	return s.faceRepo.CreateFace(ctx, face)
+	return nil
 }
 
-func (s *IdentityService) DeleteFace(ctx context.Context, faceID uuid.UUID) error {
-	return s.faceRepo.DeleteFace(ctx, faceID)
+func (s *IdentityService) DeleteFace(ctx context.Context, faceID uuid.UUID, deletedBy *uuid.UUID) error {
+	face, err := s.faceRepo.GetFace(ctx, faceID)
+	if err != nil {
+		return err
+	}
+	if err := s.faceRepo.DeleteFace(ctx, faceID); err != nil {
+		return err
+	}
+	if err := s.faceIndex.Remove(ctx, faceID); err != nil {
+		return err
+	}
+	if face != nil {
+		recordFaceVersion(ctx, s.versions, domain.VersionOpFaceDelete, face, deletedBy, "")
+		s.republishIfActive(ctx, face.IdentityID)
+	}
+	return nil
+}
+
+func (s *IdentityService) SearchFaces(ctx context.Context, req *ports.FaceSearchRequest) ([]*domain.FaceMatch, error) {
+	embedding := req.Embedding
+	if len(embedding) == 0 {
+		if req.Image == nil {
+			return nil, fmt.Errorf("%w: embedding or image is required", domain.ErrInvalidEmbedding)
+		}
+		if s.embedder == nil {
+			return nil, domain.ErrNoEmbeddingProvider
+		}
+		var err error
+		if embedding, err = s.embedder.Embed(ctx, req.Image); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.validateEmbedding(embedding); err != nil {
+		return nil, err
+	}
+
+	topK := req.TopK
+	if topK < 1 {
+		topK = s.searchCfg.TopK
+	}
+	minScore := s.searchCfg.MinScore
+	if req.MinScore != nil {
+		minScore = *req.MinScore
+	}
+
+	matches, err := s.faceIndex.Search(ctx, embedding, topK, minScore)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*domain.FaceMatch, 0, len(matches))
+	for _, m := range matches {
+		identity, err := s.repo.GetIdentity(ctx, m.IdentityID)
+		if err != nil {
+			return nil, err
+		}
+		if identity == nil {
+			continue // Deleted since it was indexed
+		}
+		m.Identity = identity
+		results = append(results, m)
+	}
+	return results, nil
 }
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+	"app/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// jobProgressInterval throttles how often progress is written to the database.
+const jobProgressInterval = time.Second
+
+type JobService struct {
+	repo ports.JobRepository
+}
+
+func NewJobService(repo ports.JobRepository) ports.JobService {
+	return &JobService{repo: repo}
+}
+
+// Start records a queued job and runs it in the background. The job body
+// gets its own context so it outlives the HTTP request that started it.
+func (s *JobService) Start(ctx context.Context, jobType string, createdBy *uuid.UUID, params map[string]any, run ports.JobFunc) (*domain.Job, error) {
+	job := &domain.Job{
+		Type:      jobType,
+		Status:    domain.JobStatusQueued,
+		Params:    params,
+		CreatedBy: createdBy,
+	}
+	if err := s.repo.Create(ctx, job); err != nil {
+		return nil, err
+	}
+
+	snapshot := *job
+	go s.run(job, run)
+	return &snapshot, nil
+}
+
+func (s *JobService) run(job *domain.Job, run ports.JobFunc) {
+	ctx := context.Background()
+	tracker := &jobTracker{repo: s.repo, job: job}
+
+	now := time.Now()
+	job.Status = domain.JobStatusRunning
+	job.StartedAt = &now
+	tracker.flush(ctx)
+
+	result, err := func() (result map[string]any, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("job panicked: %v", r)
+			}
+		}()
+		return run(ctx, tracker)
+	}()
+
+	tracker.mu.Lock()
+	finished := time.Now()
+	job.FinishedAt = &finished
+	job.Result = result
+	if err != nil {
+		job.Status = domain.JobStatusFailed
+		job.Error = err.Error()
+		logger.Error("Job failed", zap.String("id", job.ID.String()), zap.String("type", job.Type), zap.Error(err))
+	} else {
+		job.Status = domain.JobStatusCompleted
+	}
+	tracker.mu.Unlock()
+	tracker.flush(ctx)
+}
+
+func (s *JobService) GetJob(ctx context.Context, id uuid.UUID) (*domain.Job, error) {
+	job, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, domain.ErrJobNotFound
+	}
+	return job, nil
+}
+
+func (s *JobService) ListJobs(ctx context.Context, filter *ports.JobFilter) ([]*domain.Job, error) {
+	if filter.Limit < 1 {
+		filter.Limit = 20
+	}
+	return s.repo.List(ctx, filter.Type, filter.CreatedBy, filter.Limit, filter.Offset)
+}
+
+type jobTracker struct {
+	repo      ports.JobRepository
+	mu        sync.Mutex
+	job       *domain.Job
+	lastFlush time.Time
+}
+
+func (t *jobTracker) JobID() uuid.UUID {
+	return t.job.ID
+}
+
+func (t *jobTracker) SetTotal(total int) {
+	t.mu.Lock()
+	t.job.Total = total
+	t.mu.Unlock()
+	t.flush(context.Background())
+}
+
+func (t *jobTracker) Step(ok bool) {
+	t.mu.Lock()
+	t.job.Processed++
+	if !ok {
+		t.job.Failed++
+	}
+	due := time.Since(t.lastFlush) >= jobProgressInterval
+	t.mu.Unlock()
+	if due {
+		t.flush(context.Background())
+	}
+}
+
+func (t *jobTracker) SetResultURL(url string) {
+	t.mu.Lock()
+	t.job.ResultURL = url
+	t.mu.Unlock()
+}
+
+func (t *jobTracker) flush(ctx context.Context) {
+	t.mu.Lock()
+	snapshot := *t.job
+	t.lastFlush = time.Now()
+	t.mu.Unlock()
+	if err := t.repo.Update(ctx, &snapshot); err != nil {
+		logger.Error("Failed to persist job progress", zap.String("id", snapshot.ID.String()), zap.Error(err))
+	}
+}
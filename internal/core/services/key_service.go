@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+	"app/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type KeyService struct {
+	ring   ports.KeyRing // Nil when encryption is disabled
+	stores []ports.PIIStore
+	jobs   ports.JobService
+	audit  ports.AuditService
+}
+
+func NewKeyService(ring ports.KeyRing, stores []ports.PIIStore, jobs ports.JobService, audit ports.AuditService) ports.KeyService {
+	return &KeyService{ring: ring, stores: stores, jobs: jobs, audit: audit}
+}
+
+func (s *KeyService) ListKeys(ctx context.Context) ([]*domain.DataKey, error) {
+	if s.ring == nil {
+		return nil, domain.ErrEncryptionDisabled
+	}
+	return s.ring.ListKeys(ctx)
+}
+
+// RotateKey activates a new data key and starts re-encrypting existing rows.
+// Until the job finishes, rows under older keys remain readable.
+func (s *KeyService) RotateKey(ctx context.Context, requestedBy *uuid.UUID) (*domain.Job, error) {
+	if s.ring == nil {
+		return nil, domain.ErrEncryptionDisabled
+	}
+	key, err := s.ring.Rotate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.audit.LogAction(ctx, &domain.AuditLog{
+		UserID:    requestedBy,
+		Action:    "KEY_ROTATE",
+		TableName: "encryption_keys",
+		RecordID:  key.ID.String(),
+		NewValue:  map[string]any{"version": key.Version, "master_key_id": key.MasterKeyID},
+	}); err != nil {
+		logger.Error("Failed to audit key rotation", zap.Error(err))
+	}
+	return s.startReencryption(ctx, requestedBy, key.Version)
+}
+
+// Reencrypt rewrites rows that are still plaintext or under a retired key,
+// e.g. after enabling encryption on an existing database.
+func (s *KeyService) Reencrypt(ctx context.Context, requestedBy *uuid.UUID) (*domain.Job, error) {
+	if s.ring == nil {
+		return nil, domain.ErrEncryptionDisabled
+	}
+	return s.startReencryption(ctx, requestedBy, 0)
+}
+
+func (s *KeyService) startReencryption(ctx context.Context, requestedBy *uuid.UUID, version int) (*domain.Job, error) {
+	params := map[string]any{}
+	if version > 0 {
+		params["key_version"] = version
+	}
+	return s.jobs.Start(ctx, domain.JobTypeKeyRotation, requestedBy, params, func(ctx context.Context, tracker ports.JobTracker) (map[string]any, error) {
+		total := 0
+		for _, store := range s.stores {
+			n, err := store.CountPII(ctx)
+			if err != nil {
+				return nil, err
+			}
+			total += n
+		}
+		tracker.SetTotal(total)
+
+		failed := 0
+		step := func(ok bool) {
+			if !ok {
+				failed++
+			}
+			tracker.Step(ok)
+		}
+		for _, store := range s.stores {
+			if err := store.ReencryptPII(ctx, step); err != nil {
+				return map[string]any{"failed": failed}, err
+			}
+		}
+		return map[string]any{"rows": total, "failed": failed}, nil
+	})
+}
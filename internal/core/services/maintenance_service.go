@@ -0,0 +1,302 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"app/config"
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+	"app/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type MaintenanceService struct {
+	repo    ports.MaintenanceRepository
+	health  ports.CameraHealthRepository
+	cameras ports.CameraRepository
+	zones   ports.ZoneRepository
+	audit   ports.AuditService
+	cfg     config.MaintenanceConfig
+}
+
+func NewMaintenanceService(
+	repo ports.MaintenanceRepository,
+	health ports.CameraHealthRepository,
+	cameras ports.CameraRepository,
+	zones ports.ZoneRepository,
+	audit ports.AuditService,
+	cfg config.MaintenanceConfig,
+) ports.MaintenanceService {
+	if cfg.IntervalSeconds <= 0 {
+		cfg.IntervalSeconds = 30
+	}
+	return &MaintenanceService{repo: repo, health: health, cameras: cameras, zones: zones, audit: audit, cfg: cfg}
+}
+
+func (s *MaintenanceService) CreateWindow(ctx context.Context, req *ports.MaintenanceWindowRequest) (*domain.MaintenanceWindow, error) {
+	w := &domain.MaintenanceWindow{CreatedBy: req.RequestedBy}
+	if err := s.fill(ctx, w, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, w); err != nil {
+		return nil, err
+	}
+	s.logAction(ctx, req.RequestedBy, "CREATE_MAINTENANCE_WINDOW", w.ID, nil, w)
+	return w, nil
+}
+
+func (s *MaintenanceService) GetWindow(ctx context.Context, id uuid.UUID) (*domain.MaintenanceWindow, error) {
+	w, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if w == nil {
+		return nil, domain.ErrMaintenanceWindowNotFound
+	}
+	return w, nil
+}
+
+// ListWindows pages through all windows, or with ActiveAt returns every
+// window in effect at that moment with its current occurrence.
+func (s *MaintenanceService) ListWindows(ctx context.Context, filter *ports.MaintenanceWindowFilter) ([]*domain.MaintenanceWindow, int64, error) {
+	if filter.ActiveAt == nil {
+		if filter.Limit < 1 {
+			filter.Limit = 20
+		}
+		return s.repo.List(ctx, filter)
+	}
+
+	candidates, err := s.repo.ListCandidates(ctx, *filter.ActiveAt, filter.CameraID)
+	if err != nil {
+		return nil, 0, err
+	}
+	windows := []*domain.MaintenanceWindow{}
+	for _, w := range candidates {
+		if filter.ZoneID != nil && (w.ZoneID == nil || *w.ZoneID != *filter.ZoneID) {
+			continue
+		}
+		if start, end, ok := w.OccurrenceAt(*filter.ActiveAt); ok {
+			w.ActiveFrom, w.ActiveUntil = &start, &end
+			windows = append(windows, w)
+		}
+	}
+	return windows, int64(len(windows)), nil
+}
+
+func (s *MaintenanceService) UpdateWindow(ctx context.Context, id uuid.UUID, req *ports.MaintenanceWindowRequest) (*domain.MaintenanceWindow, error) {
+	w, err := s.GetWindow(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	old := *w
+	if err := s.fill(ctx, w, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, w); err != nil {
+		return nil, err
+	}
+	s.logAction(ctx, req.RequestedBy, "UPDATE_MAINTENANCE_WINDOW", w.ID, &old, w)
+	return w, nil
+}
+
+// DeleteWindow leaves cameras it holds in maintenance to the next Apply,
+// which releases them.
+func (s *MaintenanceService) DeleteWindow(ctx context.Context, id uuid.UUID, deletedBy *uuid.UUID) error {
+	w, err := s.GetWindow(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.logAction(ctx, deletedBy, "DELETE_MAINTENANCE_WINDOW", id, w, nil)
+	return nil
+}
+
+// fill validates the request and copies it onto w.
+func (s *MaintenanceService) fill(ctx context.Context, w *domain.MaintenanceWindow, req *ports.MaintenanceWindowRequest) error {
+	if (req.CameraID == nil) == (req.ZoneID == nil) {
+		return domain.ErrMaintenanceTarget
+	}
+	if !req.EndsAt.After(req.StartsAt) {
+		return domain.ErrMaintenancePeriod
+	}
+	if req.Recurrence == "" {
+		req.Recurrence = domain.MaintenanceRecurrenceNone
+	}
+	switch req.Recurrence {
+	case domain.MaintenanceRecurrenceNone, domain.MaintenanceRecurrenceDaily, domain.MaintenanceRecurrenceWeekly:
+	default:
+		return domain.ErrMaintenanceRecurrence
+	}
+	if step := req.Recurrence.Interval(); step > 0 && req.EndsAt.Sub(req.StartsAt) >= time.Duration(step)*24*time.Hour {
+		return domain.ErrMaintenanceRecurrence
+	}
+	if req.Recurrence == domain.MaintenanceRecurrenceNone {
+		req.RepeatUntil = nil
+	}
+	if req.EventPolicy == "" {
+		req.EventPolicy = domain.MaintenanceEventsTag
+	}
+	if req.EventPolicy != domain.MaintenanceEventsTag && req.EventPolicy != domain.MaintenanceEventsDrop {
+		return domain.ErrMaintenanceEventPolicy
+	}
+	if req.Timezone == "" {
+		req.Timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(req.Timezone); err != nil {
+		return domain.ErrMaintenanceTimezone
+	}
+
+	if req.CameraID != nil {
+		camera, err := s.cameras.GetByID(ctx, req.CameraID.String())
+		if err != nil {
+			return err
+		}
+		if camera == nil {
+			return domain.ErrCameraNotFound
+		}
+	} else {
+		zone, err := s.zones.GetByID(ctx, req.ZoneID.String())
+		if err != nil {
+			return err
+		}
+		if zone == nil {
+			return domain.ErrZoneNotFound
+		}
+	}
+
+	w.Name = req.Name
+	w.CameraID = req.CameraID
+	w.ZoneID = req.ZoneID
+	w.StartsAt = req.StartsAt
+	w.EndsAt = req.EndsAt
+	w.Recurrence = req.Recurrence
+	w.RepeatUntil = req.RepeatUntil
+	w.Timezone = req.Timezone
+	w.EventPolicy = req.EventPolicy
+	w.Note = req.Note
+	return nil
+}
+
+func (s *MaintenanceService) logAction(ctx context.Context, userID *uuid.UUID, action string, id uuid.UUID, oldValue, newValue *domain.MaintenanceWindow) {
+	if err := s.audit.LogAction(ctx, &domain.AuditLog{
+		UserID:    userID,
+		Action:    action,
+		TableName: "maintenance_windows",
+		RecordID:  id.String(),
+		OldValue:  maintenanceAuditValue(oldValue),
+		NewValue:  maintenanceAuditValue(newValue),
+	}); err != nil {
+		logger.Error("Failed to audit maintenance window", zap.String("window_id", id.String()), zap.Error(err))
+	}
+}
+
+func maintenanceAuditValue(w *domain.MaintenanceWindow) map[string]any {
+	if w == nil {
+		return nil
+	}
+	return map[string]any{
+		"name":         w.Name,
+		"camera_id":    w.CameraID,
+		"zone_id":      w.ZoneID,
+		"starts_at":    w.StartsAt,
+		"ends_at":      w.EndsAt,
+		"recurrence":   w.Recurrence,
+		"repeat_until": w.RepeatUntil,
+		"timezone":     w.Timezone,
+		"event_policy": w.EventPolicy,
+	}
+}
+
+func (s *MaintenanceService) Run(ctx context.Context) {
+	interval := time.Duration(s.cfg.IntervalSeconds) * time.Second
+	logger.Info("Maintenance scheduler started", zap.Duration("interval", interval))
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := s.Apply(ctx, time.Now()); err != nil {
+			logger.Error("Maintenance scheduling failed", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Apply only releases cameras it put in maintenance itself; a camera set to
+// maintenance by hand stays there.
+func (s *MaintenanceService) Apply(ctx context.Context, now time.Time) error {
+	windows, err := s.repo.ListCandidates(ctx, now, nil)
+	if err != nil {
+		return err
+	}
+	active := activeWindows(windows, now)
+
+	cameras, err := s.repo.ListCameraMaintenance(ctx)
+	if err != nil {
+		return err
+	}
+	for _, camera := range cameras {
+		change := &domain.CameraStatusChange{
+			CameraID:   camera.CameraID,
+			FromStatus: camera.Status,
+			Source:     domain.CameraStatusSourceSchedule,
+		}
+		w, inWindow := active[camera.CameraID]
+		switch {
+		case inWindow && camera.Status != domain.CameraStatusMaintenance:
+			change.ToStatus = domain.CameraStatusMaintenance
+			change.MaintenanceWindowID = &w.ID
+			change.Reason = "maintenance window started: " + w.Name
+		case !inWindow && camera.Status == domain.CameraStatusMaintenance && camera.WindowID != nil:
+			change.ToStatus = domain.CameraStatusOnline
+			change.Reason = "maintenance window ended"
+		default:
+			continue
+		}
+		if err := s.health.ChangeStatus(ctx, change); err != nil {
+			if !errors.Is(err, domain.ErrCameraStatusChanged) {
+				logger.Error("Failed to apply maintenance window", zap.String("camera_id", camera.CameraID.String()), zap.Error(err))
+			}
+			continue
+		}
+		logger.Info("Camera maintenance changed", zap.String("camera_id", camera.CameraID.String()),
+			zap.String("to", string(change.ToStatus)), zap.String("reason", change.Reason))
+	}
+	return nil
+}
+
+// activeWindows maps each camera to a window covering now. When windows
+// overlap, the one ending last wins.
+func activeWindows(windows []*domain.MaintenanceWindow, now time.Time) map[uuid.UUID]*domain.MaintenanceWindow {
+	active := map[uuid.UUID]*domain.MaintenanceWindow{}
+	ends := map[uuid.UUID]time.Time{}
+	for _, w := range windows {
+		_, end, ok := w.OccurrenceAt(now)
+		if !ok {
+			continue
+		}
+		for _, id := range w.CameraIDs {
+			if current, seen := ends[id]; !seen || end.After(current) {
+				active[id], ends[id] = w, end
+			}
+		}
+	}
+	return active
+}
+
+// activeWindowFor returns the window covering cameraID at t, if any.
+func activeWindowFor(ctx context.Context, repo ports.MaintenanceRepository, cameraID uuid.UUID, t time.Time) (*domain.MaintenanceWindow, error) {
+	windows, err := repo.ListCandidates(ctx, t, &cameraID)
+	if err != nil {
+		return nil, err
+	}
+	return activeWindows(windows, t)[cameraID], nil
+}
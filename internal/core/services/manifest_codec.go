@@ -0,0 +1,255 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"app/internal/core/domain"
+	"app/pkg/sheet"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// manifestColumns is the CSV layout: one row per camera, with its zone and
+// AI config flattened in. A row with a zone but no camera name declares a
+// zone without cameras, such as a building that only holds floors. The zone
+// column is the zone's path, "Building A/Floor 1"; a bare name is placed
+// under zone_parent instead. The AI config is only present when
+// ai_config_enabled is set.
+var manifestColumns = []string{
+	"zone", "zone_parent", "zone_kind", "zone_description", "name", "ip_address", "rtsp_url", "username", "password", "ai_enabled",
+	"ai_config_enabled", "ai_types", "sensitivity", "min_confidence", "roi_zones", "active_hours",
+}
+
+func (s *ManifestService) Decode(format string, data []byte) (*domain.SiteManifest, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, domain.ErrManifestEmpty
+	}
+	switch format {
+	case domain.ManifestFormatYAML:
+		m := &domain.SiteManifest{}
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(m); err != nil {
+			return nil, &domain.ManifestError{Problems: []string{err.Error()}}
+		}
+		return m, nil
+	case domain.ManifestFormatCSV:
+		return decodeManifestCSV(data)
+	default:
+		return nil, domain.ErrManifestFormat
+	}
+}
+
+func (s *ManifestService) Encode(format string, m *domain.SiteManifest) ([]byte, error) {
+	switch format {
+	case domain.ManifestFormatYAML:
+		var buf bytes.Buffer
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(m); err != nil {
+			return nil, err
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case domain.ManifestFormatCSV:
+		return encodeManifestCSV(m)
+	default:
+		return nil, domain.ErrManifestFormat
+	}
+}
+
+func decodeManifestCSV(data []byte) (*domain.SiteManifest, error) {
+	records, err := sheet.ReadCSV(bytes.NewReader(data))
+	if err != nil {
+		return nil, &domain.ManifestError{Problems: []string{err.Error()}}
+	}
+	if len(records) == 0 {
+		return nil, domain.ErrManifestEmpty
+	}
+
+	columns := map[string]int{}
+	for i, h := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	if _, ok := columns["name"]; !ok {
+		return nil, &domain.ManifestError{Problems: []string{"header must contain a name column"}}
+	}
+
+	m := &domain.SiteManifest{Version: domain.ManifestVersion}
+	zones := map[string]int{}
+	var problems []string
+	for n, record := range records[1:] {
+		line := n + 2
+		get := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		var zonePath string
+		if zone := get("zone"); zone != "" {
+			z := domain.ManifestZone{Name: zone, Parent: get("zone_parent")}
+			if i := strings.LastIndex(zone, "/"); i >= 0 {
+				z.Name, z.Parent = zone[i+1:], zone[:i]
+			}
+			zonePath = manifestZonePath(z)
+			i, ok := zones[zonePath]
+			if !ok {
+				i = len(m.Zones)
+				zones[zonePath] = i
+				m.Zones = append(m.Zones, z)
+			}
+			if description := get("zone_description"); description != "" && m.Zones[i].Description == "" {
+				m.Zones[i].Description = description
+			}
+			if kind := get("zone_kind"); kind != "" && m.Zones[i].Kind == "" {
+				m.Zones[i].Kind = domain.ZoneKind(kind)
+			}
+		}
+		if get("name") == "" {
+			continue
+		}
+
+		camera := domain.ManifestCamera{
+			Name:      get("name"),
+			Zone:      zonePath,
+			IPAddress: get("ip_address"),
+			RTSPURL:   get("rtsp_url"),
+			Username:  get("username"),
+		}
+		if password := get("password"); password != "" {
+			camera.Password = &password
+		}
+		var err error
+		if camera.AIEnabled, err = parseManifestBool(get("ai_enabled"), false); err != nil {
+			problems = append(problems, fmt.Sprintf("line %d: ai_enabled: %v", line, err))
+		}
+		if get("ai_config_enabled") != "" {
+			cfg, errs := decodeManifestAIConfig(get)
+			for _, e := range errs {
+				problems = append(problems, fmt.Sprintf("line %d: %s", line, e))
+			}
+			camera.AIConfig = cfg
+		}
+		m.Cameras = append(m.Cameras, camera)
+	}
+
+	if len(problems) > 0 {
+		return nil, &domain.ManifestError{Problems: problems}
+	}
+	return m, nil
+}
+
+func decodeManifestAIConfig(get func(string) string) (*domain.ManifestAIConfig, []string) {
+	cfg := &domain.ManifestAIConfig{Types: []domain.EventType{}}
+	var problems []string
+	var err error
+	if cfg.Enabled, err = parseManifestBool(get("ai_config_enabled"), false); err != nil {
+		problems = append(problems, "ai_config_enabled: "+err.Error())
+	}
+	for _, t := range strings.Split(get("ai_types"), ";") {
+		if t = strings.TrimSpace(t); t != "" {
+			cfg.Types = append(cfg.Types, domain.EventType(t))
+		}
+	}
+	for column, target := range map[string]*int{"sensitivity": &cfg.Sensitivity, "min_confidence": &cfg.MinConfidence} {
+		if v := get(column); v != "" {
+			if *target, err = strconv.Atoi(v); err != nil {
+				problems = append(problems, column+" must be a whole number")
+			}
+		}
+	}
+	for column, target := range map[string]any{"roi_zones": &cfg.ROIZones, "active_hours": &cfg.ActiveHours} {
+		if v := get(column); v != "" {
+			if err := json.Unmarshal([]byte(v), target); err != nil {
+				problems = append(problems, column+" must be JSON")
+			}
+		}
+	}
+	return cfg, problems
+}
+
+func parseManifestBool(v string, def bool) (bool, error) {
+	if v == "" {
+		return def, nil
+	}
+	switch strings.ToLower(v) {
+	case "yes", "y":
+		return true, nil
+	case "no", "n":
+		return false, nil
+	}
+	return strconv.ParseBool(v)
+}
+
+func encodeManifestCSV(m *domain.SiteManifest) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(manifestColumns); err != nil {
+		return nil, err
+	}
+
+	zones := map[string]domain.ManifestZone{}
+	used := map[string]bool{}
+	for _, z := range m.Zones {
+		zones[manifestZonePath(z)] = z
+	}
+	for _, c := range m.Cameras {
+		used[c.Zone] = true
+	}
+	for _, z := range m.Zones {
+		if path := manifestZonePath(z); !used[path] {
+			row := make([]string, len(manifestColumns))
+			row[0], row[1], row[2], row[3] = path, z.Parent, string(z.Kind), z.Description
+			if err := w.Write(row); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, c := range m.Cameras {
+		z := zones[c.Zone]
+		row := []string{c.Zone, z.Parent, string(z.Kind), z.Description, c.Name, c.IPAddress, c.RTSPURL, c.Username, "",
+			strconv.FormatBool(c.AIEnabled), "", "", "", "", "", ""}
+		if c.Password != nil {
+			row[8] = *c.Password
+		}
+		if cfg := c.AIConfig; cfg != nil {
+			types := make([]string, len(cfg.Types))
+			for i, t := range cfg.Types {
+				types[i] = string(t)
+			}
+			var roi, hours []byte
+			var err error
+			if len(cfg.ROIZones) > 0 {
+				if roi, err = json.Marshal(cfg.ROIZones); err != nil {
+					return nil, err
+				}
+			}
+			if cfg.ActiveHours != nil {
+				if hours, err = json.Marshal(cfg.ActiveHours); err != nil {
+					return nil, err
+				}
+			}
+			row[10] = strconv.FormatBool(cfg.Enabled)
+			row[11] = strings.Join(types, ";")
+			row[12] = strconv.Itoa(cfg.Sensitivity)
+			row[13] = strconv.Itoa(cfg.MinConfidence)
+			row[14] = string(roi)
+			row[15] = string(hours)
+		}
+		if err := w.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
@@ -0,0 +1,553 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+	"app/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type ManifestService struct {
+	zones   ports.ZoneRepository
+	cameras ports.CameraRepository
+	ai      ports.AIRepository
+	audit   ports.AuditService
+}
+
+func NewManifestService(zones ports.ZoneRepository, cameras ports.CameraRepository, ai ports.AIRepository, audit ports.AuditService) ports.ManifestService {
+	return &ManifestService{zones: zones, cameras: cameras, ai: ai, audit: audit}
+}
+
+// siteState is the current site indexed by name, and zones also by their
+// path of names. Neither is unique in the database, so each key maps to
+// every record carrying it.
+type siteState struct {
+	zones      []*domain.Zone
+	cameras    []*domain.Camera
+	zonePaths  map[string]string
+	zoneByPath map[string][]*domain.Zone
+	zoneByName map[string][]*domain.Zone
+	camByKey   map[string][]*domain.Camera
+	configs    map[string]*domain.AIConfig
+}
+
+func (s *ManifestService) load(ctx context.Context) (*siteState, error) {
+	zones, err := s.zones.List(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	cameras, err := s.cameras.List(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	state := &siteState{
+		zones:      zones,
+		cameras:    cameras,
+		zonePaths:  map[string]string{},
+		zoneByPath: map[string][]*domain.Zone{},
+		zoneByName: map[string][]*domain.Zone{},
+		camByKey:   map[string][]*domain.Camera{},
+		configs:    map[string]*domain.AIConfig{},
+	}
+	names := make(map[string]string, len(zones))
+	for _, z := range zones {
+		names[z.ID] = z.Name
+	}
+	for _, z := range zones {
+		ids := z.AncestorIDs()
+		path := make([]string, len(ids))
+		for i, id := range ids {
+			path[i] = names[id]
+		}
+		key := strings.Join(path, "/")
+		state.zonePaths[z.ID] = key
+		state.zoneByPath[key] = append(state.zoneByPath[key], z)
+		state.zoneByName[z.Name] = append(state.zoneByName[z.Name], z)
+	}
+	for _, c := range cameras {
+		state.camByKey[c.Name] = append(state.camByKey[c.Name], c)
+		id, err := uuid.Parse(c.ID)
+		if err != nil {
+			continue
+		}
+		cfg, err := s.ai.GetConfigByCamera(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if cfg != nil {
+			state.configs[c.ID] = cfg
+		}
+	}
+	return state, nil
+}
+
+func (state *siteState) zonePath(zoneID *string) string {
+	if zoneID == nil {
+		return ""
+	}
+	return state.zonePaths[*zoneID]
+}
+
+// manifestZonePath is the key a manifest zone is referred to by: its
+// parent's path and its own name, "Building A/Floor 1".
+func manifestZonePath(z domain.ManifestZone) string {
+	if z.Parent == "" {
+		return z.Name
+	}
+	return z.Parent + "/" + z.Name
+}
+
+// Export writes zones sorted by path and cameras by zone then name, so two
+// exports of the same site are identical.
+func (s *ManifestService) Export(ctx context.Context, req *ports.ManifestExportRequest) (*domain.SiteManifest, error) {
+	state, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &domain.SiteManifest{Version: domain.ManifestVersion, Zones: []domain.ManifestZone{}, Cameras: []domain.ManifestCamera{}}
+	for _, z := range state.zones {
+		m.Zones = append(m.Zones, domain.ManifestZone{
+			Name:        z.Name,
+			Parent:      state.zonePath(z.ParentID),
+			Kind:        z.Kind,
+			Description: z.Description,
+		})
+	}
+	sort.SliceStable(m.Zones, func(i, j int) bool { return manifestZonePath(m.Zones[i]) < manifestZonePath(m.Zones[j]) })
+
+	for _, c := range state.cameras {
+		mc := domain.ManifestCamera{
+			Name:      c.Name,
+			Zone:      state.zonePath(c.ZoneID),
+			IPAddress: c.IPAddress,
+			RTSPURL:   c.RTSPURL,
+			AIEnabled: c.AIEnabled,
+		}
+		if req.IncludeCredentials && c.HasCredentials && !c.CredentialsUnreadable {
+			password := c.Password
+			mc.Username, mc.Password = c.Username, &password
+		}
+		if cfg := state.configs[c.ID]; cfg != nil {
+			mc.AIConfig = &domain.ManifestAIConfig{
+				Enabled:       cfg.AIEnabled,
+				Types:         cfg.AITypes,
+				ROIZones:      cfg.ROIZones,
+				ActiveHours:   cfg.ActiveHours,
+				Sensitivity:   cfg.Sensitivity,
+				MinConfidence: cfg.MinConfidence,
+			}
+		}
+		m.Cameras = append(m.Cameras, mc)
+	}
+	sort.SliceStable(m.Cameras, func(i, j int) bool {
+		if m.Cameras[i].Zone != m.Cameras[j].Zone {
+			return m.Cameras[i].Zone < m.Cameras[j].Zone
+		}
+		return m.Cameras[i].Name < m.Cameras[j].Name
+	})
+
+	if req.IncludeCredentials {
+		s.logAction(ctx, req.RequestedBy, "EXPORT_SITE_MANIFEST", map[string]any{
+			"credentials": true,
+			"cameras":     len(m.Cameras),
+		})
+	}
+	return m, nil
+}
+
+func (s *ManifestService) Plan(ctx context.Context, manifest *domain.SiteManifest, prune bool) (*domain.ManifestPlan, error) {
+	plan, _, err := s.plan(ctx, manifest, prune)
+	return plan, err
+}
+
+func (s *ManifestService) Apply(ctx context.Context, req *ports.ManifestApplyRequest) (*domain.ManifestPlan, error) {
+	plan, steps, err := s.plan(ctx, req.Manifest, req.Prune)
+	if err != nil {
+		return nil, err
+	}
+
+	plan.Applied = true
+	for i, step := range steps {
+		if err := step(ctx); err != nil {
+			plan.Changes[i].Error = err.Error()
+			plan.Applied = false
+			logger.Error("Failed to apply site manifest", zap.String("kind", string(plan.Changes[i].Kind)),
+				zap.String("name", plan.Changes[i].Name), zap.Error(err))
+			break
+		}
+	}
+
+	s.logAction(ctx, req.RequestedBy, "APPLY_SITE_MANIFEST", map[string]any{
+		"creates": plan.Creates,
+		"updates": plan.Updates,
+		"deletes": plan.Deletes,
+		"prune":   plan.Prune,
+		"applied": plan.Applied,
+	})
+	return plan, nil
+}
+
+// manifestRun is one step of an apply. Steps that create zones record the
+// new IDs in zoneIDs, keyed by path, for the steps after them.
+type manifestRun func(ctx context.Context) error
+
+// plan diffs the manifest against the site. The returned steps match
+// plan.Changes one to one and are ordered so each only depends on earlier
+// ones: zones, cameras with their AI configs, then deletions.
+func (s *ManifestService) plan(ctx context.Context, m *domain.SiteManifest, prune bool) (*domain.ManifestPlan, []manifestRun, error) {
+	state, err := s.load(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := validateManifest(m, state); err != nil {
+		return nil, nil, err
+	}
+
+	plan := &domain.ManifestPlan{Changes: []domain.ManifestChange{}, Prune: prune}
+	var steps []manifestRun
+	add := func(change domain.ManifestChange, run manifestRun) {
+		plan.Add(change)
+		steps = append(steps, run)
+	}
+
+	zoneIDs := map[string]string{}
+	zoneRef := func(path string) *string {
+		if path == "" {
+			return nil
+		}
+		id := zoneIDs[path]
+		return &id
+	}
+	declaredNames := map[string]int{}
+	for _, mz := range m.Zones {
+		declaredNames[mz.Name]++
+	}
+
+	// Parents before children: each zone is then moved under a parent that
+	// already sits where the manifest wants it, which cannot be a cycle.
+	matchedZones := map[string]bool{}
+	for _, mz := range manifestZoneOrder(m.Zones) {
+		mz := mz
+		path := manifestZonePath(mz)
+		existing := state.zoneByPath[path]
+		// A zone whose name is unique on both sides was moved rather than
+		// replaced
+		if byName := state.zoneByName[mz.Name]; len(existing) == 0 && len(byName) == 1 && declaredNames[mz.Name] == 1 {
+			existing = byName
+		}
+		if len(existing) == 0 {
+			add(domain.ManifestChange{Action: domain.ManifestActionCreate, Kind: domain.ManifestKindZone, Name: path},
+				func(ctx context.Context) error {
+					zone := &domain.Zone{ParentID: zoneRef(mz.Parent), Name: mz.Name, Description: mz.Description, Kind: mz.Kind}
+					if err := s.zones.Save(ctx, zone); err != nil {
+						return err
+					}
+					zoneIDs[path] = zone.ID
+					return nil
+				})
+			continue
+		}
+		zone := *existing[0]
+		zoneIDs[path] = zone.ID
+		matchedZones[zone.ID] = true
+		var fields []string
+		if zone.Description != mz.Description {
+			zone.Description = mz.Description
+			fields = append(fields, "description")
+		}
+		if zone.Kind != mz.Kind {
+			zone.Kind = mz.Kind
+			fields = append(fields, "kind")
+		}
+		move := !inManifestZone(zone.ParentID, zoneIDs, mz.Parent)
+		if move {
+			fields = append(fields, "parent")
+		}
+		if len(fields) == 0 {
+			plan.Unchanged++
+			continue
+		}
+		add(domain.ManifestChange{Action: domain.ManifestActionUpdate, Kind: domain.ManifestKindZone, Name: path, ID: zone.ID, Fields: fields},
+			func(ctx context.Context) error {
+				if move {
+					if err := s.zones.Move(ctx, zone.ID, zoneRef(mz.Parent)); err != nil {
+						return err
+					}
+				}
+				return s.zones.Update(ctx, &zone)
+			})
+	}
+
+	for _, mc := range m.Cameras {
+		mc := mc
+		rtspURL, username, password, setCredentials := manifestCredentials(&mc)
+		existing := state.camByKey[mc.Name]
+
+		var camera *domain.Camera
+		if len(existing) == 0 {
+			camera = &domain.Camera{
+				Name:      mc.Name,
+				IPAddress: mc.IPAddress,
+				RTSPURL:   rtspURL,
+				Username:  username,
+				Password:  password,
+				Status:    domain.CameraStatusOnline,
+				AIEnabled: mc.AIEnabled,
+			}
+			add(domain.ManifestChange{Action: domain.ManifestActionCreate, Kind: domain.ManifestKindCamera, Name: mc.Name},
+				func(ctx context.Context) error {
+					camera.ZoneID = zoneRef(mc.Zone)
+					return s.cameras.Save(ctx, camera)
+				})
+		} else {
+			updated := *existing[0]
+			camera = &updated
+			var fields []string
+			if !inManifestZone(camera.ZoneID, zoneIDs, mc.Zone) {
+				fields = append(fields, "zone")
+			}
+			if camera.IPAddress != mc.IPAddress {
+				camera.IPAddress = mc.IPAddress
+				fields = append(fields, "ip_address")
+			}
+			if camera.RTSPURL != rtspURL {
+				camera.RTSPURL = rtspURL
+				fields = append(fields, "rtsp_url")
+			}
+			if setCredentials && (camera.Username != username || camera.Password != password) {
+				camera.Username, camera.Password = username, password
+				fields = append(fields, "credentials")
+			}
+			if camera.AIEnabled != mc.AIEnabled {
+				camera.AIEnabled = mc.AIEnabled
+				fields = append(fields, "ai_enabled")
+			}
+			if len(fields) == 0 {
+				plan.Unchanged++
+			} else {
+				add(domain.ManifestChange{Action: domain.ManifestActionUpdate, Kind: domain.ManifestKindCamera, Name: mc.Name, ID: camera.ID, Fields: fields},
+					func(ctx context.Context) error {
+						camera.ZoneID = zoneRef(mc.Zone)
+						return s.cameras.Update(ctx, camera)
+					})
+			}
+		}
+
+		if mc.AIConfig == nil {
+			continue
+		}
+		desired := manifestAIConfig(mc.AIConfig)
+		action := domain.ManifestActionCreate
+		var fields []string
+		if camera.ID != "" {
+			if current := state.configs[camera.ID]; current != nil {
+				action = domain.ManifestActionUpdate
+				if fields = aiConfigChanges(current, desired); len(fields) == 0 {
+					plan.Unchanged++
+					continue
+				}
+			}
+		}
+		add(domain.ManifestChange{Action: action, Kind: domain.ManifestKindAIConfig, Name: mc.Name, ID: camera.ID, Fields: fields},
+			func(ctx context.Context) error {
+				cameraID, err := uuid.Parse(camera.ID)
+				if err != nil {
+					return err
+				}
+				cfg := &domain.AIConfig{CameraID: cameraID, AISettings: *desired, Source: domain.AIConfigSourceManifest}
+				// A template link survives, so the camera shows as drifting
+				if current := state.configs[camera.ID]; current != nil {
+					cfg.TemplateID, cfg.Overrides = current.TemplateID, current.Overrides
+				}
+				return s.ai.SaveConfig(ctx, cfg)
+			})
+	}
+
+	if prune {
+		declaredCameras := map[string]bool{}
+		for _, mc := range m.Cameras {
+			declaredCameras[mc.Name] = true
+		}
+		for _, c := range state.cameras {
+			if declaredCameras[c.Name] {
+				continue
+			}
+			id := c.ID
+			add(domain.ManifestChange{Action: domain.ManifestActionDelete, Kind: domain.ManifestKindCamera, Name: c.Name, ID: id},
+				func(ctx context.Context) error { return s.cameras.Delete(ctx, id) })
+		}
+		// Deepest first, so no zone is deleted while it still has children
+		undeclared := slices.Clone(state.zones)
+		sort.SliceStable(undeclared, func(i, j int) bool { return len(undeclared[i].Path) > len(undeclared[j].Path) })
+		for _, z := range undeclared {
+			if matchedZones[z.ID] {
+				continue
+			}
+			id := z.ID
+			add(domain.ManifestChange{Action: domain.ManifestActionDelete, Kind: domain.ManifestKindZone, Name: state.zonePaths[id], ID: id},
+				func(ctx context.Context) error { return s.zones.Delete(ctx, id) })
+		}
+	}
+	return plan, steps, nil
+}
+
+// inManifestZone reports whether zoneID is the zone at path. Zones the
+// plan has yet to create have no ID, so nothing is in them.
+func inManifestZone(zoneID *string, zoneIDs map[string]string, path string) bool {
+	if path == "" || zoneID == nil {
+		return path == "" && zoneID == nil
+	}
+	id, ok := zoneIDs[path]
+	return ok && *zoneID == id
+}
+
+// manifestZoneOrder sorts zones so every parent comes before its children.
+// A parent's path is a prefix of its children's, so shallower zones first
+// is enough.
+func manifestZoneOrder(zones []domain.ManifestZone) []domain.ManifestZone {
+	ordered := slices.Clone(zones)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return strings.Count(manifestZonePath(ordered[i]), "/") < strings.Count(manifestZonePath(ordered[j]), "/")
+	})
+	return ordered
+}
+
+// manifestCredentials splits credentials out of the URL. Explicit ones win,
+// and are only applied when a password is given. validateManifest has
+// already rejected URLs that do not parse.
+func manifestCredentials(mc *domain.ManifestCamera) (string, string, string, bool) {
+	rtspURL, username, password, _ := domain.SplitStreamURL(mc.RTSPURL)
+	if mc.Password != nil {
+		if mc.Username != "" {
+			username = mc.Username
+		}
+		return rtspURL, username, *mc.Password, true
+	}
+	return rtspURL, username, password, username != ""
+}
+
+// manifestAIConfig is the settings a manifest entry stores, normalized so
+// they compare equal to a stored config with the same content.
+func manifestAIConfig(m *domain.ManifestAIConfig) *domain.AISettings {
+	settings := &domain.AISettings{
+		AIEnabled:     m.Enabled,
+		AITypes:       m.Types,
+		ROIZones:      m.ROIZones,
+		ActiveHours:   m.ActiveHours,
+		Sensitivity:   m.Sensitivity,
+		MinConfidence: m.MinConfidence,
+	}
+	settings.Normalize()
+	return settings
+}
+
+// aiConfigChanges names changed fields as the manifest does.
+func aiConfigChanges(current *domain.AIConfig, m *domain.AISettings) []string {
+	fields := current.Diff(m)
+	for i, f := range fields {
+		switch f {
+		case "ai_enabled":
+			fields[i] = "enabled"
+		case "ai_types":
+			fields[i] = "types"
+		}
+	}
+	return fields
+}
+
+func validateManifest(m *domain.SiteManifest, state *siteState) error {
+	var problems []string
+	if m.Version == 0 {
+		m.Version = domain.ManifestVersion
+	}
+	if m.Version != domain.ManifestVersion {
+		problems = append(problems, fmt.Sprintf("unsupported version %d", m.Version))
+	}
+
+	// Zones are keyed by path, so a parent is always shorter than its
+	// children and the references cannot form a cycle
+	zones := map[string]bool{}
+	for i, z := range m.Zones {
+		path := manifestZonePath(z)
+		switch {
+		case z.Name == "":
+			problems = append(problems, fmt.Sprintf("zones[%d]: name is required", i))
+		case strings.Contains(z.Name, "/"):
+			problems = append(problems, fmt.Sprintf("zone %q: name cannot contain /", z.Name))
+		case zones[path]:
+			problems = append(problems, fmt.Sprintf("zone %q is declared twice", path))
+		case len(state.zoneByPath[path]) > 1:
+			problems = append(problems, fmt.Sprintf("zone %q matches %d existing zones", path, len(state.zoneByPath[path])))
+		}
+		zones[path] = true
+		if !z.Kind.Valid() {
+			problems = append(problems, fmt.Sprintf("zone %q: %v", path, domain.ErrInvalidZoneKind))
+		}
+	}
+	for _, z := range m.Zones {
+		if z.Parent != "" && !zones[z.Parent] {
+			problems = append(problems, fmt.Sprintf("zone %q: parent %q is not declared", manifestZonePath(z), z.Parent))
+		}
+	}
+
+	cameras := map[string]bool{}
+	for i, c := range m.Cameras {
+		label := fmt.Sprintf("cameras[%d]", i)
+		if c.Name != "" {
+			label = fmt.Sprintf("camera %q", c.Name)
+		}
+		switch {
+		case c.Name == "":
+			problems = append(problems, label+": name is required")
+		case cameras[c.Name]:
+			problems = append(problems, label+" is declared twice")
+		case len(state.camByKey[c.Name]) > 1:
+			problems = append(problems, fmt.Sprintf("%s matches %d existing cameras", label, len(state.camByKey[c.Name])))
+		}
+		cameras[c.Name] = true
+
+		if u, err := url.Parse(c.RTSPURL); c.RTSPURL == "" || err != nil || u.Host == "" {
+			problems = append(problems, label+": rtsp_url is missing or invalid")
+		}
+		if c.Zone != "" && !zones[c.Zone] {
+			problems = append(problems, fmt.Sprintf("%s: zone %q is not declared", label, c.Zone))
+		}
+		if c.AIConfig != nil {
+			var cfgErr *domain.AIConfigError
+			if err := manifestAIConfig(c.AIConfig).Validate(); errors.As(err, &cfgErr) {
+				for _, f := range cfgErr.Fields {
+					// The manifest calls ai_types just types
+					field := strings.Replace(f.Field, "ai_types", "types", 1)
+					problems = append(problems, fmt.Sprintf("%s: ai_config.%s: %s", label, field, f.Message))
+				}
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return &domain.ManifestError{Problems: problems}
+	}
+	return nil
+}
+
+func (s *ManifestService) logAction(ctx context.Context, userID *uuid.UUID, action string, newValue map[string]any) {
+	if err := s.audit.LogAction(ctx, &domain.AuditLog{
+		UserID:    userID,
+		Action:    action,
+		TableName: "cameras",
+		NewValue:  newValue,
+	}); err != nil {
+		logger.Error("Failed to audit site manifest", zap.String("action", action), zap.Error(err))
+	}
+}
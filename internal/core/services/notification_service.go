@@ -0,0 +1,33 @@
+package services
+
+import (
+	"context"
+
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+
+	"github.com/google/uuid"
+)
+
+type NotificationService struct {
+	repo ports.NotificationRepository
+}
+
+func NewNotificationService(repo ports.NotificationRepository) ports.NotificationService {
+	return &NotificationService{repo: repo}
+}
+
+func (s *NotificationService) Notify(ctx context.Context, n *domain.Notification) error {
+	return s.repo.Create(ctx, n)
+}
+
+func (s *NotificationService) ListNotifications(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit, offset int32) ([]*domain.Notification, error) {
+	if limit < 1 {
+		limit = 20
+	}
+	return s.repo.ListByUser(ctx, userID, unreadOnly, limit, offset)
+}
+
+func (s *NotificationService) MarkRead(ctx context.Context, id, userID uuid.UUID) error {
+	return s.repo.MarkRead(ctx, id, userID)
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 
+	"app/internal/core/domain"
 	"app/internal/core/ports"
 
 	"github.com/google/uuid"
@@ -50,3 +51,22 @@ func (s *PermissionService) GetUserPermissions(ctx context.Context, userID uuid.
 		ZoneIDs:   zones,
 	}, nil
 }
+
+func (s *PermissionService) CameraScope(ctx context.Context, userID uuid.UUID) (*domain.CameraScope, error) {
+	cameras, err := s.repo.ListUserCameras(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	zones, err := s.repo.ListUserZones(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(cameras) == 0 && len(zones) == 0 {
+		return nil, nil
+	}
+	// Non-nil slices, so an empty side binds as an empty array, not NULL
+	return &domain.CameraScope{
+		CameraIDs: append([]uuid.UUID{}, cameras...),
+		ZoneIDs:   append([]uuid.UUID{}, zones...),
+	}, nil
+}
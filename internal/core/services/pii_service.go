@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"app/config"
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+	"app/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// permissionCacheTTL bounds how long a role change takes to affect masking.
+const permissionCacheTTL = 30 * time.Second
+
+type cachedPermissions struct {
+	permissions []string
+	expires     time.Time
+}
+
+type PIIService struct {
+	roles ports.RoleRepository
+	cfg   config.PIIMaskingConfig
+
+	mu    sync.Mutex
+	cache map[uuid.UUID]cachedPermissions
+}
+
+func NewPIIService(roles ports.RoleRepository, cfg config.PIIMaskingConfig) ports.PIIService {
+	return &PIIService{
+		roles: roles,
+		cfg:   cfg,
+		cache: make(map[uuid.UUID]cachedPermissions),
+	}
+}
+
+// CanReadPII fails closed: any error loading permissions means masked output.
+func (s *PIIService) CanReadPII(ctx context.Context, userID uuid.UUID) bool {
+	return s.HasPermission(ctx, userID, domain.PermissionIdentityPIIRead)
+}
+
+// HasPermission fails closed like CanReadPII.
+func (s *PIIService) HasPermission(ctx context.Context, userID uuid.UUID, permission string) bool {
+	if userID == uuid.Nil {
+		return false
+	}
+	permissions, err := s.permissions(ctx, userID)
+	if err != nil {
+		logger.Error("Failed to load permissions", zap.String("user_id", userID.String()), zap.Error(err))
+		return false
+	}
+	for _, p := range permissions {
+		if p == domain.PermissionAll || p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *PIIService) permissions(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	s.mu.Lock()
+	cached, ok := s.cache[userID]
+	s.mu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.permissions, nil
+	}
+
+	permissions, err := s.roles.GetUserPermissions(ctx, userID.String())
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.cache[userID] = cachedPermissions{permissions: permissions, expires: time.Now().Add(permissionCacheTTL)}
+	s.mu.Unlock()
+	return permissions, nil
+}
+
+func (s *PIIService) MaskIdentity(identity *domain.Identity) {
+	if identity == nil {
+		return
+	}
+	identity.PhoneNumber = s.mask(identity.PhoneNumber)
+	identity.IdentityCardNumber = s.mask(identity.IdentityCardNumber)
+	if len(identity.Metadata) > 0 {
+		masked := make(map[string]any, len(identity.Metadata))
+		for k, v := range identity.Metadata {
+			masked[k] = v
+			if s.isMaskedKey(k) {
+				masked[k] = "***"
+			}
+		}
+		identity.Metadata = masked
+	}
+}
+
+func (s *PIIService) MaskVersion(version *domain.IdentityVersion) {
+	if version == nil {
+		return
+	}
+	s.MaskIdentity(version.Identity)
+	for i, c := range version.Changes {
+		switch {
+		case c.Field == "phone_number" || c.Field == "identity_card_number":
+			version.Changes[i].Old = s.maskAny(c.Old)
+			version.Changes[i].New = s.maskAny(c.New)
+		case strings.HasPrefix(c.Field, "metadata.") && s.isMaskedKey(strings.TrimPrefix(c.Field, "metadata.")):
+			if c.Old != nil {
+				version.Changes[i].Old = "***"
+			}
+			if c.New != nil {
+				version.Changes[i].New = "***"
+			}
+		}
+	}
+}
+
+func (s *PIIService) maskAny(value any) any {
+	if str, ok := value.(string); ok {
+		return s.mask(str)
+	}
+	return value
+}
+
+// mask keeps the last RevealLast characters, and none of a value too short to
+// hide anything.
+func (s *PIIService) mask(value string) string {
+	n := utf8.RuneCountInString(value)
+	if n == 0 {
+		return ""
+	}
+	keep := s.cfg.RevealLast
+	if keep < 0 || n <= keep*2 {
+		keep = 0
+	}
+	runes := []rune(value)
+	return strings.Repeat("*", n-keep) + string(runes[n-keep:])
+}
+
+func (s *PIIService) isMaskedKey(key string) bool {
+	for _, k := range s.cfg.MetadataKeys {
+		if strings.EqualFold(k, key) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	"app/config"
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+	"app/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type RecognitionFeedbackService struct {
+	repo       ports.RecognitionFeedbackRepository
+	identities ports.IdentityRepository
+	audit      ports.AuditService
+	rules      attendanceRules
+}
+
+func NewRecognitionFeedbackService(
+	repo ports.RecognitionFeedbackRepository,
+	identities ports.IdentityRepository,
+	audit ports.AuditService,
+	cfg config.AttendanceConfig,
+) ports.RecognitionFeedbackService {
+	return &RecognitionFeedbackService{
+		repo:       repo,
+		identities: identities,
+		audit:      audit,
+		rules:      newAttendanceRules(cfg),
+	}
+}
+
+// SubmitFeedback records a verdict on a recognition. Rejected logs become
+// stranger logs; reassigned ones move to the correct identity. Attendance is
+// rebuilt for every identity that gained or lost the log that day.
+func (s *RecognitionFeedbackService) SubmitFeedback(ctx context.Context, logID uuid.UUID, req *ports.RecognitionFeedbackRequest) (*domain.RecognitionReview, error) {
+	log, err := s.repo.GetLog(ctx, logID)
+	if err != nil {
+		return nil, err
+	}
+	if log == nil {
+		return nil, domain.ErrRecognitionLogNotFound
+	}
+
+	var corrected *uuid.UUID
+	switch req.Action {
+	case domain.FeedbackConfirm:
+		if log.IdentityID == nil {
+			return nil, domain.ErrFeedbackNoIdentity
+		}
+		corrected = log.IdentityID
+	case domain.FeedbackReject:
+		if req.Reason == "" {
+			return nil, domain.ErrFeedbackReasonRequired
+		}
+		if log.IdentityID == nil {
+			return nil, domain.ErrFeedbackNoIdentity
+		}
+	case domain.FeedbackReassign:
+		if req.Reason == "" {
+			return nil, domain.ErrFeedbackReasonRequired
+		}
+		if req.IdentityID == nil {
+			return nil, domain.ErrFeedbackIdentityRequired
+		}
+		if log.IdentityID != nil && *log.IdentityID == *req.IdentityID {
+			return nil, domain.ErrFeedbackSameIdentity
+		}
+		target, err := s.identities.GetIdentity(ctx, *req.IdentityID)
+		if err != nil || target == nil {
+			return nil, domain.ErrIdentityNotFound
+		}
+		corrected = req.IdentityID
+	default:
+		return nil, domain.ErrInvalidFeedbackAction
+	}
+
+	fb := &domain.RecognitionFeedback{
+		LogID:               log.ID,
+		LogOccurredAt:       log.OccurredAt,
+		CameraID:            &log.CameraID,
+		Action:              req.Action,
+		OriginalIdentityID:  log.IdentityID,
+		CorrectedIdentityID: corrected,
+		Confidence:          log.Confidence,
+		FaceCropURL:         log.FaceCropURL,
+		SnapshotURL:         log.SnapshotURL,
+		Embedding:           log.Embedding,
+		Reason:              req.Reason,
+		ReviewedBy:          req.ReviewedBy,
+	}
+	if err := s.repo.ApplyFeedback(ctx, fb); err != nil {
+		return nil, err
+	}
+
+	if err := s.audit.LogAction(ctx, &domain.AuditLog{
+		UserID:    req.ReviewedBy,
+		Action:    "RECOGNITION_FEEDBACK",
+		TableName: "recognition_logs",
+		RecordID:  log.ID.String(),
+		OldValue:  map[string]any{"identity_id": log.IdentityID, "review_status": log.ReviewStatus},
+		NewValue:  map[string]any{"identity_id": corrected, "action": req.Action, "reason": req.Reason},
+	}); err != nil {
+		logger.Error("Failed to audit recognition feedback", zap.Error(err))
+	}
+
+	review := &domain.RecognitionReview{Feedback: fb, Log: log, Attendance: []*domain.AttendanceRecord{}}
+	if req.Action != domain.FeedbackConfirm {
+		date := s.rules.dateOf(log.OccurredAt)
+		for _, id := range []*uuid.UUID{log.IdentityID, corrected} {
+			if id == nil {
+				continue
+			}
+			record, err := s.recomputeAttendance(ctx, *id, date)
+			if err != nil {
+				logger.Error("Failed to recompute attendance", zap.String("identity_id", id.String()),
+					zap.Time("date", date), zap.Error(err))
+				continue
+			}
+			if record != nil {
+				review.Attendance = append(review.Attendance, record)
+			}
+		}
+	}
+
+	log.IdentityID = corrected
+	log.ReviewStatus = req.Action.ReviewStatus()
+	log.Embedding = nil
+	fb.Embedding = nil
+	return review, nil
+}
+
+// recomputeAttendance rebuilds one identity's day from its remaining
+// recognitions. A day left without any is marked absent, not deleted.
+func (s *RecognitionFeedbackService) recomputeAttendance(ctx context.Context, identityID uuid.UUID, date time.Time) (*domain.AttendanceRecord, error) {
+	from, to := s.rules.bounds(date)
+	times, err := s.repo.ListRecognitionTimes(ctx, identityID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if len(times) == 0 {
+		return s.repo.ClearAttendance(ctx, identityID, date)
+	}
+	record := s.rules.build(identityID, date, times, time.Now())
+	if err := s.repo.SaveAttendance(ctx, record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+func (s *RecognitionFeedbackService) ListFeedback(ctx context.Context, filter *ports.FeedbackFilter) ([]*domain.RecognitionFeedback, int64, error) {
+	if filter.Limit < 1 {
+		filter.Limit = 20
+	}
+	return s.repo.ListFeedback(ctx, filter)
+}
+
+// ExportDataset is audited even when it fails part way, since whatever was
+// written has left the system.
+func (s *RecognitionFeedbackService) ExportDataset(ctx context.Context, filter *ports.FeedbackFilter, requestedBy *uuid.UUID, w io.Writer) (int, error) {
+	enc := json.NewEncoder(w)
+	count := 0
+	err := s.repo.EachLatestFeedback(ctx, filter, func(fb *domain.RecognitionFeedback) error {
+		count++
+		return enc.Encode(fb)
+	})
+
+	if count > 0 {
+		if auditErr := s.audit.LogAction(ctx, &domain.AuditLog{
+			UserID:    requestedBy,
+			Action:    "FEEDBACK_DATASET_EXPORT",
+			TableName: "recognition_feedback",
+			NewValue: map[string]any{
+				"action":      filter.Action,
+				"identity_id": filter.IdentityID,
+				"camera_id":   filter.CameraID,
+				"from":        filter.FromDate,
+				"to":          filter.ToDate,
+				"rows":        count,
+				"complete":    err == nil,
+			},
+		}); auditErr != nil {
+			logger.Error("Failed to audit feedback dataset export", zap.Error(auditErr))
+		}
+	}
+	return count, err
+}
@@ -0,0 +1,389 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"sort"
+	"time"
+
+	"app/config"
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+	"app/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Crops tried per face wanted, since some fail the enrollment quality gate
+const strangerCropAttempts = 3
+
+type StrangerService struct {
+	repo       ports.StrangerRepository
+	identities ports.IdentityService
+	storage    ports.FileStorage
+	embedder   ports.EmbeddingProvider
+	jobs       ports.JobService
+	audit      ports.AuditService
+	cfg        config.StrangersConfig
+}
+
+func NewStrangerService(
+	repo ports.StrangerRepository,
+	identities ports.IdentityService,
+	storage ports.FileStorage,
+	embedder ports.EmbeddingProvider,
+	jobs ports.JobService,
+	audit ports.AuditService,
+	cfg config.StrangersConfig,
+) ports.StrangerService {
+	if cfg.BatchSize < 1 {
+		cfg.BatchSize = 1000
+	}
+	if cfg.PromoteFaces < 1 {
+		cfg.PromoteFaces = 3
+	}
+	return &StrangerService{
+		repo:       repo,
+		identities: identities,
+		storage:    storage,
+		embedder:   embedder,
+		jobs:       jobs,
+		audit:      audit,
+		cfg:        cfg,
+	}
+}
+
+// StartClustering assigns unclustered stranger logs to the closest open
+// cluster, or starts a new one. Logs without an embedding get one from their
+// crop when an embedding provider is configured and are skipped otherwise.
+func (s *StrangerService) StartClustering(ctx context.Context, requestedBy *uuid.UUID) (*domain.Job, error) {
+	params := map[string]any{"min_score": s.cfg.MinScore, "lookback_days": s.cfg.LookbackDays}
+	return s.jobs.Start(ctx, domain.JobTypeStrangerClustering, requestedBy, params, func(ctx context.Context, tracker ports.JobTracker) (map[string]any, error) {
+		clusters, err := s.repo.ListOpenCentroids(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range clusters {
+			c.Centroid = normalizeEmbedding(c.Centroid)
+		}
+
+		after := time.Time{}
+		if s.cfg.LookbackDays > 0 {
+			after = time.Now().AddDate(0, 0, -s.cfg.LookbackDays)
+		}
+		afterID := uuid.Nil
+		seen, assigned, skipped, created := 0, 0, 0, 0
+
+		for {
+			logs, err := s.repo.ListUnclustered(ctx, after, afterID, s.cfg.BatchSize)
+			if err != nil {
+				return nil, err
+			}
+			if len(logs) == 0 {
+				break
+			}
+			seen += len(logs)
+			tracker.SetTotal(seen)
+			after, afterID = logs[len(logs)-1].OccurredAt, logs[len(logs)-1].ID
+
+			pending := make(map[*domain.StrangerCluster][]uuid.UUID)
+			for _, log := range logs {
+				embedding, err := s.logEmbedding(ctx, log)
+				if err != nil || len(embedding) == 0 {
+					skipped++
+					tracker.Step(false)
+					continue
+				}
+
+				best, bestScore := (*domain.StrangerCluster)(nil), s.cfg.MinScore
+				for _, c := range clusters {
+					if score := cosineSimilarity(c.Centroid, embedding); score >= bestScore {
+						best, bestScore = c, score
+					}
+				}
+				if best == nil {
+					best = &domain.StrangerCluster{Centroid: embedding}
+					clusters = append(clusters, best)
+					created++
+				} else {
+					best.Centroid = mergeCentroid(best.Centroid, best.LogCount+len(pending[best]), embedding)
+				}
+				pending[best] = append(pending[best], log.ID)
+				assigned++
+				tracker.Step(true)
+			}
+
+			for c, ids := range pending {
+				if err := s.repo.SaveCluster(ctx, c, ids); err != nil {
+					return nil, err
+				}
+			}
+			if len(logs) < s.cfg.BatchSize {
+				break
+			}
+		}
+		return map[string]any{"logs": seen, "assigned": assigned, "skipped": skipped, "clusters_created": created}, nil
+	})
+}
+
+// logEmbedding returns the log's unit embedding, computing and storing it
+// from the face crop when the edge did not send one.
+func (s *StrangerService) logEmbedding(ctx context.Context, log *domain.RecognitionLog) ([]float32, error) {
+	if len(log.Embedding) > 0 {
+		return normalizeEmbedding(log.Embedding), nil
+	}
+	if s.embedder == nil {
+		return nil, domain.ErrNoEmbeddingProvider
+	}
+
+	file, err := s.storage.OpenFile(ctx, log.FaceCropURL)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	log.Embedding, err = s.embedder.Embed(ctx, file)
+	if err != nil {
+		logger.Error("Failed to embed stranger crop", zap.String("log_id", log.ID.String()), zap.Error(err))
+		return nil, err
+	}
+	if err := s.repo.SetLogEmbedding(ctx, log); err != nil {
+		return nil, err
+	}
+	return normalizeEmbedding(log.Embedding), nil
+}
+
+// mergeCentroid folds v into a unit centroid that already averages n vectors.
+func mergeCentroid(centroid []float32, n int, v []float32) []float32 {
+	if len(centroid) != len(v) {
+		return centroid
+	}
+	out := make([]float32, len(v))
+	for i := range v {
+		out[i] = centroid[i]*float32(n) + v[i]
+	}
+	return normalizeEmbedding(out)
+}
+
+func normalizeEmbedding(v []float32) []float32 {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	if sum == 0 {
+		return v
+	}
+	norm := float32(math.Sqrt(sum))
+	out := make([]float32, len(v))
+	for i, x := range v {
+		out[i] = x / norm
+	}
+	return out
+}
+
+// cosineSimilarity expects unit vectors.
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+	}
+	return dot
+}
+
+func (s *StrangerService) ListClusters(ctx context.Context, filter *ports.StrangerClusterFilter) ([]*domain.StrangerCluster, int64, error) {
+	if filter.Limit < 1 {
+		filter.Limit = 20
+	}
+	return s.repo.ListClusters(ctx, filter)
+}
+
+func (s *StrangerService) GetCluster(ctx context.Context, id uuid.UUID) (*domain.StrangerCluster, error) {
+	cluster, err := s.repo.GetCluster(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if cluster == nil {
+		return nil, domain.ErrStrangerClusterNotFound
+	}
+	return cluster, nil
+}
+
+func (s *StrangerService) ListClusterLogs(ctx context.Context, id uuid.UUID, limit, offset int32) ([]*domain.RecognitionLog, int64, error) {
+	if _, err := s.GetCluster(ctx, id); err != nil {
+		return nil, 0, err
+	}
+	if limit < 1 {
+		limit = 20
+	}
+	return s.repo.ListClusterLogs(ctx, id, limit, offset)
+}
+
+// PromoteCluster creates a pending identity for the cluster, links its logs
+// and enrolls the crops closest to the cluster's average face. Crops are
+// copied so recognition log retention cannot remove enrolled images.
+func (s *StrangerService) PromoteCluster(ctx context.Context, id uuid.UUID, req *ports.PromoteStrangerRequest) (*domain.StrangerPromotion, error) {
+	cluster, err := s.GetCluster(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if cluster.Status != domain.StrangerClusterOpen {
+		return nil, domain.ErrStrangerClusterPromoted
+	}
+
+	logs, _, err := s.repo.ListClusterLogs(ctx, id, int32(s.cfg.BatchSize), 0)
+	if err != nil {
+		return nil, err
+	}
+	crops := rankStrangerCrops(logs)
+	if len(crops) == 0 {
+		return nil, domain.ErrStrangerNoCrops
+	}
+
+	code, name := req.Code, req.FullName
+	if code == "" {
+		code = fmt.Sprintf("STRANGER-%d", cluster.Seq)
+	}
+	if name == "" {
+		name = cluster.Label
+	}
+	note := req.Note
+	if note == "" {
+		note = "Promoted from " + cluster.Label
+	}
+	identity, err := s.identities.CreateIdentity(ctx, &ports.CreateIdentityRequest{
+		Code:       code,
+		FullName:   name,
+		Type:       req.Type,
+		Department: req.Department,
+		Note:       note,
+		CreatedBy:  req.PromotedBy,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	linked, err := s.repo.PromoteCluster(ctx, id, identity.ID, req.PromotedBy)
+	if err != nil {
+		// Someone else promoted the cluster first
+		if delErr := s.identities.DeleteIdentity(ctx, identity.ID, req.PromotedBy); delErr != nil {
+			logger.Error("Failed to remove identity of failed promotion", zap.String("identity_id", identity.ID.String()), zap.Error(delErr))
+		}
+		return nil, err
+	}
+
+	result := &domain.StrangerPromotion{ClusterID: id, Identity: identity, Faces: []domain.IdentityFace{}, LogsLinked: linked}
+	for i, log := range crops {
+		if len(result.Faces) >= s.cfg.PromoteFaces || i >= s.cfg.PromoteFaces*strangerCropAttempts {
+			break
+		}
+		face, err := s.enrollCrop(ctx, identity, log, len(result.Faces) == 0, req.PromotedBy)
+		if err != nil {
+			result.SkippedCrops = append(result.SkippedCrops, domain.SkippedCrop{LogID: log.ID, URL: log.FaceCropURL, Reason: err.Error()})
+			continue
+		}
+		result.Faces = append(result.Faces, *face)
+	}
+
+	if err := s.audit.LogAction(ctx, &domain.AuditLog{
+		UserID:    req.PromotedBy,
+		Action:    "STRANGER_PROMOTE",
+		TableName: "identities",
+		RecordID:  identity.ID.String(),
+		NewValue: map[string]any{
+			"cluster_id":    id,
+			"cluster_label": cluster.Label,
+			"code":          identity.Code,
+			"faces":         len(result.Faces),
+			"logs_linked":   linked,
+		},
+	}); err != nil {
+		logger.Error("Failed to audit stranger promotion", zap.Error(err))
+	}
+	return result, nil
+}
+
+func (s *StrangerService) enrollCrop(ctx context.Context, identity *domain.Identity, log *domain.RecognitionLog, primary bool, by *uuid.UUID) (*domain.IdentityFace, error) {
+	file, err := s.storage.OpenFile(ctx, log.FaceCropURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrFaceImageUnreadable, err)
+	}
+	data, err := io.ReadAll(file)
+	file.Close()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrFaceImageUnreadable, err)
+	}
+
+	filename := fmt.Sprintf("identities/%s_%s%s", identity.Code, uuid.New().String()[:8], path.Ext(log.FaceCropURL))
+	url, err := s.storage.SaveFile(ctx, filename, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	face, err := s.identities.EnrollFace(ctx, &ports.EnrollFaceRequest{
+		IdentityID: identity.ID,
+		ImageURL:   url,
+		IsPrimary:  primary,
+		Embedding:  log.Embedding,
+		EnrolledBy: by,
+	})
+	if err != nil {
+		if delErr := s.storage.DeleteFile(ctx, url); delErr != nil {
+			logger.Error("Failed to remove rejected stranger crop", zap.String("url", url), zap.Error(delErr))
+		}
+		return nil, err
+	}
+	return face, nil
+}
+
+// rankStrangerCrops orders logs with a crop by similarity to the average of
+// the cluster's embeddings, then by detection confidence.
+func rankStrangerCrops(logs []*domain.RecognitionLog) []*domain.RecognitionLog {
+	var centroid []float32
+	n := 0
+	for _, l := range logs {
+		if len(l.Embedding) == 0 {
+			continue
+		}
+		if centroid == nil {
+			centroid = normalizeEmbedding(l.Embedding)
+		} else {
+			centroid = mergeCentroid(centroid, n, normalizeEmbedding(l.Embedding))
+		}
+		n++
+	}
+
+	type ranked struct {
+		log   *domain.RecognitionLog
+		score float64
+	}
+	crops := []ranked{}
+	for _, l := range logs {
+		if l.FaceCropURL == "" {
+			continue
+		}
+		score := -1.0
+		if len(l.Embedding) > 0 && centroid != nil {
+			score = cosineSimilarity(centroid, normalizeEmbedding(l.Embedding))
+		}
+		crops = append(crops, ranked{l, score})
+	}
+	sort.SliceStable(crops, func(i, j int) bool {
+		if crops[i].score != crops[j].score {
+			return crops[i].score > crops[j].score
+		}
+		return crops[i].log.Confidence > crops[j].log.Confidence
+	})
+
+	out := make([]*domain.RecognitionLog, len(crops))
+	for i, c := range crops {
+		out[i] = c.log
+	}
+	return out
+}
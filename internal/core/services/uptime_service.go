@@ -0,0 +1,220 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"app/internal/core/domain"
+	"app/internal/core/ports"
+
+	"github.com/google/uuid"
+)
+
+type UptimeService struct {
+	repo ports.UptimeRepository
+}
+
+func NewUptimeService(repo ports.UptimeRepository) ports.UptimeService {
+	return &UptimeService{repo: repo}
+}
+
+// uptimeTally accumulates time per status and outages for one camera or a
+// group of them.
+type uptimeTally struct {
+	online, offline, maintenance time.Duration
+	outages, recovered           int
+	recoveredDowntime, longest   time.Duration
+}
+
+func (t *uptimeTally) span(status domain.CameraStatus, d time.Duration) {
+	switch status {
+	case domain.CameraStatusOffline:
+		t.offline += d
+	case domain.CameraStatusMaintenance:
+		t.maintenance += d
+	default:
+		t.online += d
+	}
+}
+
+func (t *uptimeTally) outage(d time.Duration, recovered bool) {
+	t.outages++
+	if d > t.longest {
+		t.longest = d
+	}
+	if recovered {
+		t.recovered++
+		t.recoveredDowntime += d
+	}
+}
+
+func (t *uptimeTally) add(o uptimeTally) {
+	t.online += o.online
+	t.offline += o.offline
+	t.maintenance += o.maintenance
+	t.outages += o.outages
+	t.recovered += o.recovered
+	t.recoveredDowntime += o.recoveredDowntime
+	if o.longest > t.longest {
+		t.longest = o.longest
+	}
+}
+
+// stats reports 100% for a camera never monitored in the period.
+func (t uptimeTally) stats() domain.UptimeStats {
+	s := domain.UptimeStats{
+		UptimePercent:        100,
+		OnlineSeconds:        t.online.Seconds(),
+		OfflineSeconds:       t.offline.Seconds(),
+		MaintenanceSeconds:   t.maintenance.Seconds(),
+		Outages:              t.outages,
+		LongestOutageSeconds: t.longest.Seconds(),
+	}
+	if monitored := t.online + t.offline; monitored > 0 {
+		s.UptimePercent = t.online.Seconds() / monitored.Seconds() * 100
+	}
+	if t.recovered > 0 {
+		s.MTTRSeconds = t.recoveredDowntime.Seconds() / float64(t.recovered)
+	}
+	return s
+}
+
+// GetUptimeReport replays each camera's status history over the period. The
+// part of the period still in the future is left out.
+func (s *UptimeService) GetUptimeReport(ctx context.Context, filter *ports.UptimeFilter) (*domain.UptimeReport, error) {
+	from, to := filter.FromDate, filter.ToDate
+	if now := time.Now(); to.After(now) {
+		to = now
+	}
+	if !to.After(from) {
+		return nil, domain.ErrInvalidReportPeriod
+	}
+
+	cameras, err := s.repo.ListCameras(ctx, filter.ZoneID)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := s.repo.ListStatusChanges(ctx, from, to, filter.ZoneID)
+	if err != nil {
+		return nil, err
+	}
+	byCamera := map[uuid.UUID][]*domain.CameraStatusChange{}
+	for _, c := range changes {
+		byCamera[c.CameraID] = append(byCamera[c.CameraID], c)
+	}
+
+	report := &domain.UptimeReport{From: from, To: to, Cameras: []domain.CameraUptime{}, Zones: []domain.ZoneUptime{}}
+	var overall uptimeTally
+	var zoneTallies []uptimeTally
+	zoneIndex := map[uuid.UUID]int{}
+	unzoned := -1
+	for _, camera := range cameras {
+		tally := tallyCamera(camera, byCamera[camera.ID], from, to)
+		overall.add(tally)
+		report.Cameras = append(report.Cameras, domain.CameraUptime{
+			CameraID:    camera.ID,
+			CameraName:  camera.Name,
+			ZoneID:      camera.ZoneID,
+			ZoneName:    camera.ZoneName,
+			UptimeStats: tally.stats(),
+		})
+
+		idx, ok := unzoned, unzoned >= 0
+		if camera.ZoneID != nil {
+			idx, ok = zoneIndex[*camera.ZoneID]
+		}
+		if !ok {
+			report.Zones = append(report.Zones, domain.ZoneUptime{ZoneID: camera.ZoneID, ZoneName: camera.ZoneName})
+			zoneTallies = append(zoneTallies, uptimeTally{})
+			idx = len(report.Zones) - 1
+			if camera.ZoneID != nil {
+				zoneIndex[*camera.ZoneID] = idx
+			} else {
+				unzoned = idx
+			}
+		}
+		report.Zones[idx].Cameras++
+		zoneTallies[idx].add(tally)
+	}
+	for i := range report.Zones {
+		report.Zones[i].UptimeStats = zoneTallies[i].stats()
+	}
+	report.Overall = overall.stats()
+	return report, nil
+}
+
+// tallyCamera walks a camera's transitions, sorted by time, from the later of
+// from and its creation up to to. Without an earlier transition the starting
+// status is the first transition's from_status, or the current status when
+// nothing changed in the period.
+func tallyCamera(camera *domain.UptimeCamera, changes []*domain.CameraStatusChange, from, to time.Time) uptimeTally {
+	var tally uptimeTally
+	start := from
+	if camera.CreatedAt.After(start) {
+		start = camera.CreatedAt
+	}
+	if !start.Before(to) {
+		return tally
+	}
+
+	status, known := camera.Status, false
+	i := 0
+	for ; i < len(changes) && !changes[i].CreatedAt.After(start); i++ {
+		status, known = changes[i].ToStatus, true
+	}
+	if !known && i < len(changes) {
+		status = changes[i].FromStatus
+	}
+
+	cursor := start
+	outageStart := start
+	for ; i < len(changes) && changes[i].CreatedAt.Before(to); i++ {
+		c := changes[i]
+		tally.span(status, c.CreatedAt.Sub(cursor))
+		switch {
+		case status != domain.CameraStatusOffline && c.ToStatus == domain.CameraStatusOffline:
+			outageStart = c.CreatedAt
+		case status == domain.CameraStatusOffline && c.ToStatus != domain.CameraStatusOffline:
+			tally.outage(c.CreatedAt.Sub(outageStart), c.ToStatus != domain.CameraStatusMaintenance)
+		}
+		cursor, status = c.CreatedAt, c.ToStatus
+	}
+	tally.span(status, to.Sub(cursor))
+	if status == domain.CameraStatusOffline {
+		tally.outage(to.Sub(outageStart), false)
+	}
+	return tally
+}
+
+// WorstCameras only lists cameras that had downtime, lowest uptime first.
+func (s *UptimeService) WorstCameras(ctx context.Context, days, limit int) ([]domain.CameraUptime, error) {
+	if days < 1 {
+		days = 30
+	}
+	if limit < 1 {
+		limit = 5
+	}
+	to := time.Now()
+	report, err := s.GetUptimeReport(ctx, &ports.UptimeFilter{FromDate: to.AddDate(0, 0, -days), ToDate: to})
+	if err != nil {
+		return nil, err
+	}
+
+	worst := []domain.CameraUptime{}
+	for _, c := range report.Cameras {
+		if c.OfflineSeconds > 0 {
+			worst = append(worst, c)
+		}
+	}
+	sort.SliceStable(worst, func(i, j int) bool {
+		if worst[i].UptimePercent != worst[j].UptimePercent {
+			return worst[i].UptimePercent < worst[j].UptimePercent
+		}
+		return worst[i].Outages > worst[j].Outages
+	})
+	if len(worst) > limit {
+		worst = worst[:limit]
+	}
+	return worst, nil
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"sort"
 
 	"app/internal/core/domain"
 	"app/internal/core/ports"
@@ -21,9 +22,26 @@ func NewZoneService(repo ports.ZoneRepository) ports.ZoneService {
 }
 
 func (s *ZoneService) CreateZone(ctx context.Context, req *domain.CreateZoneRequest) (*domain.Zone, error) {
+	if !req.Kind.Valid() {
+		return nil, domain.ErrInvalidZoneKind
+	}
+	if req.ParentID != nil && *req.ParentID == "" {
+		req.ParentID = nil
+	}
+	if req.ParentID != nil {
+		parent, err := s.repo.GetByID(ctx, *req.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent == nil {
+			return nil, domain.ErrZoneParentNotFound
+		}
+	}
 	zone := &domain.Zone{
+		ParentID:    req.ParentID,
 		Name:        req.Name,
 		Description: req.Description,
+		Kind:        req.Kind,
 	}
 
 	err := s.repo.Save(ctx, zone)
@@ -52,6 +70,27 @@ func (s *ZoneService) UpdateZone(ctx context.Context, id string, req *domain.Upd
 		return nil, nil // Or custom error NotFound
 	}
 
+	if !req.Kind.Valid() {
+		return nil, domain.ErrInvalidZoneKind
+	}
+
+	// The move goes first: it is the step that can be refused
+	if req.ParentID != nil {
+		parentID := req.ParentID
+		if *parentID == "" {
+			parentID = nil
+		}
+		if !sameParent(zone.ParentID, parentID) {
+			if err := s.repo.Move(ctx, zone.ID, parentID); err != nil {
+				return nil, err
+			}
+			logger.Info("Zone moved", zap.String("id", zone.ID), zap.Stringp("parent_id", parentID))
+			if zone, err = s.repo.GetByID(ctx, id); err != nil || zone == nil {
+				return nil, err
+			}
+		}
+	}
+
 	// Update fields if provided (naive approach, usually check for empty string/nil)
 	if req.Name != "" {
 		zone.Name = req.Name
@@ -59,6 +98,9 @@ func (s *ZoneService) UpdateZone(ctx context.Context, id string, req *domain.Upd
 	if req.Description != "" {
 		zone.Description = req.Description
 	}
+	if req.Kind != "" {
+		zone.Kind = req.Kind
+	}
 
 	if err := s.repo.Update(ctx, zone); err != nil {
 		return nil, err
@@ -69,3 +111,56 @@ func (s *ZoneService) UpdateZone(ctx context.Context, id string, req *domain.Upd
 func (s *ZoneService) DeleteZone(ctx context.Context, id string) error {
 	return s.repo.Delete(ctx, id)
 }
+
+// ZoneTree nests every zone under its parent, sorted by name, with camera
+// counts limited to scope.
+func (s *ZoneService) ZoneTree(ctx context.Context, scope *domain.CameraScope) ([]*domain.ZoneNode, error) {
+	zones, err := s.repo.List(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.repo.CameraCounts(ctx, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make(map[string]*domain.ZoneNode, len(zones))
+	for _, z := range zones {
+		nodes[z.ID] = &domain.ZoneNode{Zone: *z, Cameras: counts[z.ID], Children: []*domain.ZoneNode{}}
+	}
+	roots := []*domain.ZoneNode{}
+	for _, z := range zones {
+		node := nodes[z.ID]
+		if parent, ok := nodes[zoneParent(z)]; ok {
+			parent.Children = append(parent.Children, node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+	sortZoneNodes(roots)
+	return roots, nil
+}
+
+func sortZoneNodes(nodes []*domain.ZoneNode) int {
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
+	total := 0
+	for _, node := range nodes {
+		node.TotalCameras = node.Cameras + sortZoneNodes(node.Children)
+		total += node.TotalCameras
+	}
+	return total
+}
+
+func zoneParent(z *domain.Zone) string {
+	if z.ParentID == nil {
+		return ""
+	}
+	return *z.ParentID
+}
+
+func sameParent(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -0,0 +1,12 @@
+-- Up
+ALTER TABLE identity_faces ADD COLUMN IF NOT EXISTS quality_details JSONB;
+ALTER TABLE identity_faces ADD COLUMN IF NOT EXISTS quality_flagged BOOLEAN DEFAULT FALSE;
+ALTER TABLE identity_faces ADD COLUMN IF NOT EXISTS quality_reason TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_identity_faces_flagged ON identity_faces(quality_flagged) WHERE quality_flagged;
+
+-- Down
+DROP INDEX IF EXISTS idx_identity_faces_flagged;
+ALTER TABLE identity_faces DROP COLUMN IF EXISTS quality_reason;
+ALTER TABLE identity_faces DROP COLUMN IF EXISTS quality_flagged;
+ALTER TABLE identity_faces DROP COLUMN IF EXISTS quality_details;
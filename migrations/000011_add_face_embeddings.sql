@@ -0,0 +1,15 @@
+-- Up
+-- Embeddings are stored as REAL[] so the schema works without pgvector.
+-- When the extension is installed the column is cast to vector for search.
+ALTER TABLE identity_faces ADD COLUMN IF NOT EXISTS embedding REAL[];
+
+DO $$ BEGIN
+    IF EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'vector') THEN
+        CREATE INDEX IF NOT EXISTS idx_identity_faces_embedding
+            ON identity_faces USING hnsw ((embedding::vector(512)) vector_cosine_ops);
+    END IF;
+END $$;
+
+-- Down
+DROP INDEX IF EXISTS idx_identity_faces_embedding;
+ALTER TABLE identity_faces DROP COLUMN IF EXISTS embedding;
@@ -0,0 +1,23 @@
+-- Up
+ALTER TABLE identities ADD COLUMN IF NOT EXISTS approved_by UUID REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE identities ADD COLUMN IF NOT EXISTS approved_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE identities ADD COLUMN IF NOT EXISTS review_reason TEXT;
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL,
+    title VARCHAR(200) NOT NULL,
+    message TEXT,
+    resource_type VARCHAR(50),
+    resource_id VARCHAR(50),
+    read_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user_time ON notifications(user_id, created_at DESC);
+
+-- Down
+DROP TABLE IF EXISTS notifications;
+ALTER TABLE identities DROP COLUMN IF EXISTS review_reason;
+ALTER TABLE identities DROP COLUMN IF EXISTS approved_at;
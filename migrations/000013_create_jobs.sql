@@ -0,0 +1,22 @@
+-- Up
+CREATE TABLE IF NOT EXISTS jobs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    type VARCHAR(50) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'queued', -- queued, running, completed, failed
+    total INT DEFAULT 0,
+    processed INT DEFAULT 0,
+    failed INT DEFAULT 0,
+    params JSONB,
+    result JSONB,
+    result_url TEXT,
+    error TEXT,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    started_at TIMESTAMP WITH TIME ZONE,
+    finished_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_jobs_type_time ON jobs(type, created_at DESC);
+
+-- Down
+DROP TABLE IF EXISTS jobs;
@@ -0,0 +1,10 @@
+-- Up
+-- Erased identities stay behind as anonymous tombstones so attendance and
+-- recognition counts keep adding up
+ALTER TABLE identities ADD COLUMN IF NOT EXISTS erased_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_record ON audit_logs(table_name, record_id);
+
+-- Down
+DROP INDEX IF EXISTS idx_audit_logs_record;
+ALTER TABLE identities DROP COLUMN IF EXISTS erased_at;
@@ -0,0 +1,34 @@
+-- Up
+-- Data keys are stored wrapped by the master key (KMS); one active data key
+-- per tenant and purpose, older versions stay for decryption until re-encrypted
+CREATE TABLE IF NOT EXISTS encryption_keys (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant VARCHAR(50) NOT NULL,
+    purpose VARCHAR(20) NOT NULL, -- data, blind_index
+    version INT NOT NULL,
+    master_key_id VARCHAR(100) NOT NULL,
+    wrapped_key BYTEA NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'active', -- active, retired
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    retired_at TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT uniq_encryption_key_version UNIQUE (tenant, purpose, version)
+);
+
+-- Ciphertext does not fit the original VARCHAR(20) columns
+ALTER TABLE identities ALTER COLUMN phone_number TYPE TEXT;
+ALTER TABLE identities ALTER COLUMN identity_card_number TYPE TEXT;
+ALTER TABLE identities ADD COLUMN IF NOT EXISTS metadata_enc TEXT;
+ALTER TABLE identities ADD COLUMN IF NOT EXISTS phone_number_bidx VARCHAR(64);
+ALTER TABLE identities ADD COLUMN IF NOT EXISTS identity_card_number_bidx VARCHAR(64);
+ALTER TABLE users ALTER COLUMN phone TYPE TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_identities_phone_bidx ON identities(phone_number_bidx);
+CREATE INDEX IF NOT EXISTS idx_identities_card_bidx ON identities(identity_card_number_bidx);
+
+-- Down
+DROP INDEX IF EXISTS idx_identities_card_bidx;
+DROP INDEX IF EXISTS idx_identities_phone_bidx;
+ALTER TABLE identities DROP COLUMN IF EXISTS identity_card_number_bidx;
+ALTER TABLE identities DROP COLUMN IF EXISTS phone_number_bidx;
+ALTER TABLE identities DROP COLUMN IF EXISTS metadata_enc;
+DROP TABLE IF EXISTS encryption_keys;
@@ -0,0 +1,23 @@
+-- Up
+-- Read-side audit trail: who viewed which biometric resources. Kept apart from
+-- audit_logs, which models writes, because reads are far more frequent.
+CREATE TABLE IF NOT EXISTS access_logs (
+    id BIGSERIAL PRIMARY KEY,
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    resource_type VARCHAR(50) NOT NULL, -- identity, face_image, recognition_log, attendance_record, identity_export, import_report
+    resource_id VARCHAR(100),
+    identity_ids UUID[] NOT NULL DEFAULT '{}',
+    route VARCHAR(255),
+    filters JSONB,
+    pii_unmasked BOOLEAN NOT NULL DEFAULT FALSE,
+    ip_address VARCHAR(45),
+    user_agent TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_access_logs_identities ON access_logs USING GIN (identity_ids);
+CREATE INDEX IF NOT EXISTS idx_access_logs_user ON access_logs(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_access_logs_created ON access_logs(created_at DESC);
+
+-- Down
+DROP TABLE IF EXISTS access_logs;
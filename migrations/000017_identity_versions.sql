@@ -0,0 +1,23 @@
+-- Up
+-- Append-only change history for identities and their faces. snapshot and
+-- changes are JSON, encrypted like other PII when field encryption is on.
+CREATE TABLE IF NOT EXISTS identity_versions (
+    id BIGSERIAL PRIMARY KEY,
+    identity_id UUID NOT NULL REFERENCES identities(id) ON DELETE CASCADE,
+    version INT NOT NULL,
+    entity_type VARCHAR(20) NOT NULL, -- identity, face
+    face_id UUID,
+    operation VARCHAR(20) NOT NULL,
+    snapshot TEXT NOT NULL,
+    changes TEXT,
+    revert_of INT,
+    reason TEXT,
+    changed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    changed_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (identity_id, version)
+);
+
+CREATE INDEX IF NOT EXISTS idx_identity_versions_as_of ON identity_versions(identity_id, entity_type, changed_at DESC);
+
+-- Down
+DROP TABLE IF EXISTS identity_versions;
@@ -0,0 +1,24 @@
+-- Up
+ALTER TABLE identities ADD COLUMN IF NOT EXISTS merged_into UUID REFERENCES identities(id);
+
+CREATE TABLE IF NOT EXISTS identity_duplicate_candidates (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    identity_a UUID NOT NULL REFERENCES identities(id) ON DELETE CASCADE,
+    identity_b UUID NOT NULL REFERENCES identities(id) ON DELETE CASCADE,
+    score FLOAT NOT NULL,
+    reasons TEXT[] NOT NULL DEFAULT '{}',
+    face_score FLOAT,
+    status VARCHAR(20) NOT NULL DEFAULT 'open', -- open, dismissed, merged
+    resolved_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_duplicate_pair_order CHECK (identity_a < identity_b),
+    UNIQUE (identity_a, identity_b)
+);
+
+CREATE INDEX IF NOT EXISTS idx_duplicate_candidates_status ON identity_duplicate_candidates(status, score DESC);
+CREATE INDEX IF NOT EXISTS idx_duplicate_candidates_b ON identity_duplicate_candidates(identity_b);
+
+-- Down
+DROP TABLE IF EXISTS identity_duplicate_candidates;
+ALTER TABLE identities DROP COLUMN IF EXISTS merged_into;
@@ -0,0 +1,33 @@
+-- Up
+-- Edge devices may send the face embedding with a stranger log; the clustering
+-- job fills it in from the crop otherwise
+ALTER TABLE recognition_logs ADD COLUMN IF NOT EXISTS embedding REAL[];
+ALTER TABLE recognition_logs ADD COLUMN IF NOT EXISTS stranger_cluster_id UUID;
+
+CREATE TABLE IF NOT EXISTS stranger_clusters (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    seq SERIAL UNIQUE, -- "Unknown person #seq"
+    centroid REAL[] NOT NULL,
+    log_count INT NOT NULL DEFAULT 0,
+    camera_ids UUID[] NOT NULL DEFAULT '{}',
+    best_crop_url TEXT,
+    first_seen_at TIMESTAMP WITH TIME ZONE,
+    last_seen_at TIMESTAMP WITH TIME ZONE,
+    status VARCHAR(20) NOT NULL DEFAULT 'open', -- open, promoted
+    promoted_identity_id UUID REFERENCES identities(id) ON DELETE SET NULL,
+    promoted_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    promoted_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_stranger_clusters_status ON stranger_clusters(status, last_seen_at DESC);
+CREATE INDEX IF NOT EXISTS idx_rec_logs_stranger_cluster ON recognition_logs(stranger_cluster_id, occurred_at DESC) WHERE stranger_cluster_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_rec_logs_unclustered ON recognition_logs(occurred_at) WHERE identity_id IS NULL AND stranger_cluster_id IS NULL;
+
+-- Down
+DROP INDEX IF EXISTS idx_rec_logs_unclustered;
+DROP INDEX IF EXISTS idx_rec_logs_stranger_cluster;
+DROP TABLE IF EXISTS stranger_clusters;
+ALTER TABLE recognition_logs DROP COLUMN IF EXISTS stranger_cluster_id;
+ALTER TABLE recognition_logs DROP COLUMN IF EXISTS embedding;
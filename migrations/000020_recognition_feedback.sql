@@ -0,0 +1,30 @@
+-- Up
+ALTER TABLE recognition_logs ADD COLUMN IF NOT EXISTS review_status VARCHAR(20); -- confirmed, rejected, reassigned
+
+-- One row per review decision. Crop, embedding and labels are copied from the
+-- log so the dataset survives log retention.
+CREATE TABLE IF NOT EXISTS recognition_feedback (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    log_id UUID NOT NULL,
+    log_occurred_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    camera_id UUID REFERENCES cameras(id) ON DELETE SET NULL,
+    action VARCHAR(20) NOT NULL, -- confirm, reject, reassign
+    original_identity_id UUID REFERENCES identities(id) ON DELETE SET NULL,
+    corrected_identity_id UUID REFERENCES identities(id) ON DELETE SET NULL,
+    confidence FLOAT,
+    face_crop_url TEXT,
+    snapshot_url TEXT,
+    embedding REAL[],
+    reason TEXT,
+    reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_recognition_feedback_log ON recognition_feedback(log_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_recognition_feedback_created ON recognition_feedback(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_recognition_feedback_corrected ON recognition_feedback(corrected_identity_id);
+CREATE INDEX IF NOT EXISTS idx_recognition_feedback_original ON recognition_feedback(original_identity_id);
+
+-- Down
+DROP TABLE IF EXISTS recognition_feedback;
+ALTER TABLE recognition_logs DROP COLUMN IF EXISTS review_status;
@@ -0,0 +1,14 @@
+-- Up
+-- Operator verdict on a detection, used to build training datasets
+ALTER TABLE ai_events ADD COLUMN IF NOT EXISTS verdict VARCHAR(20); -- true_positive, false_positive
+-- Detector output sent by the edge (bbox, image size)
+ALTER TABLE recognition_logs ADD COLUMN IF NOT EXISTS metadata JSONB;
+
+CREATE INDEX IF NOT EXISTS idx_ai_events_verdict ON ai_events(created_at) WHERE verdict IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_rec_logs_reviewed ON recognition_logs(occurred_at) WHERE review_status IS NOT NULL;
+
+-- Down
+DROP INDEX IF EXISTS idx_rec_logs_reviewed;
+DROP INDEX IF EXISTS idx_ai_events_verdict;
+ALTER TABLE recognition_logs DROP COLUMN IF EXISTS metadata;
+ALTER TABLE ai_events DROP COLUMN IF EXISTS verdict;
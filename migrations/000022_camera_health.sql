@@ -0,0 +1,32 @@
+-- Up
+-- Camera health: probe state kept on the camera so hysteresis survives a
+-- worker restart, plus every status transition.
+ALTER TABLE cameras ADD COLUMN IF NOT EXISTS last_probe_at TIMESTAMPTZ;
+ALTER TABLE cameras ADD COLUMN IF NOT EXISTS last_seen_at TIMESTAMPTZ;
+ALTER TABLE cameras ADD COLUMN IF NOT EXISTS last_probe_error TEXT;
+ALTER TABLE cameras ADD COLUMN IF NOT EXISTS probe_failures INT NOT NULL DEFAULT 0;
+ALTER TABLE cameras ADD COLUMN IF NOT EXISTS probe_successes INT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS camera_status_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    camera_id UUID NOT NULL REFERENCES cameras(id) ON DELETE CASCADE,
+    from_status VARCHAR(20),
+    to_status VARCHAR(20) NOT NULL,
+    source VARCHAR(20) NOT NULL, -- monitor, manual
+    reason TEXT,
+    latency_ms INT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_camera_status_history_camera ON camera_status_history(camera_id, created_at DESC);
+
+ALTER TYPE event_type ADD VALUE IF NOT EXISTS 'camera_offline';
+
+-- Down
+-- Postgres cannot drop an enum value; camera_offline stays in event_type.
+DROP TABLE IF EXISTS camera_status_history;
+ALTER TABLE cameras DROP COLUMN IF EXISTS probe_successes;
+ALTER TABLE cameras DROP COLUMN IF EXISTS probe_failures;
+ALTER TABLE cameras DROP COLUMN IF EXISTS last_probe_error;
+ALTER TABLE cameras DROP COLUMN IF EXISTS last_seen_at;
+ALTER TABLE cameras DROP COLUMN IF EXISTS last_probe_at;
@@ -0,0 +1,36 @@
+-- Up
+-- Scheduled maintenance for a camera or every camera in a zone. A recurring
+-- window repeats its first occurrence (starts_at to ends_at) every day or
+-- week in its timezone until repeat_until.
+CREATE TABLE IF NOT EXISTS maintenance_windows (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(100) NOT NULL,
+    camera_id UUID REFERENCES cameras(id) ON DELETE CASCADE,
+    zone_id UUID REFERENCES zones(id) ON DELETE CASCADE,
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    recurrence VARCHAR(10) NOT NULL DEFAULT 'none', -- none, daily, weekly
+    repeat_until TIMESTAMPTZ,
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    event_policy VARCHAR(10) NOT NULL DEFAULT 'tag', -- drop, tag
+    note TEXT,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_maintenance_target CHECK ((camera_id IS NULL) <> (zone_id IS NULL)),
+    CONSTRAINT chk_maintenance_period CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_maintenance_windows_camera ON maintenance_windows(camera_id) WHERE camera_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_maintenance_windows_zone ON maintenance_windows(zone_id) WHERE zone_id IS NOT NULL;
+
+-- Set while the scheduler holds a camera in maintenance, so a camera put in
+-- maintenance by hand is never released automatically. No foreign key: a
+-- deleted window must still let the scheduler release its cameras.
+ALTER TABLE cameras ADD COLUMN IF NOT EXISTS maintenance_window_id UUID;
+ALTER TABLE camera_status_history ADD COLUMN IF NOT EXISTS maintenance_window_id UUID;
+
+-- Down
+ALTER TABLE camera_status_history DROP COLUMN IF EXISTS maintenance_window_id;
+ALTER TABLE cameras DROP COLUMN IF EXISTS maintenance_window_id;
+DROP TABLE IF EXISTS maintenance_windows;
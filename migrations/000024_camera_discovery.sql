@@ -0,0 +1,25 @@
+-- Up
+-- Devices found by an ONVIF discovery job, kept until adopted or the job is deleted
+CREATE TABLE IF NOT EXISTS discovered_devices (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    job_id UUID NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    endpoint_ref TEXT NOT NULL,
+    xaddr TEXT NOT NULL,
+    ip_address VARCHAR(50),
+    name VARCHAR(100),
+    manufacturer VARCHAR(100),
+    model VARCHAR(100),
+    firmware_version VARCHAR(100),
+    serial_number VARCHAR(100),
+    hardware_id VARCHAR(100),
+    scopes TEXT[],
+    profiles JSONB NOT NULL DEFAULT '[]', -- [{token, name, encoding, width, height, rtsp_url}]
+    error TEXT,
+    camera_id UUID REFERENCES cameras(id) ON DELETE SET NULL,
+    discovered_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    adopted_at TIMESTAMP WITH TIME ZONE,
+    UNIQUE (job_id, endpoint_ref)
+);
+
+-- Down
+DROP TABLE IF EXISTS discovered_devices;
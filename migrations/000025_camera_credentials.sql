@@ -0,0 +1,10 @@
+-- Up
+-- RTSP credentials, encrypted like identity PII. rtsp_url no longer carries
+-- them; URLs that still do are redacted on read and migrated by the
+-- reencrypt job.
+ALTER TABLE cameras ADD COLUMN IF NOT EXISTS rtsp_username TEXT;
+ALTER TABLE cameras ADD COLUMN IF NOT EXISTS rtsp_password TEXT;
+
+-- Down
+ALTER TABLE cameras DROP COLUMN IF EXISTS rtsp_password;
+ALTER TABLE cameras DROP COLUMN IF EXISTS rtsp_username;
@@ -0,0 +1,19 @@
+-- Up
+-- Zones nest under a parent. path is the materialized path of IDs from the
+-- root down to the zone, '/<root>/.../<id>/', so a subtree is a prefix
+-- match. Existing zones become top-level.
+ALTER TABLE zones ADD COLUMN IF NOT EXISTS parent_id UUID REFERENCES zones(id) ON DELETE RESTRICT;
+ALTER TABLE zones ADD COLUMN IF NOT EXISTS kind VARCHAR(20);
+ALTER TABLE zones ADD COLUMN IF NOT EXISTS path TEXT;
+UPDATE zones SET path = '/' || id::text || '/' WHERE path IS NULL;
+ALTER TABLE zones ALTER COLUMN path SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_zones_parent ON zones(parent_id);
+CREATE INDEX IF NOT EXISTS idx_zones_path ON zones(path text_pattern_ops);
+
+-- Down
+DROP INDEX IF EXISTS idx_zones_path;
+DROP INDEX IF EXISTS idx_zones_parent;
+ALTER TABLE zones DROP COLUMN IF EXISTS path;
+ALTER TABLE zones DROP COLUMN IF EXISTS kind;
+ALTER TABLE zones DROP COLUMN IF EXISTS parent_id;
@@ -0,0 +1,39 @@
+-- Up
+-- One plan image per zone. Plan coordinates are image pixels, x to the
+-- right and y down from the top-left corner.
+CREATE TABLE IF NOT EXISTS floor_plans (
+    zone_id UUID PRIMARY KEY REFERENCES zones(id) ON DELETE CASCADE,
+    image_url TEXT NOT NULL,
+    width INT NOT NULL,
+    height INT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- A camera's marker and field of view on the plan of its zone or a zone
+-- above it. heading is degrees clockwise from plan up.
+CREATE TABLE IF NOT EXISTS camera_placements (
+    camera_id UUID PRIMARY KEY REFERENCES cameras(id) ON DELETE CASCADE,
+    plan_zone_id UUID NOT NULL REFERENCES floor_plans(zone_id) ON DELETE CASCADE,
+    x DOUBLE PRECISION NOT NULL,
+    y DOUBLE PRECISION NOT NULL,
+    heading DOUBLE PRECISION NOT NULL DEFAULT 0,
+    fov DOUBLE PRECISION NOT NULL DEFAULT 90,
+    range DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_camera_placements_plan ON camera_placements(plan_zone_id);
+
+-- A zone's outline on the plan of a zone above it
+CREATE TABLE IF NOT EXISTS zone_areas (
+    zone_id UUID PRIMARY KEY REFERENCES zones(id) ON DELETE CASCADE,
+    plan_zone_id UUID NOT NULL REFERENCES floor_plans(zone_id) ON DELETE CASCADE,
+    polygon JSONB NOT NULL, -- [[x, y], ...], not closed
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_zone_areas_plan ON zone_areas(plan_zone_id);
+
+-- Down
+DROP TABLE IF EXISTS zone_areas;
+DROP TABLE IF EXISTS camera_placements;
+DROP TABLE IF EXISTS floor_plans;
@@ -0,0 +1,43 @@
+-- Up
+-- roi_zones is a list of {name, mode, polygon, event_types} regions and
+-- active_hours a {timezone, windows} schedule, NULL for always active.
+-- Values that do not have that shape are set aside in ai_config_legacy and
+-- reset, so they can be re-entered through the validated API.
+CREATE TABLE IF NOT EXISTS ai_config_legacy (
+    camera_id UUID PRIMARY KEY REFERENCES cameras(id) ON DELETE CASCADE,
+    roi_zones JSONB,
+    active_hours JSONB,
+    saved_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+INSERT INTO ai_config_legacy (camera_id, roi_zones, active_hours)
+SELECT camera_id, roi_zones, active_hours FROM ai_configs
+WHERE roi_zones IS NULL
+   OR jsonb_typeof(roi_zones) <> 'array'
+   OR EXISTS (SELECT 1 FROM jsonb_array_elements(CASE WHEN jsonb_typeof(roi_zones) = 'array' THEN roi_zones ELSE '[]' END) r
+              WHERE jsonb_typeof(r) <> 'object' OR jsonb_typeof(r->'polygon') IS DISTINCT FROM 'array')
+   OR (active_hours IS NOT NULL AND active_hours <> '[]'::jsonb
+       AND (jsonb_typeof(active_hours) <> 'object' OR jsonb_typeof(active_hours->'windows') IS DISTINCT FROM 'array'))
+ON CONFLICT (camera_id) DO NOTHING;
+
+-- Each column is reset only while it still has the wrong shape, so values
+-- re-entered since the first run are kept
+UPDATE ai_configs SET roi_zones = '[]'
+WHERE roi_zones IS NULL
+   OR jsonb_typeof(roi_zones) <> 'array'
+   OR EXISTS (SELECT 1 FROM jsonb_array_elements(CASE WHEN jsonb_typeof(roi_zones) = 'array' THEN roi_zones ELSE '[]' END) r
+              WHERE jsonb_typeof(r) <> 'object' OR jsonb_typeof(r->'polygon') IS DISTINCT FROM 'array');
+UPDATE ai_configs SET active_hours = NULL
+WHERE active_hours IS NOT NULL
+  AND (jsonb_typeof(active_hours) <> 'object' OR jsonb_typeof(active_hours->'windows') IS DISTINCT FROM 'array');
+
+ALTER TABLE ai_configs ALTER COLUMN roi_zones SET NOT NULL;
+ALTER TABLE ai_configs ALTER COLUMN active_hours DROP DEFAULT;
+
+-- Down
+ALTER TABLE ai_configs ALTER COLUMN active_hours SET DEFAULT '[]';
+ALTER TABLE ai_configs ALTER COLUMN roi_zones DROP NOT NULL;
+UPDATE ai_configs SET active_hours = '[]' WHERE active_hours IS NULL;
+UPDATE ai_configs c SET roi_zones = l.roi_zones, active_hours = l.active_hours
+FROM ai_config_legacy l WHERE l.camera_id = c.camera_id;
+DROP TABLE IF EXISTS ai_config_legacy;
@@ -0,0 +1,56 @@
+-- Up
+CREATE TABLE IF NOT EXISTS ai_config_templates (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(100) NOT NULL UNIQUE,
+    description TEXT,
+    ai_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    ai_types event_type[] NOT NULL DEFAULT '{}',
+    roi_zones JSONB NOT NULL DEFAULT '[]',
+    active_hours JSONB,
+    sensitivity INT NOT NULL DEFAULT 50,
+    min_confidence INT NOT NULL DEFAULT 60,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- overrides holds the fields the camera sets over its template
+ALTER TABLE ai_configs ADD COLUMN IF NOT EXISTS template_id UUID REFERENCES ai_config_templates(id) ON DELETE SET NULL;
+ALTER TABLE ai_configs ADD COLUMN IF NOT EXISTS overrides JSONB;
+ALTER TABLE ai_configs ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
+CREATE INDEX IF NOT EXISTS idx_ai_configs_template ON ai_configs(template_id);
+
+-- Immutable history; template_id has no foreign key so versions outlive
+-- their template
+CREATE TABLE IF NOT EXISTS ai_config_versions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    camera_id UUID NOT NULL REFERENCES cameras(id) ON DELETE CASCADE,
+    version INT NOT NULL,
+    ai_enabled BOOLEAN NOT NULL,
+    ai_types event_type[] NOT NULL DEFAULT '{}',
+    roi_zones JSONB NOT NULL DEFAULT '[]',
+    active_hours JSONB,
+    sensitivity INT NOT NULL,
+    min_confidence INT NOT NULL,
+    template_id UUID,
+    overrides JSONB,
+    source VARCHAR(20) NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT uniq_ai_config_version UNIQUE (camera_id, version)
+);
+
+-- Existing configs become their camera's first version
+INSERT INTO ai_config_versions (camera_id, version, ai_enabled, ai_types, roi_zones, active_hours, sensitivity, min_confidence, source, created_at)
+SELECT camera_id, 1, COALESCE(ai_enabled, FALSE), COALESCE(ai_types, '{}'), roi_zones, active_hours,
+       COALESCE(sensitivity, 50), COALESCE(min_confidence, 60), 'manual', COALESCE(updated_at, NOW())
+FROM ai_configs
+ON CONFLICT (camera_id, version) DO NOTHING;
+
+-- Down
+DROP TABLE IF EXISTS ai_config_versions;
+DROP INDEX IF EXISTS idx_ai_configs_template;
+ALTER TABLE ai_configs DROP COLUMN IF EXISTS version;
+ALTER TABLE ai_configs DROP COLUMN IF EXISTS overrides;
+ALTER TABLE ai_configs DROP COLUMN IF EXISTS template_id;
+DROP TABLE IF EXISTS ai_config_templates;